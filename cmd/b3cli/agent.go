@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/john/b3-project/internal/agent"
+	"github.com/john/b3-project/internal/config"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/spf13/cobra"
+)
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Agente de desbloqueio (mantém a chave da carteira apenas em memória)",
+	Long: `O agente de desbloqueio funciona como o ssh-agent: mantém a chave de
+criptografia da carteira em memória e a entrega aos comandos do b3cli por um
+socket Unix acessível apenas pelo seu usuário (permissão 0600).
+
+Com o agente rodando, 'wallet open' não grava mais a carteira descriptografada
+(vault.unlocked) nem a chave (session.key) em disco. As chaves são apagadas
+quando o agente fica ocioso por mais tempo que o timeout.
+
+O caminho do socket pode ser alterado com a variável B3CLI_AGENT_SOCK; o
+diretório dele precisa ter permissão 700 e pertencer ao seu usuário.`,
+}
+
+var agentStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Inicia o agente de desbloqueio (em primeiro plano)",
+	Long: `Inicia o agente de desbloqueio e aguarda requisições.
+
+O agente roda em primeiro plano; execute-o em um terminal separado ou em
+segundo plano. Ele termina sozinho após o timeout de inatividade, ou com
+'b3cli agent stop' / Ctrl+C, sempre apagando as chaves da memória.`,
+	Example: `  b3cli agent start &
+  b3cli agent start --timeout 1h`,
+	Args: cobra.NoArgs,
+	RunE: runAgentStart,
+}
+
+var agentStopCmd = &cobra.Command{
+	Use:     "stop",
+	Short:   "Encerra o agente e apaga as chaves da memória",
+	Example: `  b3cli agent stop`,
+	Args:    cobra.NoArgs,
+	RunE:    runAgentStop,
+}
+
+var agentStatusCmd = &cobra.Command{
+	Use:     "status",
+	Short:   "Mostra se o agente está rodando e quais carteiras estão desbloqueadas",
+	Example: `  b3cli agent status`,
+	Args:    cobra.NoArgs,
+	RunE:    runAgentStatus,
+}
+
+func init() {
+	agentStartCmd.Flags().Duration("timeout", agent.DefaultIdleTimeout, "Tempo de inatividade até apagar as chaves e encerrar")
+
+	agentCmd.AddCommand(agentStartCmd)
+	agentCmd.AddCommand(agentStopCmd)
+	agentCmd.AddCommand(agentStatusCmd)
+}
+
+func runAgentStart(cmd *cobra.Command, args []string) error {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	socketPath, err := config.AgentSocketPath()
+	if err != nil {
+		return err
+	}
+	// O socket padrão fica em ~/.b3cli: restringir o diretório antes de criar o socket
+	if os.Getenv(config.AgentSocketEnv) == "" {
+		if err := config.EnsureConfigDir(); err != nil {
+			return err
+		}
+	}
+
+	server := agent.NewServer(socketPath, timeout)
+	if err := server.Listen(); err != nil {
+		return err
+	}
+
+	// Apagar chaves ao receber Ctrl+C ou SIGTERM
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		server.Close()
+	}()
+
+	fmt.Printf("✓ Unlock agent listening on %s\n", socketPath)
+	fmt.Printf("✓ Idle timeout: %s\n", timeout)
+	fmt.Println()
+	fmt.Println("Open a wallet to hand its key to the agent:")
+	fmt.Println("  b3cli wallet open <directory>")
+
+	if err := server.Serve(); err != nil {
+		return err
+	}
+
+	fmt.Println("✓ Unlock agent stopped - keys wiped from memory")
+	return nil
+}
+
+func runAgentStop(cmd *cobra.Command, args []string) error {
+	client, err := newAgentClient()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Stop(); err != nil {
+		if err == agent.ErrNotRunning {
+			fmt.Println("Unlock agent is not running.")
+			return nil
+		}
+		return err
+	}
+
+	fmt.Println("✓ Unlock agent stopped - keys wiped from memory")
+	return nil
+}
+
+func runAgentStatus(cmd *cobra.Command, args []string) error {
+	client, err := newAgentClient()
+	if err != nil {
+		return err
+	}
+
+	status, err := client.Status()
+	if err != nil {
+		if err == agent.ErrNotRunning {
+			fmt.Println("Unlock agent is not running.")
+			fmt.Println("Start it with: b3cli agent start &")
+			return nil
+		}
+		return err
+	}
+
+	fmt.Println("✓ Unlock agent is running")
+	fmt.Printf("  Expires if idle at: %s\n", status.ExpiresAt.Local().Format(time.DateTime))
+	if len(status.Wallets) == 0 {
+		fmt.Println("  No wallets unlocked")
+		return nil
+	}
+
+	fmt.Println("  Unlocked wallets:")
+	for _, walletPath := range status.Wallets {
+		fmt.Printf("    - %s\n", walletPath)
+	}
+
+	return nil
+}
+
+// newAgentClient returns a client for the configured agent socket
+func newAgentClient() (*agent.Client, error) {
+	socketPath, err := config.AgentSocketPath()
+	if err != nil {
+		return nil, err
+	}
+	return agent.NewClient(socketPath), nil
+}
+
+// runningAgent returns a client for the unlock agent, or nil if no agent is running
+func runningAgent() *agent.Client {
+	client, err := newAgentClient()
+	if err != nil || !client.IsRunning() {
+		return nil
+	}
+	return client
+}
+
+// startSession makes an unlocked wallet available to subsequent commands
+// Prefers the unlock agent (key kept in memory only) and falls back to the
+// unlocked cache on disk, valid for ttl, when no agent is running
+func startSession(w *wallet.Wallet, walletPath string, ttl time.Duration) (viaAgent bool, err error) {
+	if client := runningAgent(); client != nil {
+		if err := client.Add(walletPath, w.GetEncryptionKey()); err == nil {
+			// Remove plaintext left behind by an earlier session without agent
+			if err := wallet.ClearUnlocked(walletPath); err != nil {
+				return true, fmt.Errorf("failed to clear unlocked cache: %w", err)
+			}
+			return true, nil
+		}
+	}
+
+	w.StartSession(ttl)
+	if err := w.SaveUnlocked(walletPath); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// sessionTTL resolves how long an unlocked cache stays valid
+// Precedence: value passed on the command line, session_ttl in config.yaml, default
+func sessionTTL(override time.Duration) (time.Duration, error) {
+	if override < 0 {
+		return 0, fmt.Errorf("--ttl deve ser positivo")
+	}
+	if override > 0 {
+		return override, nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao carregar configuração: %w", err)
+	}
+
+	ttl, err := cfg.GetSessionTTL()
+	if err != nil {
+		return 0, err
+	}
+	if ttl == 0 {
+		ttl = wallet.DefaultSessionTTL
+	}
+
+	return ttl, nil
+}
+
+// endSession removes every trace of an unlocked session for the wallet
+// (key held by the agent and unlocked cache on disk)
+func endSession(walletPath string) error {
+	if client := runningAgent(); client != nil {
+		if err := client.Remove(walletPath); err != nil {
+			return fmt.Errorf("failed to remove key from agent: %w", err)
+		}
+	}
+
+	return wallet.ClearUnlocked(walletPath)
+}
+
+// loadFromAgent loads the wallet using a key held by the unlock agent
+// Returns nil if no agent is running or it has no key for the wallet
+func loadFromAgent(walletPath string) *wallet.Wallet {
+	client := runningAgent()
+	if client == nil {
+		return nil
+	}
+
+	key, err := client.Get(walletPath)
+	if err != nil {
+		return nil
+	}
+
+	w, err := wallet.LoadWithKey(walletPath, key)
+	if err != nil {
+		return nil
+	}
+
+	return w
+}
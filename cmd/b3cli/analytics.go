@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/john/b3-project/internal/analytics"
+	"github.com/john/b3-project/internal/quotes"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var analyticsCmd = &cobra.Command{
+	Use:   "analytics",
+	Short: "Métricas de desempenho da carteira",
+	Long: `Métricas de desempenho calculadas a partir das transações, dos proventos e
+dos fechamentos históricos importados com 'b3cli quotes import'.`,
+}
+
+var analyticsReturnsCmd = &cobra.Command{
+	Use:   "returns",
+	Short: "Rentabilidade da carteira e de cada ativo (TWR e XIRR)",
+	Long: `Calcula a rentabilidade da carteira e de cada ativo em um período:
+
+- TWR (rentabilidade ponderada pelo tempo): encadeia a variação entre
+  avaliações sucessivas da carteira, sem a distorção de aportes e vendas.
+  É a medida para comparar com índices (CDI, IBOV).
+- XIRR (taxa interna de retorno, ao ano): considera quando e quanto você
+  aportou; mede o resultado do seu dinheiro.
+
+Compras são aportes; vendas e proventos são valores recebidos. A carteira é
+avaliada no fim de cada mês (ou de cada dia com --frequency daily) com os
+fechamentos importados. Posições sem fechamento são avaliadas pelo custo e
+listadas no final; importe o período com 'b3cli quotes import'.
+
+Ativos que passaram por incorporação, mudança de ticker ou cisão no período
+não têm rentabilidade individual (a posição mudou de ticker); a carteira não
+é afetada.`,
+	Example: `  b3cli analytics returns
+  b3cli analytics returns --from 2024-01-01 --to 2024-12-31
+  b3cli analytics returns --from 2024-06-01 --frequency daily`,
+	Args: cobra.NoArgs,
+	RunE: runAnalyticsReturns,
+}
+
+var analyticsAllocationCmd = &cobra.Command{
+	Use:   "allocation",
+	Short: "Alocação da carteira por tipo, subtipo, segmento e instituição",
+	Long: `Exibe, em uma interface interativa com gráficos de barras, o percentual da
+carteira em cada tipo, subtipo, segmento e instituição, e a concentração
+das posições:
+
+- Pesos pelo valor de mercado (cotações em cache, veja 'b3cli quotes') ou
+  pelo custo (quantidade × preço médio); 'm' alterna entre os dois
+- Top 1/5/10: peso somado das maiores posições
+- HHI (índice Herfindahl-Hirschman): soma dos pesos ao quadrado, de
+  10000/N (carteira igualmente dividida) a 10000 (uma única posição)
+
+Na visão por instituição, cada ativo é dividido pela quantidade líquida
+comprada em cada corretora. Ações recebidas em eventos corporativos seguem
+a mesma divisão; sem negociações, a instituição fica como não identificada.
+
+Navegação:
+- ←/→ ou Tab: trocar a dimensão
+- m: alternar mercado/custo
+- q ou Esc: sair`,
+	Example: `  b3cli analytics allocation
+  b3cli analytics allocation --refresh`,
+	Args: cobra.NoArgs,
+	RunE: runAnalyticsAllocation,
+}
+
+func init() {
+	analyticsAllocationCmd.Flags().Bool("refresh", false, "Atualiza as cotações antes de exibir")
+	analyticsCmd.AddCommand(analyticsAllocationCmd)
+
+	analyticsReturnsCmd.Flags().String("from", "", "Data inicial (YYYY-MM-DD, padrão: início do ano)")
+	analyticsReturnsCmd.Flags().String("to", "", "Data final (YYYY-MM-DD, padrão: hoje)")
+	analyticsReturnsCmd.Flags().String("frequency", "monthly", "Frequência das avaliações: monthly ou daily")
+
+	analyticsCmd.AddCommand(analyticsReturnsCmd)
+}
+
+func runAnalyticsReturns(cmd *cobra.Command, args []string) error {
+	from, to, err := periodFlags(cmd)
+	if err != nil {
+		return err
+	}
+	frequency, _ := cmd.Flags().GetString("frequency")
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	history := quotes.OpenHistory(w.GetDirPath())
+	report, err := analytics.ComputeReturns(w, history, analytics.ReturnsOptions{
+		From:      from,
+		To:        to,
+		Frequency: analytics.Frequency(frequency),
+	})
+	if err != nil {
+		return err
+	}
+
+	p := report.Portfolio
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Rentabilidade de %s a %s", p.From.Format("02/01/2006"), p.To.Format("02/01/2006"))))
+	fmt.Printf("%d avaliações (%s)\n\n", report.Valuations, frequency)
+
+	fmt.Println(selectedItemStyle.Render("Carteira"))
+	fmt.Printf("  Valor inicial:  R$ %s\n", p.StartValue.StringFixed(2))
+	fmt.Printf("  Aportes:        R$ %s\n", p.Invested.StringFixed(2))
+	fmt.Printf("  Vendas:         R$ %s\n", p.Withdrawn.StringFixed(2))
+	fmt.Printf("  Proventos:      R$ %s\n", p.Income.StringFixed(2))
+	fmt.Printf("  Valor final:    R$ %s\n", p.EndValue.StringFixed(2))
+	fmt.Printf("  Resultado:      R$ %s\n", signed(p.Gain()))
+	fmt.Printf("  TWR:            %s no período", formatRate(p.TWR))
+	if p.Days() >= 365 {
+		fmt.Printf(" (%s a.a.)", formatRate(p.AnnualizedTWR()))
+	}
+	fmt.Println()
+	if p.HasXIRR {
+		fmt.Printf("  XIRR:           %s a.a.\n", formatRate(p.XIRR))
+	} else {
+		fmt.Println("  XIRR:           n/d")
+	}
+
+	if len(report.Assets) > 0 {
+		fmt.Println()
+		fmt.Printf("%-8s %12s %12s %12s %12s %10s %12s %9s %9s\n",
+			"TICKER", "INICIAL", "APORTES", "VENDAS", "FINAL", "PROVENTOS", "RESULTADO", "TWR", "XIRR a.a.")
+		for _, r := range report.Assets {
+			twr, xirr := formatRate(r.TWR), "n/d"
+			if r.HasXIRR {
+				xirr = formatRate(r.XIRR)
+			}
+			if r.TransferEvent != "" {
+				twr, xirr = "n/d", "n/d"
+			}
+			fmt.Printf("%-8s %12s %12s %12s %12s %10s %12s %9s %9s\n",
+				r.Ticker, r.StartValue.StringFixed(2), r.Invested.StringFixed(2), r.Withdrawn.StringFixed(2),
+				r.EndValue.StringFixed(2), r.Income.StringFixed(2), signed(r.Gain()), twr, xirr)
+		}
+		for _, r := range report.Assets {
+			if r.TransferEvent != "" {
+				fmt.Printf("  %s: %s no período, sem rentabilidade individual\n", r.Ticker, r.TransferEvent)
+			}
+		}
+	}
+
+	if len(report.Unpriced) > 0 {
+		tickers := make([]string, 0, len(report.Unpriced))
+		for ticker := range report.Unpriced {
+			tickers = append(tickers, ticker)
+		}
+		sort.Strings(tickers)
+
+		fmt.Println("\n⚠ Sem fechamento (avaliados pelo custo, a variação de preço não aparece):")
+		for _, ticker := range tickers {
+			fmt.Printf("  - %s a partir de %s\n", ticker, report.Unpriced[ticker].Format("02/01/2006"))
+		}
+		fmt.Println("  Importe o período com 'b3cli quotes import <COTAHIST>'.")
+	}
+
+	return nil
+}
+
+func runAnalyticsAllocation(cmd *cobra.Command, args []string) error {
+	refresh, _ := cmd.Flags().GetBool("refresh")
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+	if len(w.GetActiveAssets()) == 0 {
+		fmt.Println("Nenhum ativo ativo encontrado na carteira.")
+		return nil
+	}
+
+	var cache *quotes.Cache
+	if refresh {
+		cache, err = refreshQuotes(w, "", false, false)
+	} else {
+		cache, err = quotes.LoadCache(w.GetDirPath())
+	}
+	if err != nil {
+		return err
+	}
+
+	allocations := make(map[analytics.Dimension]*analytics.Allocation, len(analytics.Dimensions))
+	for _, dimension := range analytics.Dimensions {
+		allocation, err := analytics.Allocate(w, dimension, cache.Prices())
+		if err != nil {
+			return err
+		}
+		allocations[dimension] = allocation
+	}
+
+	p := tea.NewProgram(newAllocationModel(allocations), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("erro ao executar interface: %w", err)
+	}
+
+	return nil
+}
+
+// periodFlags lê --from e --to (padrão: do início do ano até hoje)
+func periodFlags(cmd *cobra.Command) (from, to time.Time, err error) {
+	fromStr, _ := cmd.Flags().GetString("from")
+	toStr, _ := cmd.Flags().GetString("to")
+
+	now := time.Now()
+	to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from = time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if fromStr != "" {
+		if from, err = time.Parse("2006-01-02", fromStr); err != nil {
+			return from, to, fmt.Errorf("data inicial inválida: %s (use YYYY-MM-DD)", fromStr)
+		}
+	}
+	if toStr != "" {
+		if to, err = time.Parse("2006-01-02", toStr); err != nil {
+			return from, to, fmt.Errorf("data final inválida: %s (use YYYY-MM-DD)", toStr)
+		}
+	}
+	if to.Before(from) {
+		return from, to, fmt.Errorf("a data final (%s) é anterior à inicial (%s)", toStr, fromStr)
+	}
+
+	return from, to, nil
+}
+
+// formatRate formata uma taxa (0.1234 = +12.34%)
+func formatRate(rate float64) string {
+	return fmt.Sprintf("%+.2f%%", rate*100)
+}
+
+// signed formata um valor com sinal explícito
+func signed(value decimal.Decimal) string {
+	if value.IsNegative() {
+		return value.StringFixed(2)
+	}
+	return "+" + value.StringFixed(2)
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/john/b3-project/internal/analytics"
+)
+
+type allocationModel struct {
+	allocations map[analytics.Dimension]*analytics.Allocation
+	dimension   int  // Índice em analytics.Dimensions
+	byCost      bool // Pesos pelo custo em vez do valor de mercado
+	hasPrices   bool // Há cotação para algum ativo
+}
+
+// allocationDimensionNames são os rótulos das abas
+var allocationDimensionNames = map[analytics.Dimension]string{
+	analytics.ByGroup:       "Tipo / Segmento",
+	analytics.ByType:        "Tipo",
+	analytics.BySubType:     "Subtipo",
+	analytics.BySegment:     "Segmento",
+	analytics.ByInstitution: "Instituição",
+}
+
+const allocationBarWidth = 40
+
+var (
+	allocationTabStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("241")).
+				Padding(0, 1)
+
+	allocationActiveTabStyle = lipgloss.NewStyle().
+					Bold(true).
+					Foreground(lipgloss.Color("205")).
+					Underline(true).
+					Padding(0, 1)
+
+	allocationBarStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("141"))
+
+	allocationKeyStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("117"))
+)
+
+func newAllocationModel(allocations map[analytics.Dimension]*analytics.Allocation) allocationModel {
+	group := allocations[analytics.ByGroup]
+	hasPrices := group != nil && len(group.Unpriced) < len(group.Assets.Weights)
+
+	return allocationModel{
+		allocations: allocations,
+		byCost:      !hasPrices,
+		hasPrices:   hasPrices,
+	}
+}
+
+func (m allocationModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m allocationModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			return m, tea.Quit
+		case "right", "l", "tab":
+			m.dimension = (m.dimension + 1) % len(analytics.Dimensions)
+		case "left", "h", "shift+tab":
+			m.dimension = (m.dimension + len(analytics.Dimensions) - 1) % len(analytics.Dimensions)
+		case "m":
+			if m.hasPrices {
+				m.byCost = !m.byCost
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m allocationModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(assetsTitleStyle.Render("📊 Alocação da Carteira"))
+	b.WriteString("\n")
+
+	// Abas
+	tabs := make([]string, 0, len(analytics.Dimensions))
+	for i, d := range analytics.Dimensions {
+		if i == m.dimension {
+			tabs = append(tabs, allocationActiveTabStyle.Render(allocationDimensionNames[d]))
+		} else {
+			tabs = append(tabs, allocationTabStyle.Render(allocationDimensionNames[d]))
+		}
+	}
+	b.WriteString(strings.Join(tabs, " "))
+	b.WriteString("\n\n")
+
+	a := m.allocations[analytics.Dimensions[m.dimension]]
+	basis := "valor de mercado"
+	total := a.TotalMarket
+	if m.byCost {
+		basis = "custo (quantidade × PM)"
+		total = a.TotalCost
+	}
+	b.WriteString(assetsLabelStyle.Render(fmt.Sprintf("Total: R$ %s • pesos por %s", total.StringFixed(2), basis)))
+	b.WriteString("\n\n")
+
+	// Barras (escala: maior fatia ocupa a largura toda)
+	largest := 0.0
+	for _, s := range a.Slices {
+		largest = maxFloat(largest, m.weight(s))
+	}
+	for _, s := range a.Slices {
+		weight := m.weight(s)
+		width := 0
+		if largest > 0 {
+			width = int(weight / largest * allocationBarWidth)
+		}
+		value := s.Market
+		if m.byCost {
+			value = s.Cost
+		}
+
+		b.WriteString(allocationKeyStyle.Render(fmt.Sprintf("%-28s", truncate(s.Key, 28))))
+		b.WriteString(" ")
+		b.WriteString(allocationBarStyle.Render(strings.Repeat("█", width)))
+		b.WriteString(strings.Repeat(" ", allocationBarWidth-width))
+		b.WriteString(" ")
+		b.WriteString(overviewPercentStyle.Render(fmt.Sprintf("%6.2f%%", weight*100)))
+		b.WriteString(assetsLabelStyle.Render(fmt.Sprintf("  R$ %12s  (%d)", value.StringFixed(2), len(s.Tickers))))
+		b.WriteString("\n")
+	}
+
+	// Concentração (sempre por valor de mercado, com custo para ativos sem cotação)
+	c := a.Assets
+	b.WriteString("\n")
+	b.WriteString(assetsGroupStyle.Render("Concentração"))
+	b.WriteString("\n")
+	b.WriteString(assetsLabelStyle.Render(fmt.Sprintf("Maior posição: %s (%.2f%%) • Top 5: %.2f%% • Top 10: %.2f%%",
+		topTicker(c), c.Top(1)*100, c.Top(5)*100, c.Top(10)*100)))
+	b.WriteString("\n")
+	b.WriteString(assetsLabelStyle.Render(fmt.Sprintf("HHI dos ativos: %.0f (equivale a %.1f posições iguais) • HHI de %s: %.0f",
+		c.HHI*10000, c.EffectivePositions(), strings.ToLower(allocationDimensionNames[a.Dimension]), a.HHI()*10000)))
+	b.WriteString("\n")
+
+	if len(a.Unpriced) > 0 && m.hasPrices {
+		b.WriteString(assetsHintStyle.Render(fmt.Sprintf("ℹ  Sem cotação (contados pelo custo): %s", strings.Join(a.Unpriced, ", "))))
+		b.WriteString("\n")
+	} else if !m.hasPrices {
+		b.WriteString(assetsHintStyle.Render("ℹ  Sem cotações em cache: pesos pelo custo. Use 'b3cli quotes update' para pesos de mercado."))
+		b.WriteString("\n")
+	}
+
+	help := "←/→: dimensão • q/esc: sair"
+	if m.hasPrices {
+		help = "←/→: dimensão • m: mercado/custo • q/esc: sair"
+	}
+	b.WriteString(assetsHelpStyle.Render(help))
+
+	return docStyle.Render(b.String())
+}
+
+// weight retorna o peso da fatia na base selecionada
+func (m allocationModel) weight(s analytics.AllocationSlice) float64 {
+	if m.byCost {
+		return s.CostWeight
+	}
+	return s.MarketWeight
+}
+
+// topTicker retorna o ticker da maior posição
+func topTicker(c analytics.Concentration) string {
+	if len(c.Weights) == 0 {
+		return "-"
+	}
+	return c.Weights[0].Ticker
+}
+
+// truncate corta um texto longo em n caracteres
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/john/b3-project/internal/analytics"
+	"github.com/john/b3-project/internal/quotes"
+	"github.com/spf13/cobra"
+)
+
+var analyticsBenchmarksCmd = &cobra.Command{
+	Use:   "benchmarks",
+	Short: "Gerencia as séries de índices usadas na comparação",
+	Long: `Índices de referência (CDI, IPCA, IBOV, IFIX, ...) carregados de arquivos CSV
+locais e guardados no diretório benchmarks/ da wallet.
+
+Formatos aceitos (coluna de data e coluna de valor, ',' ou ';', decimal com
+vírgula ou ponto, como baixados do Banco Central/IBGE/B3):
+- daily_rate: taxa diária em % (CDI, série 12 do SGS: 0,043739)
+- monthly_rate: variação mensal em % (IPCA, série 433 do SGS: 0,42)
+- monthly_index: número-índice mensal (IPCA, tabela 1737 do IBGE)
+- close: fechamento ou pontos do índice (IBOV, IFIX)
+
+CDI, SELIC, IPCA, IBOV, IFIX, SMLL e IDIV têm formato padrão; para outros
+nomes (ou para o IPCA em variação mensal) informe --kind.`,
+}
+
+var analyticsBenchmarksImportCmd = &cobra.Command{
+	Use:   "import <nome> <arquivo>",
+	Short: "Carrega (ou substitui) a série de um índice",
+	Example: `  b3cli analytics benchmarks import cdi bcdata.sgs.12.csv
+  b3cli analytics benchmarks import ipca bcdata.sgs.433.csv --kind monthly_rate
+  b3cli analytics benchmarks import ibov ibov.csv`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAnalyticsBenchmarksImport,
+}
+
+var analyticsBenchmarksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lista as séries carregadas",
+	Args:  cobra.NoArgs,
+	RunE:  runAnalyticsBenchmarksList,
+}
+
+var analyticsCompareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compara a rentabilidade da carteira com os índices",
+	Long: `Compara a rentabilidade acumulada (TWR) da carteira com a dos índices
+carregados em 'b3cli analytics benchmarks', no mesmo período, e desenha a
+evolução em um gráfico de linhas.
+
+Para o CDI, mostra também quanto a carteira rendeu em percentual do índice
+(ex: 120% do CDI). Se a série de um índice não cobre o período inteiro (ex:
+IPCA do último mês ainda não divulgado), a comparação vai até a última data
+disponível e é sinalizada.`,
+	Example: `  b3cli analytics compare
+  b3cli analytics compare --from 2024-01-01 --to 2024-12-31 --benchmarks cdi,ibov`,
+	Args: cobra.NoArgs,
+	RunE: runAnalyticsCompare,
+}
+
+// benchmarkColors dá a cor de cada índice no gráfico
+var benchmarkColors = map[string]lipgloss.Color{
+	"CDI":  "42",
+	"IPCA": "226",
+	"IBOV": "117",
+	"IFIX": "141",
+}
+
+func init() {
+	analyticsBenchmarksImportCmd.Flags().String("kind", "", "Formato da série: daily_rate, monthly_rate, monthly_index ou close")
+
+	analyticsCompareCmd.Flags().String("from", "", "Data inicial (YYYY-MM-DD, padrão: início do ano)")
+	analyticsCompareCmd.Flags().String("to", "", "Data final (YYYY-MM-DD, padrão: hoje)")
+	analyticsCompareCmd.Flags().String("frequency", "monthly", "Frequência das avaliações: monthly ou daily")
+	analyticsCompareCmd.Flags().String("benchmarks", "", "Índices separados por vírgula (padrão: todos os carregados)")
+
+	analyticsBenchmarksCmd.AddCommand(analyticsBenchmarksImportCmd)
+	analyticsBenchmarksCmd.AddCommand(analyticsBenchmarksListCmd)
+	analyticsCmd.AddCommand(analyticsBenchmarksCmd)
+	analyticsCmd.AddCommand(analyticsCompareCmd)
+}
+
+func runAnalyticsBenchmarksImport(cmd *cobra.Command, args []string) error {
+	name := strings.ToUpper(strings.TrimSpace(args[0]))
+	kindFlag, _ := cmd.Flags().GetString("kind")
+
+	kind := analytics.SeriesKind(kindFlag)
+	if kind == "" {
+		defaultKind, known := analytics.DefaultBenchmarkKind(name)
+		if !known {
+			return fmt.Errorf("formato desconhecido para %s: informe --kind (daily_rate, monthly_rate, monthly_index ou close)", name)
+		}
+		kind = defaultKind
+	}
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(args[1])
+	if err != nil {
+		return fmt.Errorf("erro ao abrir %s: %w", args[1], err)
+	}
+	defer file.Close()
+
+	benchmark, err := analytics.ParseBenchmarkCSV(file, name, kind)
+	if err != nil {
+		return fmt.Errorf("erro ao ler %s: %w", args[1], err)
+	}
+	if err := analytics.SaveBenchmark(w.GetDirPath(), benchmark); err != nil {
+		return err
+	}
+
+	first, last := benchmark.Points[0].Date, benchmark.Points[len(benchmark.Points)-1].Date
+	fmt.Printf("✓ %s: %d valores (%s) de %s a %s\n", benchmark.Name, len(benchmark.Points), benchmark.Kind,
+		first.Format("02/01/2006"), last.Format("02/01/2006"))
+
+	return nil
+}
+
+func runAnalyticsBenchmarksList(cmd *cobra.Command, args []string) error {
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	names, err := analytics.ListBenchmarks(w.GetDirPath())
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Println("Nenhum índice carregado. Use 'b3cli analytics benchmarks import <nome> <arquivo>'.")
+		return nil
+	}
+
+	fmt.Printf("%-8s %-14s %8s  %s\n", "NOME", "FORMATO", "VALORES", "PERÍODO")
+	for _, name := range names {
+		b, err := analytics.LoadBenchmark(w.GetDirPath(), name)
+		if err != nil {
+			fmt.Printf("%-8s ⚠ %v\n", name, err)
+			continue
+		}
+		fmt.Printf("%-8s %-14s %8d  %s a %s\n", b.Name, b.Kind, len(b.Points),
+			b.Points[0].Date.Format("02/01/2006"), b.Points[len(b.Points)-1].Date.Format("02/01/2006"))
+	}
+
+	return nil
+}
+
+func runAnalyticsCompare(cmd *cobra.Command, args []string) error {
+	from, to, err := periodFlags(cmd)
+	if err != nil {
+		return err
+	}
+	frequency, _ := cmd.Flags().GetString("frequency")
+	selected, _ := cmd.Flags().GetString("benchmarks")
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	names, err := analytics.ListBenchmarks(w.GetDirPath())
+	if err != nil {
+		return err
+	}
+	if selected != "" {
+		names = strings.Split(strings.ToUpper(selected), ",")
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("nenhum índice carregado: use 'b3cli analytics benchmarks import <nome> <arquivo>'")
+	}
+
+	benchmarks := make([]*analytics.Benchmark, 0, len(names))
+	for _, name := range names {
+		b, err := analytics.LoadBenchmark(w.GetDirPath(), strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+		benchmarks = append(benchmarks, b)
+	}
+
+	report, err := analytics.ComputeReturns(w, quotes.OpenHistory(w.GetDirPath()), analytics.ReturnsOptions{
+		From:      from,
+		To:        to,
+		Frequency: analytics.Frequency(frequency),
+	})
+	if err != nil {
+		return err
+	}
+	p := report.Portfolio
+	comparisons := analytics.CompareBenchmarks(p, benchmarks)
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Carteira vs. índices de %s a %s", p.From.Format("02/01/2006"), p.To.Format("02/01/2006"))))
+	fmt.Println()
+	fmt.Printf("%-10s %10s %12s %12s\n", "", "RETORNO", "DIFERENÇA", "% DO ÍNDICE")
+	fmt.Printf("%-10s %10s\n", "Carteira", formatRate(p.TWR))
+
+	notes := make([]string, 0)
+	for _, c := range comparisons {
+		if !c.Covered {
+			fmt.Printf("%-10s %10s\n", c.Name, "n/d")
+			notes = append(notes, fmt.Sprintf("%s: a série não cobre o início do período", c.Name))
+			continue
+		}
+
+		percentOf := "—"
+		if c.Return > 0 {
+			percentOf = fmt.Sprintf("%.1f%%", p.TWR/c.Return*100)
+		}
+		fmt.Printf("%-10s %10s %10.2fpp %12s\n", c.Name, formatRate(c.Return), (p.TWR-c.Return)*100, percentOf)
+
+		if c.Through.Before(p.To) {
+			notes = append(notes, fmt.Sprintf("%s: série disponível até %s", c.Name, c.Through.Format("02/01/2006")))
+		}
+	}
+	for _, note := range notes {
+		fmt.Printf("⚠ %s\n", note)
+	}
+	if len(report.Unpriced) > 0 {
+		fmt.Printf("⚠ %d ativo(s) sem fechamento avaliados pelo custo (veja 'b3cli analytics returns')\n", len(report.Unpriced))
+	}
+
+	// Gráfico: retorno acumulado em % nas datas de avaliação da carteira
+	dates := make([]time.Time, len(p.Path))
+	portfolioLine := chartSeries{name: "Carteira", color: "205", values: make([]float64, len(p.Path))}
+	for i, point := range p.Path {
+		dates[i] = point.Date
+		portfolioLine.values[i] = point.Return * 100
+	}
+	lines := []chartSeries{portfolioLine}
+	for _, c := range comparisons {
+		if !c.Covered {
+			continue
+		}
+		color, ok := benchmarkColors[c.Name]
+		if !ok {
+			color = "208"
+		}
+		line := chartSeries{name: c.Name, color: color, values: make([]float64, len(p.Path))}
+		for i := range line.values {
+			line.values[i] = math.NaN()
+			if i < len(c.Path) {
+				line.values[i] = c.Path[i].Return * 100
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	if chart := renderLineChart(dates, lines, 60, 14); chart != "" {
+		fmt.Println()
+		fmt.Print(chart)
+	}
+
+	return nil
+}
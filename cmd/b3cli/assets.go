@@ -6,6 +6,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/quotes"
 	"github.com/spf13/cobra"
 )
 
@@ -24,7 +25,10 @@ Direitos de subscrição são direitos de compra de novas ações/cotas emitidas
 ou fundo. Quando você recebe ou vende direitos de subscrição, eles aparecem como um ticker
 separado (geralmente terminando em 11, 12, etc.).
 
-Este comando permite vincular o direito de subscrição ao ativo original.`,
+Este comando permite vincular o direito de subscrição ao ativo original.
+
+Para acompanhar preço de exercício, exercício parcial, direitos vendidos e expirados,
+use 'b3cli events subscription'.`,
 	Example: `  # Marcar MXRF12 como subscrição de MXRF11
   b3cli assets subscription MXRF12 subscription@MXRF11
 
@@ -42,14 +46,19 @@ var assetsOverviewCmd = &cobra.Command{
 - Quantidade de ativos em carteira
 - Valor total investido (soma de todas as compras)
 - Preço médio ponderado
+- Cotação, valor de mercado e ganho/perda não realizado (quando há cotação)
 
 A lista é ordenada alfabeticamente por ticker.
 
+As cotações vêm do cache da wallet (veja 'b3cli quotes'). Use --refresh para
+atualizá-las antes de exibir o resumo.
+
 IMPORTANTE: Você deve ter aberto uma wallet antes de usar este comando.
 Use 'b3cli wallet open <diretório>' para abrir uma wallet.`,
-	Example: `  b3cli assets overview`,
-	Args:    cobra.NoArgs,
-	RunE:    runAssetsOverview,
+	Example: `  b3cli assets overview
+  b3cli assets overview --refresh`,
+	Args: cobra.NoArgs,
+	RunE: runAssetsOverview,
 }
 
 var assetsSoldCmd = &cobra.Command{
@@ -99,6 +108,8 @@ Use 'b3cli wallet open <diretório>' para abrir uma wallet.`,
 }
 
 func init() {
+	assetsOverviewCmd.Flags().Bool("refresh", false, "Atualiza as cotações antes de exibir o resumo")
+
 	assetsCmd.AddCommand(assetsSubscriptionCmd)
 	assetsCmd.AddCommand(assetsOverviewCmd)
 	assetsCmd.AddCommand(assetsSoldCmd)
@@ -180,8 +191,20 @@ func runAssetsOverview(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Cotações em cache (ou atualizadas com --refresh)
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	var cache *quotes.Cache
+	if refresh {
+		cache, err = refreshQuotes(w, "", false, false)
+	} else {
+		cache, err = quotes.LoadCache(w.GetDirPath())
+	}
+	if err != nil {
+		return err
+	}
+
 	// Iniciar interface Bubble Tea
-	p := tea.NewProgram(initialAssetsOverviewModel(w), tea.WithAltScreen())
+	p := tea.NewProgram(initialAssetsOverviewModel(w, cache), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("erro ao executar interface: %w", err)
 	}
@@ -222,9 +222,16 @@ func (m model) updateEditView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case "enter":
 		// Salvar mudanças
-		m.selectedAsset.Type = m.typeInput.Value()
-		m.selectedAsset.SubType = m.subTypeInput.Value()
-		m.selectedAsset.Segment = m.segmentInput.Value()
+		err := m.wallet.UpdateAssetMetadata(
+			m.selectedAsset.ID,
+			m.typeInput.Value(),
+			m.subTypeInput.Value(),
+			m.segmentInput.Value(),
+		)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
 
 		if err := m.wallet.Save(m.walletPath); err != nil {
 			m.err = err
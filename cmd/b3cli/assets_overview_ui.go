@@ -7,7 +7,10 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/john/b3-project/internal/analytics"
+	"github.com/john/b3-project/internal/quotes"
 	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
 )
 
 type assetsOverviewModel struct {
@@ -15,6 +18,10 @@ type assetsOverviewModel struct {
 	activeAssets map[string]*wallet.Asset
 	soldAssets   map[string]*wallet.Asset
 	groups       []groupInfo
+	quotes       map[string]quotes.Quote
+	valuations   map[string]wallet.AssetValuation
+	unpriced     []string
+	allocation   map[string]analytics.AllocationSlice // Peso de cada grupo, pelo rótulo "tipo / segmento"
 }
 
 type groupInfo struct {
@@ -55,12 +62,19 @@ var (
 	assetsLabelStyle = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("252"))
 
+	assetsGainStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("42"))
+
+	assetsLossStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196"))
+
 	assetsHelpStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("241")).
 			MarginTop(1)
 )
 
-func initialAssetsOverviewModel(w *wallet.Wallet) assetsOverviewModel {
+// initialAssetsOverviewModel monta o resumo; cache pode ser nil (sem cotações)
+func initialAssetsOverviewModel(w *wallet.Wallet, cache *quotes.Cache) assetsOverviewModel {
 	activeAssets := w.GetActiveAssets()
 	soldAssets := w.GetSoldAssets()
 	groups := w.GroupActiveAssetsByTypeAndSegment()
@@ -93,11 +107,33 @@ func initialAssetsOverviewModel(w *wallet.Wallet) assetsOverviewModel {
 		return sortedGroups[i].key.Segment < sortedGroups[j].key.Segment
 	})
 
+	// Avaliar as posições com as cotações em cache
+	quoteByTicker := make(map[string]quotes.Quote)
+	prices := make(map[string]decimal.Decimal)
+	if cache != nil {
+		for _, q := range cache.All() {
+			quoteByTicker[q.Ticker] = q
+			prices[q.Ticker] = q.Price
+		}
+	}
+	valuations, unpriced := w.ValuateActiveAssets(prices)
+
+	allocation := make(map[string]analytics.AllocationSlice)
+	if a, err := analytics.Allocate(w, analytics.ByGroup, prices); err == nil {
+		for _, slice := range a.Slices {
+			allocation[slice.Key] = slice
+		}
+	}
+
 	return assetsOverviewModel{
 		wallet:       w,
 		activeAssets: activeAssets,
 		soldAssets:   soldAssets,
 		groups:       sortedGroups,
+		quotes:       quoteByTicker,
+		valuations:   valuations,
+		unpriced:     unpriced,
+		allocation:   allocation,
 	}
 }
 
@@ -125,6 +161,10 @@ func (m assetsOverviewModel) View() string {
 	b.WriteString("\n")
 	b.WriteString(assetsLabelStyle.Render(fmt.Sprintf("Ativos em carteira: %d", len(m.activeAssets))))
 	b.WriteString("\n")
+	if len(m.valuations) > 0 {
+		b.WriteString(m.renderMarketTotals())
+		b.WriteString("\n")
+	}
 
 	// Exibir cada grupo
 	for _, group := range m.groups {
@@ -139,6 +179,12 @@ func (m assetsOverviewModel) View() string {
 
 		b.WriteString("\n")
 		b.WriteString(assetsGroupStyle.Render(fmt.Sprintf("📁 %s / %s", subType, segment)))
+		if slice, ok := m.allocation[subType+" / "+segment]; ok {
+			b.WriteString(assetsLabelStyle.Render(fmt.Sprintf("  Custo: R$ %s (%.1f%%)", slice.Cost.StringFixed(2), slice.CostWeight*100)))
+			if len(m.valuations) > 0 {
+				b.WriteString(assetsLabelStyle.Render(fmt.Sprintf(" • Mercado: R$ %s (%.1f%%)", slice.Market.StringFixed(2), slice.MarketWeight*100)))
+			}
+		}
 		b.WriteString("\n")
 
 		for _, ticker := range group.tickers {
@@ -155,10 +201,31 @@ func (m assetsOverviewModel) View() string {
 			b.WriteString(" • ")
 			b.WriteString(assetsLabelStyle.Render("PM: "))
 			b.WriteString(assetsPMStyle.Render(fmt.Sprintf("R$ %8s", asset.AveragePrice.StringFixed(4))))
+			if v, ok := m.valuations[ticker]; ok {
+				b.WriteString(" • ")
+				b.WriteString(assetsLabelStyle.Render("Cotação: "))
+				b.WriteString(assetsPMStyle.Render(fmt.Sprintf("R$ %8s", v.Price.StringFixed(2))))
+				b.WriteString(" • ")
+				b.WriteString(assetsLabelStyle.Render("Mercado: "))
+				b.WriteString(assetsValueStyle.Render(fmt.Sprintf("R$ %10s", v.MarketValue.StringFixed(2))))
+				b.WriteString(" • ")
+				b.WriteString(renderUnrealized(v.UnrealizedGain, v.UnrealizedRatio))
+			}
 			b.WriteString("\n")
 		}
 	}
 
+	// Hint sobre ativos sem cotação
+	if len(m.unpriced) > 0 {
+		b.WriteString("\n")
+		if len(m.valuations) == 0 {
+			b.WriteString(assetsHintStyle.Render("ℹ  Sem cotações em cache. Use 'b3cli quotes update' ou --refresh para ver o valor de mercado."))
+		} else {
+			b.WriteString(assetsHintStyle.Render(fmt.Sprintf("ℹ  Sem cotação: %s (fora dos totais de mercado).", strings.Join(m.unpriced, ", "))))
+		}
+		b.WriteString("\n")
+	}
+
 	// Hint sobre ativos vendidos
 	if len(m.soldAssets) > 0 {
 		b.WriteString("\n")
@@ -172,3 +239,49 @@ func (m assetsOverviewModel) View() string {
 
 	return docStyle.Render(b.String())
 }
+
+// renderMarketTotals resume o valor de mercado dos ativos com cotação
+func (m assetsOverviewModel) renderMarketTotals() string {
+	cost := decimal.Zero
+	market := decimal.Zero
+	var oldest quotes.Quote
+	for ticker, v := range m.valuations {
+		cost = cost.Add(v.CostBasis)
+		market = market.Add(v.MarketValue)
+		if q := m.quotes[ticker]; oldest.Ticker == "" || q.Time.Before(oldest.Time) {
+			oldest = q
+		}
+	}
+
+	gain := market.Sub(cost)
+	ratio := decimal.Zero
+	if cost.IsPositive() {
+		ratio = gain.Div(cost)
+	}
+
+	var b strings.Builder
+	b.WriteString(assetsLabelStyle.Render("Valor de mercado: "))
+	b.WriteString(assetsValueStyle.Render("R$ " + market.StringFixed(2)))
+	b.WriteString(assetsLabelStyle.Render(" • Custo: "))
+	b.WriteString(assetsValueStyle.Render("R$ " + cost.StringFixed(2)))
+	b.WriteString(" • ")
+	b.WriteString(renderUnrealized(gain, ratio))
+	b.WriteString("\n")
+	b.WriteString(assetsLabelStyle.Render(fmt.Sprintf("Cotações de %d/%d ativo(s), mais antiga em %s",
+		len(m.valuations), len(m.activeAssets), oldest.Time.Local().Format("02/01/2006 15:04"))))
+
+	return b.String()
+}
+
+// renderUnrealized formata o ganho/perda não realizado com o percentual sobre o custo
+func renderUnrealized(gain, ratio decimal.Decimal) string {
+	style := assetsGainStyle
+	sign := "+"
+	if gain.IsNegative() {
+		style = assetsLossStyle
+		sign = ""
+	}
+	percent := ratio.Mul(decimal.NewFromInt(100))
+	return assetsLabelStyle.Render("Resultado: ") +
+		style.Render(fmt.Sprintf("%sR$ %s (%s%s%%)", sign, gain.StringFixed(2), sign, percent.StringFixed(2)))
+}
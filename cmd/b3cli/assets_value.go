@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/john/b3-project/internal/quotes"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var assetsValueCmd = &cobra.Command{
+	Use:   "value",
+	Short: "Avalia a carteira em uma data passada",
+	Long: `Avalia a carteira ao final de uma data usando os fechamentos diários
+importados com 'b3cli quotes import'.
+
+A posição de cada ativo é a vigente naquele dia: eventos corporativos
+posteriores (desdobramentos, grupamentos, incorporações) não são aplicados,
+assim as quantidades são comparáveis aos preços históricos, que não são
+ajustados. Sem pregão na data, vale o último fechamento anterior.`,
+	Example: `  b3cli assets value --date 2023-12-29
+  b3cli assets value`,
+	Args: cobra.NoArgs,
+	RunE: runAssetsValue,
+}
+
+func init() {
+	assetsValueCmd.Flags().String("date", "", "Data da avaliação (YYYY-MM-DD, padrão: hoje)")
+
+	assetsCmd.AddCommand(assetsValueCmd)
+}
+
+func runAssetsValue(cmd *cobra.Command, args []string) error {
+	dateStr, _ := cmd.Flags().GetString("date")
+
+	date := time.Now()
+	if dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return fmt.Errorf("data inválida: %s (use YYYY-MM-DD)", dateStr)
+		}
+		date = parsed
+	}
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	history := quotes.OpenHistory(w.GetDirPath())
+	valuation := w.ValuateAt(date, history)
+
+	if len(valuation.Assets) == 0 && len(valuation.Missing) == 0 {
+		fmt.Printf("Nenhum ativo em carteira em %s.\n", date.Format("02/01/2006"))
+		return nil
+	}
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Carteira em %s", date.Format("02/01/2006"))))
+	fmt.Println()
+
+	tickers := make([]string, 0, len(valuation.Assets))
+	for ticker := range valuation.Assets {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+
+	fmt.Printf("%-8s %8s %12s %12s  %-10s %14s %14s %16s\n",
+		"TICKER", "QTD", "PM", "FECHAMENTO", "PREGÃO", "CUSTO", "MERCADO", "RESULTADO")
+	for _, ticker := range tickers {
+		v := valuation.Assets[ticker]
+		closing, _, _ := history.LastClose(ticker, date)
+		fmt.Printf("%-8s %8d %12s %12s  %-10s %14s %14s %16s\n",
+			ticker, v.Quantity, v.AveragePrice.StringFixed(4),
+			v.Price.StringFixed(2), closing.Date.Format("02/01/2006"),
+			v.CostBasis.StringFixed(2), v.MarketValue.StringFixed(2), formatSignedPercent(v.UnrealizedGain, v.UnrealizedRatio))
+	}
+
+	gain := valuation.UnrealizedGain()
+	ratio := decimal.Zero
+	if valuation.CostBasis.IsPositive() {
+		ratio = gain.Div(valuation.CostBasis)
+	}
+	fmt.Println()
+	fmt.Printf("Custo: R$ %s • Valor de mercado: R$ %s • Resultado: %s\n",
+		valuation.CostBasis.StringFixed(2), valuation.MarketValue.StringFixed(2), formatSignedPercent(gain, ratio))
+
+	if len(valuation.Missing) > 0 {
+		fmt.Printf("\n⚠ Sem fechamento até %s (fora dos totais): %s\n", date.Format("02/01/2006"), strings.Join(valuation.Missing, ", "))
+		fmt.Println("  Importe o período com 'b3cli quotes import <COTAHIST>'.")
+	}
+
+	return nil
+}
+
+// formatSignedPercent formata um resultado com sinal e percentual (ex: "+801.00 (+26.26%)")
+func formatSignedPercent(value, ratio decimal.Decimal) string {
+	sign := ""
+	if !value.IsNegative() {
+		sign = "+"
+	}
+	return fmt.Sprintf("%s%s (%s%s%%)", sign, value.StringFixed(2), sign, ratio.Mul(decimal.NewFromInt(100)).StringFixed(2))
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// chartSeries é uma linha do gráfico: um valor por data (NaN quando não há dado)
+type chartSeries struct {
+	name   string
+	color  lipgloss.Color
+	values []float64
+}
+
+var chartAxisStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+// renderLineChart desenha as séries em um gráfico de linhas no terminal
+// Os valores são percentuais; as colunas interpolam entre as datas
+func renderLineChart(dates []time.Time, series []chartSeries, width, height int) string {
+	if len(dates) < 2 || len(series) == 0 {
+		return ""
+	}
+
+	low, high := 0.0, 0.0
+	for _, s := range series {
+		for _, v := range s.values {
+			if !math.IsNaN(v) {
+				low, high = math.Min(low, v), math.Max(high, v)
+			}
+		}
+	}
+	if high-low < 1e-9 {
+		high = low + 1
+	}
+
+	grid := make([][]string, height)
+	for row := range grid {
+		grid[row] = make([]string, width)
+		for col := range grid[row] {
+			grid[row][col] = " "
+		}
+	}
+	rowOf := func(v float64) int {
+		return height - 1 - int(math.Round((v-low)/(high-low)*float64(height-1)))
+	}
+
+	// Linha do zero
+	zero := rowOf(0)
+	for col := 0; col < width; col++ {
+		grid[zero][col] = chartAxisStyle.Render("┄")
+	}
+
+	for _, s := range series {
+		style := lipgloss.NewStyle().Foreground(s.color)
+		for col := 0; col < width; col++ {
+			v, ok := interpolate(s.values, float64(col)*float64(len(dates)-1)/float64(width-1))
+			if !ok {
+				continue
+			}
+			grid[rowOf(v)][col] = style.Render("•")
+		}
+	}
+
+	var b strings.Builder
+	labelWidth := 9
+	for row := range grid {
+		label := ""
+		switch row {
+		case 0:
+			label = fmt.Sprintf("%+.1f%%", high)
+		case zero:
+			label = "0%"
+		case height - 1:
+			label = fmt.Sprintf("%+.1f%%", low)
+		}
+		b.WriteString(chartAxisStyle.Render(fmt.Sprintf("%*s ┤", labelWidth, label)))
+		b.WriteString(strings.Join(grid[row], ""))
+		b.WriteString("\n")
+	}
+
+	first, last := dates[0].Format("02/01/2006"), dates[len(dates)-1].Format("02/01/2006")
+	padding := width - len(first) - len(last)
+	if padding < 1 {
+		padding = 1
+	}
+	b.WriteString(chartAxisStyle.Render(strings.Repeat(" ", labelWidth+2) + first + strings.Repeat(" ", padding) + last))
+	b.WriteString("\n\n")
+
+	legend := make([]string, 0, len(series))
+	for _, s := range series {
+		legend = append(legend, lipgloss.NewStyle().Foreground(s.color).Render("• "+s.name))
+	}
+	b.WriteString(strings.Repeat(" ", labelWidth+2) + strings.Join(legend, "   "))
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// interpolate lê a série em uma posição fracionária (NaN nas pontas fica de fora)
+func interpolate(values []float64, pos float64) (float64, bool) {
+	i := int(math.Floor(pos))
+	if i >= len(values)-1 {
+		v := values[len(values)-1]
+		return v, !math.IsNaN(v) && i == len(values)-1
+	}
+	a, b := values[i], values[i+1]
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return 0, false
+	}
+	frac := pos - float64(i)
+	return a + (b-a)*frac, true
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/john/b3-project/internal/wallet/events"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Look for signs of corporate events missing from the wallet",
+	Long: `Scan the transactions and earnings of each asset for signs of a split or
+grouping that was not registered:
+- a price change by a near-integer factor between consecutive trades
+  (e.g., R$ 20.00 then R$ 10.10: probably a 1:2 split)
+- a sale of more shares than held
+- an earning paid on a multiple (or fraction) of the quantity held
+
+Registered events are already applied, so a suspicion disappears once the
+missing event is registered. When a suspicion suggests a ratio, you can open
+the split/grouping interface pre-filled with the ticker, ratio and date, review
+the preview and confirm.`,
+	Example: `  b3cli doctor
+  b3cli doctor --report`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().Bool("report", false, "Apenas exibe o relatório (não oferece abrir a TUI)")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	reportOnly, _ := cmd.Flags().GetBool("report")
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	found := events.DetectMissedEvents(w)
+	if len(found) == 0 {
+		fmt.Println("✓ No signs of missed splits or groupings.")
+		return nil
+	}
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Possible missed corporate events (%d)", len(found))))
+
+	// Suggestions are numbered so one can be opened in the TUI
+	suggestions := make([]events.Suspicion, 0)
+	ticker := ""
+	for _, s := range found {
+		if s.Ticker != ticker {
+			ticker = s.Ticker
+			fmt.Printf("\n[%s]\n", selectedItemStyle.Render(ticker))
+		}
+
+		fmt.Printf("  - %s: %s\n", s.Kind, s.Detail)
+		if s.HasSuggestion() {
+			suggestions = append(suggestions, s)
+			fmt.Printf("    #%d suggested: %s\n", len(suggestions), describeSuggestion(s))
+		}
+	}
+	fmt.Println()
+
+	if reportOnly || len(suggestions) == 0 {
+		return nil
+	}
+
+	answer, err := readLine(fmt.Sprintf("Open the interface for a suggestion? [1-%d, empty to skip]: ", len(suggestions)))
+	if err != nil || answer == "" {
+		return nil
+	}
+	index, err := strconv.Atoi(answer)
+	if err != nil || index < 1 || index > len(suggestions) {
+		return fmt.Errorf("invalid suggestion %q", answer)
+	}
+	s := suggestions[index-1]
+
+	var model tea.Model
+	if s.Ratio.IsSplit() {
+		model = newSplitModel(w, w.GetDirPath()).withSuggestion(s.Ticker, s.Ratio, s.Date)
+	} else {
+		model = newGroupingModel(w, w.GetDirPath()).withSuggestion(s.Ticker, s.Ratio, s.Date)
+	}
+
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// describeSuggestion formats the suggested event (e.g., "split 1:2 from 2024-03-10")
+func describeSuggestion(s events.Suspicion) string {
+	kind := "grouping"
+	if s.Ratio.IsSplit() {
+		kind = "split"
+	}
+	return fmt.Sprintf("%s %s from %s", kind, s.Ratio, s.Date.Format("2006-01-02"))
+}
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/john/b3-project/internal/analytics"
 	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/quotes"
 	"github.com/john/b3-project/internal/wallet"
 	"github.com/shopspring/decimal"
 	"github.com/spf13/cobra"
@@ -14,14 +17,19 @@ import (
 
 var earningsCmd = &cobra.Command{
 	Use:   "earnings",
-	Short: "Gerencia proventos (rendimentos, dividendos, JCP, resgates)",
+	Short: "Gerencia proventos (rendimentos, dividendos, JCP, resgates, amortizações)",
 	Long: `Gerencia proventos e resgates recebidos de ações e fundos imobiliários.
 
 Tipos de proventos suportados:
 - Rendimento
 - Dividendo
 - Juros Sobre Capital Próprio (JCP)
-- Resgate (fechamento de capital/retirada de circulação)`,
+- Resgate (fechamento de capital/retirada de circulação)
+- Amortização (devolução de capital, comum em FIIs)
+
+Resgates e amortizações são devolução de capital: reduzem o valor investido e o
+preço médio do ativo, não entram no total de proventos nem no yield e aparecem
+separados nos relatórios.`,
 }
 
 var earningsParseCmd = &cobra.Command{
@@ -32,7 +40,7 @@ var earningsParseCmd = &cobra.Command{
 Os arquivos devem estar no formato esperado com as seguintes colunas:
 - Entrada/Saída (ignorado)
 - Data (DD/MM/YYYY)
-- Movimentação (tipo: Rendimento/Dividendo/Juros Sobre Capital Próprio/Resgate)
+- Movimentação (tipo: Rendimento/Dividendo/Juros Sobre Capital Próprio/Resgate/Amortização)
 - Produto (formato: TICKER - Nome da empresa)
 - Instituição (ignorado)
 - Quantidade
@@ -44,6 +52,7 @@ Tipos de movimentação aceitos:
 - Dividendo: distribuição de lucros
 - JCP / Juros Sobre Capital Próprio: distribuição com benefício fiscal
 - Resgate: fechamento de capital ou retirada de circulação
+- Amortização: devolução de parte do capital (reduz o custo do ativo)
 
 O comando automaticamente deduplica proventos, atualiza a carteira atual
 e calcula o total de proventos recebidos para cada ativo.
@@ -106,6 +115,13 @@ func runEarningsParse(cmd *cobra.Command, args []string) error {
 
 	displayEarningsSummary(w)
 
+	if checks := w.CheckEarnings(newEarnings); len(checks) > 0 {
+		fmt.Printf("\n=== PROVENTOS A VERIFICAR (%d) ===\n", len(checks))
+		fmt.Println("Quantidade do provento diferente da posição na data-com:")
+		fmt.Println()
+		printEarningChecks(checks)
+	}
+
 	return nil
 }
 
@@ -134,19 +150,33 @@ func displayEarningsSummary(w *wallet.Wallet) {
 	}
 
 	for ticker, asset := range w.Assets {
-		if len(asset.Earnings) == 0 {
+		if len(asset.Earnings) == 0 && len(asset.InheritedEarnings) == 0 {
 			continue
 		}
 
 		fmt.Printf("\n[%s]\n", ticker)
 		fmt.Printf("  Total de proventos recebidos: %d\n", len(asset.Earnings))
 		fmt.Printf("  Valor total recebido: R$ %s\n", asset.TotalEarnings.StringFixed(2))
+		if asset.TotalCapitalReturned.IsPositive() {
+			fmt.Printf("  Capital devolvido: R$ %s\n", asset.TotalCapitalReturned.StringFixed(2))
+		}
+		if asset.SucceededBy != "" {
+			fmt.Printf("  Sucedido por: %s\n", asset.SucceededBy)
+		}
+		if len(asset.InheritedEarnings) > 0 {
+			inherited := decimal.Zero
+			for _, e := range asset.InheritedEarnings {
+				inherited = inherited.Add(e.Net())
+			}
+			fmt.Printf("  Histórico herdado: %d proventos (R$ %s)\n", len(asset.InheritedEarnings), inherited.StringFixed(2))
+		}
 
 		// Breakdown por tipo
 		rendimentos := 0
 		dividendos := 0
 		jcp := 0
 		resgates := 0
+		amortizacoes := 0
 
 		for _, e := range asset.Earnings {
 			switch e.Type {
@@ -158,6 +188,8 @@ func displayEarningsSummary(w *wallet.Wallet) {
 				jcp++
 			case "Resgate":
 				resgates++
+			case "Amortização":
+				amortizacoes++
 			}
 		}
 
@@ -173,6 +205,9 @@ func displayEarningsSummary(w *wallet.Wallet) {
 		if resgates > 0 {
 			fmt.Printf("    - Resgates: %d\n", resgates)
 		}
+		if amortizacoes > 0 {
+			fmt.Printf("    - Amortizações: %d\n", amortizacoes)
+		}
 	}
 }
 
@@ -189,26 +224,34 @@ func displayEarningsOverview(w *wallet.Wallet) {
 		"Dividendo":                   {Count: 0, TotalAmount: decimal.Zero, Assets: make(map[string]decimal.Decimal)},
 		"Juros Sobre Capital Próprio": {Count: 0, TotalAmount: decimal.Zero, Assets: make(map[string]decimal.Decimal)},
 		"Resgate":                     {Count: 0, TotalAmount: decimal.Zero, Assets: make(map[string]decimal.Decimal)},
+		"Amortização":                 {Count: 0, TotalAmount: decimal.Zero, Assets: make(map[string]decimal.Decimal)},
 	}
 
+	// Devoluções de capital (resgate, amortização) ficam fora do total de proventos
 	totalGeneral := decimal.Zero
+	totalReturned := decimal.Zero
 	totalCount := 0
 
 	// Agrupar earnings por tipo
 	for ticker, asset := range w.Assets {
 		for _, earning := range asset.Earnings {
 			if cat, exists := categories[earning.Type]; exists {
+				received := earning.Net()
 				cat.Count++
-				cat.TotalAmount = cat.TotalAmount.Add(earning.TotalAmount)
+				cat.TotalAmount = cat.TotalAmount.Add(received)
 
 				// Adicionar ao total do ativo
 				if current, ok := cat.Assets[ticker]; ok {
-					cat.Assets[ticker] = current.Add(earning.TotalAmount)
+					cat.Assets[ticker] = current.Add(received)
 				} else {
-					cat.Assets[ticker] = earning.TotalAmount
+					cat.Assets[ticker] = received
 				}
 
-				totalGeneral = totalGeneral.Add(earning.TotalAmount)
+				if parser.IsCapitalReturn(earning.Type) {
+					totalReturned = totalReturned.Add(received)
+					continue
+				}
+				totalGeneral = totalGeneral.Add(received)
 				totalCount++
 			}
 		}
@@ -217,15 +260,20 @@ func displayEarningsOverview(w *wallet.Wallet) {
 	// Exibir resumo
 	fmt.Println("=== RESUMO GERAL DE PROVENTOS ===")
 	fmt.Printf("Total de pagamentos recebidos: %d\n", totalCount)
-	fmt.Printf("Valor total recebido: R$ %s\n\n", totalGeneral.StringFixed(2))
+	fmt.Printf("Valor total recebido: R$ %s\n", totalGeneral.StringFixed(2))
+	if totalReturned.IsPositive() {
+		fmt.Printf("Capital devolvido (resgates/amortizações): R$ %s\n", totalReturned.StringFixed(2))
+	}
+	fmt.Println()
 
 	// Exibir por categoria
-	types := []string{"Rendimento", "Dividendo", "Juros Sobre Capital Próprio", "Resgate"}
+	types := []string{"Rendimento", "Dividendo", "Juros Sobre Capital Próprio", "Resgate", "Amortização"}
 	typeLabels := map[string]string{
 		"Rendimento":                  "RENDIMENTOS",
 		"Dividendo":                   "DIVIDENDOS",
 		"Juros Sobre Capital Próprio": "JUROS SOBRE CAPITAL PRÓPRIO (JCP)",
-		"Resgate":                     "RESGATES",
+		"Resgate":                     "RESGATES (DEVOLUÇÃO DE CAPITAL)",
+		"Amortização":                 "AMORTIZAÇÕES (DEVOLUÇÃO DE CAPITAL)",
 	}
 
 	for _, earningType := range types {
@@ -238,8 +286,8 @@ func displayEarningsOverview(w *wallet.Wallet) {
 		fmt.Printf("Quantidade de pagamentos: %d\n", cat.Count)
 		fmt.Printf("Valor total: R$ %s\n", cat.TotalAmount.StringFixed(2))
 
-		// Calcular percentual do total
-		if !totalGeneral.IsZero() {
+		// Calcular percentual do total (só para proventos de renda)
+		if !totalGeneral.IsZero() && !parser.IsCapitalReturn(earningType) {
 			percentage := cat.TotalAmount.Div(totalGeneral).Mul(decimal.NewFromInt(100))
 			fmt.Printf("Percentual do total: %.2f%%\n", percentage.InexactFloat64())
 		}
@@ -280,11 +328,14 @@ var earningsOverviewCmd = &cobra.Command{
 	Short: "Exibe resumo de proventos agrupados por tipo",
 	Long: `Exibe um resumo completo de todos os proventos recebidos, agrupados por tipo.
 
-Mostra para cada categoria (Rendimento, Dividendo, JCP, Resgate):
+Mostra para cada categoria (Rendimento, Dividendo, JCP, Resgate, Amortização):
 - Quantidade total de pagamentos
 - Valor total recebido
 - Lista de ativos que pagaram este tipo de provento
 
+Resgates e amortizações aparecem à parte como capital devolvido e não entram
+no total nem nos percentuais.
+
 Útil para entender a composição dos seus ganhos passivos.`,
 	Example: `  b3cli earnings overview`,
 	Args:    cobra.NoArgs,
@@ -354,6 +405,68 @@ func runEarningsReports(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var earningsYieldCmd = &cobra.Command{
+	Use:   "yield",
+	Short: "Ranking de yield on cost e dividend yield dos ativos",
+	Long: `Exibe, em uma interface interativa, os ativos em carteira ordenados pela
+renda que geram:
+
+- YoC 12m (yield on cost): proventos por ação dos últimos 12 meses ÷ preço
+  médio. Mostra quanto rende o que você pagou.
+- DY 12m (dividend yield): proventos por ação dos últimos 12 meses ÷ cotação
+  atual (cotações em cache, veja 'b3cli quotes').
+- YoC por ano: proventos por ação do ano ÷ preço médio atual.
+
+Os proventos por ação consideram a quantidade em carteira em cada pagamento,
+já ajustada por desdobramentos e bonificações. Amortizações e resgates não
+entram (são devolução de capital).
+
+Navegação:
+- ↑/↓: navegar
+- s ou Tab: alternar a ordenação (YoC, DY, renda)
+- ←/→: ano exibido na última coluna
+- q ou Esc: sair`,
+	Example: `  b3cli earnings yield
+  b3cli earnings yield --refresh`,
+	Args: cobra.NoArgs,
+	RunE: runEarningsYield,
+}
+
+func runEarningsYield(cmd *cobra.Command, args []string) error {
+	refresh, _ := cmd.Flags().GetBool("refresh")
+
+	// Get or load wallet (will prompt for password if locked)
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	if len(w.GetActiveAssets()) == 0 {
+		fmt.Println("Nenhum ativo ativo encontrado na carteira.")
+		return nil
+	}
+
+	var cache *quotes.Cache
+	if refresh {
+		cache, err = refreshQuotes(w, "", false, false)
+	} else {
+		cache, err = quotes.LoadCache(w.GetDirPath())
+	}
+	if err != nil {
+		return err
+	}
+
+	report := analytics.Yields(w, cache.Prices(), time.Now())
+
+	// Iniciar interface Bubble Tea
+	p := tea.NewProgram(newYieldModel(report), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("erro ao executar interface: %w", err)
+	}
+
+	return nil
+}
+
 var earningsAddCmd = &cobra.Command{
 	Use:   "add",
 	Short: "Adiciona um provento manualmente",
@@ -362,7 +475,7 @@ var earningsAddCmd = &cobra.Command{
 Permite selecionar um ativo da carteira e registrar:
 - Quantidade de cotas/ações
 - Valor líquido total recebido
-- Tipo de provento (Dividendo/JCP/Rendimento)
+- Tipo de provento (Dividendo/JCP/Rendimento/Amortização)
 - Data do pagamento (opcional, padrão hoje)
 
 O preço unitário é calculado automaticamente (total / quantidade).
@@ -407,19 +520,32 @@ Mostra para cada provento:
 - Quantidade de ações/cotas
 - Data do pagamento
 
-Os proventos são ordenados por data, do mais recente ao mais antigo.`,
-	Example: `  b3cli earnings list`,
-	Args:    cobra.NoArgs,
-	RunE:    runEarningsList,
+Os proventos são ordenados por data, do mais recente ao mais antigo.
+
+Com --check, verifica todos os proventos registrados: a quantidade de cada
+provento é comparada com a posição da carteira na data-com (a de um provento
+anunciado ou, sem anúncio, qualquer dia de uma janela antes do pagamento: 45 dias
+para rendimentos, 180 para os demais). Divergências costumam indicar negociações
+não importadas, desdobramentos não registrados ou ticker errado.`,
+	Example: `  b3cli earnings list
+  b3cli earnings list --check`,
+	Args: cobra.NoArgs,
+	RunE: runEarningsList,
 }
 
 func runEarningsList(cmd *cobra.Command, args []string) error {
+	check, _ := cmd.Flags().GetBool("check")
+
 	// Get or load wallet (will prompt for password if locked)
 	w, err := getOrLoadWallet()
 	if err != nil {
 		return err
 	}
 
+	if check {
+		return runEarningsCheck(w)
+	}
+
 	// Verificar se há proventos
 	totalEarnings := countTotalEarnings(w)
 	if totalEarnings == 0 {
@@ -472,11 +598,34 @@ func runEarningsList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runEarningsCheck lista os proventos com quantidade diferente da posição na data-com
+func runEarningsCheck(w *wallet.Wallet) error {
+	if countTotalEarnings(w) == 0 {
+		fmt.Println("Nenhum provento registrado ainda.")
+		return nil
+	}
+
+	checks := w.CheckAllEarnings()
+	if len(checks) == 0 {
+		fmt.Printf("✓ As quantidades dos %d proventos conferem com as posições na data-com.\n", countTotalEarnings(w))
+		return nil
+	}
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Proventos a verificar (%d de %d)", len(checks), countTotalEarnings(w))))
+	fmt.Println()
+	printEarningChecks(checks)
+	return nil
+}
+
 func init() {
+	earningsYieldCmd.Flags().Bool("refresh", false, "Atualiza as cotações antes de exibir")
+	earningsListCmd.Flags().Bool("check", false, "Verifica as quantidades dos proventos contra as posições na data-com")
+
 	// Adicionar subcomandos ao earnings
 	earningsCmd.AddCommand(earningsParseCmd)
 	earningsCmd.AddCommand(earningsOverviewCmd)
 	earningsCmd.AddCommand(earningsReportsCmd)
+	earningsCmd.AddCommand(earningsYieldCmd)
 	earningsCmd.AddCommand(earningsAddCmd)
 	earningsCmd.AddCommand(earningsListCmd)
 }
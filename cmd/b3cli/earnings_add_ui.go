@@ -119,6 +119,7 @@ func newAddEarningModel(w *wallet.Wallet, walletPath string) addEarningModel {
 		"Dividendo",
 		"Juros Sobre Capital Próprio",
 		"Rendimento",
+		"Amortização",
 	}
 
 	return addEarningModel{
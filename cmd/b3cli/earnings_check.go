@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+// describeEarningCheck explica a divergência e a causa mais provável
+func describeEarningCheck(c wallet.EarningCheck) string {
+	switch c.Issue {
+	case wallet.IssueNotHeld:
+		return "nenhuma posição na data-com: ticker errado ou compras não importadas?"
+	case wallet.IssueRatio:
+		if c.Factor.GreaterThan(decimal.NewFromInt(1)) {
+			return fmt.Sprintf("%sx a posição: desdobramento 1:%s não registrado?", c.Factor, c.Factor)
+		}
+		n := decimal.NewFromInt(1).Div(c.Factor).Round(0)
+		return fmt.Sprintf("1/%s da posição: grupamento %s:1 não registrado?", n, n)
+	case wallet.IssueMissingBuys:
+		return fmt.Sprintf("%s papéis a mais que a carteira: compras não importadas?", c.Difference().StringFixed(0))
+	default:
+		return fmt.Sprintf("%s papéis a menos que a carteira: vendas não importadas?", c.Difference().Neg().StringFixed(0))
+	}
+}
+
+// recordDateLabel formata a data-com comparada ("~" quando estimada pela janela)
+func recordDateLabel(c wallet.EarningCheck) string {
+	if c.Announced {
+		return c.RecordDate.Format("02/01/2006")
+	}
+	return "~" + c.RecordDate.Format("02/01/2006")
+}
+
+// printEarningChecks lista as divergências entre proventos e posições na data-com
+func printEarningChecks(checks []wallet.EarningCheck) {
+	fmt.Printf("%-10s %-8s %-28s %10s %10s %11s  %s\n",
+		"PAGAMENTO", "TICKER", "TIPO", "QTD PROV.", "CARTEIRA", "DATA-COM", "DIAGNÓSTICO")
+	for _, c := range checks {
+		fmt.Printf("%-10s %-8s %-28s %10s %10s %11s  %s\n",
+			c.Earning.Date.Format("02/01/2006"), c.Ticker, truncate(c.Earning.Type, 28),
+			c.Earning.Quantity.StringFixed(0), c.Held.StringFixed(0), recordDateLabel(c), describeEarningCheck(c))
+	}
+	fmt.Println()
+	fmt.Println("Datas-com com ~ são estimadas: o provento é aceito se bater com a posição de qualquer dia")
+	fmt.Println("da janela antes do pagamento. Registre o anúncio ('b3cli earnings announce add') para")
+	fmt.Println("comparar com a data-com exata; use 'b3cli doctor' para eventos corporativos.")
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/john/b3-project/internal/analytics"
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/john/b3-project/internal/wallet/events"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var earningsProjectionCmd = &cobra.Command{
+	Use:   "projection",
+	Short: "Projeta a renda passiva dos próximos 12 meses",
+	Long: `Projeta os proventos dos próximos 12 meses com a quantidade atual de cada
+ativo, a partir do padrão de pagamentos dos últimos 24 meses:
+
+- Mensal (a maioria dos FIIs): repete a média por cota dos últimos 3 meses
+  com pagamento
+- Trimestral, semestral, anual ou irregular: repete, por ação, o que foi
+  pago no mesmo mês do ano anterior
+
+Proventos anunciados e ainda não recebidos (veja 'b3cli earnings announce')
+substituem a projeção do ativo no mês do pagamento.`,
+	Example: `  b3cli earnings projection`,
+	Args:    cobra.NoArgs,
+	RunE:    runEarningsProjection,
+}
+
+var earningsCalendarCmd = &cobra.Command{
+	Use:   "calendar",
+	Short: "Calendário de proventos: esperado x recebido por mês",
+	Long: `Exibe, mês a mês, os proventos esperados e os recebidos: os últimos meses,
+o mês atual e os próximos 12 meses.
+
+O esperado de um mês passado é a projeção feita com o histórico até o fim do
+mês anterior, como seria vista naquela data (veja 'b3cli earnings projection').
+Proventos anunciados e ainda não recebidos aparecem como pendentes.`,
+	Example: `  b3cli earnings calendar
+  b3cli earnings calendar --past 12`,
+	Args: cobra.NoArgs,
+	RunE: runEarningsCalendar,
+}
+
+var earningsAnnounceCmd = &cobra.Command{
+	Use:   "announce",
+	Short: "Registra proventos anunciados (pendentes)",
+	Long: `Registra proventos anunciados pelas empresas antes do pagamento, com data-com
+e data de pagamento. Eles aparecem como pendentes no calendário e na projeção
+até que o provento seja importado (mesmo ativo e tipo, com data até 7 dias
+da data de pagamento anunciada).
+
+O valor esperado é a quantidade em carteira ao final da data-com × valor por
+papel (ou a quantidade atual, se a data-com ainda não passou).`,
+	Example: `  b3cli earnings announce add ITSA4 --type jcp --record-date 2024-08-15 --payment-date 2024-09-20 --value 0.0217
+  b3cli earnings announce list
+  b3cli earnings announce remove 3f2a9c`,
+}
+
+var earningsAnnounceAddCmd = &cobra.Command{
+	Use:   "add <ticker>",
+	Short: "Registra um provento anunciado",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEarningsAnnounceAdd,
+}
+
+var earningsAnnounceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lista os proventos anunciados",
+	Args:  cobra.NoArgs,
+	RunE:  runEarningsAnnounceList,
+}
+
+var earningsAnnounceRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove um provento anunciado (pelo ID ou prefixo)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEarningsAnnounceRemove,
+}
+
+var (
+	calendarExpectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	calendarReceivedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	calendarCurrentStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+)
+
+// calendarBarWidth é a largura da barra do maior valor do calendário
+const calendarBarWidth = 30
+
+// shortMonthNames são os meses abreviados usados no calendário
+var shortMonthNames = []string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"}
+
+// patternNames traduz os padrões de pagamento
+var patternNames = map[analytics.PaymentPattern]string{
+	analytics.PatternMonthly:    "mensal",
+	analytics.PatternQuarterly:  "trimestral",
+	analytics.PatternSemiannual: "semestral",
+	analytics.PatternAnnual:     "anual",
+	analytics.PatternIrregular:  "irregular",
+	analytics.PatternNone:       "sem histórico",
+}
+
+func init() {
+	earningsCalendarCmd.Flags().Int("past", 6, "Quantidade de meses anteriores exibidos")
+
+	earningsAnnounceAddCmd.Flags().String("type", "dividendo", "Tipo: dividendo, jcp ou rendimento")
+	earningsAnnounceAddCmd.Flags().String("record-date", "", "Data-com (YYYY-MM-DD)")
+	earningsAnnounceAddCmd.Flags().String("payment-date", "", "Data de pagamento (YYYY-MM-DD)")
+	earningsAnnounceAddCmd.Flags().String("value", "", "Valor anunciado por ação/cota (R$)")
+	earningsAnnounceAddCmd.Flags().String("notes", "", "Observação livre")
+	earningsAnnounceListCmd.Flags().Bool("all", false, "Inclui os anúncios já recebidos")
+
+	earningsAnnounceCmd.AddCommand(earningsAnnounceAddCmd)
+	earningsAnnounceCmd.AddCommand(earningsAnnounceListCmd)
+	earningsAnnounceCmd.AddCommand(earningsAnnounceRemoveCmd)
+
+	earningsCmd.AddCommand(earningsProjectionCmd)
+	earningsCmd.AddCommand(earningsCalendarCmd)
+	earningsCmd.AddCommand(earningsAnnounceCmd)
+}
+
+func runEarningsProjection(cmd *cobra.Command, args []string) error {
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	projection := analytics.ProjectIncome(w, time.Now())
+	if len(projection.Assets) == 0 {
+		fmt.Println("Nenhum ativo em carteira com histórico de proventos.")
+		return nil
+	}
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Renda passiva projetada: %s a %s",
+		monthLabel(projection.Months[0].Month), monthLabel(projection.Months[len(projection.Months)-1].Month))))
+	fmt.Println()
+
+	fmt.Printf("%-8s %-14s %8s %12s %14s %12s\n", "TICKER", "PADRÃO", "QTD", "PROV/AÇÃO", "PRÓX. 12M", "MÉDIA/MÊS")
+	for _, a := range projection.Assets {
+		fmt.Printf("%-8s %-14s %8s %12s %14s %12s\n",
+			a.Ticker, patternNames[a.Pattern], a.Quantity.StringFixed(0), a.PerShare12.StringFixed(4),
+			a.Total.StringFixed(2), a.Total.Div(decimal.NewFromInt(12)).StringFixed(2))
+	}
+
+	fmt.Println()
+	fmt.Println(selectedItemStyle.Render("Por mês"))
+	for _, m := range projection.Months {
+		line := fmt.Sprintf("  %-9s R$ %10s", monthLabel(m.Month), m.Expected.StringFixed(2))
+		if m.Announced.IsPositive() {
+			line += fmt.Sprintf("  (R$ %s anunciado)", m.Announced.StringFixed(2))
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Println()
+	fmt.Printf("Total em 12 meses: R$ %s • Média mensal: R$ %s\n",
+		projection.Total.StringFixed(2), projection.Total.Div(decimal.NewFromInt(12)).StringFixed(2))
+	fmt.Println("A projeção repete o histórico com a quantidade atual; não é garantia de pagamento.")
+
+	return nil
+}
+
+func runEarningsCalendar(cmd *cobra.Command, args []string) error {
+	past, _ := cmd.Flags().GetInt("past")
+	if past < 0 {
+		return fmt.Errorf("--past deve ser zero ou positivo")
+	}
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	today := time.Now()
+	calendar := analytics.IncomeCalendar(w, today, past, 12)
+
+	largest := decimal.Zero
+	for _, m := range calendar {
+		largest = decimal.Max(largest, m.Expected, m.Received)
+	}
+
+	fmt.Println(titleStyle.Render("Calendário de proventos"))
+	fmt.Println()
+	fmt.Printf("%-9s %12s %12s %12s\n", "MÊS", "ESPERADO", "RECEBIDO", "DIFERENÇA")
+
+	current := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for _, m := range calendar {
+		label := fmt.Sprintf("%-9s", monthLabel(m.Month))
+		received, diff := m.Received.StringFixed(2), signed(m.Received.Sub(m.Expected))
+		if m.Month.After(current) && m.Received.IsZero() {
+			received, diff = "—", "—"
+		}
+		if m.Month.Equal(current) {
+			label = calendarCurrentStyle.Render(label)
+		}
+
+		line := fmt.Sprintf("%s %12s %12s %12s  %s", label, m.Expected.StringFixed(2), received, diff,
+			calendarBar(m.Expected, m.Received, largest))
+		if m.Announced.IsPositive() {
+			line += fmt.Sprintf("  R$ %s pendente", m.Announced.StringFixed(2))
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Println()
+	fmt.Println(calendarReceivedStyle.Render("█ recebido") + "  " + calendarExpectedStyle.Render("░ esperado"))
+
+	pending := w.PendingAnnouncedEarnings()
+	if len(pending) > 0 {
+		fmt.Println()
+		fmt.Println(selectedItemStyle.Render("Anunciados pendentes"))
+		printAnnouncedEarnings(w, pending, today)
+	}
+
+	return nil
+}
+
+// calendarBar desenha o recebido (█) sobre o esperado (░), na escala do maior valor
+func calendarBar(expected, received, largest decimal.Decimal) string {
+	if !largest.IsPositive() {
+		return ""
+	}
+	width := func(value decimal.Decimal) int {
+		return int(value.Div(largest).Mul(decimal.NewFromInt(calendarBarWidth)).IntPart())
+	}
+	filled, outline := width(received), width(expected)
+	bar := calendarReceivedStyle.Render(strings.Repeat("█", filled))
+	if outline > filled {
+		bar += calendarExpectedStyle.Render(strings.Repeat("░", outline-filled))
+	}
+	return bar
+}
+
+// monthLabel formata um mês como "set/2024"
+func monthLabel(month time.Time) string {
+	return fmt.Sprintf("%s/%d", shortMonthNames[month.Month()-1], month.Year())
+}
+
+func runEarningsAnnounceAdd(cmd *cobra.Command, args []string) error {
+	typeStr, _ := cmd.Flags().GetString("type")
+	recordStr, _ := cmd.Flags().GetString("record-date")
+	paymentStr, _ := cmd.Flags().GetString("payment-date")
+	valueStr, _ := cmd.Flags().GetString("value")
+	notes, _ := cmd.Flags().GetString("notes")
+
+	if recordStr == "" || paymentStr == "" || valueStr == "" {
+		return fmt.Errorf("--record-date, --payment-date e --value são obrigatórios")
+	}
+	recordDate, err := time.Parse("2006-01-02", recordStr)
+	if err != nil {
+		return fmt.Errorf("data-com inválida: %s (use YYYY-MM-DD)", recordStr)
+	}
+	paymentDate, err := time.Parse("2006-01-02", paymentStr)
+	if err != nil {
+		return fmt.Errorf("data de pagamento inválida: %s (use YYYY-MM-DD)", paymentStr)
+	}
+	value, err := events.ParseAmount(valueStr)
+	if err != nil {
+		return fmt.Errorf("valor inválido: %w", err)
+	}
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	ticker := strings.ToUpper(args[0])
+	if _, exists := w.Assets[ticker]; !exists {
+		return fmt.Errorf("ativo %s não encontrado na carteira", ticker)
+	}
+
+	announced, err := w.AddAnnouncedEarning(wallet.AnnouncedEarning{
+		Ticker:      ticker,
+		Type:        parser.NormalizeEarningType(typeStr),
+		RecordDate:  recordDate,
+		PaymentDate: paymentDate,
+		UnitPrice:   value,
+		Notes:       notes,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := w.Save(w.GetDirPath()); err != nil {
+		return fmt.Errorf("erro ao salvar carteira: %w", err)
+	}
+
+	quantity, amount := w.ExpectedAmount(*announced, time.Now())
+	fmt.Printf("✓ Provento anunciado registrado (%s)\n", announced.ID)
+	fmt.Printf("  %s %s: R$ %s × %s = R$ %s em %s\n", ticker, announced.Type, value.StringFixed(4),
+		quantity.StringFixed(0), amount.StringFixed(2), paymentDate.Format("02/01/2006"))
+	return nil
+}
+
+func runEarningsAnnounceList(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	list := w.PendingAnnouncedEarnings()
+	if all {
+		list = w.SortedAnnouncedEarnings()
+	}
+	if len(list) == 0 {
+		fmt.Println("Nenhum provento anunciado pendente.")
+		fmt.Println("\nUse 'b3cli earnings announce add' para registrar um anúncio.")
+		return nil
+	}
+
+	printAnnouncedEarnings(w, list, time.Now())
+	return nil
+}
+
+// printAnnouncedEarnings lista anúncios com o valor esperado e a situação
+func printAnnouncedEarnings(w *wallet.Wallet, list []wallet.AnnouncedEarning, today time.Time) {
+	fmt.Printf("%-12s %-8s %-28s %10s %10s %10s %10s  %s\n",
+		"ID", "TICKER", "TIPO", "DATA-COM", "PAGAMENTO", "R$/PAPEL", "ESPERADO", "SITUAÇÃO")
+	for _, a := range list {
+		_, amount := w.ExpectedAmount(a, today)
+		status := "pendente"
+		if w.IsReceived(a) {
+			status = "recebido"
+		}
+		fmt.Printf("%-12s %-8s %-28s %10s %10s %10s %10s  %s\n",
+			a.ID, a.Ticker, truncate(a.Type, 28), a.RecordDate.Format("02/01/2006"), a.PaymentDate.Format("02/01/2006"),
+			a.UnitPrice.StringFixed(4), amount.StringFixed(2), status)
+	}
+}
+
+func runEarningsAnnounceRemove(cmd *cobra.Command, args []string) error {
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	removed, err := w.RemoveAnnouncedEarning(args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := w.Save(w.GetDirPath()); err != nil {
+		return fmt.Errorf("erro ao salvar carteira: %w", err)
+	}
+
+	fmt.Printf("✓ Anúncio %s removido (%s %s, pagamento em %s)\n",
+		removed.ID, removed.Ticker, removed.Type, removed.PaymentDate.Format("02/01/2006"))
+	return nil
+}
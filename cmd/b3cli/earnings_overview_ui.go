@@ -6,6 +6,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/john/b3-project/internal/parser"
 	"github.com/john/b3-project/internal/wallet"
 	"github.com/shopspring/decimal"
 )
@@ -15,6 +16,7 @@ type overviewModel struct {
 	categories map[string]*EarningsByType
 	types      []string
 	total      decimal.Decimal
+	returned   decimal.Decimal // Resgates e amortizações (fora de total)
 	totalCount int
 }
 
@@ -59,37 +61,45 @@ func initialOverviewModel(w *wallet.Wallet) overviewModel {
 		"Dividendo":                   {Count: 0, TotalAmount: decimal.Zero, Assets: make(map[string]decimal.Decimal)},
 		"Juros Sobre Capital Próprio": {Count: 0, TotalAmount: decimal.Zero, Assets: make(map[string]decimal.Decimal)},
 		"Resgate":                     {Count: 0, TotalAmount: decimal.Zero, Assets: make(map[string]decimal.Decimal)},
+		"Amortização":                 {Count: 0, TotalAmount: decimal.Zero, Assets: make(map[string]decimal.Decimal)},
 	}
 
 	totalGeneral := decimal.Zero
+	totalReturned := decimal.Zero
 	totalCount := 0
 
 	// Agrupar earnings por tipo
 	for ticker, asset := range w.Assets {
 		for _, earning := range asset.Earnings {
 			if cat, exists := categories[earning.Type]; exists {
+				received := earning.Net()
 				cat.Count++
-				cat.TotalAmount = cat.TotalAmount.Add(earning.TotalAmount)
+				cat.TotalAmount = cat.TotalAmount.Add(received)
 
 				if current, ok := cat.Assets[ticker]; ok {
-					cat.Assets[ticker] = current.Add(earning.TotalAmount)
+					cat.Assets[ticker] = current.Add(received)
 				} else {
-					cat.Assets[ticker] = earning.TotalAmount
+					cat.Assets[ticker] = received
 				}
 
-				totalGeneral = totalGeneral.Add(earning.TotalAmount)
+				if parser.IsCapitalReturn(earning.Type) {
+					totalReturned = totalReturned.Add(received)
+					continue
+				}
+				totalGeneral = totalGeneral.Add(received)
 				totalCount++
 			}
 		}
 	}
 
-	types := []string{"Rendimento", "Dividendo", "Juros Sobre Capital Próprio", "Resgate"}
+	types := []string{"Rendimento", "Dividendo", "Juros Sobre Capital Próprio", "Resgate", "Amortização"}
 
 	return overviewModel{
 		wallet:     w,
 		categories: categories,
 		types:      types,
 		total:      totalGeneral,
+		returned:   totalReturned,
 		totalCount: totalCount,
 	}
 }
@@ -126,11 +136,18 @@ func (m overviewModel) View() string {
 	b.WriteString(overviewValueStyle.Render(fmt.Sprintf("R$ %s", m.total.StringFixed(2))))
 	b.WriteString("\n")
 
+	if m.returned.IsPositive() {
+		b.WriteString(overviewLabelStyle.Render("Capital devolvido (resgates/amortizações): "))
+		b.WriteString(overviewValueStyle.Render(fmt.Sprintf("R$ %s", m.returned.StringFixed(2))))
+		b.WriteString("\n")
+	}
+
 	typeLabels := map[string]string{
 		"Rendimento":                  "📊 RENDIMENTOS",
 		"Dividendo":                   "💵 DIVIDENDOS",
 		"Juros Sobre Capital Próprio": "🏦 JUROS SOBRE CAPITAL PRÓPRIO (JCP)",
-		"Resgate":                     "🔄 RESGATES",
+		"Resgate":                     "🔄 RESGATES (DEVOLUÇÃO DE CAPITAL)",
+		"Amortização":                 "↩️  AMORTIZAÇÕES (DEVOLUÇÃO DE CAPITAL)",
 	}
 
 	// Exibir por categoria
@@ -155,8 +172,8 @@ func (m overviewModel) View() string {
 		b.WriteString(overviewValueStyle.Render(fmt.Sprintf("R$ %s", cat.TotalAmount.StringFixed(2))))
 		b.WriteString("\n")
 
-		// Percentual
-		if !m.total.IsZero() {
+		// Percentual (devoluções de capital não entram no total)
+		if !m.total.IsZero() && !parser.IsCapitalReturn(earningType) {
 			percentage := cat.TotalAmount.Div(m.total).Mul(decimal.NewFromInt(100))
 			b.WriteString(overviewLabelStyle.Render("  Percentual do total: "))
 			b.WriteString(overviewPercentStyle.Render(fmt.Sprintf("%.2f%%", percentage.InexactFloat64())))
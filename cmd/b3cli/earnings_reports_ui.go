@@ -6,6 +6,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/john/b3-project/internal/parser"
 	"github.com/john/b3-project/internal/wallet"
 	"github.com/shopspring/decimal"
 )
@@ -222,8 +223,9 @@ func (m reportsModel) viewYearSelect() string {
 func (m reportsModel) viewAnnualReport() string {
 	var b strings.Builder
 
-	// Agrupar proventos por ano
-	yearlyData := make(map[int]decimal.Decimal)
+	// Agrupar proventos por ano; devoluções de capital ficam em separado
+	yearlyData := make(map[int]reportAmounts)
+	yearlyReturned := make(map[int]decimal.Decimal)
 	var years []int
 
 	for _, asset := range m.wallet.Assets {
@@ -231,9 +233,13 @@ func (m reportsModel) viewAnnualReport() string {
 			year := earning.Date.Year()
 			if _, exists := yearlyData[year]; !exists {
 				years = append(years, year)
-				yearlyData[year] = decimal.Zero
+				yearlyData[year] = reportAmounts{}
 			}
-			yearlyData[year] = yearlyData[year].Add(earning.TotalAmount)
+			if parser.IsCapitalReturn(earning.Type) {
+				yearlyReturned[year] = yearlyReturned[year].Add(earning.TotalAmount)
+				continue
+			}
+			yearlyData[year] = yearlyData[year].add(earning)
 		}
 	}
 
@@ -248,31 +254,43 @@ func (m reportsModel) viewAnnualReport() string {
 
 	b.WriteString(reportTitleStyle.Render("📈 Relatório Anual de Proventos"))
 	b.WriteString("\n\n")
+	b.WriteString(reportNormalStyle.Render(fmt.Sprintf("%-6s %s", "", reportAmountsHeader())))
+	b.WriteString("\n")
 
-	totalGeral := decimal.Zero
+	total := reportAmounts{}
+	totalReturned := decimal.Zero
 	for _, year := range years {
-		amount := yearlyData[year]
-		totalGeral = totalGeral.Add(amount)
+		amounts := yearlyData[year]
+		total = total.sum(amounts)
 
 		yearLine := fmt.Sprintf("%d:", year)
-		valueLine := fmt.Sprintf("R$ %s", amount.StringFixed(2))
 
 		b.WriteString(reportYearStyle.Render(fmt.Sprintf("%-6s", yearLine)))
 		b.WriteString(" ")
-		b.WriteString(reportValueStyle.Render(valueLine))
+		b.WriteString(reportValueStyle.Render(amounts.columns()))
+		if returned := yearlyReturned[year]; returned.IsPositive() {
+			totalReturned = totalReturned.Add(returned)
+			b.WriteString(reportNormalStyle.Render(fmt.Sprintf("  (capital devolvido: R$ %s)", returned.StringFixed(2))))
+		}
 		b.WriteString("\n")
 	}
 
 	b.WriteString("\n")
-	b.WriteString(strings.Repeat("─", 40))
+	b.WriteString(strings.Repeat("─", 52))
+	b.WriteString("\n")
+	b.WriteString(reportSelectedStyle.Render(fmt.Sprintf("Total geral: R$ %s líquido", total.net.StringFixed(2))))
 	b.WriteString("\n")
-	b.WriteString(reportSelectedStyle.Render(fmt.Sprintf("Total geral: R$ %s", totalGeral.StringFixed(2))))
+	b.WriteString(reportNormalStyle.Render(total.summary()))
 	b.WriteString("\n")
+	if totalReturned.IsPositive() {
+		b.WriteString(reportNormalStyle.Render(fmt.Sprintf("Capital devolvido (resgates/amortizações): R$ %s", totalReturned.StringFixed(2))))
+		b.WriteString("\n")
+	}
 
 	// Calcular média anual
 	if len(years) > 0 {
-		media := totalGeral.Div(decimal.NewFromInt(int64(len(years))))
-		b.WriteString(reportNormalStyle.Render(fmt.Sprintf("Média anual: R$ %s", media.StringFixed(2))))
+		media := total.net.Div(decimal.NewFromInt(int64(len(years))))
+		b.WriteString(reportNormalStyle.Render(fmt.Sprintf("Média anual (líquida): R$ %s", media.StringFixed(2))))
 	}
 
 	b.WriteString(reportHelpStyle.Render("\n\nesc: voltar • q: sair"))
@@ -283,7 +301,8 @@ func (m reportsModel) viewAnnualReport() string {
 func (m reportsModel) viewMonthlyReport() string {
 	var b strings.Builder
 
-	monthlyData := make(map[int]decimal.Decimal)
+	monthlyData := make(map[int]reportAmounts)
+	monthlyReturned := make(map[int]decimal.Decimal)
 	monthNames := []string{
 		"Janeiro", "Fevereiro", "Março", "Abril", "Maio", "Junho",
 		"Julho", "Agosto", "Setembro", "Outubro", "Novembro", "Dezembro",
@@ -293,47 +312,101 @@ func (m reportsModel) viewMonthlyReport() string {
 		for _, earning := range asset.Earnings {
 			if earning.Date.Year() == m.selectedYear {
 				month := int(earning.Date.Month())
-				if _, exists := monthlyData[month]; !exists {
-					monthlyData[month] = decimal.Zero
+				if parser.IsCapitalReturn(earning.Type) {
+					monthlyReturned[month] = monthlyReturned[month].Add(earning.TotalAmount)
+					continue
 				}
-				monthlyData[month] = monthlyData[month].Add(earning.TotalAmount)
+				monthlyData[month] = monthlyData[month].add(earning)
 			}
 		}
 	}
 
 	b.WriteString(reportTitleStyle.Render(fmt.Sprintf("📅 Relatório Mensal de Proventos - %d", m.selectedYear)))
 	b.WriteString("\n\n")
+	b.WriteString(reportNormalStyle.Render(fmt.Sprintf("%-13s %s", "", reportAmountsHeader())))
+	b.WriteString("\n")
 
-	totalAnual := decimal.Zero
+	total := reportAmounts{}
+	totalReturned := decimal.Zero
 	monthsWithPayments := 0
 
 	for month := 1; month <= 12; month++ {
-		if amount, exists := monthlyData[month]; exists && !amount.IsZero() {
-			totalAnual = totalAnual.Add(amount)
+		amounts := monthlyData[month]
+		returned := monthlyReturned[month]
+		if amounts.gross.IsZero() && !returned.IsPositive() {
+			continue
+		}
+
+		if !amounts.gross.IsZero() {
+			total = total.sum(amounts)
 			monthsWithPayments++
+		}
 
-			monthLine := fmt.Sprintf("%-12s:", monthNames[month-1])
-			valueLine := fmt.Sprintf("R$ %s", amount.StringFixed(2))
+		monthLine := fmt.Sprintf("%-12s:", monthNames[month-1])
 
-			b.WriteString(reportNormalStyle.Render(monthLine))
-			b.WriteString(" ")
-			b.WriteString(reportValueStyle.Render(valueLine))
-			b.WriteString("\n")
+		b.WriteString(reportNormalStyle.Render(monthLine))
+		b.WriteString(" ")
+		b.WriteString(reportValueStyle.Render(amounts.columns()))
+		if returned.IsPositive() {
+			totalReturned = totalReturned.Add(returned)
+			b.WriteString(reportNormalStyle.Render(fmt.Sprintf("  (capital devolvido: R$ %s)", returned.StringFixed(2))))
 		}
+		b.WriteString("\n")
 	}
 
 	b.WriteString("\n")
-	b.WriteString(strings.Repeat("─", 40))
+	b.WriteString(strings.Repeat("─", 52))
 	b.WriteString("\n")
-	b.WriteString(reportSelectedStyle.Render(fmt.Sprintf("Total do ano: R$ %s", totalAnual.StringFixed(2))))
+	b.WriteString(reportSelectedStyle.Render(fmt.Sprintf("Total do ano: R$ %s líquido", total.net.StringFixed(2))))
 	b.WriteString("\n")
+	b.WriteString(reportNormalStyle.Render(total.summary()))
+	b.WriteString("\n")
+	if totalReturned.IsPositive() {
+		b.WriteString(reportNormalStyle.Render(fmt.Sprintf("Capital devolvido (resgates/amortizações): R$ %s", totalReturned.StringFixed(2))))
+		b.WriteString("\n")
+	}
 
 	if monthsWithPayments > 0 {
-		media := totalAnual.Div(decimal.NewFromInt(int64(monthsWithPayments)))
-		b.WriteString(reportNormalStyle.Render(fmt.Sprintf("Média mensal (meses com pagamento): R$ %s", media.StringFixed(2))))
+		media := total.net.Div(decimal.NewFromInt(int64(monthsWithPayments)))
+		b.WriteString(reportNormalStyle.Render(fmt.Sprintf("Média mensal líquida (meses com pagamento): R$ %s", media.StringFixed(2))))
 	}
 
 	b.WriteString(reportHelpStyle.Render("\n\nesc: voltar • q: sair"))
 
 	return docStyle.Render(b.String())
 }
+
+// reportAmounts soma os valores bruto, IR retido na fonte e líquido dos proventos
+type reportAmounts struct {
+	gross, tax, net decimal.Decimal
+}
+
+// add soma um provento; sem os valores preenchidos, eles são inferidos do total
+func (r reportAmounts) add(e parser.Earning) reportAmounts {
+	if e.GrossAmount.IsZero() && e.NetAmount.IsZero() {
+		parser.InferEarningAmounts(&e)
+	}
+	return reportAmounts{
+		gross: r.gross.Add(e.GrossAmount),
+		tax:   r.tax.Add(e.WithheldTax),
+		net:   r.net.Add(e.NetAmount),
+	}
+}
+
+func (r reportAmounts) sum(other reportAmounts) reportAmounts {
+	return reportAmounts{gross: r.gross.Add(other.gross), tax: r.tax.Add(other.tax), net: r.net.Add(other.net)}
+}
+
+// columns formata bruto, IR e líquido alinhados com reportAmountsHeader
+func (r reportAmounts) columns() string {
+	return fmt.Sprintf("R$ %10s  R$ %8s  R$ %10s", r.gross.StringFixed(2), r.tax.StringFixed(2), r.net.StringFixed(2))
+}
+
+// summary resume o total bruto e o IR retido (JCP)
+func (r reportAmounts) summary() string {
+	return fmt.Sprintf("Bruto: R$ %s • IR retido na fonte (JCP): R$ %s", r.gross.StringFixed(2), r.tax.StringFixed(2))
+}
+
+func reportAmountsHeader() string {
+	return fmt.Sprintf("%13s  %11s  %13s", "BRUTO", "IR RETIDO", "LÍQUIDO")
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/john/b3-project/internal/analytics"
+)
+
+// yieldSortKeys são os critérios de ordenação do ranking
+var yieldSortKeys = []struct {
+	label  string
+	metric func(analytics.AssetYield) float64
+}{
+	{"YoC 12m", func(y analytics.AssetYield) float64 { return y.YieldOnCost12 }},
+	{"DY 12m", func(y analytics.AssetYield) float64 { return y.Yield12 }},
+	{"Renda 12m", func(y analytics.AssetYield) float64 { return y.Income12.InexactFloat64() }},
+}
+
+const yieldVisibleRows = 20
+
+type yieldModel struct {
+	report  *analytics.YieldReport
+	sortKey int // Índice em yieldSortKeys
+	year    int // Índice em report.Years (coluna do ano)
+	cursor  int
+	offset  int // Primeira linha visível
+}
+
+func newYieldModel(report *analytics.YieldReport) yieldModel {
+	return yieldModel{report: report, year: len(report.Years) - 1}
+}
+
+func (m yieldModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m yieldModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.report.Assets)-1 {
+				m.cursor++
+			}
+		case "s", "tab":
+			m.sortKey = (m.sortKey + 1) % len(yieldSortKeys)
+			analytics.SortYields(m.report.Assets, yieldSortKeys[m.sortKey].metric)
+			m.cursor, m.offset = 0, 0
+		case "left", "h":
+			if m.year > 0 {
+				m.year--
+			}
+		case "right", "l":
+			if m.year < len(m.report.Years)-1 {
+				m.year++
+			}
+		}
+
+		if m.cursor < m.offset {
+			m.offset = m.cursor
+		}
+		if m.cursor >= m.offset+yieldVisibleRows {
+			m.offset = m.cursor - yieldVisibleRows + 1
+		}
+	}
+	return m, nil
+}
+
+func (m yieldModel) View() string {
+	var b strings.Builder
+	r := m.report
+
+	b.WriteString(assetsTitleStyle.Render("💰 Yield on Cost e Dividend Yield"))
+	b.WriteString("\n")
+	b.WriteString(assetsLabelStyle.Render(fmt.Sprintf("Últimos 12 meses até %s • ordenado por %s",
+		r.AsOf.Format("02/01/2006"), yieldSortKeys[m.sortKey].label)))
+	b.WriteString("\n\n")
+
+	yearLabel := "—"
+	if m.year >= 0 && m.year < len(r.Years) {
+		yearLabel = fmt.Sprintf("%d", r.Years[m.year])
+	}
+
+	header := fmt.Sprintf("  %-3s %-8s %8s %10s %10s %12s %12s %9s %9s %9s",
+		"#", "TICKER", "QTD", "PM", "COTAÇÃO", "PROV/AÇÃO", "RENDA 12M", "YOC 12M", "DY 12M", "YOC "+yearLabel)
+	b.WriteString(assetsGroupStyle.Render(header))
+	b.WriteString("\n")
+
+	end := m.offset + yieldVisibleRows
+	if end > len(r.Assets) {
+		end = len(r.Assets)
+	}
+	for i := m.offset; i < end; i++ {
+		y := r.Assets[i]
+		price, dy := "—", "—"
+		if y.HasPrice {
+			price = y.Price.StringFixed(2)
+			dy = fmt.Sprintf("%.2f%%", y.Yield12*100)
+		}
+		yearYoC := "—"
+		if yearLabel != "—" {
+			if year, ok := y.Years[r.Years[m.year]]; ok {
+				yearYoC = fmt.Sprintf("%.2f%%", year.YieldOnCost*100)
+			}
+		}
+
+		line := fmt.Sprintf("%-3d %-8s %8d %10s %10s %12s %12s %9s %9s %9s",
+			i+1, y.Ticker, y.Quantity, y.AveragePrice.StringFixed(2), price,
+			y.PerShare12.StringFixed(4), y.Income12.StringFixed(2),
+			fmt.Sprintf("%.2f%%", y.YieldOnCost12*100), dy, yearYoC)
+		if i == m.cursor {
+			b.WriteString(selectedItemStyle.Render("> " + line))
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+	if len(r.Assets) > yieldVisibleRows {
+		b.WriteString(assetsLabelStyle.Render(fmt.Sprintf("  %d-%d de %d", m.offset+1, end, len(r.Assets))))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(assetsGroupStyle.Render("Carteira"))
+	b.WriteString("\n")
+	summary := fmt.Sprintf("Renda 12m: R$ %s • Custo: R$ %s • YoC: %.2f%%",
+		r.Income12.StringFixed(2), r.CostBasis.StringFixed(2), r.YieldOnCost12()*100)
+	if r.MarketValue.IsPositive() {
+		summary += fmt.Sprintf(" • DY (ativos com cotação): %.2f%%", r.Yield12()*100)
+	}
+	b.WriteString(assetsLabelStyle.Render(summary))
+	b.WriteString("\n")
+
+	if !r.MarketValue.IsPositive() {
+		b.WriteString(assetsHintStyle.Render("ℹ  Sem cotações em cache: DY indisponível. Use 'b3cli quotes update' ou --refresh."))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(assetsHelpStyle.Render("↑/↓: navegar • s: ordenação • ←/→: ano • q/esc: sair"))
+
+	return docStyle.Render(b.String())
+}
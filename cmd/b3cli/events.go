@@ -1,7 +1,12 @@
 package main
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/john/b3-project/internal/wallet/events"
 	"github.com/spf13/cobra"
 )
 
@@ -11,8 +16,16 @@ var eventsCmd = &cobra.Command{
 	Long: `Commands to manage corporate events that affect your assets:
 - Grouping (reverse split): reduces number of shares
 - Split (stock split): increases number of shares
-- Bonus shares: free share distribution (coming soon)
-- Mergers & acquisitions: asset conversion (coming soon)`,
+- Bonus shares (bonificação): new shares with attributed cost
+- Mergers (incorporação) and ticker changes: position converted into a new ticker
+- Spin-offs (cisão): part of the cost basis moves to new tickers
+- Subscription rights: received, exercised at the exercise price, sold or expired
+
+Events are stored as records in the wallet and applied when positions are
+calculated. Imported transactions are never modified, so reimporting the same
+B3 file does not create duplicates. Use 'events list' and 'events remove' to
+review or undo registered events, and 'events import' to apply a shared
+events file.`,
 }
 
 var eventsGroupingCmd = &cobra.Command{
@@ -20,16 +33,20 @@ var eventsGroupingCmd = &cobra.Command{
 	Short: "Apply a grouping (reverse split) to an asset",
 	Long: `Apply a grouping (reverse split) to an asset in your portfolio.
 
-A grouping reduces the number of shares by combining N old shares into 1 new share.
-For example, in a 10:1 grouping:
+A grouping reduces the number of shares by combining N old shares into M new
+shares (any ratio with N > M, e.g., 10:1 or 5:4). For example, in a 10:1 grouping:
 - 1,000 shares become 100 shares
 - Price of R$ 2.80 becomes R$ 28.00
 - Total invested value remains the same
 
+When the ratio leaves a fraction (e.g., 1,005 shares at 10:1 = 100.5), the
+fraction is sold in the company's auction: enter the amount credited to
+register the sale, or leave it empty to truncate the fraction.
+
 This command launches an interactive interface where you can:
 1. Select which asset to apply the grouping to
-2. Enter the grouping ratio (e.g., "10:1")
-3. Enter the event date
+2. Enter the grouping ratio (e.g., "10:1", "5:4")
+3. Enter the event date and the optional auction amount
 4. See a preview of the changes
 5. Confirm and apply the grouping`,
 	Example: `  # Launch interactive grouping interface
@@ -42,16 +59,20 @@ var eventsSplitCmd = &cobra.Command{
 	Short: "Apply a split (stock split) to an asset",
 	Long: `Apply a split (stock split / desdobramento) to an asset in your portfolio.
 
-A split increases the number of shares by dividing each share into N parts.
-For example, in a 1:2 split:
+A split increases the number of shares: N old shares become M new shares (any
+ratio with M > N, e.g., 1:2 or 2:3). For example, in a 1:2 split:
 - 100 shares become 200 shares
 - Price of R$ 10.50 becomes R$ 5.25
 - Total invested value remains the same
 
+When the ratio leaves a fraction (e.g., 101 shares at 2:3 = 151.5), the
+fraction is sold in the company's auction: enter the amount credited to
+register the sale, or leave it empty to truncate the fraction.
+
 This command launches an interactive interface where you can:
 1. Select which asset to apply the split to
-2. Enter the split ratio (e.g., "1:2", "1:3")
-3. Enter the event date
+2. Enter the split ratio (e.g., "1:2", "2:3")
+3. Enter the event date and the optional auction amount
 4. See a preview of the changes
 5. Confirm and apply the split`,
 	Example: `  # Launch interactive split interface
@@ -59,10 +80,48 @@ This command launches an interactive interface where you can:
 	RunE: runEventsSplit,
 }
 
+var eventsBonusCmd = &cobra.Command{
+	Use:   "bonus",
+	Short: "Register bonus shares (bonificação) for an asset",
+	Long: `Register bonus shares (bonificação) distributed by a company.
+
+The company distributes N% new shares over the position held before the event
+date and declares the unit cost attributed to them. As required by the tax
+rules, the new shares enter the position at that cost, increasing the invested
+value and recomputing the average price. For example, a 10% bonus at R$ 12.50:
+- 100 shares become 110 shares
+- Invested value grows by 10 × R$ 12.50 = R$ 125.00
+
+Fractions (e.g., 10% of 105 shares = 10.5) are sold by the company in an
+auction. Inform the cash received to register the fraction as sold; otherwise
+the fraction is discarded.
+
+Without flags an interactive interface is launched. With --ticker the bonus is
+registered directly (--percent, --unit-cost and --date are required).`,
+	Example: `  # Launch interactive bonus interface
+  b3cli events bonus
+
+  # Register directly
+  b3cli events bonus --ticker ITSA4 --percent 10 --unit-cost 12.50 --date 2024-05-01
+
+  # Fraction sold in auction
+  b3cli events bonus --ticker ITSA4 --percent 10 --unit-cost 12.50 --date 2024-05-01 --auction-amount 6.30 --yes`,
+	Args: cobra.NoArgs,
+	RunE: runEventsBonus,
+}
+
 func init() {
+	eventsBonusCmd.Flags().String("ticker", "", "Ativo que recebeu a bonificação (ativa o modo sem TUI)")
+	eventsBonusCmd.Flags().String("percent", "", "Percentual de ações novas (ex: 10)")
+	eventsBonusCmd.Flags().String("unit-cost", "", "Custo unitário atribuído pela empresa (R$)")
+	eventsBonusCmd.Flags().String("date", "", "Data ex-bonificação (YYYY-MM-DD)")
+	eventsBonusCmd.Flags().String("auction-amount", "", "Valor recebido pelas frações vendidas em leilão (R$)")
+	eventsBonusCmd.Flags().BoolP("yes", "y", false, "Não pede confirmação")
+
 	// Add subcommands to events
 	eventsCmd.AddCommand(eventsGroupingCmd)
 	eventsCmd.AddCommand(eventsSplitCmd)
+	eventsCmd.AddCommand(eventsBonusCmd)
 }
 
 func runEventsGrouping(cmd *cobra.Command, args []string) error {
@@ -102,3 +161,101 @@ func runEventsSplit(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runEventsBonus(cmd *cobra.Command, args []string) error {
+	ticker, _ := cmd.Flags().GetString("ticker")
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	if ticker == "" {
+		// Launch interactive TUI
+		p := tea.NewProgram(
+			newBonusModel(w, w.GetDirPath()),
+			tea.WithAltScreen(),
+		)
+
+		if _, err := p.Run(); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	percentStr, _ := cmd.Flags().GetString("percent")
+	unitCostStr, _ := cmd.Flags().GetString("unit-cost")
+	dateStr, _ := cmd.Flags().GetString("date")
+	auctionStr, _ := cmd.Flags().GetString("auction-amount")
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	if percentStr == "" || unitCostStr == "" || dateStr == "" {
+		return fmt.Errorf("--percent, --unit-cost and --date are required with --ticker")
+	}
+
+	percent, err := events.ParseBonusPercent(percentStr)
+	if err != nil {
+		return fmt.Errorf("invalid --percent: %w", err)
+	}
+	unitCost, err := events.ParseAmount(unitCostStr)
+	if err != nil {
+		return fmt.Errorf("invalid --unit-cost: %w", err)
+	}
+	auction, err := events.ParseAmount(auctionStr)
+	if err != nil {
+		return fmt.Errorf("invalid --auction-amount: %w", err)
+	}
+	eventDate, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return fmt.Errorf("invalid --date (use YYYY-MM-DD): %w", err)
+	}
+
+	ticker = strings.ToUpper(ticker)
+	params := events.BonusParams{Percent: percent, UnitCost: unitCost, AuctionAmount: auction}
+
+	preview, err := events.PreviewBonus(w, ticker, params, eventDate)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(titleStyle.Render("Preview: Bonus Shares"))
+	fmt.Println()
+	fmt.Print(writeBonusSummary(preview))
+	fmt.Printf("AFTER:\n  Quantity: ~%d shares\n  Avg Price: ~R$ %s\n  Invested: ~R$ %s\n\n",
+		preview.QuantityAfter, preview.PriceAfter.StringFixed(2), preview.InvestedAfter.StringFixed(2))
+
+	if !confirmEvent(skipConfirm, "Register this bonus?") {
+		return nil
+	}
+
+	result, err := events.ApplyBonus(w, ticker, params, eventDate)
+	if err != nil {
+		return fmt.Errorf("failed to apply bonus: %w", err)
+	}
+
+	if err := w.Save(w.GetDirPath()); err != nil {
+		return fmt.Errorf("failed to save wallet: %w", err)
+	}
+
+	fmt.Printf("✓ Bonus registered (event %s)\n", result.EventID)
+	fmt.Printf("  %s quantity: %d → %d, avg R$ %s → R$ %s\n",
+		ticker, result.QuantityBefore, result.QuantityAfter,
+		result.PriceBefore.StringFixed(2), result.PriceAfter.StringFixed(2))
+
+	return nil
+}
+
+// confirmEvent asks for confirmation unless skip is set
+func confirmEvent(skip bool, question string) bool {
+	if skip {
+		return true
+	}
+
+	answer, err := readLine(question + " [y/N]: ")
+	if err != nil || (!strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "s")) {
+		fmt.Println("Cancelled - nothing changed.")
+		return false
+	}
+	return true
+}
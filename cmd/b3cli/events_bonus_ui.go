@@ -0,0 +1,403 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/john/b3-project/internal/wallet/events"
+)
+
+type bonusViewMode int
+
+const (
+	bonusViewSelectAsset bonusViewMode = iota
+	bonusViewInputs
+	bonusViewConfirm
+	bonusViewResult
+)
+
+// Índices dos campos do formulário de bonificação
+const (
+	bonusInputPercent = iota
+	bonusInputUnitCost
+	bonusInputDate
+	bonusInputAuction
+)
+
+type bonusModel struct {
+	walletPath    string
+	wallet        *wallet.Wallet
+	mode          bonusViewMode
+	assetList     list.Model
+	selectedAsset string
+	inputs        []textinput.Model
+	currentInput  int
+	result        *events.BonusResult
+	err           error
+	cancelled     bool
+}
+
+func newBonusModel(w *wallet.Wallet, walletPath string) bonusModel {
+	if w == nil {
+		return bonusModel{
+			walletPath: walletPath,
+			err:        fmt.Errorf("wallet is nil"),
+		}
+	}
+
+	// Build asset list (only active assets)
+	items := []list.Item{}
+	tickers := make([]string, 0)
+	for ticker, asset := range w.Assets {
+		if asset.Quantity > 0 {
+			tickers = append(tickers, ticker)
+		}
+	}
+	sort.Strings(tickers)
+
+	for _, ticker := range tickers {
+		asset := w.Assets[ticker]
+		items = append(items, splitAssetItem{
+			ticker: ticker,
+			qty:    asset.Quantity,
+			price:  asset.AveragePrice.StringFixed(2),
+		})
+	}
+
+	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Select Asset for Bonus Shares"
+	l.SetShowHelp(true)
+	l.SetFilteringEnabled(true)
+
+	inputs := make([]textinput.Model, 4)
+
+	inputs[bonusInputPercent] = textinput.New()
+	inputs[bonusInputPercent].Placeholder = "e.g., 10"
+	inputs[bonusInputPercent].CharLimit = 10
+	inputs[bonusInputPercent].Width = 30
+	inputs[bonusInputPercent].Focus()
+
+	inputs[bonusInputUnitCost] = textinput.New()
+	inputs[bonusInputUnitCost].Placeholder = "e.g., 12.50"
+	inputs[bonusInputUnitCost].CharLimit = 15
+	inputs[bonusInputUnitCost].Width = 30
+
+	inputs[bonusInputDate] = textinput.New()
+	inputs[bonusInputDate].Placeholder = "YYYY-MM-DD"
+	inputs[bonusInputDate].CharLimit = 10
+	inputs[bonusInputDate].Width = 30
+
+	inputs[bonusInputAuction] = textinput.New()
+	inputs[bonusInputAuction].Placeholder = "empty = fractions discarded"
+	inputs[bonusInputAuction].CharLimit = 15
+	inputs[bonusInputAuction].Width = 30
+
+	return bonusModel{
+		walletPath: walletPath,
+		wallet:     w,
+		mode:       bonusViewSelectAsset,
+		assetList:  l,
+		inputs:     inputs,
+	}
+}
+
+func (m bonusModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m bonusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := docStyle.GetFrameSize()
+		m.assetList.SetSize(msg.Width-h, msg.Height-v)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case bonusViewSelectAsset:
+			return m.updateSelectAsset(msg)
+		case bonusViewInputs:
+			return m.updateInputs(msg)
+		case bonusViewConfirm:
+			return m.updateConfirm(msg)
+		case bonusViewResult:
+			return m.updateResult(msg)
+		}
+	}
+
+	if m.mode == bonusViewSelectAsset {
+		var cmd tea.Cmd
+		m.assetList, cmd = m.assetList.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m bonusModel) updateSelectAsset(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc", "q":
+		m.cancelled = true
+		return m, tea.Quit
+
+	case "enter":
+		if item, ok := m.assetList.SelectedItem().(splitAssetItem); ok {
+			m.selectedAsset = item.ticker
+			m.mode = bonusViewInputs
+			m.focusInput(0)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.assetList, cmd = m.assetList.Update(msg)
+	return m, cmd
+}
+
+func (m bonusModel) updateInputs(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.cancelled = true
+		return m, tea.Quit
+
+	case "esc":
+		m.mode = bonusViewSelectAsset
+		m.focusInput(0)
+		m.err = nil
+		return m, nil
+
+	case "enter", "tab", "down":
+		if m.currentInput < len(m.inputs)-1 {
+			m.focusInput(m.currentInput + 1)
+			return m, nil
+		}
+		m.mode = bonusViewConfirm
+		return m, nil
+
+	case "up", "shift+tab":
+		if m.currentInput > 0 {
+			m.focusInput(m.currentInput - 1)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.currentInput], cmd = m.inputs[m.currentInput].Update(msg)
+	return m, cmd
+}
+
+// focusInput focuses one field and blurs the others
+func (m *bonusModel) focusInput(index int) {
+	m.currentInput = index
+	for i := range m.inputs {
+		if i == index {
+			m.inputs[i].Focus()
+		} else {
+			m.inputs[i].Blur()
+		}
+	}
+}
+
+func (m bonusModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.cancelled = true
+		return m, tea.Quit
+
+	case "esc", "n":
+		m.mode = bonusViewInputs
+		m.err = nil
+		return m, nil
+
+	case "enter", "y":
+		if err := m.applyBonus(); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.mode = bonusViewResult
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m bonusModel) updateResult(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", "q", "esc":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// parseInputs reads the form into bonus parameters and event date
+func (m bonusModel) parseInputs() (events.BonusParams, time.Time, error) {
+	percent, err := events.ParseBonusPercent(m.inputs[bonusInputPercent].Value())
+	if err != nil {
+		return events.BonusParams{}, time.Time{}, fmt.Errorf("invalid percent: %w", err)
+	}
+
+	unitCost, err := events.ParseAmount(m.inputs[bonusInputUnitCost].Value())
+	if err != nil {
+		return events.BonusParams{}, time.Time{}, fmt.Errorf("invalid unit cost: %w", err)
+	}
+
+	eventDate, err := time.Parse("2006-01-02", strings.TrimSpace(m.inputs[bonusInputDate].Value()))
+	if err != nil {
+		return events.BonusParams{}, time.Time{}, fmt.Errorf("invalid date format (use YYYY-MM-DD): %w", err)
+	}
+
+	auction, err := events.ParseAmount(m.inputs[bonusInputAuction].Value())
+	if err != nil {
+		return events.BonusParams{}, time.Time{}, fmt.Errorf("invalid auction amount: %w", err)
+	}
+
+	return events.BonusParams{Percent: percent, UnitCost: unitCost, AuctionAmount: auction}, eventDate, nil
+}
+
+func (m *bonusModel) applyBonus() error {
+	params, eventDate, err := m.parseInputs()
+	if err != nil {
+		return err
+	}
+
+	result, err := events.ApplyBonus(m.wallet, m.selectedAsset, params, eventDate)
+	if err != nil {
+		return fmt.Errorf("failed to apply bonus: %w", err)
+	}
+
+	m.result = result
+
+	if err := m.wallet.Save(m.walletPath); err != nil {
+		return fmt.Errorf("failed to save wallet: %w", err)
+	}
+
+	return nil
+}
+
+func (m bonusModel) View() string {
+	if m.err != nil && m.mode != bonusViewConfirm && m.mode != bonusViewResult {
+		return errorStyle.Render(fmt.Sprintf("Error: %v\n\nPress Enter to continue or Esc to cancel", m.err))
+	}
+
+	switch m.mode {
+	case bonusViewSelectAsset:
+		return docStyle.Render(m.assetList.View())
+	case bonusViewInputs:
+		return m.viewInputs()
+	case bonusViewConfirm:
+		return m.viewConfirm()
+	case bonusViewResult:
+		return m.viewResult()
+	}
+
+	return ""
+}
+
+func (m bonusModel) viewInputs() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("Register Bonus Shares (Bonificação)"))
+	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf("Asset: %s\n\n", selectedItemStyle.Render(m.selectedAsset)))
+
+	labels := []string{
+		"New shares (% of position, e.g., 10):",
+		"Unit cost declared by the company (R$):",
+		"Event Date - first day ex-bonus (YYYY-MM-DD):",
+		"Cash received for fractions sold in auction (R$, optional):",
+	}
+	for i, label := range labels {
+		b.WriteString(label + "\n")
+		b.WriteString(m.inputs[i].View())
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(helpStyle.Render("Tab/Enter: Next field • Shift+Tab/Up: Previous field • Esc: Back • Ctrl+C: Quit"))
+
+	return docStyle.Render(b.String())
+}
+
+func (m bonusModel) viewConfirm() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("Preview: Bonus Shares"))
+	b.WriteString("\n\n")
+
+	params, eventDate, err := m.parseInputs()
+	if err != nil {
+		return errorStyle.Render(fmt.Sprintf("%v\n\nPress Esc to go back", err))
+	}
+
+	preview, err := events.PreviewBonus(m.wallet, m.selectedAsset, params, eventDate)
+	if err != nil {
+		return errorStyle.Render(fmt.Sprintf("Error: %v\n\nPress Esc to go back", err))
+	}
+
+	b.WriteString(writeBonusSummary(preview))
+	b.WriteString("AFTER:\n")
+	b.WriteString(fmt.Sprintf("  Quantity: ~%d shares\n", preview.QuantityAfter))
+	b.WriteString(fmt.Sprintf("  Avg Price: ~R$ %s\n", preview.PriceAfter.StringFixed(2)))
+	b.WriteString(fmt.Sprintf("  Invested: ~R$ %s\n\n", preview.InvestedAfter.StringFixed(2)))
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v\n\n", m.err)))
+	}
+
+	b.WriteString(helpStyle.Render("Y/Enter: Apply • N/Esc: Cancel • Ctrl+C: Quit"))
+
+	return docStyle.Render(b.String())
+}
+
+func (m bonusModel) viewResult() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("✓ Bonus Shares Registered"))
+	b.WriteString("\n\n")
+
+	if m.result != nil {
+		b.WriteString(writeBonusSummary(m.result))
+		b.WriteString("AFTER:\n")
+		b.WriteString(fmt.Sprintf("  Quantity: %d shares\n", m.result.QuantityAfter))
+		b.WriteString(fmt.Sprintf("  Avg Price: R$ %s\n", m.result.PriceAfter.StringFixed(2)))
+		b.WriteString(fmt.Sprintf("  Invested: R$ %s\n\n", m.result.InvestedAfter.StringFixed(2)))
+		b.WriteString(fmt.Sprintf("Event ID: %s (undo with 'b3cli events remove %s')\n", m.result.EventID, m.result.EventID))
+		b.WriteString("✓ Wallet saved successfully\n\n")
+	}
+
+	b.WriteString(helpStyle.Render("Press Enter to exit"))
+
+	return docStyle.Render(b.String())
+}
+
+// writeBonusSummary describes the bonus computation (shared by preview, result and flag mode)
+func writeBonusSummary(r *events.BonusResult) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("Asset: %s\n", selectedItemStyle.Render(r.Ticker)))
+	b.WriteString(fmt.Sprintf("Bonus: %s%% at R$ %s per new share\n", r.Params.Percent, r.Params.UnitCost.StringFixed(2)))
+	b.WriteString(fmt.Sprintf("Event Date: %s\n\n", r.EventDate.Format("2006-01-02")))
+
+	b.WriteString(fmt.Sprintf("Eligible shares (held before the date): %s\n", r.EligibleShares.String()))
+	b.WriteString(fmt.Sprintf("New shares: %s (attributed cost R$ %s)\n", r.BonusShares.String(), r.AttributedCost.StringFixed(2)))
+	if r.FractionShares.IsPositive() {
+		if r.FractionSoldFor.IsPositive() {
+			b.WriteString(fmt.Sprintf("Fraction: %s sold in auction for R$ %s\n", r.FractionShares.String(), r.FractionSoldFor.StringFixed(2)))
+		} else {
+			b.WriteString(fmt.Sprintf("Fraction: %s discarded (no auction amount informed)\n", r.FractionShares.String()))
+		}
+	}
+	b.WriteString("\n")
+
+	b.WriteString("BEFORE:\n")
+	b.WriteString(fmt.Sprintf("  Quantity: %d shares\n", r.QuantityBefore))
+	b.WriteString(fmt.Sprintf("  Avg Price: R$ %s\n", r.PriceBefore.StringFixed(2)))
+	b.WriteString(fmt.Sprintf("  Invested: R$ %s\n\n", r.InvestedBefore.StringFixed(2)))
+
+	return b.String()
+}
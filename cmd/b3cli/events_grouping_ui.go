@@ -11,6 +11,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/john/b3-project/internal/wallet"
 	"github.com/john/b3-project/internal/wallet/events"
+	"github.com/shopspring/decimal"
 )
 
 type groupingViewMode int
@@ -84,7 +85,7 @@ func newGroupingModel(w *wallet.Wallet, walletPath string) groupingModel {
 	l.SetFilteringEnabled(true)
 
 	// Create text inputs
-	inputs := make([]textinput.Model, 2)
+	inputs := make([]textinput.Model, 3)
 
 	// Ratio input
 	inputs[0] = textinput.New()
@@ -99,6 +100,12 @@ func newGroupingModel(w *wallet.Wallet, walletPath string) groupingModel {
 	inputs[1].CharLimit = 10
 	inputs[1].Width = 30
 
+	// Auction amount input (optional, cash credited for the leftover fraction)
+	inputs[2] = textinput.New()
+	inputs[2].Placeholder = "0.00 (fraction truncated)"
+	inputs[2].CharLimit = 15
+	inputs[2].Width = 30
+
 	return groupingModel{
 		walletPath: walletPath,
 		wallet:     w,
@@ -108,6 +115,20 @@ func newGroupingModel(w *wallet.Wallet, walletPath string) groupingModel {
 	}
 }
 
+// withSuggestion pre-fills ticker, ratio and date (e.g., from 'b3cli doctor') and
+// opens the inputs directly; the values can still be edited before the preview
+func (m groupingModel) withSuggestion(ticker string, ratio events.Ratio, date time.Time) groupingModel {
+	if m.wallet == nil || m.wallet.Assets[ticker] == nil {
+		return m
+	}
+
+	m.selectedAsset = ticker
+	m.mode = groupingViewInputs
+	m.inputs[0].SetValue(ratio.String())
+	m.inputs[1].SetValue(date.Format("2006-01-02"))
+	return m
+}
+
 func (m groupingModel) Init() tea.Cmd {
 	return nil
 }
@@ -174,7 +195,9 @@ func (m groupingModel) updateInputs(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.mode = groupingViewSelectAsset
 		m.currentInput = 0
 		m.inputs[0].Focus()
-		m.inputs[1].Blur()
+		for i := 1; i < len(m.inputs); i++ {
+			m.inputs[i].Blur()
+		}
 		m.err = nil
 		return m, nil
 
@@ -248,23 +271,37 @@ func (m groupingModel) updateResult(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m groupingModel) applyGrouping() error {
-	// Parse ratio
-	ratioStr := m.inputs[0].Value()
-	ratio, err := events.ParseRatio(ratioStr)
+// parseInputs reads the ratio, date and auction amount typed by the user
+func (m groupingModel) parseInputs() (events.Ratio, time.Time, decimal.Decimal, error) {
+	ratio, err := events.ParseRatio(m.inputs[0].Value())
 	if err != nil {
-		return fmt.Errorf("invalid ratio: %w", err)
+		return events.Ratio{}, time.Time{}, decimal.Zero, fmt.Errorf("invalid ratio: %w", err)
+	}
+	if err := events.ValidateGroupingRatio(ratio); err != nil {
+		return events.Ratio{}, time.Time{}, decimal.Zero, err
+	}
+
+	eventDate, err := time.Parse("2006-01-02", m.inputs[1].Value())
+	if err != nil {
+		return events.Ratio{}, time.Time{}, decimal.Zero, fmt.Errorf("invalid date format (use YYYY-MM-DD): %w", err)
 	}
 
-	// Parse date
-	dateStr := m.inputs[1].Value()
-	eventDate, err := time.Parse("2006-01-02", dateStr)
+	auction, err := events.ParseAmount(m.inputs[2].Value())
 	if err != nil {
-		return fmt.Errorf("invalid date format (use YYYY-MM-DD): %w", err)
+		return events.Ratio{}, time.Time{}, decimal.Zero, fmt.Errorf("invalid auction amount: %w", err)
+	}
+
+	return ratio, eventDate, auction, nil
+}
+
+func (m *groupingModel) applyGrouping() error {
+	ratio, eventDate, auction, err := m.parseInputs()
+	if err != nil {
+		return err
 	}
 
 	// Apply grouping
-	result, err := events.ApplyGrouping(m.wallet, m.selectedAsset, ratio, eventDate)
+	result, err := events.ApplyRatio(m.wallet, m.selectedAsset, ratio, auction, eventDate)
 	if err != nil {
 		return fmt.Errorf("failed to apply grouping: %w", err)
 	}
@@ -309,7 +346,7 @@ func (m groupingModel) viewInputs() string {
 	b.WriteString("\n\n")
 	b.WriteString(fmt.Sprintf("Asset: %s\n\n", selectedItemStyle.Render(m.selectedAsset)))
 
-	b.WriteString("Grouping Ratio (e.g., 10:1):\n")
+	b.WriteString("Grouping Ratio (e.g., 10:1, 5:4):\n")
 	b.WriteString(m.inputs[0].View())
 	b.WriteString("\n\n")
 
@@ -317,6 +354,10 @@ func (m groupingModel) viewInputs() string {
 	b.WriteString(m.inputs[1].View())
 	b.WriteString("\n\n")
 
+	b.WriteString("Auction Amount for fractions (R$, optional):\n")
+	b.WriteString(m.inputs[2].View())
+	b.WriteString("\n\n")
+
 	b.WriteString(helpStyle.Render("Tab/Enter: Next field • Shift+Tab/Up: Previous field • Esc: Back • Ctrl+C: Quit"))
 
 	return docStyle.Render(b.String())
@@ -329,48 +370,30 @@ func (m groupingModel) viewConfirm() string {
 	b.WriteString("\n\n")
 
 	// Parse inputs for preview
-	ratioStr := m.inputs[0].Value()
-	ratio, err := events.ParseRatio(ratioStr)
+	ratio, eventDate, auction, err := m.parseInputs()
 	if err != nil {
-		return errorStyle.Render(fmt.Sprintf("Invalid ratio: %v\n\nPress Esc to go back", err))
+		return errorStyle.Render(fmt.Sprintf("%v\n\nPress Esc to go back", err))
 	}
 
-	dateStr := m.inputs[1].Value()
-	_, err = time.Parse("2006-01-02", dateStr)
+	preview, err := events.PreviewRatio(m.wallet, m.selectedAsset, ratio, auction, eventDate)
 	if err != nil {
-		return errorStyle.Render(fmt.Sprintf("Invalid date: %v\n\nPress Esc to go back", err))
+		return errorStyle.Render(fmt.Sprintf("%v\n\nPress Esc to go back", err))
 	}
 
-	asset := m.wallet.Assets[m.selectedAsset]
-
 	b.WriteString(fmt.Sprintf("Asset: %s\n", selectedItemStyle.Render(m.selectedAsset)))
 	b.WriteString(fmt.Sprintf("Ratio: %s\n", events.FormatRatio(ratio)))
-	b.WriteString(fmt.Sprintf("Event Date: %s\n\n", dateStr))
-
-	// Calculate preview values
-	divisor := float64(ratio.From) / float64(ratio.To)
-	newQty := float64(asset.Quantity) / divisor
-	newPrice, _ := asset.AveragePrice.Float64()
-	newPrice *= divisor
+	b.WriteString(fmt.Sprintf("Event Date: %s\n\n", eventDate.Format("2006-01-02")))
 
 	b.WriteString("BEFORE:\n")
-	b.WriteString(fmt.Sprintf("  Quantity: %d shares\n", asset.Quantity))
-	b.WriteString(fmt.Sprintf("  Avg Price: R$ %s\n\n", asset.AveragePrice.StringFixed(2)))
+	b.WriteString(fmt.Sprintf("  Quantity: %d shares\n", preview.QuantityBefore))
+	b.WriteString(fmt.Sprintf("  Avg Price: R$ %s\n\n", preview.PriceBefore.StringFixed(2)))
 
 	b.WriteString("AFTER:\n")
-	b.WriteString(fmt.Sprintf("  Quantity: ~%.0f shares\n", newQty))
-	b.WriteString(fmt.Sprintf("  Avg Price: ~R$ %.2f\n\n", newPrice))
-
-	// Count transactions that will be affected
-	txBefore := 0
-	for _, tx := range asset.Negotiations {
-		eventDate, _ := time.Parse("2006-01-02", dateStr)
-		if tx.Date.Before(eventDate) {
-			txBefore++
-		}
-	}
+	b.WriteString(fmt.Sprintf("  Quantity: ~%d shares\n", preview.QuantityAfter))
+	b.WriteString(fmt.Sprintf("  Avg Price: ~R$ %s\n\n", preview.PriceAfter.StringFixed(2)))
 
-	b.WriteString(fmt.Sprintf("Transactions to adjust: %d\n\n", txBefore))
+	writeRatioFraction(&b, preview)
+	b.WriteString(fmt.Sprintf("Transactions affected: %d (imported records are kept as-is)\n\n", preview.TransactionsAdjusted))
 
 	if m.err != nil {
 		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v\n\n", m.err)))
@@ -395,7 +418,16 @@ func (m groupingModel) viewResult() string {
 		b.WriteString("CHANGES:\n")
 		b.WriteString(fmt.Sprintf("  Quantity: %d → %d shares\n", m.result.QuantityBefore, m.result.QuantityAfter))
 		b.WriteString(fmt.Sprintf("  Avg Price: R$ %s → R$ %s\n", m.result.PriceBefore.StringFixed(2), m.result.PriceAfter.StringFixed(2)))
-		b.WriteString(fmt.Sprintf("  Transactions adjusted: %d\n\n", m.result.TransactionsAdjusted))
+		b.WriteString(fmt.Sprintf("  Transactions affected: %d\n", m.result.TransactionsAdjusted))
+		if m.result.FractionShares.IsPositive() {
+			b.WriteString(fmt.Sprintf("  Fraction: %s share(s)", m.result.FractionShares))
+			if m.result.FractionSoldFor.IsPositive() {
+				b.WriteString(fmt.Sprintf(" sold in auction for R$ %s\n", m.result.FractionSoldFor.StringFixed(2)))
+			} else {
+				b.WriteString(" truncated\n")
+			}
+		}
+		b.WriteString(fmt.Sprintf("  Event ID: %s (undo with 'b3cli events remove %s')\n\n", m.result.EventID, m.result.EventID))
 
 		b.WriteString("✓ Wallet saved successfully\n\n")
 	}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/john/b3-project/internal/wallet/events"
+	"github.com/spf13/cobra"
+)
+
+var eventsImportCmd = &cobra.Command{
+	Use:   "import <file.yaml|file.csv>",
+	Short: "Import corporate events from a shared events file",
+	Long: `Import corporate events (split, grouping, bonus, rename, merge, spin-off) from a
+YAML or CSV file, so a team can maintain one shared events file instead of
+entering each event through the interactive commands.
+
+Every event is checked against the wallet and shown with its status:
+- to apply: the ticker is held before the event date
+- already registered: the same event is already in the wallet
+- not held: no shares of the ticker before the event date (ignored)
+- invalid: the entry could not be read (the reason is shown)
+
+Events are applied in chronological order, so a rename followed by a split of
+the new ticker works in the same file. Importing the same file again registers
+nothing: events are identified by their fields, including those entered by hand.
+
+YAML format:
+  events:
+    - type: split        # split, grouping, bonus, rename, merge, spinoff
+      ticker: MGLU3
+      date: 2024-05-01
+      ratio: "1:4"
+      auction_amount: 0  # optional, fractions sold (split/grouping/bonus)
+    - type: bonus
+      ticker: ITSA4
+      date: 2024-05-01
+      percent: 10
+      unit_cost: 12.50
+    - type: rename       # merge also takes ratio and cash_per_share
+      ticker: OLDC3
+      target: NEWC3
+      date: 2024-06-01
+    - type: spinoff
+      ticker: OLDC3
+      date: 2024-06-01
+      spin_offs: ["NEWA3:20:1:0.5"]
+
+CSV format (header required; ',' or ';' separated; spin_offs separated by '|'):
+  type,ticker,date,ratio,target,percent,unit_cost,auction_amount,cash_per_share,spin_offs,notes`,
+	Example: `  b3cli events import events.yaml
+  b3cli events import shared/events.csv --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEventsImport,
+}
+
+func init() {
+	eventsImportCmd.Flags().BoolP("yes", "y", false, "Não pede confirmação")
+
+	eventsCmd.AddCommand(eventsImportCmd)
+}
+
+func runEventsImport(cmd *cobra.Command, args []string) error {
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	entries, err := events.ParseEventsFile(args[0])
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("No events found in the file.")
+		return nil
+	}
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	plan := events.PlanImport(w, entries)
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Events in %s (%d)", args[0], len(plan))))
+	fmt.Println()
+	printImportEntries(plan)
+
+	pending := events.CountImportStatus(plan, events.ImportPending)
+	fmt.Printf("\n%d to apply, %d already registered, %d not held, %d invalid\n\n",
+		pending,
+		events.CountImportStatus(plan, events.ImportDuplicate),
+		events.CountImportStatus(plan, events.ImportNotHeld),
+		events.CountImportStatus(plan, events.ImportInvalid))
+
+	if pending == 0 {
+		fmt.Println("Nothing to apply - the wallet is up to date with this file.")
+		return nil
+	}
+
+	if !confirmEvent(skipConfirm, fmt.Sprintf("Register %d event(s)?", pending)) {
+		return nil
+	}
+
+	result := events.ApplyImport(w, entries)
+	applied := events.CountImportStatus(result, events.ImportApplied)
+
+	if applied > 0 {
+		if err := w.Save(w.GetDirPath()); err != nil {
+			return fmt.Errorf("failed to save wallet: %w", err)
+		}
+	}
+
+	fmt.Printf("✓ %d event(s) registered\n", applied)
+	if applied < pending {
+		// Some entries failed when registered (e.g., position changed by an earlier event)
+		fmt.Println()
+		for _, entry := range result {
+			if entry.Status != events.ImportApplied && entry.Status != events.ImportDuplicate {
+				printImportEntry(entry)
+			}
+		}
+	}
+
+	return nil
+}
+
+// printImportEntries lists the file entries with their import status
+func printImportEntries(entries []events.ImportEntry) {
+	fmt.Printf("%-5s  %-10s  %-8s  %-18s  %s\n", "LINE", "DATE", "TICKER", "STATUS", "EVENT")
+	for _, entry := range entries {
+		printImportEntry(entry)
+	}
+}
+
+func printImportEntry(entry events.ImportEntry) {
+	if entry.Status == events.ImportInvalid {
+		fmt.Printf("%-5d  %-10s  %-8s  %-18s  %s\n", entry.Line, "-", "-",
+			errorStyle.Render(fmt.Sprintf("%-18s", entry.Status)), entry.Err)
+		return
+	}
+
+	status := fmt.Sprintf("%-18s", entry.Status)
+	switch entry.Status {
+	case events.ImportPending, events.ImportApplied:
+		status = selectedItemStyle.Render(status)
+	default:
+		status = helpStyle.Render(status)
+	}
+	fmt.Printf("%-5d  %-10s  %-8s  %s  %s\n", entry.Line, entry.Event.Date.Format("2006-01-02"),
+		entry.Event.Ticker, status, entry.Event.Describe())
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var eventsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered corporate events",
+	Long: `List the corporate events registered in the wallet, in chronological order.
+
+Events do not modify imported transactions: they are applied when positions are
+calculated. Use the displayed ID to remove an event with 'b3cli events remove'.`,
+	Example: `  b3cli events list
+  b3cli events list --ticker ITSA4`,
+	Args: cobra.NoArgs,
+	RunE: runEventsList,
+}
+
+var eventsRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove a corporate event",
+	Long: `Remove a corporate event by ID (or a unique prefix of the ID).
+
+The asset positions are recalculated without the event. Imported transactions
+are not modified.`,
+	Example: `  b3cli events remove 3f2a9c1b7d4e
+  b3cli events remove 3f2a --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEventsRemove,
+}
+
+func init() {
+	eventsListCmd.Flags().String("ticker", "", "Mostra apenas eventos do ticker")
+	eventsRemoveCmd.Flags().BoolP("yes", "y", false, "Não pede confirmação")
+
+	eventsCmd.AddCommand(eventsListCmd)
+	eventsCmd.AddCommand(eventsRemoveCmd)
+}
+
+func runEventsList(cmd *cobra.Command, args []string) error {
+	ticker, _ := cmd.Flags().GetString("ticker")
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	list := w.SortedCorporateEvents(strings.ToUpper(ticker))
+	if len(list) == 0 {
+		fmt.Println("No corporate events registered.")
+		return nil
+	}
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Corporate events (%d)", len(list))))
+	fmt.Println()
+
+	fmt.Printf("%-12s  %-10s  %-8s  %s\n", "ID", "DATE", "TICKER", "EVENT")
+	for _, e := range list {
+		fmt.Printf("%-12s  %-10s  %-8s  %s\n", e.ID, e.Date.Format("2006-01-02"), e.Ticker, e.Describe())
+		if e.Notes != "" {
+			fmt.Printf("%-12s  %s\n", "", helpStyle.Render(e.Notes))
+		}
+	}
+
+	return nil
+}
+
+func runEventsRemove(cmd *cobra.Command, args []string) error {
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	// Localizar o evento antes de confirmar
+	target, err := w.FindCorporateEvent(args[0])
+	if err != nil {
+		return err
+	}
+
+	asset := w.Assets[target.Ticker]
+	var quantityBefore int
+	if asset != nil {
+		quantityBefore = asset.Quantity
+	}
+
+	fmt.Printf("Event: %s  %s  %s  %s\n", target.ID, target.Date.Format("2006-01-02"), target.Ticker, target.Describe())
+
+	if !confirmEvent(skipConfirm, "Remove this event?") {
+		return nil
+	}
+
+	removed, err := w.RemoveCorporateEvent(target.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := w.Save(w.GetDirPath()); err != nil {
+		return fmt.Errorf("erro ao salvar carteira: %w", err)
+	}
+
+	fmt.Printf("✓ Removed %s (%s)\n", removed.ID, removed.Describe())
+	if asset := w.Assets[removed.Ticker]; asset != nil {
+		fmt.Printf("  %s quantity: %d → %d, avg R$ %s\n", removed.Ticker, quantityBefore, asset.Quantity, asset.AveragePrice.StringFixed(2))
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/john/b3-project/internal/wallet/events"
+	"github.com/spf13/cobra"
+)
+
+var eventsMergeCmd = &cobra.Command{
+	Use:   "merge",
+	Short: "Register an incorporation (old ticker converted into a new one)",
+	Long: `Register an incorporation (incorporação): the shares of the old ticker held
+before the event date are converted into the new ticker at the given ratio.
+
+The cost basis moves with the shares: the invested value stays the same and the
+average price is recomputed for the new quantity. Cash paid per old share, if
+any, reduces the cost of the converted position. The new ticker inherits the
+earnings history and metadata (subtype, segment) of the old one.
+
+The ratio is old:new. For example, 1:0.5 means each old share becomes half a
+new share:
+- 200 shares of OLDC3 become 100 shares of NEWC3
+- Average price of R$ 10.00 becomes R$ 20.00`,
+	Example: `  b3cli events merge --from OLDC3 --to NEWC3 --ratio 1:0.5 --date 2024-06-01
+  b3cli events merge --from OLDC3 --to NEWC3 --ratio 1:1 --cash-per-share 2.15 --date 2024-06-01 --yes`,
+	Args: cobra.NoArgs,
+	RunE: runEventsMerge,
+}
+
+var eventsRenameCmd = &cobra.Command{
+	Use:   "rename",
+	Short: "Register a ticker change (e.g., company rebrand)",
+	Long: `Register a pure ticker change: the position held before the event date, its
+cost basis, earnings history and metadata move to the new ticker unchanged.`,
+	Example: `  b3cli events rename --from OLDC3 --to NEWC3 --date 2024-06-01`,
+	Args:    cobra.NoArgs,
+	RunE:    runEventsRename,
+}
+
+func init() {
+	eventsMergeCmd.Flags().String("from", "", "Ticker incorporado (antigo)")
+	eventsMergeCmd.Flags().String("to", "", "Ticker que recebe as ações (novo)")
+	eventsMergeCmd.Flags().String("ratio", "", "Proporção antigas:novas (ex: 1:0.5)")
+	eventsMergeCmd.Flags().String("cash-per-share", "", "Valor em dinheiro pago por ação antiga (R$)")
+	eventsMergeCmd.Flags().String("date", "", "Data do evento (YYYY-MM-DD)")
+	eventsMergeCmd.Flags().BoolP("yes", "y", false, "Não pede confirmação")
+
+	eventsRenameCmd.Flags().String("from", "", "Ticker antigo")
+	eventsRenameCmd.Flags().String("to", "", "Ticker novo")
+	eventsRenameCmd.Flags().String("date", "", "Data da mudança (YYYY-MM-DD)")
+	eventsRenameCmd.Flags().BoolP("yes", "y", false, "Não pede confirmação")
+
+	eventsCmd.AddCommand(eventsMergeCmd)
+	eventsCmd.AddCommand(eventsRenameCmd)
+}
+
+func runEventsMerge(cmd *cobra.Command, args []string) error {
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	ratioStr, _ := cmd.Flags().GetString("ratio")
+	cashStr, _ := cmd.Flags().GetString("cash-per-share")
+	dateStr, _ := cmd.Flags().GetString("date")
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	if from == "" || to == "" || ratioStr == "" || dateStr == "" {
+		return fmt.Errorf("--from, --to, --ratio and --date are required")
+	}
+
+	ratioFrom, ratioTo, err := events.ParseConversionRatio(ratioStr)
+	if err != nil {
+		return err
+	}
+	cash, err := events.ParseAmount(cashStr)
+	if err != nil {
+		return fmt.Errorf("invalid --cash-per-share: %w", err)
+	}
+	eventDate, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return fmt.Errorf("invalid --date (use YYYY-MM-DD): %w", err)
+	}
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	ticker := strings.ToUpper(from)
+	params := events.MergeParams{TargetTicker: to, From: ratioFrom, To: ratioTo, CashPerShare: cash}
+
+	preview, err := events.PreviewMerge(w, ticker, params, eventDate)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(titleStyle.Render("Preview: Incorporation"))
+	fmt.Println()
+	printConversionPreview(preview)
+
+	if !confirmEvent(skipConfirm, "Register this incorporation?") {
+		return nil
+	}
+
+	result, err := events.ApplyMerge(w, ticker, params, eventDate)
+	if err != nil {
+		return fmt.Errorf("failed to apply merge: %w", err)
+	}
+
+	return saveConversion(w, result)
+}
+
+func runEventsRename(cmd *cobra.Command, args []string) error {
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	dateStr, _ := cmd.Flags().GetString("date")
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	if from == "" || to == "" || dateStr == "" {
+		return fmt.Errorf("--from, --to and --date are required")
+	}
+
+	eventDate, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return fmt.Errorf("invalid --date (use YYYY-MM-DD): %w", err)
+	}
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	ticker := strings.ToUpper(from)
+	preview, err := events.PreviewRename(w, ticker, to, eventDate)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(titleStyle.Render("Preview: Ticker Change"))
+	fmt.Println()
+	printConversionPreview(preview)
+
+	if !confirmEvent(skipConfirm, "Register this ticker change?") {
+		return nil
+	}
+
+	result, err := events.ApplyRename(w, ticker, to, eventDate)
+	if err != nil {
+		return fmt.Errorf("failed to apply ticker change: %w", err)
+	}
+
+	return saveConversion(w, result)
+}
+
+// printConversionPreview shows both assets before and after a merge or ticker change
+func printConversionPreview(r *events.MergeResult) {
+	fmt.Printf("From: %s → To: %s\n", selectedItemStyle.Render(r.Ticker), selectedItemStyle.Render(r.TargetTicker))
+	fmt.Printf("Ratio: %s:%s\n", r.Params.From, r.Params.To)
+	fmt.Printf("Event Date: %s\n\n", r.EventDate.Format("2006-01-02"))
+
+	fmt.Printf("Shares converted: %s %s → %s %s\n", r.SharesConverted, r.Ticker, r.SharesReceived, r.TargetTicker)
+	if r.CashReceived.IsPositive() {
+		fmt.Printf("Cash received: R$ %s (reduces the cost basis)\n", r.CashReceived.StringFixed(2))
+	}
+	fmt.Printf("Transactions affected: %d (imported records are kept as-is)\n", r.TransactionsConverted)
+	if r.EarningsCarried > 0 {
+		fmt.Printf("Earnings history carried over: %d\n", r.EarningsCarried)
+	}
+	fmt.Println()
+
+	fmt.Printf("  %-8s qty %d → ~%d\n", r.Ticker, r.SourceQuantityBefore, r.SourceQuantityAfter)
+	fmt.Printf("  %-8s qty %d → ~%d\n\n", r.TargetTicker, r.TargetQuantityBefore, r.TargetQuantityAfter)
+}
+
+// saveConversion saves the wallet and prints the new positions
+func saveConversion(w *wallet.Wallet, r *events.MergeResult) error {
+	if err := w.Save(w.GetDirPath()); err != nil {
+		return fmt.Errorf("failed to save wallet: %w", err)
+	}
+
+	fmt.Printf("✓ Event registered (%s)\n", r.EventID)
+	fmt.Printf("  %-8s qty %d\n", r.Ticker, r.SourceQuantityAfter)
+	fmt.Printf("  %-8s qty %d, avg R$ %s, invested R$ %s\n",
+		r.TargetTicker, r.TargetQuantityAfter, r.TargetPriceAfter.StringFixed(2), r.InvestedAfter.StringFixed(2))
+
+	return nil
+}
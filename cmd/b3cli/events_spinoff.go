@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/john/b3-project/internal/wallet/events"
+	"github.com/spf13/cobra"
+)
+
+var eventsSpinOffCmd = &cobra.Command{
+	Use:   "spinoff",
+	Short: "Register a spin-off (cisão) creating new tickers",
+	Long: `Register a spin-off (cisão): part of the company is split into one or more new
+companies and shareholders receive shares of the new tickers.
+
+The cost basis of the position held before the event date is split by the
+percentages published by the company. Each new ticker receives its share of the
+cost and shares at its ratio (parent:new, whole shares only); the parent keeps
+the remaining cost with the same quantity.
+
+Each --spin is TICKER:PERCENT[:N:M]. For example, NEWA3:20:1:0.5 moves 20% of
+the cost to NEWA3 and credits half a NEWA3 share per parent share:
+- 100 shares of OLDC3 at R$ 10.00 (cost R$ 1000.00)
+- OLDC3 keeps 100 shares at R$ 8.00
+- NEWA3 receives 50 shares at R$ 4.00 (cost R$ 200.00)`,
+	Example: `  b3cli events spinoff --from OLDC3 --spin NEWA3:20:1:0.5 --date 2024-06-01
+  b3cli events spinoff --from OLDC3 --spin NEWA3:20 --spin NEWB3:10:1:1 --date 2024-06-01 --yes`,
+	Args: cobra.NoArgs,
+	RunE: runEventsSpinOff,
+}
+
+func init() {
+	eventsSpinOffCmd.Flags().String("from", "", "Ticker cindido (original)")
+	eventsSpinOffCmd.Flags().StringArray("spin", nil, "Ativo novo TICKER:PERCENTUAL[:N:M] (repetível)")
+	eventsSpinOffCmd.Flags().String("date", "", "Data do evento (YYYY-MM-DD)")
+	eventsSpinOffCmd.Flags().BoolP("yes", "y", false, "Não pede confirmação")
+
+	eventsCmd.AddCommand(eventsSpinOffCmd)
+}
+
+func runEventsSpinOff(cmd *cobra.Command, args []string) error {
+	from, _ := cmd.Flags().GetString("from")
+	specs, _ := cmd.Flags().GetStringArray("spin")
+	dateStr, _ := cmd.Flags().GetString("date")
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	if from == "" || len(specs) == 0 || dateStr == "" {
+		return fmt.Errorf("--from, --spin and --date are required")
+	}
+
+	targets := make([]wallet.SpinOffTarget, 0, len(specs))
+	for _, spec := range specs {
+		target, err := events.ParseSpinOffTarget(spec)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, target)
+	}
+
+	eventDate, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return fmt.Errorf("invalid --date (use YYYY-MM-DD): %w", err)
+	}
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	ticker := strings.ToUpper(from)
+	preview, err := events.PreviewSpinOff(w, ticker, targets, eventDate)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(titleStyle.Render("Preview: Spin-off"))
+	fmt.Println()
+	fmt.Printf("Asset: %s\n", selectedItemStyle.Render(preview.Ticker))
+	fmt.Printf("Event Date: %s\n", preview.EventDate.Format("2006-01-02"))
+	fmt.Printf("Eligible shares (held before the date): %s\n", preview.SharesHeld)
+	for _, target := range preview.Targets {
+		fmt.Printf("  → %-8s %s%% of the cost, ratio %s:%s\n", target.Ticker, target.CostPercent, target.RatioFrom, target.RatioTo)
+	}
+	fmt.Println()
+	printSpinOffPositions(preview, "~")
+
+	if !confirmEvent(skipConfirm, "Register this spin-off?") {
+		return nil
+	}
+
+	result, err := events.ApplySpinOff(w, ticker, targets, eventDate)
+	if err != nil {
+		return fmt.Errorf("failed to apply spin-off: %w", err)
+	}
+
+	if err := w.Save(w.GetDirPath()); err != nil {
+		return fmt.Errorf("failed to save wallet: %w", err)
+	}
+
+	fmt.Printf("✓ Event registered (%s)\n", result.EventID)
+	printSpinOffPositions(result, "")
+
+	return nil
+}
+
+// printSpinOffPositions shows the parent and each new ticker before and after the spin-off
+func printSpinOffPositions(r *events.SpinOffResult, approx string) {
+	fmt.Printf("  %-8s  %-26s  %s\n", "TICKER", "BEFORE", "AFTER")
+	for _, ticker := range r.Tickers {
+		before, after := r.Before[ticker], r.After[ticker]
+		fmt.Printf("  %-8s  %-26s  %s%s\n", ticker, formatPosition(before), approx, formatPosition(after))
+	}
+	fmt.Println()
+}
+
+func formatPosition(p wallet.PositionPreview) string {
+	if p.Quantity == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d @ R$ %s", p.Quantity, p.AveragePrice.StringFixed(2))
+}
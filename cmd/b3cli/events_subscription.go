@@ -0,0 +1,283 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/john/b3-project/internal/wallet/events"
+	"github.com/spf13/cobra"
+)
+
+var eventsSubscriptionCmd = &cobra.Command{
+	Use:   "subscription",
+	Short: "Track subscription rights (received, exercised, sold, expired)",
+	Long: `Track the lifecycle of subscription rights (direitos de subscrição):
+
+- receive:  rights credited by the company over the parent asset (usually at no cost)
+- exercise: rights exercised at the exercise price; the parent purchase is dated
+            on the settlement date. Partial exercise is supported
+- expire:   rights not exercised nor sold leave the wallet as a loss
+- status:   summary with exercised quantity and price, sold rights with realized
+            result and expired rights
+
+Rights bought or sold on the market come from the imported B3 files. Each step is
+stored as a corporate event and can be undone with 'b3cli events remove'.`,
+}
+
+var eventsSubscriptionReceiveCmd = &cobra.Command{
+	Use:   "receive",
+	Short: "Register subscription rights received",
+	Example: `  b3cli events subscription receive --right MXRF12 --parent MXRF11 --quantity 20 --date 2024-03-01
+  b3cli events subscription receive --right MXRF12 --parent MXRF11 --quantity 20 --unit-cost 0.10 --date 2024-03-01`,
+	Args: cobra.NoArgs,
+	RunE: runEventsSubscriptionReceive,
+}
+
+var eventsSubscriptionExerciseCmd = &cobra.Command{
+	Use:   "exercise",
+	Short: "Register the exercise of subscription rights",
+	Long: `Register the exercise of subscription rights at the exercise price.
+
+The parent receives a purchase dated on the settlement date, costing the
+exercise price plus the average cost of the rights consumed. Without --parent,
+the parent of the received rights is used.`,
+	Example: `  b3cli events subscription exercise --right MXRF12 --quantity 15 --price 9.50 --settlement 2024-04-01`,
+	Args:    cobra.NoArgs,
+	RunE:    runEventsSubscriptionExercise,
+}
+
+var eventsSubscriptionExpireCmd = &cobra.Command{
+	Use:     "expire",
+	Short:   "Register the expiry of the remaining subscription rights",
+	Example: `  b3cli events subscription expire --right MXRF12 --date 2024-04-30`,
+	Args:    cobra.NoArgs,
+	RunE:    runEventsSubscriptionExpire,
+}
+
+var eventsSubscriptionStatusCmd = &cobra.Command{
+	Use:   "status [right]",
+	Short: "Show the lifecycle summary of subscription rights",
+	Example: `  b3cli events subscription status
+  b3cli events subscription status MXRF12`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runEventsSubscriptionStatus,
+}
+
+func init() {
+	eventsSubscriptionReceiveCmd.Flags().String("right", "", "Ticker do direito (ex: MXRF12)")
+	eventsSubscriptionReceiveCmd.Flags().String("parent", "", "Ticker do ativo pai (ex: MXRF11)")
+	eventsSubscriptionReceiveCmd.Flags().String("quantity", "", "Quantidade de direitos recebidos")
+	eventsSubscriptionReceiveCmd.Flags().String("unit-cost", "", "Custo unitário atribuído aos direitos (R$, padrão zero)")
+	eventsSubscriptionReceiveCmd.Flags().String("date", "", "Data do crédito dos direitos (YYYY-MM-DD)")
+
+	eventsSubscriptionExerciseCmd.Flags().String("right", "", "Ticker do direito (ex: MXRF12)")
+	eventsSubscriptionExerciseCmd.Flags().String("parent", "", "Ticker do ativo pai (padrão: o dos direitos recebidos)")
+	eventsSubscriptionExerciseCmd.Flags().String("quantity", "", "Quantidade de direitos exercidos")
+	eventsSubscriptionExerciseCmd.Flags().String("price", "", "Preço de exercício por ação (R$)")
+	eventsSubscriptionExerciseCmd.Flags().String("settlement", "", "Data de liquidação (YYYY-MM-DD)")
+
+	eventsSubscriptionExpireCmd.Flags().String("right", "", "Ticker do direito (ex: MXRF12)")
+	eventsSubscriptionExpireCmd.Flags().String("date", "", "Data de vencimento (YYYY-MM-DD)")
+
+	for _, c := range []*cobra.Command{eventsSubscriptionReceiveCmd, eventsSubscriptionExerciseCmd, eventsSubscriptionExpireCmd} {
+		c.Flags().BoolP("yes", "y", false, "Não pede confirmação")
+		eventsSubscriptionCmd.AddCommand(c)
+	}
+	eventsSubscriptionCmd.AddCommand(eventsSubscriptionStatusCmd)
+	eventsCmd.AddCommand(eventsSubscriptionCmd)
+}
+
+func runEventsSubscriptionReceive(cmd *cobra.Command, args []string) error {
+	right, _ := cmd.Flags().GetString("right")
+	parent, _ := cmd.Flags().GetString("parent")
+	quantityStr, _ := cmd.Flags().GetString("quantity")
+	costStr, _ := cmd.Flags().GetString("unit-cost")
+	dateStr, _ := cmd.Flags().GetString("date")
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	if right == "" || parent == "" || quantityStr == "" || dateStr == "" {
+		return fmt.Errorf("--right, --parent, --quantity and --date are required")
+	}
+
+	quantity, err := events.ParseAmount(quantityStr)
+	if err != nil {
+		return fmt.Errorf("invalid --quantity: %w", err)
+	}
+	unitCost, err := events.ParseAmount(costStr)
+	if err != nil {
+		return fmt.Errorf("invalid --unit-cost: %w", err)
+	}
+	eventDate, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return fmt.Errorf("invalid --date (use YYYY-MM-DD): %w", err)
+	}
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Rights received: %s %s over %s at R$ %s each on %s\n",
+		quantity, strings.ToUpper(right), strings.ToUpper(parent), unitCost.StringFixed(2), eventDate.Format("2006-01-02"))
+	if !confirmEvent(skipConfirm, "Register these rights?") {
+		return nil
+	}
+
+	result, err := events.ReceiveRights(w, right, parent, quantity, unitCost, eventDate)
+	if err != nil {
+		return fmt.Errorf("failed to register rights: %w", err)
+	}
+
+	return saveSubscription(w, result)
+}
+
+func runEventsSubscriptionExercise(cmd *cobra.Command, args []string) error {
+	right, _ := cmd.Flags().GetString("right")
+	parent, _ := cmd.Flags().GetString("parent")
+	quantityStr, _ := cmd.Flags().GetString("quantity")
+	priceStr, _ := cmd.Flags().GetString("price")
+	dateStr, _ := cmd.Flags().GetString("settlement")
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	if right == "" || quantityStr == "" || priceStr == "" || dateStr == "" {
+		return fmt.Errorf("--right, --quantity, --price and --settlement are required")
+	}
+
+	quantity, err := events.ParseAmount(quantityStr)
+	if err != nil {
+		return fmt.Errorf("invalid --quantity: %w", err)
+	}
+	price, err := events.ParseAmount(priceStr)
+	if err != nil {
+		return fmt.Errorf("invalid --price: %w", err)
+	}
+	settlement, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return fmt.Errorf("invalid --settlement (use YYYY-MM-DD): %w", err)
+	}
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Exercise: %s %s at R$ %s, settled on %s (R$ %s)\n",
+		quantity, strings.ToUpper(right), price.StringFixed(2), settlement.Format("2006-01-02"),
+		quantity.Mul(price).StringFixed(2))
+	if !confirmEvent(skipConfirm, "Register this exercise?") {
+		return nil
+	}
+
+	result, err := events.ExerciseRights(w, right, parent, quantity, price, settlement)
+	if err != nil {
+		return fmt.Errorf("failed to register exercise: %w", err)
+	}
+
+	return saveSubscription(w, result)
+}
+
+func runEventsSubscriptionExpire(cmd *cobra.Command, args []string) error {
+	right, _ := cmd.Flags().GetString("right")
+	dateStr, _ := cmd.Flags().GetString("date")
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+
+	if right == "" || dateStr == "" {
+		return fmt.Errorf("--right and --date are required")
+	}
+
+	eventDate, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return fmt.Errorf("invalid --date (use YYYY-MM-DD): %w", err)
+	}
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Expire the remaining %s rights on %s\n", strings.ToUpper(right), eventDate.Format("2006-01-02"))
+	if !confirmEvent(skipConfirm, "Register the expiry?") {
+		return nil
+	}
+
+	result, err := events.ExpireRights(w, right, eventDate)
+	if err != nil {
+		return fmt.Errorf("failed to register expiry: %w", err)
+	}
+
+	return saveSubscription(w, result)
+}
+
+func runEventsSubscriptionStatus(cmd *cobra.Command, args []string) error {
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	tickers := w.SubscriptionRights()
+	if len(args) == 1 {
+		tickers = []string{strings.ToUpper(args[0])}
+	}
+	if len(tickers) == 0 {
+		fmt.Println("No subscription rights registered.")
+		return nil
+	}
+
+	for _, ticker := range tickers {
+		status, err := w.SubscriptionStatus(ticker)
+		if err != nil {
+			return err
+		}
+		printSubscriptionStatus(status)
+	}
+
+	return nil
+}
+
+// saveSubscription saves the wallet and prints the new state of the rights and the parent
+func saveSubscription(w *wallet.Wallet, r *events.SubscriptionResult) error {
+	if err := w.Save(w.GetDirPath()); err != nil {
+		return fmt.Errorf("failed to save wallet: %w", err)
+	}
+
+	fmt.Printf("✓ Event registered (%s)\n", r.EventID)
+	if r.ParentQuantityAfter != r.ParentQuantityBefore {
+		fmt.Printf("  %-8s qty %d → %d, avg R$ %s → R$ %s\n", r.ParentTicker,
+			r.ParentQuantityBefore, r.ParentQuantityAfter,
+			r.ParentPriceBefore.StringFixed(2), r.ParentPriceAfter.StringFixed(2))
+	}
+	fmt.Println()
+	printSubscriptionStatus(r.Status)
+
+	return nil
+}
+
+// printSubscriptionStatus shows the lifecycle summary of a subscription right
+func printSubscriptionStatus(s *wallet.SubscriptionStatus) {
+	parent := s.ParentTicker
+	if parent == "" {
+		parent = "?"
+	}
+	fmt.Println(titleStyle.Render(fmt.Sprintf("%s (rights of %s)", s.RightTicker, parent)))
+	fmt.Printf("  Received:   %s\n", s.Received)
+	if s.Bought.IsPositive() {
+		fmt.Printf("  Bought:     %s\n", s.Bought)
+	}
+	for _, e := range s.Exercises {
+		fmt.Printf("  Exercised:  %s @ R$ %s (settled %s)\n", e.Quantity, e.UnitCost.StringFixed(2), e.Date.Format("2006-01-02"))
+	}
+	if s.Sold.IsPositive() {
+		fmt.Printf("  Sold:       %s for R$ %s (result R$ %s)\n", s.Sold, s.SaleProceeds.StringFixed(2), s.SaleResult.StringFixed(2))
+	}
+	if s.Expired.IsPositive() {
+		fmt.Printf("  Expired:    %s (loss R$ %s)\n", s.Expired, s.ExpiredLoss.StringFixed(2))
+	}
+	fmt.Printf("  Remaining:  %s", s.Remaining)
+	if s.Remaining.IsPositive() {
+		fmt.Printf(" (avg cost R$ %s)", s.AverageCost.StringFixed(2))
+	}
+	fmt.Println()
+	fmt.Printf("  Realized:   R$ %s\n\n", s.RealizedTotal.StringFixed(2))
+}
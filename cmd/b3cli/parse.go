@@ -88,6 +88,7 @@ func runParse(cmd *cobra.Command, args []string) error {
 
 	totalAdded := 0
 	totalDuplicates := 0
+	var earningChecks []wallet.EarningCheck
 
 	// Processar arquivos de transações
 	if len(transactionFiles) > 0 {
@@ -121,6 +122,10 @@ func runParse(cmd *cobra.Command, args []string) error {
 		}
 
 		fmt.Printf("  ✓ Proventos: %d adicionados, %d duplicados\n", added, duplicates)
+		earningChecks = w.CheckEarnings(newEarnings)
+		if len(earningChecks) > 0 {
+			fmt.Printf("  ⚠ Proventos a verificar: %d (quantidade diferente da posição na data-com)\n", len(earningChecks))
+		}
 		totalAdded += added
 		totalDuplicates += duplicates
 	}
@@ -144,7 +149,7 @@ func runParse(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Total duplicados (ignorados): %d\n\n", totalDuplicates)
 
 	// Iniciar interface Bubble Tea
-	p := tea.NewProgram(initialParseResultsModel(w), tea.WithAltScreen())
+	p := tea.NewProgram(initialParseResultsModel(w, earningChecks), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("erro ao executar interface: %w", err)
 	}
@@ -12,8 +12,12 @@ import (
 
 type parseResultsModel struct {
 	wallet *wallet.Wallet
+	checks []wallet.EarningCheck // Proventos importados com quantidade divergente na data-com
 }
 
+// parseResultsMaxChecks limita as divergências exibidas (as demais via earnings list --check)
+const parseResultsMaxChecks = 10
+
 var (
 	parseResultsTitleStyle = lipgloss.NewStyle().
 				Bold(true).
@@ -42,14 +46,18 @@ var (
 					Foreground(lipgloss.Color("141")).
 					Bold(true)
 
+	parseResultsWarningStyle = lipgloss.NewStyle().
+					Foreground(lipgloss.Color("214"))
+
 	parseResultsHelpStyle = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("241")).
 				MarginTop(1)
 )
 
-func initialParseResultsModel(w *wallet.Wallet) parseResultsModel {
+func initialParseResultsModel(w *wallet.Wallet, checks []wallet.EarningCheck) parseResultsModel {
 	return parseResultsModel{
 		wallet: w,
+		checks: checks,
 	}
 }
 
@@ -95,6 +103,31 @@ func (m parseResultsModel) View() string {
 	b.WriteString(parseResultsValueStyle.Render(fmt.Sprintf("%d", len(m.wallet.Assets))))
 	b.WriteString("\n")
 
+	// Proventos com quantidade divergente da posição na data-com
+	if len(m.checks) > 0 {
+		b.WriteString("\n")
+		b.WriteString(parseResultsHeaderStyle.Render(fmt.Sprintf("=== PROVENTOS A VERIFICAR (%d) ===", len(m.checks))))
+		b.WriteString("\n")
+		b.WriteString(parseResultsTableHeaderStyle.Render(fmt.Sprintf("%-10s | %-8s | %8s | %8s | %-11s | %s",
+			"Pagamento", "Ticker", "Qtd", "Carteira", "Data-com", "Diagnóstico")))
+		b.WriteString("\n")
+
+		for i, c := range m.checks {
+			if i == parseResultsMaxChecks {
+				b.WriteString(parseResultsLabelStyle.Render(fmt.Sprintf("... e mais %d (veja 'b3cli earnings list --check')",
+					len(m.checks)-parseResultsMaxChecks)))
+				b.WriteString("\n")
+				break
+			}
+			b.WriteString(parseResultsLabelStyle.Render(fmt.Sprintf("%-10s | ", c.Earning.Date.Format("02/01/2006"))))
+			b.WriteString(parseResultsTickerStyle.Render(fmt.Sprintf("%-8s", c.Ticker)))
+			b.WriteString(parseResultsLabelStyle.Render(fmt.Sprintf(" | %8s | %8s | %-11s | ",
+				c.Earning.Quantity.StringFixed(0), c.Held.StringFixed(0), recordDateLabel(c))))
+			b.WriteString(parseResultsWarningStyle.Render(describeEarningCheck(c)))
+			b.WriteString("\n")
+		}
+	}
+
 	// Seção de ativos
 	b.WriteString("\n")
 	b.WriteString(parseResultsHeaderStyle.Render("=== ATIVOS ==="))
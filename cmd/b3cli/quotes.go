@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/john/b3-project/internal/config"
+	"github.com/john/b3-project/internal/quotes"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/spf13/cobra"
+)
+
+var quotesCmd = &cobra.Command{
+	Use:   "quotes",
+	Short: "Gerencia as cotações dos ativos da carteira",
+	Long: `Busca e exibe as cotações usadas para calcular o valor de mercado da carteira.
+
+As cotações vêm de um provedor configurado em config.yaml e ficam guardadas em
+quotes.json, no diretório da wallet, para que 'b3cli assets overview' funcione
+offline com o último preço conhecido.
+
+Provedores disponíveis:
+- file: arquivo CSV (ticker,price[,date]) ou JSON local, ex: exportado de uma planilha
+- http: endpoint JSON, uma requisição por ticker
+
+Fechamentos históricos são importados dos arquivos COTAHIST da B3 com
+'b3cli quotes import' e usados por 'b3cli assets value'.
+
+Exemplo de config.yaml:
+
+  quotes:
+    provider: http
+    url: https://api.exemplo.com/quote/{ticker}?token=SEU_TOKEN
+    price_field: results.0.regularMarketPrice
+    max_age: 15m`,
+}
+
+var quotesUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Atualiza as cotações dos ativos em carteira",
+	Long: `Busca no provedor configurado as cotações dos ativos em carteira e atualiza o
+cache da wallet.
+
+Cotações mais novas que quotes.max_age não são buscadas de novo (use --force).
+Com --file, lê os preços do arquivo informado em vez do provedor configurado.`,
+	Example: `  b3cli quotes update
+  b3cli quotes update --file precos.csv
+  b3cli quotes update --force`,
+	Args: cobra.NoArgs,
+	RunE: runQuotesUpdate,
+}
+
+var quotesListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "Lista as cotações em cache",
+	Example: `  b3cli quotes list`,
+	Args:    cobra.NoArgs,
+	RunE:    runQuotesList,
+}
+
+func init() {
+	quotesUpdateCmd.Flags().String("file", "", "Arquivo CSV/JSON de preços (ignora o provedor configurado)")
+	quotesUpdateCmd.Flags().Bool("force", false, "Busca todas as cotações, mesmo as recentes")
+
+	quotesCmd.AddCommand(quotesUpdateCmd)
+	quotesCmd.AddCommand(quotesListCmd)
+}
+
+func runQuotesUpdate(cmd *cobra.Command, args []string) error {
+	file, _ := cmd.Flags().GetString("file")
+	force, _ := cmd.Flags().GetBool("force")
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	_, err = refreshQuotes(w, file, force, true)
+	return err
+}
+
+func runQuotesList(cmd *cobra.Command, args []string) error {
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	cache, err := quotes.LoadCache(w.GetDirPath())
+	if err != nil {
+		return err
+	}
+
+	list := cache.All()
+	if len(list) == 0 {
+		fmt.Println("Nenhuma cotação em cache. Use 'b3cli quotes update'.")
+		return nil
+	}
+
+	active := w.GetActiveAssets()
+	fmt.Printf("%-8s %12s  %-16s  %s\n", "TICKER", "PREÇO", "DATA", "FONTE")
+	for _, q := range list {
+		marker := ""
+		if _, held := active[q.Ticker]; !held {
+			marker = "  (fora da carteira)"
+		}
+		fmt.Printf("%-8s %12s  %-16s  %s%s\n",
+			q.Ticker, "R$ "+q.Price.StringFixed(2), q.Time.Local().Format("02/01/2006 15:04"), q.Source, marker)
+	}
+
+	return nil
+}
+
+// refreshQuotes atualiza o cache de cotações dos ativos em carteira e o retorna
+// file substitui o provedor configurado; force ignora quotes.max_age.
+// Com verbose, exibe o resumo da atualização
+func refreshQuotes(w *wallet.Wallet, file string, force, verbose bool) (*quotes.Cache, error) {
+	provider, maxAge, err := quoteProvider(file)
+	if err != nil {
+		return nil, err
+	}
+	if force {
+		maxAge = 0
+	}
+
+	cache, err := quotes.LoadCache(w.GetDirPath())
+	if err != nil {
+		return nil, err
+	}
+
+	tickers := make([]string, 0)
+	for ticker, asset := range w.GetActiveAssets() {
+		if asset.IsSubscription {
+			continue
+		}
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	updated, missing, fetchErr := quotes.Refresh(ctx, provider, cache, tickers, maxAge, time.Now())
+
+	var failures *quotes.FetchError
+	if fetchErr != nil && !errors.As(fetchErr, &failures) {
+		return nil, fmt.Errorf("erro ao buscar cotações em %s: %w", provider.Name(), fetchErr)
+	}
+
+	if err := cache.Save(); err != nil {
+		return nil, err
+	}
+
+	if verbose {
+		fmt.Printf("✓ %d cotação(ões) atualizada(s) via %s\n", updated, provider.Name())
+		if skipped := len(tickers) - updated - len(missing) - failureCount(failures); skipped > 0 {
+			fmt.Printf("  %d ainda recente(s) no cache (use --force para buscar de novo)\n", skipped)
+		}
+		if len(missing) > 0 {
+			fmt.Printf("⚠ Sem cotação no provedor: %s\n", strings.Join(missing, ", "))
+		}
+	}
+	if failures != nil {
+		fmt.Println("⚠ Falha ao buscar:")
+		tickers := make([]string, 0, len(failures.Errors))
+		for ticker := range failures.Errors {
+			tickers = append(tickers, ticker)
+		}
+		sort.Strings(tickers)
+		for _, ticker := range tickers {
+			fmt.Printf("  - %s: %v\n", ticker, failures.Errors[ticker])
+		}
+	}
+
+	return cache, nil
+}
+
+// failureCount retorna a quantidade de tickers com falha (0 se não houve)
+func failureCount(failures *quotes.FetchError) int {
+	if failures == nil {
+		return 0
+	}
+	return len(failures.Errors)
+}
+
+// quoteProvider monta o provedor de cotações a partir de config.yaml
+// Um arquivo informado na linha de comando tem precedência sobre a configuração
+func quoteProvider(file string) (quotes.QuoteProvider, time.Duration, error) {
+	if file != "" {
+		return quotes.FileProvider{Path: file}, 0, nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao carregar configuração: %w", err)
+	}
+	if cfg.Quotes == nil || cfg.Quotes.Provider == "" {
+		return nil, 0, fmt.Errorf("nenhum provedor de cotações configurado: adicione a seção 'quotes' em config.yaml ou use --file")
+	}
+
+	maxAge, err := cfg.Quotes.GetMaxAge()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch cfg.Quotes.Provider {
+	case "file":
+		if cfg.Quotes.File == "" {
+			return nil, 0, fmt.Errorf("quotes.file não definido em config.yaml")
+		}
+		return quotes.FileProvider{Path: cfg.Quotes.File}, maxAge, nil
+	case "http":
+		if cfg.Quotes.URL == "" {
+			return nil, 0, fmt.Errorf("quotes.url não definido em config.yaml")
+		}
+		return quotes.HTTPProvider{
+			URLTemplate: cfg.Quotes.URL,
+			PriceField:  cfg.Quotes.PriceField,
+			TimeField:   cfg.Quotes.TimeField,
+			Headers:     cfg.Quotes.Headers,
+		}, maxAge, nil
+	default:
+		return nil, 0, fmt.Errorf("quotes.provider desconhecido em config.yaml (%q): use file ou http", cfg.Quotes.Provider)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/john/b3-project/internal/quotes"
+	"github.com/spf13/cobra"
+)
+
+var quotesImportCmd = &cobra.Command{
+	Use:   "import <arquivo> [arquivo...]",
+	Short: "Importa fechamentos diários de arquivos COTAHIST da B3",
+	Long: `Importa o histórico de fechamentos diários dos arquivos COTAHIST da B3
+(séries históricas), baixados manualmente do site da B3 em formato TXT ou ZIP.
+
+Os fechamentos ficam no diretório prices/ da wallet, um arquivo por ticker, e
+permitem avaliar a carteira em qualquer data passada ('b3cli assets value')
+sem depender de uma API online.
+
+Por padrão apenas os tickers da carteira (incluindo os já vendidos) são
+importados; use --all para importar todos. Só o mercado à vista (lote padrão)
+é considerado. Importar o mesmo período de novo substitui os fechamentos.`,
+	Example: `  b3cli quotes import COTAHIST_A2023.ZIP COTAHIST_A2024.ZIP
+  b3cli quotes import COTAHIST_M062024.TXT
+  b3cli quotes import COTAHIST_A2024.TXT --all`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runQuotesImport,
+}
+
+var quotesHistoryCmd = &cobra.Command{
+	Use:   "history <ticker>",
+	Short: "Exibe os fechamentos diários importados de um ticker",
+	Example: `  b3cli quotes history PETR4
+  b3cli quotes history PETR4 --from 2024-01-01 --to 2024-03-31`,
+	Args: cobra.ExactArgs(1),
+	RunE: runQuotesHistory,
+}
+
+func init() {
+	quotesImportCmd.Flags().Bool("all", false, "Importa todos os tickers do arquivo, não só os da carteira")
+	quotesHistoryCmd.Flags().String("from", "", "Data inicial (YYYY-MM-DD)")
+	quotesHistoryCmd.Flags().String("to", "", "Data final (YYYY-MM-DD)")
+
+	quotesCmd.AddCommand(quotesImportCmd)
+	quotesCmd.AddCommand(quotesHistoryCmd)
+}
+
+func runQuotesImport(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	var keep func(string) bool
+	if !all {
+		keep = func(ticker string) bool {
+			_, ok := w.Assets[ticker]
+			return ok
+		}
+	}
+
+	history := quotes.OpenHistory(w.GetDirPath())
+	tickers := make(map[string]bool)
+	totalAdded := 0
+
+	for _, path := range args {
+		closes, stats, err := quotes.ReadCotahistFile(path, keep)
+		if err != nil {
+			return fmt.Errorf("erro ao ler %s: %w", path, err)
+		}
+
+		added := 0
+		for ticker, series := range closes {
+			n, err := history.Merge(ticker, series)
+			if err != nil {
+				return err
+			}
+			added += n
+			tickers[ticker] = true
+		}
+		totalAdded += added
+
+		if stats.Kept == 0 {
+			fmt.Printf("%s: %d registros, nenhum fechamento de ativos da carteira\n", path, stats.Records)
+			continue
+		}
+		fmt.Printf("%s: %d fechamentos de %d ticker(s) entre %s e %s (%d novos)\n",
+			path, stats.Kept, len(stats.Tickers), stats.From.Format("02/01/2006"), stats.To.Format("02/01/2006"), added)
+	}
+
+	if err := history.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n✓ %d novo(s) dia(s) de pregão em %d ticker(s)\n", totalAdded, len(tickers))
+
+	if !all {
+		missing := make([]string, 0)
+		for ticker := range w.Assets {
+			if !tickers[ticker] {
+				missing = append(missing, ticker)
+			}
+		}
+		if len(missing) > 0 && len(missing) < len(w.Assets) {
+			sort.Strings(missing)
+			fmt.Printf("ℹ  Sem fechamentos nos arquivos: %s\n", strings.Join(missing, ", "))
+		}
+	}
+
+	return nil
+}
+
+func runQuotesHistory(cmd *cobra.Command, args []string) error {
+	ticker := strings.ToUpper(strings.TrimSpace(args[0]))
+	fromStr, _ := cmd.Flags().GetString("from")
+	toStr, _ := cmd.Flags().GetString("to")
+
+	var from, to time.Time
+	var err error
+	if fromStr != "" {
+		if from, err = time.Parse("2006-01-02", fromStr); err != nil {
+			return fmt.Errorf("data inicial inválida: %s", fromStr)
+		}
+	}
+	if toStr != "" {
+		if to, err = time.Parse("2006-01-02", toStr); err != nil {
+			return fmt.Errorf("data final inválida: %s", toStr)
+		}
+	}
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	series, err := quotes.OpenHistory(w.GetDirPath()).Series(ticker)
+	if err != nil {
+		return err
+	}
+	if len(series) == 0 {
+		fmt.Printf("Nenhum fechamento importado para %s. Use 'b3cli quotes import'.\n", ticker)
+		return nil
+	}
+
+	shown := 0
+	fmt.Printf("%-10s %12s\n", "DATA", "FECHAMENTO")
+	for _, c := range series {
+		if (!from.IsZero() && c.Date.Before(from)) || (!to.IsZero() && c.Date.After(to)) {
+			continue
+		}
+		fmt.Printf("%-10s %12s\n", c.Date.Format("02/01/2006"), "R$ "+c.Close.StringFixed(2))
+		shown++
+	}
+	fmt.Printf("\n%d pregão(ões) • série completa de %s a %s\n", shown,
+		series[0].Date.Format("02/01/2006"), series[len(series)-1].Date.Format("02/01/2006"))
+
+	return nil
+}
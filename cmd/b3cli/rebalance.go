@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/john/b3-project/internal/analytics"
+	"github.com/john/b3-project/internal/quotes"
+	"github.com/john/b3-project/internal/rebalance"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var rebalanceCmd = &cobra.Command{
+	Use:   "rebalance",
+	Short: "Sugere compras (e vendas) para aproximar a carteira dos pesos-alvo",
+	Long: `Calcula, a partir de um aporte, quais ativos comprar para aproximar a
+carteira dos pesos-alvo definidos em targets.yaml, no diretório da carteira
+(crie um modelo com 'b3cli rebalance init').
+
+Os pesos podem ser por ticker, segmento ou subtipo. Em um segmento ou
+subtipo, o alvo é dividido igualmente entre os ativos em carteira. As
+compras vão, lote a lote, para o ativo mais abaixo do alvo enquanto houver
+dinheiro; com 'fractional: true' compras menores que o lote padrão vão para
+o mercado fracionário (ticker + F). Ordens abaixo de 'min_order' são
+descartadas e o valor vai para o próximo ativo.
+
+Com --sell, posições acima do alvo são vendidas e o valor entra no aporte.
+
+Os ativos são avaliados pelas cotações em cache (veja 'b3cli quotes').
+Ativos sem cotação entram nos pesos pelo custo, mas não recebem ordens;
+ativos fora dos alvos são ignorados. Nada é registrado na carteira: execute
+as ordens na corretora e importe o extrato normalmente.`,
+	Example: `  b3cli rebalance --contribution 5000
+  b3cli rebalance --contribution 5000 --sell --refresh
+  b3cli rebalance init --by subtype`,
+	Args: cobra.NoArgs,
+	RunE: runRebalance,
+}
+
+var rebalanceInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Cria targets.yaml com os pesos atuais da carteira",
+	Long: `Cria targets.yaml no diretório da carteira com os pesos atuais (pelo valor
+de mercado, ou pelo custo sem cotação) no nível escolhido. Edite o arquivo
+com os pesos desejados antes de usar 'b3cli rebalance'.
+
+Ativos cujo subtipo indica fundo imobiliário recebem lote de 1 cota.`,
+	Example: `  b3cli rebalance init
+  b3cli rebalance init --by segment`,
+	Args: cobra.NoArgs,
+	RunE: runRebalanceInit,
+}
+
+func init() {
+	rebalanceCmd.Flags().String("contribution", "0", "Valor do aporte (R$)")
+	rebalanceCmd.Flags().Bool("sell", false, "Permite vender posições acima do alvo")
+	rebalanceCmd.Flags().Bool("refresh", false, "Atualiza as cotações antes de calcular")
+
+	rebalanceInitCmd.Flags().String("by", "ticker", "Nível dos pesos: ticker, segment ou subtype")
+	rebalanceCmd.AddCommand(rebalanceInitCmd)
+}
+
+func runRebalance(cmd *cobra.Command, args []string) error {
+	contributionStr, _ := cmd.Flags().GetString("contribution")
+	sell, _ := cmd.Flags().GetBool("sell")
+	refresh, _ := cmd.Flags().GetBool("refresh")
+
+	contribution, err := decimal.NewFromString(strings.Replace(strings.TrimSpace(contributionStr), ",", ".", 1))
+	if err != nil || contribution.IsNegative() {
+		return fmt.Errorf("aporte inválido: %s", contributionStr)
+	}
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	targets, err := rebalance.LoadTargets(w.GetDirPath())
+	if err != nil {
+		return fmt.Errorf("%w (crie um modelo com 'b3cli rebalance init')", err)
+	}
+
+	var cache *quotes.Cache
+	if refresh {
+		cache, err = refreshQuotes(w, "", false, false)
+	} else {
+		cache, err = quotes.LoadCache(w.GetDirPath())
+	}
+	if err != nil {
+		return err
+	}
+
+	plan, err := rebalance.Build(w, targets, cache.Prices(), rebalance.Options{Contribution: contribution, AllowSells: sell})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Rebalanceamento com aporte de R$ %s", contribution.StringFixed(2))))
+	fmt.Println()
+
+	fmt.Printf("%-24s %7s %7s %7s %14s %14s\n", strings.ToUpper(levelLabel(plan.By)), "ALVO", "ATUAL", "APÓS", "VALOR", "APÓS ORDENS")
+	for _, g := range plan.Groups {
+		fmt.Printf("%-24s %6.1f%% %6.1f%% %6.1f%% %14s %14s\n",
+			truncate(g.Key, 24), g.TargetWeight*100, g.Weight*100, g.AfterWeight*100,
+			g.Value.StringFixed(2), g.After.StringFixed(2))
+	}
+
+	fmt.Println()
+	if len(plan.Orders) == 0 {
+		fmt.Println("Nenhuma ordem: a carteira já está no alvo ou o aporte não cobre um lote.")
+	} else {
+		fmt.Println(selectedItemStyle.Render("Ordens"))
+		fmt.Printf("%-8s %-7s %8s %12s %14s  %s\n", "TICKER", "ORDEM", "QTD", "PREÇO", "VALOR", "EXECUÇÃO")
+		for _, o := range plan.Orders {
+			fmt.Printf("%-8s %-7s %8d %12s %14s  %s\n",
+				o.Ticker, o.Side, o.Quantity, o.Price.StringFixed(2), o.Value().StringFixed(2), orderExecution(o))
+		}
+	}
+
+	fmt.Println()
+	if plan.Sold.IsPositive() {
+		fmt.Printf("Vendas: R$ %s • ", plan.Sold.StringFixed(2))
+	}
+	fmt.Printf("Compras: R$ %s • Sobra: R$ %s\n", plan.Bought.StringFixed(2), plan.Leftover.StringFixed(2))
+
+	for _, warning := range plan.Warnings {
+		fmt.Printf("⚠ %s\n", warning)
+	}
+	if len(plan.Unpriced) > 0 {
+		fmt.Printf("⚠ Sem cotação (sem ordens): %s\n", strings.Join(plan.Unpriced, ", "))
+		fmt.Println("  Atualize com 'b3cli quotes update' ou use --refresh.")
+	}
+	if len(plan.Ignored) > 0 {
+		fmt.Printf("Fora dos alvos (ignorados): %s\n", strings.Join(plan.Ignored, ", "))
+	}
+
+	return nil
+}
+
+// orderExecution descreve como a ordem é executada: lote padrão e/ou fracionário
+func orderExecution(o rebalance.Order) string {
+	var parts []string
+	if lots := o.RoundLot(); lots > 0 {
+		parts = append(parts, fmt.Sprintf("%d em lote padrão (%s)", lots, o.Ticker))
+	}
+	if odd := o.OddLot(); odd > 0 {
+		parts = append(parts, fmt.Sprintf("%d no fracionário (%sF)", odd, o.Ticker))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// levelLabel traduz o nível dos pesos para os cabeçalhos
+func levelLabel(level rebalance.Level) string {
+	switch level {
+	case rebalance.BySegment:
+		return "segmento"
+	case rebalance.BySubType:
+		return "subtipo"
+	default:
+		return "ticker"
+	}
+}
+
+func runRebalanceInit(cmd *cobra.Command, args []string) error {
+	by, _ := cmd.Flags().GetString("by")
+
+	var dimension analytics.Dimension
+	switch rebalance.Level(by) {
+	case rebalance.ByTicker:
+	case rebalance.BySegment:
+		dimension = analytics.BySegment
+	case rebalance.BySubType:
+		dimension = analytics.BySubType
+	default:
+		return fmt.Errorf("nível inválido: %s (use ticker, segment ou subtype)", by)
+	}
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+	if len(w.GetActiveAssets()) == 0 {
+		fmt.Println("Nenhum ativo ativo encontrado na carteira.")
+		return nil
+	}
+
+	cache, err := quotes.LoadCache(w.GetDirPath())
+	if err != nil {
+		return err
+	}
+	// Por ticker, os pesos vêm da concentração das posições, igual em qualquer dimensão
+	allocated := dimension
+	if allocated == "" {
+		allocated = analytics.BySubType
+	}
+	allocation, err := analytics.Allocate(w, allocated, cache.Prices())
+	if err != nil {
+		return err
+	}
+
+	targets := &rebalance.Targets{
+		By:         rebalance.Level(by),
+		Weights:    make(map[string]decimal.Decimal),
+		Fractional: true,
+		LotSize:    rebalance.DefaultLotSize,
+		Lots:       make(map[string]int),
+	}
+	if dimension == "" {
+		for _, weight := range allocation.Assets.Weights {
+			targets.Weights[weight.Ticker] = decimal.NewFromFloat(weight.Weight * 100).Round(1)
+		}
+	} else {
+		// Ativos sem classificação ficam de fora: os rótulos entre parênteses não são segmentos
+		for _, slice := range allocation.Slices {
+			if !strings.HasPrefix(slice.Key, "(") {
+				targets.Weights[slice.Key] = decimal.NewFromFloat(slice.MarketWeight * 100).Round(1)
+			}
+		}
+	}
+	if len(targets.Weights) == 0 {
+		return fmt.Errorf("nenhum ativo classificado por %s; defina com 'b3cli assets manage'", levelLabel(targets.By))
+	}
+
+	for ticker, asset := range w.GetActiveAssets() {
+		subType := strings.ToLower(asset.SubType)
+		if strings.Contains(subType, "fii") || strings.Contains(subType, "imobili") {
+			targets.Lots[ticker] = 1
+		}
+	}
+
+	if err := rebalance.SaveTargets(w.GetDirPath(), targets); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ %s criado com %d pesos por %s (valores atuais).\n", rebalance.TargetsFileName, len(targets.Weights), levelLabel(targets.By))
+	fmt.Println("  Edite os pesos desejados e rode 'b3cli rebalance --contribution <valor>'.")
+	return nil
+}
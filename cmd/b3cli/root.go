@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/john/b3-project/internal/config"
@@ -34,11 +35,26 @@ func init() {
 	rootCmd.AddCommand(assetsCmd)
 	rootCmd.AddCommand(earningsCmd)
 	rootCmd.AddCommand(eventsCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(quotesCmd)
+	rootCmd.AddCommand(analyticsCmd)
+	rootCmd.AddCommand(rebalanceCmd)
+	rootCmd.AddCommand(agentCmd)
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(redoCmd)
+}
+
+// setCurrentWallet stores the unlocked wallet in memory, applying user settings
+func setCurrentWallet(w *wallet.Wallet) {
+	if cfg, err := config.Load(); err == nil && cfg.UndoDepth != nil {
+		w.SetUndoDepth(*cfg.UndoDepth)
+	}
+	currentWallet = w
 }
 
 // getOrLoadWallet returns the current wallet, loading it if necessary
-// First tries to load from unlocked session cache (no password needed)
-// If cache doesn't exist, prompts for password to decrypt
+// First asks the unlock agent for the key, then tries the unlocked session cache
+// (no password needed). If neither is available, prompts for password to decrypt
 func getOrLoadWallet() (*wallet.Wallet, error) {
 	// Get current wallet path
 	walletPath, err := config.GetCurrentWallet()
@@ -56,16 +72,26 @@ func getOrLoadWallet() (*wallet.Wallet, error) {
 		return currentWallet, nil
 	}
 
-	// Try to load from unlocked cache first (session persistence)
+	// Try the unlock agent first (key kept in memory only)
+	if w := loadFromAgent(walletPath); w != nil {
+		setCurrentWallet(w)
+		return w, nil
+	}
+
+	// Try to load from unlocked cache (session persistence)
 	if wallet.IsUnlocked(walletPath) {
 		w, err := wallet.LoadUnlocked(walletPath)
 		if err == nil {
 			// Successfully loaded from cache (includes encryption key from session)
-			currentWallet = w
+			setCurrentWallet(w)
 			return w, nil
 		}
-		// If cache is corrupted, fall through to password prompt
-		fmt.Printf("⚠ Unlocked cache corrupted, will prompt for password\n")
+		if errors.Is(err, wallet.ErrSessionExpired) {
+			fmt.Printf("⚠ Session expired - unlocked cache wiped, will prompt for password\n")
+		} else {
+			// If cache is corrupted, fall through to password prompt
+			fmt.Printf("⚠ Unlocked cache corrupted, will prompt for password\n")
+		}
 	}
 
 	// Wallet needs to be unlocked - prompt for password
@@ -81,15 +107,21 @@ func getOrLoadWallet() (*wallet.Wallet, error) {
 		return nil, fmt.Errorf("failed to unlock wallet: %w", err)
 	}
 
-	// Save unlocked cache for future commands in this session
-	if err := w.SaveUnlocked(walletPath); err != nil {
+	// Keep the wallet unlocked for future commands in this session
+	ttl, err := sessionTTL(0)
+	if err != nil {
+		fmt.Printf("⚠ Warning: %v - using default session TTL\n", err)
+		ttl = wallet.DefaultSessionTTL
+	}
+	if _, err := startSession(w, walletPath, ttl); err != nil {
 		// Non-fatal - just warn
-		fmt.Printf("⚠ Warning: failed to save session cache: %v\n", err)
+		fmt.Printf("⚠ Warning: failed to save session: %v\n", err)
 	}
 
 	// Store unlocked wallet in memory
-	currentWallet = w
+	setCurrentWallet(w)
 
 	fmt.Println("✓ Wallet unlocked")
+	printMigration(w)
 	return w, nil
 }
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Desfaz a última alteração da carteira",
+	Long: `Restaura a carteira ao estado anterior à última alteração salva.
+
+Antes de cada alteração (desdobramentos, grupamentos, conversões de subscrição,
+merges de fracionários, importações, edições) uma cópia criptografada do cofre é
+guardada. O comando mostra as operações que serão revertidas e o efeito nos
+ativos, e pede confirmação. A alteração desfeita pode ser refeita com 'b3cli redo'.
+
+A quantidade de operações guardadas é definida por undo_depth em
+~/.b3cli/config.yaml (padrão 10; 0 desativa).`,
+	Example: `  b3cli undo
+  b3cli undo --list
+  b3cli undo --yes`,
+	Args: cobra.NoArgs,
+	RunE: runUndo,
+}
+
+var redoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "Refaz a última alteração desfeita",
+	Long: `Reaplica a última alteração desfeita com 'b3cli undo'.
+
+Qualquer nova alteração na carteira descarta o histórico de redo.`,
+	Example: `  b3cli redo
+  b3cli redo --list`,
+	Args: cobra.NoArgs,
+	RunE: runRedo,
+}
+
+func init() {
+	undoCmd.Flags().BoolP("yes", "y", false, "Não pede confirmação")
+	undoCmd.Flags().Bool("list", false, "Lista as alterações que podem ser desfeitas")
+	redoCmd.Flags().BoolP("yes", "y", false, "Não pede confirmação")
+	redoCmd.Flags().Bool("list", false, "Lista as alterações que podem ser refeitas")
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	return runRestore(cmd, "undo")
+}
+
+func runRedo(cmd *cobra.Command, args []string) error {
+	return runRestore(cmd, "redo")
+}
+
+// runRestore implements undo and redo, which only differ in the stack they use
+func runRestore(cmd *cobra.Command, action string) error {
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+	list, _ := cmd.Flags().GetBool("list")
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	if list {
+		history := w.UndoHistory
+		if action == "redo" {
+			history = w.RedoHistory
+		}
+		snapshots, err := history()
+		if err != nil {
+			return err
+		}
+		printSnapshotList(action, snapshots)
+		return nil
+	}
+
+	preview := w.PreviewUndo
+	if action == "redo" {
+		preview = w.PreviewRedo
+	}
+	snapshot, changes, err := preview()
+	if err != nil {
+		return err
+	}
+
+	verb := "reverted"
+	if action == "redo" {
+		verb = "re-applied"
+	}
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Operations to be %s", verb)))
+	fmt.Println()
+	for _, op := range snapshot.Operations {
+		printJournalEntry(op)
+	}
+	printAssetChanges(changes)
+
+	if !skipConfirm {
+		answer, err := readLine(fmt.Sprintf("Confirm %s? [y/N]: ", action))
+		if err != nil {
+			return fmt.Errorf("erro ao ler confirmação: %w", err)
+		}
+		if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "s") {
+			fmt.Println("Cancelled - nothing changed.")
+			return nil
+		}
+	}
+
+	restore := w.Undo
+	if action == "redo" {
+		restore = w.Redo
+	}
+	restored, _, err := restore()
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", action, err)
+	}
+
+	setCurrentWallet(restored)
+
+	fmt.Printf("✓ %s: %s\n", strings.ToUpper(action[:1])+action[1:], snapshot.Summary())
+	if action == "undo" {
+		fmt.Println("  Run 'b3cli redo' to re-apply it.")
+	}
+
+	return nil
+}
+
+// printAssetChanges shows how quantities and average prices change
+func printAssetChanges(changes []wallet.AssetChange) {
+	if len(changes) == 0 {
+		fmt.Println("No quantity or average price changes.")
+		fmt.Println()
+		return
+	}
+
+	fmt.Println("Effect on assets:")
+	for _, c := range changes {
+		switch {
+		case c.Added:
+			fmt.Printf("  %-8s (new)      qty %d, avg R$ %s\n", c.Ticker, c.QuantityAfter, c.AveragePriceNew.StringFixed(2))
+		case c.Removed:
+			fmt.Printf("  %-8s (removed)  qty %d, avg R$ %s\n", c.Ticker, c.QuantityBefore, c.AveragePriceOld.StringFixed(2))
+		default:
+			fmt.Printf("  %-8s qty %d → %d, avg R$ %s → R$ %s\n",
+				c.Ticker,
+				c.QuantityBefore, c.QuantityAfter,
+				c.AveragePriceOld.StringFixed(2), c.AveragePriceNew.StringFixed(2))
+		}
+	}
+	fmt.Println()
+}
+
+// printSnapshotList lists the snapshots of a stack, newest first
+func printSnapshotList(action string, snapshots []wallet.Snapshot) {
+	if len(snapshots) == 0 {
+		fmt.Printf("Nothing to %s.\n", action)
+		return
+	}
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Changes available to %s (newest first)", action)))
+	fmt.Println()
+	for i, s := range snapshots {
+		fmt.Printf("%2d. %s  %s\n", i+1, s.CreatedAt.Local().Format(time.DateTime), s.Summary())
+	}
+}
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/john/b3-project/internal/config"
 	"github.com/john/b3-project/internal/wallet"
@@ -26,7 +30,8 @@ O arquivo wallet.yaml será criado para armazenar suas transações e ativos.
 Use o comando 'parse' posteriormente para importar transações de arquivos .xlsx.`,
 	Example: `  b3cli wallet create .
   b3cli wallet create ~/meus-investimentos
-  b3cli wallet create /caminho/para/carteira`,
+  b3cli wallet create /caminho/para/carteira
+  b3cli wallet create ~/meus-investimentos --recovery`,
 	Args: cobra.ExactArgs(1),
 	RunE: runWalletCreate,
 }
@@ -37,10 +42,16 @@ var walletOpenCmd = &cobra.Command{
 	Long: `Define a carteira atual para ser usada pelos comandos subsequentes.
 
 Após abrir uma carteira, todos os comandos (parse, assets, etc.) operarão
-automaticamente nesta carteira sem precisar especificar o caminho.`,
+automaticamente nesta carteira sem precisar especificar o caminho.
+
+Sem o agente de desbloqueio, a sessão fica em um cache descriptografado no
+diretório da carteira que expira após o TTL (--ttl, ou session_ttl em
+~/.b3cli/config.yaml, padrão 8h). Sessões expiradas são apagadas e a senha
+é solicitada novamente.`,
 	Example: `  b3cli wallet open data
   b3cli wallet open ~/meus-investimentos
-  b3cli wallet open /caminho/para/carteira`,
+  b3cli wallet open /caminho/para/carteira
+  b3cli wallet open ~/meus-investimentos --ttl 30m`,
 	Args: cobra.ExactArgs(1),
 	RunE: runWalletOpen,
 }
@@ -75,12 +86,30 @@ com a senha mestra para realizar operações.`,
 	RunE:    runWalletLock,
 }
 
+var walletRecoverCmd = &cobra.Command{
+	Use:   "recover [diretório]",
+	Short: "Redefine a senha mestra usando o código de recuperação",
+	Long: `Redefine a senha mestra de uma carteira usando o código de recuperação
+gerado na criação (b3cli wallet create --recovery).
+
+O código de recuperação desbloqueia a chave de criptografia da carteira de forma
+independente da senha. Após informar o código, defina uma nova senha mestra;
+a senha antiga deixa de funcionar. O código de recuperação continua válido.`,
+	Example: `  b3cli wallet recover ~/meus-investimentos`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runWalletRecover,
+}
+
 func init() {
+	walletCreateCmd.Flags().Bool("recovery", false, "Gera um código de recuperação para redefinir a senha mestra")
+	walletOpenCmd.Flags().Duration("ttl", 0, "Validade da sessão desbloqueada em disco (padrão: session_ttl do config ou 8h)")
+
 	walletCmd.AddCommand(walletCreateCmd)
 	walletCmd.AddCommand(walletOpenCmd)
 	walletCmd.AddCommand(walletCurrentCmd)
 	walletCmd.AddCommand(walletCloseCmd)
 	walletCmd.AddCommand(walletLockCmd)
+	walletCmd.AddCommand(walletRecoverCmd)
 }
 
 // readPassword reads a password from stdin without echoing it
@@ -94,6 +123,40 @@ func readPassword(prompt string) (string, error) {
 	return string(bytePassword), nil
 }
 
+// printSessionMode tells the user where the unlocked session is kept
+func printSessionMode(w *wallet.Wallet, viaAgent bool) {
+	if viaAgent {
+		fmt.Println("✓ Session: key held in memory by the unlock agent (nothing decrypted on disk)")
+	} else {
+		fmt.Println("✓ Session: unlocked cache saved on disk (start 'b3cli agent start' to avoid this)")
+		fmt.Printf("✓ Session expires at %s\n", w.Session().ExpiresAt().Local().Format(time.DateTime))
+	}
+}
+
+// printMigration reports a vault schema upgrade performed while loading the wallet
+func printMigration(w *wallet.Wallet) {
+	m := w.Migration()
+	if m == nil {
+		return
+	}
+
+	fmt.Printf("✓ Wallet format upgraded: v%d -> v%d\n", m.FromVersion, m.ToVersion)
+	for _, step := range m.Steps {
+		fmt.Printf("  - %s\n", step)
+	}
+	fmt.Printf("✓ Backup of the previous vault: %s\n", m.BackupPath)
+}
+
+// readLine reads a single line from stdin (echoed), trimming surrounding whitespace
+func readLine(prompt string) (string, error) {
+	fmt.Print(prompt)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
 // readAndConfirmPassword reads a password twice to confirm
 func readAndConfirmPassword() (string, error) {
 	password, err := readPassword("Enter master password: ")
@@ -148,9 +211,24 @@ func runWalletCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("erro ao criar wallet: %w", err)
 	}
 
-	// Salvar cache descriptografado para persistência de sessão
-	if err := w.SaveUnlocked(absPath); err != nil {
-		return fmt.Errorf("erro ao salvar cache de sessão: %w", err)
+	// Gerar código de recuperação (opcional)
+	withRecovery, _ := cmd.Flags().GetBool("recovery")
+	recoveryCode := ""
+	if withRecovery {
+		recoveryCode, err = w.EnableRecovery()
+		if err != nil {
+			return fmt.Errorf("erro ao gerar código de recuperação: %w", err)
+		}
+	}
+
+	// Manter sessão desbloqueada (agente em memória ou cache em disco)
+	ttl, err := sessionTTL(0)
+	if err != nil {
+		return err
+	}
+	viaAgent, err := startSession(w, absPath, ttl)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar sessão: %w", err)
 	}
 
 	// Definir como wallet atual
@@ -159,14 +237,25 @@ func runWalletCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Armazenar wallet desbloqueada globalmente
-	currentWallet = w
+	setCurrentWallet(w)
 
 	fmt.Printf("\n✓ Encrypted wallet created successfully: %s\n", absPath)
 	fmt.Printf("✓ Encryption: AES-256-GCM with Argon2id KDF\n")
 	fmt.Printf("✓ Files created:\n")
 	fmt.Printf("  - %s (encrypted vault)\n", filepath.Join(absPath, "vault.enc"))
 	fmt.Printf("  - %s (encryption metadata)\n", filepath.Join(absPath, "salt.bin"))
+	if recoveryCode != "" {
+		fmt.Printf("  - %s (recovery key)\n", filepath.Join(absPath, "recovery_key.bin"))
+		fmt.Println()
+		fmt.Println("⚠️  RECOVERY CODE - write it down and store it offline. It will NOT be shown again:")
+		fmt.Println()
+		fmt.Printf("    %s\n", recoveryCode)
+		fmt.Println()
+		fmt.Println("⚠️  Anyone with this code can reset your master password.")
+		fmt.Println("    To reset a forgotten password: b3cli wallet recover <directory>")
+	}
 	fmt.Println()
+	printSessionMode(w, viaAgent)
 	fmt.Println("Wallet is now open for the session - no password needed for commands.")
 	fmt.Println()
 	fmt.Println("Next steps:")
@@ -181,6 +270,13 @@ func runWalletCreate(cmd *cobra.Command, args []string) error {
 
 func runWalletOpen(cmd *cobra.Command, args []string) error {
 	dirPath := args[0]
+	ttlFlag, _ := cmd.Flags().GetDuration("ttl")
+
+	// Resolver validade da sessão antes de pedir a senha
+	ttl, err := sessionTTL(ttlFlag)
+	if err != nil {
+		return err
+	}
 
 	// Converter para caminho absoluto
 	absPath, err := filepath.Abs(dirPath)
@@ -205,9 +301,10 @@ func runWalletOpen(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to unlock wallet: %w", err)
 	}
 
-	// Salvar cache descriptografado para persistência de sessão
-	if err := w.SaveUnlocked(absPath); err != nil {
-		return fmt.Errorf("erro ao salvar cache de sessão: %w", err)
+	// Manter sessão desbloqueada (agente em memória ou cache em disco)
+	viaAgent, err := startSession(w, absPath, ttl)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar sessão: %w", err)
 	}
 
 	// Definir como wallet atual
@@ -216,11 +313,13 @@ func runWalletOpen(cmd *cobra.Command, args []string) error {
 	}
 
 	// Armazenar wallet desbloqueada globalmente
-	currentWallet = w
+	setCurrentWallet(w)
 
 	fmt.Printf("\n✓ Wallet unlocked: %s\n", absPath)
+	printMigration(w)
 	fmt.Printf("✓ Transactions: %d\n", len(w.Transactions))
 	fmt.Printf("✓ Assets: %d\n", len(w.Assets))
+	printSessionMode(w, viaAgent)
 	fmt.Println()
 	fmt.Println("Wallet is now open for the session - no password needed for commands:")
 	fmt.Println("  b3cli parse files/*.xlsx")
@@ -240,6 +339,14 @@ func runWalletCurrent(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Wallet atual: %s\n", walletPath)
+
+	if session, err := wallet.UnlockedSession(walletPath); err == nil {
+		if session.Expired(time.Now()) {
+			fmt.Println("Sessão: expirada (a senha será solicitada no próximo comando)")
+		} else {
+			fmt.Printf("Sessão: expira em %s\n", session.ExpiresAt().Local().Format(time.DateTime))
+		}
+	}
 	return nil
 }
 
@@ -252,9 +359,9 @@ func runWalletClose(cmd *cobra.Command, args []string) error {
 	// Obter wallet atual antes de fechar
 	walletPath, _ := config.GetCurrentWallet()
 
-	// Limpar cache descriptografado (remove session persistence)
-	if err := wallet.ClearUnlocked(walletPath); err != nil {
-		fmt.Printf("⚠ Warning: failed to clear unlocked cache: %v\n", err)
+	// Limpar sessão (chave no agente e cache descriptografado)
+	if err := endSession(walletPath); err != nil {
+		fmt.Printf("⚠ Warning: failed to clear session: %v\n", err)
 	}
 
 	// Limpar chaves da memória se houver wallet desbloqueada
@@ -292,9 +399,9 @@ func runWalletLock(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to save wallet before locking: %w", err)
 	}
 
-	// Clear session cache
-	if err := wallet.ClearUnlocked(walletPath); err != nil {
-		fmt.Printf("⚠ Warning: failed to clear unlocked cache: %v\n", err)
+	// Clear session (agent key and unlocked cache)
+	if err := endSession(walletPath); err != nil {
+		fmt.Printf("⚠ Warning: failed to clear session: %v\n", err)
 	}
 
 	// Lock the wallet (clear encryption key from memory)
@@ -310,3 +417,72 @@ func runWalletLock(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runWalletRecover(cmd *cobra.Command, args []string) error {
+	dirPath := args[0]
+
+	// Converter para caminho absoluto
+	absPath, err := filepath.Abs(dirPath)
+	if err != nil {
+		return fmt.Errorf("erro ao resolver caminho: %w", err)
+	}
+
+	// Verificar se a wallet existe
+	if !wallet.Exists(absPath) {
+		return fmt.Errorf("wallet não encontrada em %s", absPath)
+	}
+
+	if !wallet.HasRecovery(absPath) {
+		return fmt.Errorf("a wallet em %s não possui código de recuperação", absPath)
+	}
+
+	// Solicitar código de recuperação
+	code, err := readLine("Enter recovery code: ")
+	if err != nil {
+		return fmt.Errorf("erro ao ler código de recuperação: %w", err)
+	}
+
+	fmt.Println()
+	fmt.Println("Choose a new master password.")
+
+	// Solicitar nova senha mestra
+	password, err := readAndConfirmPassword()
+	if err != nil {
+		return fmt.Errorf("erro ao ler senha: %w", err)
+	}
+
+	// Desbloquear com o código e redefinir a senha
+	w, err := wallet.Recover(absPath, code, password)
+	if err != nil {
+		return err
+	}
+
+	// Manter sessão desbloqueada (agente em memória ou cache em disco)
+	ttl, err := sessionTTL(0)
+	if err != nil {
+		return err
+	}
+	viaAgent, err := startSession(w, absPath, ttl)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar sessão: %w", err)
+	}
+
+	// Definir como wallet atual
+	if err := config.SetCurrentWallet(absPath); err != nil {
+		return fmt.Errorf("erro ao definir wallet atual: %w", err)
+	}
+
+	// Armazenar wallet desbloqueada globalmente
+	setCurrentWallet(w)
+
+	fmt.Printf("\n✓ Master password reset: %s\n", absPath)
+	printMigration(w)
+	fmt.Printf("✓ Transactions: %d\n", len(w.Transactions))
+	fmt.Printf("✓ Assets: %d\n", len(w.Assets))
+	fmt.Println()
+	printSessionMode(w, viaAgent)
+	fmt.Println("The old password no longer unlocks this wallet.")
+	fmt.Println("Your recovery code remains valid - keep it stored offline.")
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/spf13/cobra"
+)
+
+var walletHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Mostra o histórico de operações que alteraram a carteira",
+	Long: `Exibe o journal de auditoria da carteira: cada operação que alterou os dados
+(importações, compras, vendas, proventos, merges, eventos corporativos, edição
+de metadados) com data, comando, parâmetros e o hash do estado antes e depois.
+
+O journal é criptografado com a chave da carteira e cada entrada é assinada e
+encadeada à anterior. Entradas alteradas, removidas ou reordenadas são apontadas
+na verificação. O cofre guarda a entrada mais recente a cada gravação, então
+apagar as últimas entradas do journal também é detectado.`,
+	Example: `  b3cli wallet history
+  b3cli wallet history --limit 0
+  b3cli wallet history --ticker PETR4
+  b3cli wallet history --operation ApplySplit`,
+	Args: cobra.NoArgs,
+	RunE: runWalletHistory,
+}
+
+func init() {
+	walletHistoryCmd.Flags().Int("limit", 20, "Quantidade de entradas mais recentes (0 = todas)")
+	walletHistoryCmd.Flags().String("ticker", "", "Mostra apenas operações envolvendo o ticker")
+	walletHistoryCmd.Flags().String("operation", "", "Mostra apenas operações do tipo informado (ex: AddTransaction)")
+
+	walletCmd.AddCommand(walletHistoryCmd)
+}
+
+func runWalletHistory(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+	ticker, _ := cmd.Flags().GetString("ticker")
+	operation, _ := cmd.Flags().GetString("operation")
+
+	w, err := getOrLoadWallet()
+	if err != nil {
+		return err
+	}
+
+	entries, issues, err := w.History()
+	if err != nil {
+		return fmt.Errorf("erro ao ler histórico: %w", err)
+	}
+
+	if len(entries) == 0 && len(issues) == 0 {
+		fmt.Println("No operations recorded yet.")
+		return nil
+	}
+
+	// Filtrar
+	filtered := make([]wallet.JournalEntry, 0, len(entries))
+	for _, entry := range entries {
+		if operation != "" && !strings.EqualFold(entry.Operation, operation) {
+			continue
+		}
+		if ticker != "" && !entryMentionsTicker(entry, strings.ToUpper(ticker)) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+
+	shown := filtered
+	if limit > 0 && len(shown) > limit {
+		shown = shown[len(shown)-limit:]
+	}
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Wallet history (%d of %d operations)", len(shown), len(entries))))
+	fmt.Println()
+
+	for _, entry := range shown {
+		printJournalEntry(entry)
+	}
+
+	if len(issues) == 0 {
+		fmt.Printf("✓ Journal verified: %d entries, signature chain intact\n", len(entries))
+		return nil
+	}
+
+	fmt.Println(errorStyle.Render(fmt.Sprintf("✗ Journal verification failed (%d issues):", len(issues))))
+	for _, issue := range issues {
+		fmt.Printf("  line %d: %s\n", issue.Line, issue.Reason)
+	}
+
+	return nil
+}
+
+// printJournalEntry prints one journal entry with its inputs
+func printJournalEntry(entry wallet.JournalEntry) {
+	fmt.Printf("#%-4d %s  %s\n",
+		entry.Seq,
+		entry.Timestamp.Local().Format(time.DateTime),
+		selectedItemStyle.Render(entry.Operation))
+	if entry.Command != "" {
+		fmt.Printf("      command: %s\n", entry.Command)
+	}
+	fmt.Printf("      state:   %s → %s\n", shortHash(entry.BeforeHash), shortHash(entry.AfterHash))
+
+	keys := make([]string, 0, len(entry.Inputs))
+	for key := range entry.Inputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("      %s: %s\n", key, entry.Inputs[key])
+	}
+	fmt.Println()
+}
+
+// entryMentionsTicker checks if any input of the entry references the ticker
+func entryMentionsTicker(entry wallet.JournalEntry, ticker string) bool {
+	for _, value := range entry.Inputs {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), ticker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// shortHash abbreviates a state hash for display
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -0,0 +1,57 @@
+// Package agent implements a local unlock agent, similar to ssh-agent.
+//
+// The agent keeps decrypted wallet encryption keys in memory only and serves
+// them to b3cli commands over a Unix socket restricted to the owner (0600).
+// Keys are wiped when the agent stays idle longer than its timeout, so no
+// plaintext wallet data or key material needs to be written to disk.
+package agent
+
+import (
+	"errors"
+	"time"
+)
+
+// Operations supported by the agent protocol
+const (
+	opAdd    = "add"
+	opGet    = "get"
+	opRemove = "remove"
+	opStatus = "status"
+	opStop   = "stop"
+)
+
+// DefaultIdleTimeout is how long the agent keeps keys without any request
+const DefaultIdleTimeout = 30 * time.Minute
+
+var (
+	// ErrNotRunning is returned when no agent is listening on the socket
+	ErrNotRunning = errors.New("agent is not running")
+
+	// ErrKeyNotFound is returned when the agent holds no key for a wallet
+	ErrKeyNotFound = errors.New("agent has no key for this wallet")
+)
+
+// request is a single JSON message sent by the client (one per connection)
+type request struct {
+	Op     string `json:"op"`
+	Wallet string `json:"wallet,omitempty"`
+	Key    []byte `json:"key,omitempty"`
+}
+
+// response is the JSON message returned by the agent
+type response struct {
+	OK      bool     `json:"ok"`
+	Error   string   `json:"error,omitempty"`
+	Key     []byte   `json:"key,omitempty"`
+	Wallets []string `json:"wallets,omitempty"`
+	Expires string   `json:"expires,omitempty"`
+}
+
+// Status describes the state of a running agent
+type Status struct {
+	// Wallets are the wallet directories whose keys are held by the agent
+	Wallets []string
+
+	// ExpiresAt is when the agent wipes its keys if no request arrives
+	ExpiresAt time.Time
+}
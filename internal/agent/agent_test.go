@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// startTestServer starts an agent on a temporary socket and stops it on cleanup
+func startTestServer(t *testing.T, idleTimeout time.Duration) (*Server, *Client) {
+	t.Helper()
+
+	// Unix socket paths are limited in length; keep it short
+	dir, err := os.MkdirTemp("", "b3agent")
+	if err != nil {
+		t.Fatalf("MkdirTemp returned error: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socketPath := filepath.Join(dir, "agent.sock")
+	server := NewServer(socketPath, idleTimeout)
+	if err := server.Listen(); err != nil {
+		t.Fatalf("Listen returned error: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		server.Serve()
+		close(done)
+	}()
+	t.Cleanup(func() {
+		server.Close()
+		<-done
+	})
+
+	return server, NewClient(socketPath)
+}
+
+func TestAgentAddGetRemove(t *testing.T) {
+	_, client := startTestServer(t, time.Minute)
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	if err := client.Add("/wallets/main", key); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+
+	got, err := client.Get("/wallets/main")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if !bytes.Equal(got, key) {
+		t.Errorf("Get = %q, expected %q", got, key)
+	}
+
+	if _, err := client.Get("/wallets/other"); err != ErrKeyNotFound {
+		t.Errorf("Get for unknown wallet returned %v, expected ErrKeyNotFound", err)
+	}
+
+	status, err := client.Status()
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	if len(status.Wallets) != 1 || status.Wallets[0] != "/wallets/main" {
+		t.Errorf("Status.Wallets = %v, expected [/wallets/main]", status.Wallets)
+	}
+
+	if err := client.Remove("/wallets/main"); err != nil {
+		t.Fatalf("Remove returned error: %v", err)
+	}
+	if _, err := client.Get("/wallets/main"); err != ErrKeyNotFound {
+		t.Errorf("Get after Remove returned %v, expected ErrKeyNotFound", err)
+	}
+}
+
+func TestAgentSocketPermissions(t *testing.T) {
+	server, _ := startTestServer(t, time.Minute)
+
+	info, err := os.Stat(server.socketPath)
+	if err != nil {
+		t.Fatalf("Stat returned error: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("socket permissions = %o, expected 600", perm)
+	}
+}
+
+func TestAgentRefusesSharedSocketDir(t *testing.T) {
+	dir, err := os.MkdirTemp("", "b3agent")
+	if err != nil {
+		t.Fatalf("MkdirTemp returned error: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	if err := os.Chmod(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	socketPath := filepath.Join(dir, "agent.sock")
+	if err := NewServer(socketPath, time.Minute).Listen(); err == nil {
+		t.Fatal("expected error for a socket directory readable by other users")
+	}
+	if _, err := os.Stat(socketPath); !os.IsNotExist(err) {
+		t.Error("socket created in a shared directory")
+	}
+}
+
+func TestAgentRefusesSecondInstance(t *testing.T) {
+	server, _ := startTestServer(t, time.Minute)
+
+	if err := NewServer(server.socketPath, time.Minute).Listen(); err == nil {
+		t.Error("expected error when another agent is already running")
+	}
+}
+
+func TestAgentIdleTimeoutWipesKeys(t *testing.T) {
+	server, client := startTestServer(t, 200*time.Millisecond)
+
+	if err := client.Add("/wallets/main", []byte("secret-key")); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for client.IsRunning() && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if client.IsRunning() {
+		t.Fatal("agent still running after idle timeout")
+	}
+	if _, err := client.Get("/wallets/main"); err != ErrNotRunning {
+		t.Errorf("Get after expiry returned %v, expected ErrNotRunning", err)
+	}
+	server.mu.Lock()
+	remaining := len(server.keys)
+	server.mu.Unlock()
+	if remaining != 0 {
+		t.Errorf("agent still holds %d keys after expiry", remaining)
+	}
+	if _, err := os.Stat(server.socketPath); !os.IsNotExist(err) {
+		t.Error("socket file was not removed after expiry")
+	}
+}
+
+func TestAgentStop(t *testing.T) {
+	_, client := startTestServer(t, time.Minute)
+
+	if err := client.Stop(); err != nil {
+		t.Fatalf("Stop returned error: %v", err)
+	}
+	if client.IsRunning() {
+		t.Error("agent still running after Stop")
+	}
+}
@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Client talks to a running agent over its Unix socket
+type Client struct {
+	socketPath string
+}
+
+// NewClient creates a client for the agent listening on socketPath
+func NewClient(socketPath string) *Client {
+	return &Client{socketPath: socketPath}
+}
+
+// IsRunning checks if an agent is accepting connections on the socket
+func (c *Client) IsRunning() bool {
+	conn, err := net.DialTimeout("unix", c.socketPath, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// Add stores the encryption key of a wallet in the agent
+func (c *Client) Add(walletPath string, key []byte) error {
+	_, err := c.call(request{Op: opAdd, Wallet: walletPath, Key: key})
+	return err
+}
+
+// Get retrieves the encryption key of a wallet from the agent
+// Returns ErrKeyNotFound if the agent does not hold a key for the wallet
+func (c *Client) Get(walletPath string) ([]byte, error) {
+	resp, err := c.call(request{Op: opGet, Wallet: walletPath})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Key, nil
+}
+
+// Remove wipes the key of a wallet from the agent
+func (c *Client) Remove(walletPath string) error {
+	_, err := c.call(request{Op: opRemove, Wallet: walletPath})
+	return err
+}
+
+// Status returns which wallets are held by the agent and when it expires
+func (c *Client) Status() (*Status, error) {
+	resp, err := c.call(request{Op: opStatus})
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt, _ := time.Parse(time.RFC3339, resp.Expires)
+	return &Status{
+		Wallets:   resp.Wallets,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Stop asks the agent to wipe all keys and exit
+func (c *Client) Stop() error {
+	_, err := c.call(request{Op: opStop})
+	return err
+}
+
+// call sends a request and decodes the response
+func (c *Client) call(req request) (*response, error) {
+	conn, err := net.DialTimeout("unix", c.socketPath, time.Second)
+	if err != nil {
+		return nil, ErrNotRunning
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("failed to send request to agent: %w", err)
+	}
+
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to read agent response: %w", err)
+	}
+
+	if !resp.OK {
+		if resp.Error == ErrKeyNotFound.Error() {
+			return nil, ErrKeyNotFound
+		}
+		return nil, errors.New(resp.Error)
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,241 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	wcrypto "github.com/john/b3-project/internal/wallet/crypto"
+)
+
+// Server holds decrypted wallet keys in memory and serves them over a Unix socket
+type Server struct {
+	socketPath  string
+	idleTimeout time.Duration
+
+	mu           sync.Mutex
+	keys         map[string][]byte // wallet dir -> encryption key
+	lastActivity time.Time
+
+	listener net.Listener
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewServer creates an agent server for the given socket path
+// A non-positive idleTimeout falls back to DefaultIdleTimeout
+func NewServer(socketPath string, idleTimeout time.Duration) *Server {
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultIdleTimeout
+	}
+
+	return &Server{
+		socketPath:  socketPath,
+		idleTimeout: idleTimeout,
+		keys:        make(map[string][]byte),
+		done:        make(chan struct{}),
+	}
+}
+
+// Listen creates the Unix socket with owner-only permissions
+// Fails if another agent is already listening on the same path, or if the socket
+// directory can be reached by other users
+func (s *Server) Listen() error {
+	if NewClient(s.socketPath).IsRunning() {
+		return fmt.Errorf("an agent is already running on %s", s.socketPath)
+	}
+
+	dir := filepath.Dir(s.socketPath)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	if err := checkSocketDir(dir); err != nil {
+		return err
+	}
+
+	// Remove a stale socket left behind by an agent that was killed
+	if err := os.Remove(s.socketPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+
+	// The socket is created without group/other permissions: there is no window
+	// between bind and chmod in which another user could connect
+	listener, err := listenPrivate(s.socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.socketPath, err)
+	}
+
+	if err := os.Chmod(s.socketPath, 0600); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to restrict socket permissions: %w", err)
+	}
+
+	s.listener = listener
+	s.touch()
+
+	return nil
+}
+
+// Serve accepts requests until the agent is stopped or stays idle past its timeout
+// All keys are wiped from memory before Serve returns
+func (s *Server) Serve() error {
+	if s.listener == nil {
+		return fmt.Errorf("agent is not listening")
+	}
+
+	go s.watchIdle()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.done:
+				return nil
+			default:
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			continue
+		}
+
+		go s.handle(conn)
+	}
+}
+
+// Close wipes all keys, closes the socket and removes the socket file
+func (s *Server) Close() error {
+	var err error
+
+	s.once.Do(func() {
+		close(s.done)
+
+		s.mu.Lock()
+		for walletPath, key := range s.keys {
+			wcrypto.ZeroBytes(key)
+			delete(s.keys, walletPath)
+		}
+		s.mu.Unlock()
+
+		if s.listener != nil {
+			err = s.listener.Close()
+		}
+		os.Remove(s.socketPath)
+	})
+
+	return err
+}
+
+// ExpiresAt returns when the agent will wipe its keys if it stays idle
+func (s *Server) ExpiresAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastActivity.Add(s.idleTimeout)
+}
+
+// touch records activity, postponing the idle expiry
+func (s *Server) touch() {
+	s.mu.Lock()
+	s.lastActivity = time.Now()
+	s.mu.Unlock()
+}
+
+// watchIdle closes the server once the idle timeout elapses without requests
+func (s *Server) watchIdle() {
+	interval := s.idleTimeout / 10
+	if interval > time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if time.Now().After(s.ExpiresAt()) {
+				s.Close()
+				return
+			}
+		}
+	}
+}
+
+// handle processes one request per connection
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	var req request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		json.NewEncoder(conn).Encode(response{Error: "invalid request"})
+		return
+	}
+	defer wcrypto.ZeroBytes(req.Key)
+
+	// Status queries must not keep an otherwise idle agent alive
+	if req.Op != opStatus {
+		s.touch()
+	}
+
+	resp := s.process(req)
+	json.NewEncoder(conn).Encode(resp)
+	wcrypto.ZeroBytes(resp.Key)
+
+	if req.Op == opStop {
+		s.Close()
+	}
+}
+
+// process executes a request against the in-memory key store
+func (s *Server) process(req request) response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch req.Op {
+	case opAdd:
+		if req.Wallet == "" || len(req.Key) == 0 {
+			return response{Error: "wallet and key are required"}
+		}
+		if old, exists := s.keys[req.Wallet]; exists {
+			wcrypto.ZeroBytes(old)
+		}
+		s.keys[req.Wallet] = append([]byte(nil), req.Key...)
+		return response{OK: true}
+
+	case opGet:
+		key, exists := s.keys[req.Wallet]
+		if !exists {
+			return response{Error: ErrKeyNotFound.Error()}
+		}
+		return response{OK: true, Key: append([]byte(nil), key...)}
+
+	case opRemove:
+		if key, exists := s.keys[req.Wallet]; exists {
+			wcrypto.ZeroBytes(key)
+			delete(s.keys, req.Wallet)
+		}
+		return response{OK: true}
+
+	case opStatus:
+		wallets := make([]string, 0, len(s.keys))
+		for walletPath := range s.keys {
+			wallets = append(wallets, walletPath)
+		}
+		sort.Strings(wallets)
+		expires := s.lastActivity.Add(s.idleTimeout).Format(time.RFC3339)
+		return response{OK: true, Wallets: wallets, Expires: expires}
+
+	case opStop:
+		return response{OK: true}
+	}
+
+	return response{Error: fmt.Sprintf("unknown operation %q", req.Op)}
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package agent
+
+import "net"
+
+// listenPrivate binds the socket; permissions are restricted right after by Listen
+func listenPrivate(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
+
+// checkSocketDir has no ownership information to check outside Unix
+func checkSocketDir(dir string) error {
+	return nil
+}
@@ -0,0 +1,36 @@
+//go:build unix
+
+package agent
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// listenPrivate binds the Unix socket under a 0177 umask, so it is created 0600
+func listenPrivate(path string) (net.Listener, error) {
+	old := syscall.Umask(0177)
+	defer syscall.Umask(old)
+	return net.Listen("unix", path)
+}
+
+// checkSocketDir requires the socket directory to be owned by the current user
+// and closed to group and others (e.g. not /tmp)
+func checkSocketDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to inspect socket directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("socket directory %s is not a directory", dir)
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok && int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("socket directory %s is not owned by the current user", dir)
+	}
+	if perm := info.Mode().Perm(); perm&0077 != 0 {
+		return fmt.Errorf("socket directory %s is accessible by other users (mode %o); use a directory with mode 700", dir, perm)
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+// Dimension is the asset attribute the allocation is grouped by
+type Dimension string
+
+const (
+	ByGroup       Dimension = "group" // Type / Segment, as in 'assets overview'
+	ByType        Dimension = "type"
+	BySubType     Dimension = "subtype"
+	BySegment     Dimension = "segment"
+	ByInstitution Dimension = "institution"
+)
+
+// Dimensions lists the supported dimensions in display order
+var Dimensions = []Dimension{ByGroup, ByType, BySubType, BySegment, ByInstitution}
+
+// UnknownInstitution labels positions whose broker cannot be told from the
+// transactions (e.g., shares received in a merge)
+const UnknownInstitution = "(não identificada)"
+
+// AllocationSlice is the part of the portfolio in one group
+type AllocationSlice struct {
+	Key     string
+	Tickers []string
+
+	Cost   decimal.Decimal // Quantity × average price
+	Market decimal.Decimal // Quantity × price (cost when there is no price)
+
+	CostWeight   float64 // Share of the total cost (0.25 = 25%)
+	MarketWeight float64 // Share of the total market value
+}
+
+// Allocation is the portfolio split along a dimension
+type Allocation struct {
+	Dimension Dimension
+	Slices    []AllocationSlice // Largest first (by market value)
+
+	TotalCost   decimal.Decimal
+	TotalMarket decimal.Decimal
+
+	// Unpriced lists the active assets without price, counted at cost in Market
+	Unpriced []string
+
+	// Assets is the concentration of the individual positions (by market value)
+	Assets Concentration
+}
+
+// Concentration measures how much of the portfolio is in few positions
+type Concentration struct {
+	Weights []TickerWeight // Largest first
+
+	// HHI is the Herfindahl–Hirschman index: the sum of squared weights, from
+	// 1/N (evenly split) to 1 (a single position)
+	HHI float64
+}
+
+// TickerWeight is the share of a position in the portfolio
+type TickerWeight struct {
+	Ticker string
+	Weight float64
+}
+
+// Top returns the combined weight of the n largest positions
+func (c Concentration) Top(n int) float64 {
+	total := 0.0
+	for i := 0; i < n && i < len(c.Weights); i++ {
+		total += c.Weights[i].Weight
+	}
+	return total
+}
+
+// EffectivePositions is 1/HHI: how many equal positions would have the same
+// concentration
+func (c Concentration) EffectivePositions() float64 {
+	if c.HHI == 0 {
+		return 0
+	}
+	return 1 / c.HHI
+}
+
+// HHI returns the Herfindahl–Hirschman index of the slices (by market value)
+func (a *Allocation) HHI() float64 {
+	total := 0.0
+	for _, s := range a.Slices {
+		total += s.MarketWeight * s.MarketWeight
+	}
+	return total
+}
+
+// Allocate splits the active assets along a dimension, weighting by cost and by
+// market value with the given prices (ticker -> price; may be empty)
+func Allocate(w *wallet.Wallet, dimension Dimension, prices map[string]decimal.Decimal) (*Allocation, error) {
+	// Portions of each asset assigned to each group key
+	type portion struct {
+		ticker string
+		share  decimal.Decimal
+	}
+	portions := make(map[string][]portion)
+
+	switch dimension {
+	case ByGroup:
+		for key, assets := range w.GroupActiveAssetsByTypeAndSegment() {
+			label := groupLabel(key.Type, "(sem classificação)") + " / " + groupLabel(key.Segment, "(sem segmento)")
+			for _, asset := range assets {
+				portions[label] = append(portions[label], portion{asset.ID, decimal.NewFromInt(1)})
+			}
+		}
+	case ByType, BySubType, BySegment:
+		for ticker, asset := range w.GetActiveAssets() {
+			var label string
+			switch dimension {
+			case ByType:
+				label = groupLabel(asset.Type, "(sem classificação)")
+			case BySubType:
+				label = groupLabel(asset.SubType, "(sem subtipo)")
+			default:
+				label = groupLabel(asset.Segment, "(sem segmento)")
+			}
+			portions[label] = append(portions[label], portion{ticker, decimal.NewFromInt(1)})
+		}
+	case ByInstitution:
+		for ticker, asset := range w.GetActiveAssets() {
+			for institution, share := range institutionShares(asset) {
+				portions[institution] = append(portions[institution], portion{ticker, share})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown allocation dimension %q", dimension)
+	}
+
+	result := &Allocation{Dimension: dimension, TotalCost: decimal.Zero, TotalMarket: decimal.Zero}
+
+	// Cost and market value of each active asset
+	active := w.GetActiveAssets()
+	cost := make(map[string]decimal.Decimal, len(active))
+	market := make(map[string]decimal.Decimal, len(active))
+	for ticker, asset := range active {
+		cost[ticker] = asset.CostBasis()
+		if price, ok := prices[ticker]; ok {
+			market[ticker] = asset.Valuate(price).MarketValue
+		} else {
+			market[ticker] = cost[ticker]
+			result.Unpriced = append(result.Unpriced, ticker)
+		}
+		result.TotalCost = result.TotalCost.Add(cost[ticker])
+		result.TotalMarket = result.TotalMarket.Add(market[ticker])
+	}
+	sort.Strings(result.Unpriced)
+
+	for key, list := range portions {
+		slice := AllocationSlice{Key: key, Cost: decimal.Zero, Market: decimal.Zero}
+		for _, p := range list {
+			slice.Tickers = append(slice.Tickers, p.ticker)
+			slice.Cost = slice.Cost.Add(cost[p.ticker].Mul(p.share))
+			slice.Market = slice.Market.Add(market[p.ticker].Mul(p.share))
+		}
+		sort.Strings(slice.Tickers)
+		slice.Cost, slice.Market = slice.Cost.Round(2), slice.Market.Round(2)
+		slice.CostWeight = Ratio(slice.Cost, result.TotalCost)
+		slice.MarketWeight = Ratio(slice.Market, result.TotalMarket)
+		result.Slices = append(result.Slices, slice)
+	}
+	sort.Slice(result.Slices, func(i, j int) bool {
+		if result.Slices[i].MarketWeight != result.Slices[j].MarketWeight {
+			return result.Slices[i].MarketWeight > result.Slices[j].MarketWeight
+		}
+		return result.Slices[i].Key < result.Slices[j].Key
+	})
+
+	for ticker, value := range market {
+		weight := Ratio(value, result.TotalMarket)
+		result.Assets.Weights = append(result.Assets.Weights, TickerWeight{Ticker: ticker, Weight: weight})
+		result.Assets.HHI += weight * weight
+	}
+	sort.Slice(result.Assets.Weights, func(i, j int) bool {
+		if result.Assets.Weights[i].Weight != result.Assets.Weights[j].Weight {
+			return result.Assets.Weights[i].Weight > result.Assets.Weights[j].Weight
+		}
+		return result.Assets.Weights[i].Ticker < result.Assets.Weights[j].Ticker
+	})
+
+	return result, nil
+}
+
+// institutionShares splits a position by broker using the net quantity bought at
+// each institution. Shares created by corporate events carry no broker and follow
+// the split of the traded shares; without any, the position is unidentified
+func institutionShares(asset *wallet.Asset) map[string]decimal.Decimal {
+	net := make(map[string]decimal.Decimal)
+	for _, tx := range asset.EffectiveNegotiations() {
+		if wallet.IsEventTransaction(tx) {
+			continue
+		}
+		switch tx.Type {
+		case "Compra":
+			net[tx.Institution] = net[tx.Institution].Add(tx.Quantity)
+		case "Venda":
+			net[tx.Institution] = net[tx.Institution].Sub(tx.Quantity)
+		}
+	}
+
+	total := decimal.Zero
+	for institution, qty := range net {
+		if !qty.IsPositive() {
+			delete(net, institution)
+			continue
+		}
+		total = total.Add(qty)
+	}
+	if total.IsZero() {
+		return map[string]decimal.Decimal{UnknownInstitution: decimal.NewFromInt(1)}
+	}
+
+	shares := make(map[string]decimal.Decimal, len(net))
+	for institution, qty := range net {
+		label := groupLabel(institution, UnknownInstitution)
+		shares[label] = shares[label].Add(qty.Div(total))
+	}
+	return shares
+}
+
+// groupLabel replaces an empty attribute by a placeholder
+func groupLabel(value, empty string) string {
+	if value == "" {
+		return empty
+	}
+	return value
+}
+
+// Ratio returns part/total as a float (0 when total is zero)
+func Ratio(part, total decimal.Decimal) float64 {
+	if total.IsZero() {
+		return 0
+	}
+	return part.Div(total).InexactFloat64()
+}
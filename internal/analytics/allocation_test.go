@@ -0,0 +1,122 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+func TestAllocate(t *testing.T) {
+	itsa := buy("ITSA4", date(1, 10), 100, 10) // R$ 1000 at XP
+	itsaNu := buy("ITSA4", date(1, 11), 100, 10)
+	itsaNu.Institution = "NU INVEST"
+	itsaNu.Hash = parser.CalculateHash(&itsaNu)
+
+	w := wallet.NewWallet([]parser.Transaction{
+		itsa,
+		itsaNu,
+		buy("BBAS3", date(1, 10), 100, 20), // R$ 2000 at XP
+		buy("MXRF11", date(1, 10), 100, 10),
+	})
+	for ticker, meta := range map[string][3]string{
+		"ITSA4":  {"renda variável", "ações", "bancos"},
+		"BBAS3":  {"renda variável", "ações", "bancos"},
+		"MXRF11": {"renda variável", "fiis", "papel"},
+	} {
+		if err := w.UpdateAssetMetadata(ticker, meta[0], meta[1], meta[2]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Without prices, market value falls back to cost
+	prices := map[string]decimal.Decimal{"ITSA4": decimal.NewFromInt(12), "BBAS3": decimal.NewFromInt(26)}
+
+	tests := []struct {
+		dimension Dimension
+		expected  map[string][2]float64 // key -> cost weight, market weight
+	}{
+		{BySubType, map[string][2]float64{"ações": {4.0 / 5, 5.0 / 6}, "fiis": {1.0 / 5, 1.0 / 6}}},
+		{BySegment, map[string][2]float64{"bancos": {4.0 / 5, 5.0 / 6}, "papel": {1.0 / 5, 1.0 / 6}}},
+		{ByGroup, map[string][2]float64{"renda variável / bancos": {4.0 / 5, 5.0 / 6}, "renda variável / papel": {1.0 / 5, 1.0 / 6}}},
+		// ITSA4 is split evenly between XP and NU INVEST
+		{ByInstitution, map[string][2]float64{"XP": {4.0 / 5, 4800.0 / 6000}, "NU INVEST": {1.0 / 5, 1200.0 / 6000}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dimension), func(t *testing.T) {
+			a, err := Allocate(w, tt.dimension, prices)
+			if err != nil {
+				t.Fatalf("Allocate returned error: %v", err)
+			}
+			if !a.TotalCost.Equal(decimal.NewFromInt(5000)) || !a.TotalMarket.Equal(decimal.NewFromInt(6000)) {
+				t.Errorf("totals = %s / %s, expected 5000 / 6000", a.TotalCost, a.TotalMarket)
+			}
+			if len(a.Unpriced) != 1 || a.Unpriced[0] != "MXRF11" {
+				t.Errorf("unpriced = %v", a.Unpriced)
+			}
+
+			got := make(map[string][2]float64)
+			for _, s := range a.Slices {
+				got[s.Key] = [2]float64{s.CostWeight, s.MarketWeight}
+			}
+			if len(got) != len(tt.expected) {
+				t.Errorf("slices = %v", got)
+			}
+			for key, want := range tt.expected {
+				g := got[key]
+				if math.Abs(g[0]-want[0]) > 1e-9 || math.Abs(g[1]-want[1]) > 1e-9 {
+					t.Errorf("%s = %.4f / %.4f, expected %.4f / %.4f", key, g[0], g[1], want[0], want[1])
+				}
+			}
+			if a.Slices[0].MarketWeight < a.Slices[len(a.Slices)-1].MarketWeight {
+				t.Error("slices should be sorted by market weight")
+			}
+		})
+	}
+}
+
+func TestAllocate_Concentration(t *testing.T) {
+	itsa := buy("ITSA4", date(1, 10), 100, 10) // R$ 1000 at XP
+	itsaNu := buy("ITSA4", date(1, 11), 100, 10)
+	itsaNu.Institution = "NU INVEST"
+	itsaNu.Hash = parser.CalculateHash(&itsaNu)
+
+	w := wallet.NewWallet([]parser.Transaction{
+		itsa,
+		itsaNu,
+		buy("BBAS3", date(1, 10), 100, 20), // R$ 2000 at XP
+		buy("MXRF11", date(1, 10), 100, 10),
+	})
+	for ticker, meta := range map[string][3]string{
+		"ITSA4":  {"renda variável", "ações", "bancos"},
+		"BBAS3":  {"renda variável", "ações", "bancos"},
+		"MXRF11": {"renda variável", "fiis", "papel"},
+	} {
+		if err := w.UpdateAssetMetadata(ticker, meta[0], meta[1], meta[2]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a, err := Allocate(w, ByType, nil)
+	if err != nil {
+		t.Fatalf("Allocate returned error: %v", err)
+	}
+
+	// At cost: BBAS3 2000, ITSA4 2000, MXRF11 1000 of 5000
+	c := a.Assets
+	if len(c.Weights) != 3 || c.Weights[0].Ticker != "BBAS3" || c.Weights[2].Ticker != "MXRF11" {
+		t.Fatalf("weights = %+v", c.Weights)
+	}
+	if math.Abs(c.Top(2)-0.8) > 1e-9 || math.Abs(c.Top(10)-1) > 1e-9 {
+		t.Errorf("top 2 = %.4f, top 10 = %.4f", c.Top(2), c.Top(10))
+	}
+	if math.Abs(c.HHI-0.36) > 1e-9 || math.Abs(c.EffectivePositions()-1/0.36) > 1e-9 {
+		t.Errorf("HHI = %.4f", c.HHI)
+	}
+	if len(a.Slices) != 1 || math.Abs(a.HHI()-1) > 1e-9 {
+		t.Errorf("a single type should have HHI 1, got %.4f", a.HHI())
+	}
+}
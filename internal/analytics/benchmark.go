@@ -0,0 +1,367 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BenchmarksDirName is the directory in the wallet that holds benchmark series
+const BenchmarksDirName = "benchmarks"
+
+// SeriesKind tells how the values of a benchmark series are read
+type SeriesKind string
+
+const (
+	// KindClose is an index level or close (IBOV, IFIX): the return is the ratio
+	// between two levels
+	KindClose SeriesKind = "close"
+
+	// KindDailyRate is a rate in percent per business day (CDI, e.g. 0.043739)
+	KindDailyRate SeriesKind = "daily_rate"
+
+	// KindMonthlyRate is a rate in percent for the month (IPCA variation, e.g. 0.42)
+	KindMonthlyRate SeriesKind = "monthly_rate"
+
+	// KindMonthlyIndex is a monthly index number (IPCA número-índice). Accepted on
+	// import only: it is stored as the equivalent monthly rates
+	KindMonthlyIndex SeriesKind = "monthly_index"
+)
+
+// rateCoverageSlack is how far a rate series may start after (or end before) the
+// requested period and still be considered to cover it (holidays, weekends)
+const rateCoverageSlack = 7 * 24 * time.Hour
+
+// DefaultBenchmarkKind returns the usual layout of the well-known benchmarks
+func DefaultBenchmarkKind(name string) (SeriesKind, bool) {
+	switch strings.ToUpper(name) {
+	case "CDI", "SELIC":
+		return KindDailyRate, true
+	case "IPCA":
+		return KindMonthlyIndex, true
+	case "IBOV", "IFIX", "SMLL", "IDIV":
+		return KindClose, true
+	}
+	return "", false
+}
+
+// BenchmarkPoint is a dated value of a benchmark series
+type BenchmarkPoint struct {
+	Date  time.Time
+	Value float64
+}
+
+// Benchmark is a series used to compare the portfolio returns
+type Benchmark struct {
+	Name   string
+	Kind   SeriesKind
+	Points []BenchmarkPoint // Chronological
+}
+
+// ParseBenchmarkCSV reads "date,value" rows. Accepts the files published by the
+// Central Bank (SGS) and IBGE as downloaded: optional header, ',' or ';'
+// separators, decimal comma, dates as YYYY-MM-DD, DD/MM/YYYY or MM/YYYY
+func ParseBenchmarkCSV(r io.Reader, name string, kind SeriesKind) (*Benchmark, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read benchmark file: %w", err)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.LazyQuotes = true
+	if first, _, _ := bytes.Cut(data, []byte("\n")); bytes.Contains(first, []byte(";")) {
+		reader.Comma = ';'
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse benchmark CSV: %w", err)
+	}
+
+	points := make([]BenchmarkPoint, 0, len(records))
+	for i, record := range records {
+		if len(record) < 2 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		date, dateErr := parseBenchmarkDate(record[0])
+		value, valueErr := parseBenchmarkValue(record[1])
+		if dateErr != nil || valueErr != nil {
+			if i == 0 {
+				continue // header
+			}
+			if dateErr != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, dateErr)
+			}
+			return nil, fmt.Errorf("line %d: %w", i+1, valueErr)
+		}
+		points = append(points, BenchmarkPoint{Date: date, Value: value})
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("no values found in benchmark file")
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Date.Before(points[j].Date) })
+
+	b := &Benchmark{Name: strings.ToUpper(name), Kind: kind, Points: points}
+	switch kind {
+	case KindClose, KindDailyRate, KindMonthlyRate:
+	case KindMonthlyIndex:
+		b.Kind, b.Points = KindMonthlyRate, indexToMonthlyRates(points)
+	default:
+		return nil, fmt.Errorf("unknown series kind %q (use close, daily_rate, monthly_rate or monthly_index)", kind)
+	}
+	if b.Kind == KindClose {
+		for _, p := range b.Points {
+			if p.Value <= 0 {
+				return nil, fmt.Errorf("close must be positive (%s on %s)", strconv.FormatFloat(p.Value, 'f', -1, 64), p.Date.Format("2006-01-02"))
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// indexToMonthlyRates converts index numbers to the variation of each month
+// The first month has no previous index and is dropped
+func indexToMonthlyRates(points []BenchmarkPoint) []BenchmarkPoint {
+	rates := make([]BenchmarkPoint, 0, len(points))
+	for i := 1; i < len(points); i++ {
+		if points[i-1].Value == 0 {
+			continue
+		}
+		rates = append(rates, BenchmarkPoint{
+			Date:  points[i].Date,
+			Value: (points[i].Value/points[i-1].Value - 1) * 100,
+		})
+	}
+	return rates
+}
+
+// Growth returns the growth factor of the benchmark from the end of day from to
+// the end of day to (1.05 = +5%) and the last date the series covers in the
+// period. ok is false when the series does not reach back to from
+func (b *Benchmark) Growth(from, to time.Time) (factor float64, through time.Time, ok bool) {
+	from, to = day(from), day(to)
+	if len(b.Points) == 0 || !to.After(from) {
+		return 1, from, len(b.Points) > 0 && !to.Before(from)
+	}
+
+	switch b.Kind {
+	case KindClose:
+		start, okStart := b.levelOn(from)
+		end, okEnd := b.levelOn(to)
+		if !okStart || !okEnd {
+			return 1, from, false
+		}
+		return end.Value / start.Value, end.Date, true
+
+	case KindDailyRate:
+		if b.Points[0].Date.Sub(from) > rateCoverageSlack {
+			return 1, from, false
+		}
+		factor, through = 1, from
+		for _, p := range b.Points {
+			if p.Date.After(from) && !p.Date.After(to) {
+				factor *= 1 + p.Value/100
+				through = p.Date
+			}
+		}
+		if to.Sub(through) <= rateCoverageSlack {
+			through = to
+		}
+		return factor, through, true
+
+	case KindMonthlyRate:
+		byMonth := make(map[time.Time]float64, len(b.Points))
+		for _, p := range b.Points {
+			byMonth[monthStart(p.Date)] = p.Value
+		}
+		if _, covered := byMonth[monthStart(from.AddDate(0, 0, 1))]; !covered {
+			return 1, from, false
+		}
+
+		factor, through = 1, from
+		for month := monthStart(from.AddDate(0, 0, 1)); !month.After(to); month = month.AddDate(0, 1, 0) {
+			rate, found := byMonth[month]
+			if !found {
+				break
+			}
+			// Partial months count pro rata by calendar days
+			first := maxTime(month, from.AddDate(0, 0, 1))
+			last := minTime(monthEnd(month), to)
+			days := last.Sub(first).Hours()/24 + 1
+			factor *= math.Pow(1+rate/100, days/float64(monthEnd(month).Day()))
+			through = last
+		}
+		return factor, through, true
+	}
+
+	return 1, from, false
+}
+
+// levelOn returns the last point on or before date
+func (b *Benchmark) levelOn(date time.Time) (BenchmarkPoint, bool) {
+	i := sort.Search(len(b.Points), func(i int) bool { return b.Points[i].Date.After(date) })
+	if i == 0 {
+		return BenchmarkPoint{}, false
+	}
+	return b.Points[i-1], true
+}
+
+// SaveBenchmark stores a series in the wallet, replacing a previous one with the
+// same name. The header keeps the kind: "date,close", "date,daily_rate", ...
+func SaveBenchmark(walletDir string, b *Benchmark) error {
+	dir := filepath.Join(walletDir, BenchmarksDirName)
+	// Private like the rest of the wallet directory
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create benchmarks directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	_ = writer.Write([]string{"date", string(b.Kind)})
+	for _, p := range b.Points {
+		_ = writer.Write([]string{p.Date.Format("2006-01-02"), strconv.FormatFloat(p.Value, 'f', -1, 64)})
+	}
+	writer.Flush()
+
+	path := filepath.Join(dir, strings.ToUpper(b.Name)+".csv")
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write benchmark %s: %w", b.Name, err)
+	}
+	return nil
+}
+
+// LoadBenchmark reads a stored series by name
+func LoadBenchmark(walletDir, name string) (*Benchmark, error) {
+	name = strings.ToUpper(name)
+	path := filepath.Join(walletDir, BenchmarksDirName, name+".csv")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("benchmark %s not loaded", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read benchmark %s: %w", name, err)
+	}
+
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+	_, kind, found := strings.Cut(strings.TrimSpace(string(header)), ",")
+	if !found {
+		return nil, fmt.Errorf("benchmark file %s has no header", path)
+	}
+
+	return ParseBenchmarkCSV(bytes.NewReader(data), name, SeriesKind(kind))
+}
+
+// ListBenchmarks returns the names of the stored series
+func ListBenchmarks(walletDir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(walletDir, BenchmarksDirName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read benchmarks directory: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if name := entry.Name(); !entry.IsDir() && strings.HasSuffix(name, ".csv") {
+			names = append(names, strings.TrimSuffix(name, ".csv"))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// parseBenchmarkDate accepts YYYY-MM-DD, DD/MM/YYYY, MM/YYYY and YYYY-MM
+// (monthly dates are the first day of the month)
+func parseBenchmarkDate(value string) (time.Time, error) {
+	value = strings.Trim(strings.TrimSpace(value), `"`)
+	for _, layout := range []string{"2006-01-02", "02/01/2006", "01/2006", "2006-01"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// parseBenchmarkValue accepts "0.043739", "0,043739" and "1.234,56"
+func parseBenchmarkValue(value string) (float64, error) {
+	cleaned := strings.Trim(strings.TrimSpace(value), `"`)
+	if strings.Contains(cleaned, ",") {
+		cleaned = strings.ReplaceAll(strings.ReplaceAll(cleaned, ".", ""), ",", ".")
+	}
+	v, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	return v, nil
+}
+
+// monthStart returns the first day of the month of t
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// BenchmarkComparison is a benchmark measured over the same valuation dates as
+// the portfolio
+type BenchmarkComparison struct {
+	Name    string
+	Return  float64     // Cumulative return up to Through (0.1 = +10%)
+	Through time.Time   // Last date covered by the series (may be before the period end)
+	Covered bool        // False when the series does not reach back to the period start
+	Path    []PathPoint // Cumulative return at each portfolio valuation date covered
+}
+
+// CompareBenchmarks measures each benchmark along the portfolio path
+func CompareBenchmarks(portfolio Returns, benchmarks []*Benchmark) []BenchmarkComparison {
+	result := make([]BenchmarkComparison, 0, len(benchmarks))
+	if len(portfolio.Path) == 0 {
+		return result
+	}
+	start := portfolio.Path[0].Date
+
+	for _, b := range benchmarks {
+		c := BenchmarkComparison{Name: b.Name}
+		factor, through, ok := b.Growth(start, portfolio.To)
+		if !ok {
+			result = append(result, c)
+			continue
+		}
+		c.Return, c.Through, c.Covered = factor-1, through, true
+
+		for _, point := range portfolio.Path {
+			f, t, ok := b.Growth(start, point.Date)
+			if !ok || point.Date.Sub(t) > rateCoverageSlack {
+				break
+			}
+			c.Path = append(c.Path, PathPoint{Date: point.Date, Return: f - 1})
+		}
+		result = append(result, c)
+	}
+
+	return result
+}
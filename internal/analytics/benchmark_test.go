@@ -0,0 +1,84 @@
+package analytics
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBenchmarkCSV_CentralBankLayout(t *testing.T) {
+	// SGS download: quoted header, ';' separator and decimal comma
+	input := "\"data\";\"valor\"\n\"02/01/2024\";\"0,043739\"\n\"03/01/2024\";\"0,043739\"\n"
+	b, err := ParseBenchmarkCSV(strings.NewReader(input), "cdi", KindDailyRate)
+	if err != nil {
+		t.Fatalf("ParseBenchmarkCSV returned error: %v", err)
+	}
+	if b.Name != "CDI" || len(b.Points) != 2 || b.Points[1].Value != 0.043739 {
+		t.Errorf("benchmark = %+v", b)
+	}
+
+	factor, through, ok := b.Growth(date(1, 1), date(1, 3))
+	expected := math.Pow(1.00043739, 2)
+	if !ok || math.Abs(factor-expected) > 1e-12 || !through.Equal(date(1, 3)) {
+		t.Errorf("Growth = %.10f through %s (ok %v), expected %.10f", factor, through, ok, expected)
+	}
+
+	// The series starts long after the period: not covered
+	if _, _, ok := b.Growth(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), date(1, 3)); ok {
+		t.Error("series should not cover a period starting months before it")
+	}
+}
+
+func TestBenchmarkGrowth_MonthlyIndex(t *testing.T) {
+	// IPCA index numbers: +1% in January, +2% in February
+	input := "month,index\n12/2023,100\n01/2024,101\n02/2024,103.02\n"
+	b, err := ParseBenchmarkCSV(strings.NewReader(input), "ipca", KindMonthlyIndex)
+	if err != nil {
+		t.Fatalf("ParseBenchmarkCSV returned error: %v", err)
+	}
+	if b.Kind != KindMonthlyRate || len(b.Points) != 2 {
+		t.Fatalf("index not converted to monthly rates: %+v", b)
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		expected float64
+		through  time.Time
+	}{
+		{"two full months", date(1, 1).AddDate(0, 0, -1), date(2, 29), 1.0302, date(2, 29)},
+		{"half of February", date(1, 31), date(2, 14), math.Pow(1.02, 14.0/29), date(2, 14)},
+		{"beyond the data", date(1, 31), date(4, 30), 1.02, date(2, 29)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factor, through, ok := b.Growth(tt.from, tt.to)
+			if !ok || math.Abs(factor-tt.expected) > 1e-9 || !through.Equal(tt.through) {
+				t.Errorf("Growth = %.6f through %s (ok %v), expected %.6f through %s", factor, through, ok, tt.expected, tt.through)
+			}
+		})
+	}
+}
+
+func TestCompareBenchmarks_Close(t *testing.T) {
+	ibov := &Benchmark{Name: "IBOV", Kind: KindClose, Points: []BenchmarkPoint{
+		{date(1, 2), 130000},
+		{date(1, 31), 127000},
+		{date(2, 29), 129000},
+		{date(3, 28), 128000}, // Mar 29-31: holiday and weekend
+	}}
+	portfolio := Returns{
+		From: date(1, 3),
+		To:   date(3, 31),
+		Path: []PathPoint{{Date: date(1, 2)}, {Date: date(1, 31)}, {Date: date(2, 29)}, {Date: date(3, 31)}},
+	}
+
+	c := CompareBenchmarks(portfolio, []*Benchmark{ibov})[0]
+	if !c.Covered || math.Abs(c.Return-(128000.0/130000-1)) > 1e-12 || !c.Through.Equal(date(3, 28)) {
+		t.Errorf("comparison = %+v", c)
+	}
+	if len(c.Path) != 4 || math.Abs(c.Path[1].Return-(127000.0/130000-1)) > 1e-12 {
+		t.Errorf("path = %+v", c.Path)
+	}
+}
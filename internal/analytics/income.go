@@ -0,0 +1,336 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+// PaymentPattern is how often an asset has paid income
+type PaymentPattern string
+
+const (
+	PatternMonthly    PaymentPattern = "monthly"
+	PatternQuarterly  PaymentPattern = "quarterly"
+	PatternSemiannual PaymentPattern = "semiannual"
+	PatternAnnual     PaymentPattern = "annual"
+	PatternIrregular  PaymentPattern = "irregular"
+	PatternNone       PaymentPattern = "none"
+)
+
+// patternWindow is how far back payments are looked at to infer the pattern
+const patternWindow = 24
+
+// monthlyAverage is how many paying months are averaged for monthly payers
+const monthlyAverage = 3
+
+// InferPattern classifies the payments of the last 24 months up to asOf by the
+// typical gap, in months, between months with a payment
+func InferPattern(dates []time.Time, asOf time.Time) PaymentPattern {
+	last := monthIndex(asOf)
+	seen := make(map[int]bool)
+	var months []int
+	for _, d := range dates {
+		m := monthIndex(d)
+		if d.After(asOf) || m <= last-patternWindow || seen[m] {
+			continue
+		}
+		seen[m] = true
+		months = append(months, m)
+	}
+	if len(months) == 0 {
+		return PatternNone
+	}
+	if len(months) == 1 {
+		return PatternIrregular
+	}
+	sort.Ints(months)
+
+	gaps := make([]int, 0, len(months)-1)
+	for i := 1; i < len(months); i++ {
+		gaps = append(gaps, months[i]-months[i-1])
+	}
+	sort.Ints(gaps)
+	gap := gaps[len(gaps)/2]
+
+	// A payer that stopped (no payment for twice its usual gap) is not projected
+	// as regular anymore
+	if last-months[len(months)-1] > max(2*gap, 13) {
+		return PatternIrregular
+	}
+
+	switch {
+	case gap == 1:
+		return PatternMonthly
+	case gap >= 2 && gap <= 4:
+		return PatternQuarterly
+	case gap >= 5 && gap <= 7:
+		return PatternSemiannual
+	case gap >= 10 && gap <= 14:
+		return PatternAnnual
+	default:
+		return PatternIrregular
+	}
+}
+
+// MonthIncome is the income of a calendar month
+type MonthIncome struct {
+	Month time.Time // First day of the month
+
+	// Expected is the projected income; Announced is the part of it that comes
+	// from announced earnings not received yet
+	Expected  decimal.Decimal
+	Announced decimal.Decimal
+
+	Received decimal.Decimal // Income credited in the month
+}
+
+// AssetIncome is the projected income of an asset
+type AssetIncome struct {
+	Ticker     string
+	Pattern    PaymentPattern
+	Quantity   decimal.Decimal
+	PerShare12 decimal.Decimal // Income per share of the last 12 months
+
+	Expected map[time.Time]decimal.Decimal // By month (first day)
+	Total    decimal.Decimal
+}
+
+// IncomeProjection is the expected income of the next 12 months
+type IncomeProjection struct {
+	AsOf   time.Time
+	Assets []AssetIncome // Largest total first
+	Months []MonthIncome // The 12 months after the month of AsOf
+	Total  decimal.Decimal
+}
+
+// ProjectIncome projects the income of the 12 months after the month of asOf
+// for the current positions
+//
+// Monthly payers repeat the average of their last paying months; the others
+// repeat, per share, what they paid in the same month of the previous year, so
+// quarterly and semiannual calendars keep their months. Announced earnings not
+// received yet replace the projection of their asset in the payment month.
+func ProjectIncome(w *wallet.Wallet, asOf time.Time) *IncomeProjection {
+	asOf = day(asOf)
+	first := monthStart(asOf).AddDate(0, 1, 0)
+	projection := &IncomeProjection{AsOf: asOf, Total: decimal.Zero}
+
+	announced := announcedByMonth(w, asOf)
+	totals := make(map[time.Time]MonthIncome)
+	for i := 0; i < 12; i++ {
+		month := first.AddDate(0, i, 0)
+		totals[month] = MonthIncome{Month: month, Expected: decimal.Zero, Announced: decimal.Zero, Received: decimal.Zero}
+	}
+
+	for ticker, asset := range w.Assets {
+		model := newIncomeModel(asset, asOf)
+		if !model.quantity.IsPositive() {
+			continue
+		}
+		income := AssetIncome{
+			Ticker:     ticker,
+			Pattern:    model.pattern,
+			Quantity:   model.quantity,
+			PerShare12: model.perShare12(),
+			Expected:   make(map[time.Time]decimal.Decimal),
+			Total:      decimal.Zero,
+		}
+		for month, total := range totals {
+			amount, fromAnnouncement := model.expected(month), false
+			if value, ok := announced[ticker][month]; ok {
+				amount, fromAnnouncement = value, true
+			}
+			if amount.IsZero() {
+				continue
+			}
+			income.Expected[month] = amount
+			income.Total = income.Total.Add(amount)
+			total.Expected = total.Expected.Add(amount)
+			if fromAnnouncement {
+				total.Announced = total.Announced.Add(amount)
+			}
+			totals[month] = total
+		}
+		if income.Total.IsPositive() || income.Pattern != PatternNone {
+			projection.Assets = append(projection.Assets, income)
+		}
+	}
+
+	for i := 0; i < 12; i++ {
+		month := totals[first.AddDate(0, i, 0)]
+		month.Expected = month.Expected.Round(2)
+		month.Announced = month.Announced.Round(2)
+		projection.Months = append(projection.Months, month)
+		projection.Total = projection.Total.Add(month.Expected)
+	}
+	sort.Slice(projection.Assets, func(i, j int) bool {
+		if !projection.Assets[i].Total.Equal(projection.Assets[j].Total) {
+			return projection.Assets[i].Total.GreaterThan(projection.Assets[j].Total)
+		}
+		return projection.Assets[i].Ticker < projection.Assets[j].Ticker
+	})
+	return projection
+}
+
+// IncomeCalendar compares expected and received income month by month: the
+// past months before today's (each one projected from the history up to the end
+// of the previous month, as it would have been seen then), the current month and
+// the next ahead months (projected from today)
+func IncomeCalendar(w *wallet.Wallet, today time.Time, past, ahead int) []MonthIncome {
+	today = day(today)
+	current := monthStart(today)
+	received := receivedByMonth(w)
+	announced := announcedByMonth(w, today)
+
+	var calendar []MonthIncome
+	for i := -past; i <= 0; i++ {
+		month := current.AddDate(0, i, 0)
+		asOf := month.AddDate(0, 0, -1)
+		entry := MonthIncome{Month: month, Expected: decimal.Zero, Announced: decimal.Zero, Received: received[month]}
+		for ticker, asset := range w.Assets {
+			amount := newIncomeModel(asset, asOf).expected(month)
+			if value, ok := announced[ticker][month]; ok {
+				amount = value
+				entry.Announced = entry.Announced.Add(value)
+			}
+			entry.Expected = entry.Expected.Add(amount)
+		}
+		entry.Expected, entry.Announced = entry.Expected.Round(2), entry.Announced.Round(2)
+		if entry.Received.IsZero() {
+			entry.Received = decimal.Zero
+		}
+		calendar = append(calendar, entry)
+	}
+
+	if ahead > 0 {
+		projection := ProjectIncome(w, today)
+		for i := 0; i < ahead && i < len(projection.Months); i++ {
+			entry := projection.Months[i]
+			entry.Received = received[entry.Month]
+			if entry.Received.IsZero() {
+				entry.Received = decimal.Zero
+			}
+			calendar = append(calendar, entry)
+		}
+	}
+	return calendar
+}
+
+// incomeModel is the payment history of an asset seen from a date
+type incomeModel struct {
+	pattern  PaymentPattern
+	quantity decimal.Decimal         // Shares held at the end of asOf (today's basis)
+	byMonth  map[int]decimal.Decimal // Income per share by month index
+	asOf     time.Time
+}
+
+func newIncomeModel(asset *wallet.Asset, asOf time.Time) incomeModel {
+	model := incomeModel{
+		quantity: asset.QuantityBefore(asOf.AddDate(0, 0, 1)),
+		byMonth:  make(map[int]decimal.Decimal),
+		asOf:     asOf,
+	}
+
+	var dates []time.Time
+	for _, e := range asset.EarningsHistory() {
+		if parser.IsCapitalReturn(e.Type) || day(e.Date).After(asOf) {
+			continue
+		}
+		dates = append(dates, e.Date)
+		m := monthIndex(e.Date)
+		model.byMonth[m] = model.byMonth[m].Add(incomePerShare(asset, e))
+	}
+	model.pattern = InferPattern(dates, asOf)
+	return model
+}
+
+// expected projects the income of the position in a month after asOf
+func (m incomeModel) expected(month time.Time) decimal.Decimal {
+	if !m.quantity.IsPositive() {
+		return decimal.Zero
+	}
+
+	var perShare decimal.Decimal
+	switch m.pattern {
+	case PatternNone:
+		return decimal.Zero
+	case PatternMonthly:
+		perShare = m.recentMonthlyAverage()
+	default:
+		perShare = m.byMonth[monthIndex(month)-12]
+	}
+	return perShare.Mul(m.quantity).Round(2)
+}
+
+// recentMonthlyAverage averages the income per share of the last paying months
+func (m incomeModel) recentMonthlyAverage() decimal.Decimal {
+	months := make([]int, 0, len(m.byMonth))
+	for month := range m.byMonth {
+		months = append(months, month)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(months)))
+	if len(months) > monthlyAverage {
+		months = months[:monthlyAverage]
+	}
+	if len(months) == 0 {
+		return decimal.Zero
+	}
+
+	total := decimal.Zero
+	for _, month := range months {
+		total = total.Add(m.byMonth[month])
+	}
+	return total.Div(decimal.NewFromInt(int64(len(months))))
+}
+
+// perShare12 is the income per share of the 12 months up to asOf
+func (m incomeModel) perShare12() decimal.Decimal {
+	last := monthIndex(m.asOf)
+	total := decimal.Zero
+	for month, value := range m.byMonth {
+		if month > last-12 {
+			total = total.Add(value)
+		}
+	}
+	return total
+}
+
+// receivedByMonth sums the income credited in each month
+func receivedByMonth(w *wallet.Wallet) map[time.Time]decimal.Decimal {
+	received := make(map[time.Time]decimal.Decimal)
+	for _, asset := range w.Assets {
+		for _, e := range asset.Earnings {
+			if parser.IsCapitalReturn(e.Type) {
+				continue
+			}
+			month := monthStart(e.Date)
+			received[month] = received[month].Add(e.Net())
+		}
+	}
+	return received
+}
+
+// announcedByMonth sums the pending announced earnings by ticker and payment month
+func announcedByMonth(w *wallet.Wallet, today time.Time) map[string]map[time.Time]decimal.Decimal {
+	announced := make(map[string]map[time.Time]decimal.Decimal)
+	for _, a := range w.PendingAnnouncedEarnings() {
+		// The announced value is gross: the projection counts what will be credited
+		_, amount := w.ExpectedAmount(a, today)
+		amount = parser.NetOf(a.Type, amount)
+		month := monthStart(a.PaymentDate)
+		if announced[a.Ticker] == nil {
+			announced[a.Ticker] = make(map[time.Time]decimal.Decimal)
+		}
+		announced[a.Ticker][month] = announced[a.Ticker][month].Add(amount)
+	}
+	return announced
+}
+
+// monthIndex numbers the months continuously (year × 12 + month)
+func monthIndex(t time.Time) int {
+	return t.Year()*12 + int(t.Month()) - 1
+}
@@ -0,0 +1,129 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+func TestInferPattern(t *testing.T) {
+	asOf := ymd(2024, 6, 20)
+	tests := []struct {
+		name     string
+		dates    []time.Time
+		expected PaymentPattern
+	}{
+		{"none", nil, PatternNone},
+		{"single payment", []time.Time{ymd(2024, 3, 10)}, PatternIrregular},
+		{"monthly", []time.Time{ymd(2024, 3, 14), ymd(2024, 4, 15), ymd(2024, 5, 14), ymd(2024, 6, 14)}, PatternMonthly},
+		{"quarterly with two payments in a month", []time.Time{
+			ymd(2023, 8, 1), ymd(2023, 11, 1), ymd(2023, 11, 20), ymd(2024, 2, 1), ymd(2024, 5, 2),
+		}, PatternQuarterly},
+		{"semiannual", []time.Time{ymd(2023, 3, 10), ymd(2023, 9, 10), ymd(2024, 3, 10)}, PatternSemiannual},
+		{"annual", []time.Time{ymd(2022, 8, 1), ymd(2023, 8, 1)}, PatternAnnual},
+		{"stopped paying", []time.Time{ymd(2022, 7, 10), ymd(2022, 8, 10), ymd(2022, 9, 10)}, PatternIrregular},
+		{"future payments ignored", []time.Time{ymd(2024, 5, 10), ymd(2024, 7, 10)}, PatternIrregular},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InferPattern(tt.dates, asOf); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProjectIncome(t *testing.T) {
+	w := wallet.NewWallet([]parser.Transaction{
+		buy("MXRF11", ymd(2023, 12, 1), 100, 10),
+		buy("BBAS3", ymd(2023, 1, 5), 100, 20),
+	})
+	// MXRF11 pays monthly, BBAS3 twice a year
+	for i, total := range []string{"10", "10", "10", "9", "11", "10"} {
+		addEarning(t, w, "MXRF11", ymd(2024, i+1, 14), 100, total)
+	}
+	addEarning(t, w, "BBAS3", ymd(2023, 3, 10), 100, "50")
+	addEarning(t, w, "BBAS3", ymd(2023, 9, 10), 100, "30")
+	addEarning(t, w, "BBAS3", ymd(2024, 3, 10), 100, "60")
+	if _, err := w.AddAnnouncedEarning(wallet.AnnouncedEarning{
+		Ticker: "BBAS3", Type: "Juros Sobre Capital Próprio",
+		RecordDate: ymd(2024, 8, 15), PaymentDate: ymd(2024, 9, 20),
+		UnitPrice: decimal.RequireFromString("0.40"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	p := ProjectIncome(w, ymd(2024, 6, 20))
+
+	if len(p.Months) != 12 || !p.Months[0].Month.Equal(ymd(2024, 7, 1)) {
+		t.Fatalf("expected 12 months from July 2024, got %+v", p.Months)
+	}
+	patterns := map[string]PaymentPattern{}
+	for _, a := range p.Assets {
+		patterns[a.Ticker] = a.Pattern
+	}
+	if patterns["MXRF11"] != PatternMonthly || patterns["BBAS3"] != PatternSemiannual {
+		t.Errorf("unexpected patterns: %v", patterns)
+	}
+
+	// MXRF11: average of the last 3 months (0.10) every month; BBAS3: the
+	// announced JCP (R$ 40 gross, R$ 34 net) in September and last March's R$ 60 in March
+	expected := map[time.Month]string{time.September: "44", time.March: "70"}
+	for _, m := range p.Months {
+		want := "10"
+		if value, ok := expected[m.Month.Month()]; ok {
+			want = value
+		}
+		if !m.Expected.Equal(decimal.RequireFromString(want)) {
+			t.Errorf("%s: expected %s, got %s", m.Month.Format("2006-01"), want, m.Expected)
+		}
+	}
+	if !p.Months[2].Announced.Equal(decimal.NewFromInt(34)) {
+		t.Errorf("expected R$ 34 announced in September, got %s", p.Months[2].Announced)
+	}
+	if !p.Total.Equal(decimal.NewFromInt(214)) {
+		t.Errorf("expected R$ 214 in 12 months, got %s", p.Total)
+	}
+}
+
+func TestIncomeCalendar(t *testing.T) {
+	w := wallet.NewWallet([]parser.Transaction{
+		buy("MXRF11", ymd(2023, 12, 1), 100, 10),
+		buy("BBAS3", ymd(2023, 1, 5), 100, 20),
+	})
+	// MXRF11 pays monthly, BBAS3 twice a year
+	for i, total := range []string{"10", "10", "10", "9", "11", "10"} {
+		addEarning(t, w, "MXRF11", ymd(2024, i+1, 14), 100, total)
+	}
+	addEarning(t, w, "BBAS3", ymd(2023, 3, 10), 100, "50")
+	addEarning(t, w, "BBAS3", ymd(2023, 9, 10), 100, "30")
+	addEarning(t, w, "BBAS3", ymd(2024, 3, 10), 100, "60")
+
+	calendar := IncomeCalendar(w, ymd(2024, 6, 20), 2, 1)
+	if len(calendar) != 4 {
+		t.Fatalf("expected 4 months, got %d", len(calendar))
+	}
+
+	// Each past month is projected from the months before it
+	expected := []struct {
+		month    time.Time
+		expected string
+		received string
+	}{
+		{ymd(2024, 4, 1), "10", "9"},
+		{ymd(2024, 5, 1), "9.67", "11"},
+		{ymd(2024, 6, 1), "10", "10"},
+		{ymd(2024, 7, 1), "10", "0"},
+	}
+	for i, want := range expected {
+		got := calendar[i]
+		if !got.Month.Equal(want.month) || !got.Expected.Equal(decimal.RequireFromString(want.expected)) ||
+			!got.Received.Equal(decimal.RequireFromString(want.received)) {
+			t.Errorf("month %d: expected %s %s/%s, got %s %s/%s", i, want.month.Format("2006-01"), want.expected, want.received,
+				got.Month.Format("2006-01"), got.Expected, got.Received)
+		}
+	}
+}
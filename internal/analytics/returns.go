@@ -0,0 +1,355 @@
+// Package analytics computes performance metrics of a wallet from its
+// transactions, earnings and historical prices.
+//
+// Returns are measured between valuations of the portfolio (daily or at each
+// month end) built with wallet.ValuateAt, so they depend on the closes
+// imported into the price history.
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+// Frequency is how often the portfolio is valued between From and To
+type Frequency string
+
+const (
+	Daily   Frequency = "daily"
+	Monthly Frequency = "monthly"
+)
+
+// ReturnsOptions selects the period and the valuation frequency
+type ReturnsOptions struct {
+	From      time.Time
+	To        time.Time
+	Frequency Frequency // empty is Monthly
+}
+
+// Returns are the metrics of the portfolio or of a single asset over a period
+type Returns struct {
+	Ticker string // Empty for the whole portfolio
+	From   time.Time
+	To     time.Time
+
+	StartValue decimal.Decimal // Market value at the end of the day before From
+	EndValue   decimal.Decimal // Market value at the end of To
+	Invested   decimal.Decimal // Buys in the period
+	Withdrawn  decimal.Decimal // Sales in the period
+	Income     decimal.Decimal // Earnings received in the period
+
+	// TWR is the time-weighted return of the period (0.1 = 10%): the sub-period
+	// returns between valuations, chain-linked, so deposits do not distort it
+	TWR float64
+
+	// Path is the cumulative TWR at each valuation date (starts at 0)
+	Path []PathPoint
+
+	// XIRR is the money-weighted annual return of the cash flows (0.1 = 10% a.a.)
+	// HasXIRR is false when the flows have no rate (e.g., nothing invested)
+	XIRR    float64
+	HasXIRR bool
+
+	// TransferEvent is the corporate event that moved this asset's position to or
+	// from another ticker in the period (merge, rename, spin-off). Its returns are
+	// not meaningful on their own and are left at zero; the portfolio is unaffected
+	TransferEvent string
+}
+
+// PathPoint is the cumulative return at a date (0.1 = +10% since the start)
+type PathPoint struct {
+	Date   time.Time
+	Return float64
+}
+
+// Gain is the result of the period in money: final value plus cash received
+// minus initial value and cash invested
+func (r Returns) Gain() decimal.Decimal {
+	return r.EndValue.Add(r.Withdrawn).Add(r.Income).Sub(r.StartValue).Sub(r.Invested)
+}
+
+// Days is the length of the period in days
+func (r Returns) Days() int {
+	return int(r.To.Sub(r.From).Hours()/24) + 1
+}
+
+// AnnualizedTWR converts the period TWR to a yearly rate
+func (r Returns) AnnualizedTWR() float64 {
+	return math.Pow(1+r.TWR, 365/float64(r.Days())) - 1
+}
+
+// Report holds the portfolio returns and the returns of each asset
+type Report struct {
+	Portfolio Returns
+	Assets    []Returns // Sorted by ticker
+
+	// Unpriced lists, per ticker, the first valuation date without a close. Those
+	// positions were valued at cost, which hides their price changes
+	Unpriced map[string]time.Time
+
+	// Valuations is the number of valuation dates used (including both ends)
+	Valuations int
+}
+
+// flow is a cash flow from the portfolio's side: positive when money comes in
+// (buy), negative when it goes out (sale, earning)
+type flow struct {
+	ticker string
+	date   time.Time
+	amount decimal.Decimal
+	income bool // Earning (otherwise a buy or sale)
+}
+
+// ComputeReturns measures the portfolio and each asset between opts.From and
+// opts.To, valuing the positions with the closes of prices
+func ComputeReturns(w *wallet.Wallet, prices wallet.PriceSource, opts ReturnsOptions) (*Report, error) {
+	from, to := day(opts.From), day(opts.To)
+	if to.Before(from) {
+		return nil, fmt.Errorf("end date %s is before start date %s", to.Format("2006-01-02"), from.Format("2006-01-02"))
+	}
+	frequency := opts.Frequency
+	if frequency == "" {
+		frequency = Monthly
+	}
+	if frequency != Daily && frequency != Monthly {
+		return nil, fmt.Errorf("unknown frequency %q (use daily or monthly)", frequency)
+	}
+
+	start := from.AddDate(0, 0, -1)
+	dates := valuationDates(start, to, frequency)
+
+	// Market value of each ticker at each valuation date
+	report := &Report{Unpriced: make(map[string]time.Time), Valuations: len(dates)}
+	values := make([]map[string]decimal.Decimal, len(dates))
+	for i, date := range dates {
+		values[i] = make(map[string]decimal.Decimal)
+		for ticker, holding := range w.HoldingsAt(date) {
+			price, ok := prices.CloseOn(ticker, date)
+			if !ok {
+				if _, seen := report.Unpriced[ticker]; !seen {
+					report.Unpriced[ticker] = date
+				}
+				values[i][ticker] = holding.CostBasis()
+				continue
+			}
+			values[i][ticker] = holding.Valuate(price).MarketValue
+		}
+	}
+
+	flows := periodFlows(w, start, to)
+	transfers := transferEvents(w, start, to)
+
+	report.Portfolio = measure("", dates, values, flows)
+
+	tickers := make(map[string]bool)
+	for _, snapshot := range values {
+		for ticker := range snapshot {
+			tickers[ticker] = true
+		}
+	}
+	for _, f := range flows {
+		tickers[f.ticker] = true
+	}
+	for ticker := range tickers {
+		if event, ok := transfers[ticker]; ok {
+			r := measure(ticker, dates, values, flows)
+			r.TWR, r.XIRR, r.HasXIRR, r.Path = 0, 0, false, nil
+			r.TransferEvent = event
+			report.Assets = append(report.Assets, r)
+			continue
+		}
+		report.Assets = append(report.Assets, measure(ticker, dates, values, flows))
+	}
+	sort.Slice(report.Assets, func(i, j int) bool { return report.Assets[i].Ticker < report.Assets[j].Ticker })
+
+	return report, nil
+}
+
+// measure computes the returns of a ticker (or of the portfolio when ticker is empty)
+func measure(ticker string, dates []time.Time, values []map[string]decimal.Decimal, flows []flow) Returns {
+	valueAt := func(i int) decimal.Decimal {
+		if ticker != "" {
+			return values[i][ticker]
+		}
+		total := decimal.Zero
+		for _, v := range values[i] {
+			total = total.Add(v)
+		}
+		return total
+	}
+
+	own := make([]flow, 0)
+	for _, f := range flows {
+		if ticker == "" || f.ticker == ticker {
+			own = append(own, f)
+		}
+	}
+
+	r := Returns{
+		Ticker:     ticker,
+		From:       dates[0].AddDate(0, 0, 1),
+		To:         dates[len(dates)-1],
+		StartValue: valueAt(0),
+		EndValue:   valueAt(len(dates) - 1),
+		Invested:   decimal.Zero,
+		Withdrawn:  decimal.Zero,
+		Income:     decimal.Zero,
+	}
+
+	// Time-weighted: Modified Dietz between consecutive valuations, chain-linked
+	growth := 1.0
+	next := 0
+	r.Path = append(make([]PathPoint, 0, len(dates)), PathPoint{Date: dates[0]})
+	for i := 1; i < len(dates); i++ {
+		var sub []flow
+		for next < len(own) && !own[next].date.After(dates[i]) {
+			sub = append(sub, own[next])
+			next++
+		}
+		growth *= 1 + dietz(valueAt(i-1), valueAt(i), dates[i-1], dates[i], sub)
+		r.Path = append(r.Path, PathPoint{Date: dates[i], Return: growth - 1})
+	}
+	r.TWR = growth - 1
+
+	// Money-weighted: the initial value is the first investment, the final value
+	// the last receipt
+	cash := make([]CashFlow, 0, len(own)+2)
+	if r.StartValue.IsPositive() {
+		cash = append(cash, CashFlow{Date: dates[0], Amount: -r.StartValue.InexactFloat64()})
+	}
+	for _, f := range own {
+		cash = append(cash, CashFlow{Date: f.date, Amount: -f.amount.InexactFloat64()})
+		switch {
+		case f.income:
+			r.Income = r.Income.Sub(f.amount)
+		case f.amount.IsPositive():
+			r.Invested = r.Invested.Add(f.amount)
+		default:
+			r.Withdrawn = r.Withdrawn.Sub(f.amount)
+		}
+	}
+	if r.EndValue.IsPositive() {
+		cash = append(cash, CashFlow{Date: dates[len(dates)-1], Amount: r.EndValue.InexactFloat64()})
+	}
+	if rate, err := XIRR(cash); err == nil {
+		r.XIRR, r.HasXIRR = rate, true
+	}
+
+	return r
+}
+
+// dietz is the Modified Dietz return between two valuations:
+// (V1 - V0 - Σflows) / (V0 + Σ weight × flow). Money coming in counts from the
+// start of its day and money going out until the end of its day
+func dietz(v0, v1 decimal.Decimal, t0, t1 time.Time, flows []flow) float64 {
+	length := t1.Sub(t0).Hours() / 24
+	if length <= 0 {
+		return 0
+	}
+
+	net := 0.0
+	weighted := v0.InexactFloat64()
+	for _, f := range flows {
+		amount := f.amount.InexactFloat64()
+		remaining := t1.Sub(f.date).Hours() / 24
+		if amount > 0 {
+			remaining++
+		}
+		net += amount
+		weighted += amount * remaining / length
+	}
+
+	if weighted <= 0 {
+		return 0
+	}
+	return (v1.InexactFloat64() - v0.InexactFloat64() - net) / weighted
+}
+
+// periodFlows collects the cash moved in (start, end]: buys and sales as
+// imported, earnings and the cash of corporate events (fraction auctions,
+// merger cash, rights exercise). The imported amounts are used rather than
+// the effective negotiations, whose buys have their cost reduced by events
+func periodFlows(w *wallet.Wallet, start, end time.Time) []flow {
+	in := func(date time.Time) bool {
+		d := day(date)
+		return d.After(start) && !d.After(end)
+	}
+
+	flows := make([]flow, 0)
+	for ticker, asset := range w.Assets {
+		for _, tx := range asset.Negotiations {
+			if !in(tx.Date) {
+				continue
+			}
+			switch tx.Type {
+			case "Compra":
+				flows = append(flows, flow{ticker: ticker, date: day(tx.Date), amount: tx.Amount})
+			case "Venda":
+				flows = append(flows, flow{ticker: ticker, date: day(tx.Date), amount: tx.Amount.Neg()})
+			}
+		}
+		for _, e := range asset.Earnings {
+			if in(e.Date) {
+				flows = append(flows, flow{ticker: ticker, date: day(e.Date), amount: e.Net().Neg(), income: true})
+			}
+		}
+	}
+	for _, cash := range w.EventCashFlows() {
+		if in(cash.Date) {
+			flows = append(flows, flow{ticker: cash.Ticker, date: day(cash.Date), amount: cash.Amount})
+		}
+	}
+
+	sort.SliceStable(flows, func(i, j int) bool { return flows[i].date.Before(flows[j].date) })
+	return flows
+}
+
+// transferEvents maps each ticker whose position moved to or from another
+// ticker in (start, end] to the event type
+func transferEvents(w *wallet.Wallet, start, end time.Time) map[string]string {
+	transfers := make(map[string]string)
+	for _, e := range w.SortedCorporateEvents("") {
+		d := day(e.Date)
+		if !d.After(start) || d.After(end) || len(e.Targets()) == 0 {
+			continue
+		}
+		transfers[e.Ticker] = e.Type
+		for _, target := range e.Targets() {
+			transfers[target] = e.Type
+		}
+	}
+	return transfers
+}
+
+// valuationDates returns start, the month ends (or every day) in between and end
+func valuationDates(start, end time.Time, frequency Frequency) []time.Time {
+	dates := []time.Time{start}
+	switch frequency {
+	case Daily:
+		for d := start.AddDate(0, 0, 1); d.Before(end); d = d.AddDate(0, 0, 1) {
+			dates = append(dates, d)
+		}
+	case Monthly:
+		for d := monthEnd(start); d.Before(end); d = monthEnd(d.AddDate(0, 0, 1)) {
+			if d.After(start) {
+				dates = append(dates, d)
+			}
+		}
+	}
+	return append(dates, end)
+}
+
+// monthEnd returns the last day of the month of t
+func monthEnd(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+}
+
+// day truncates a time to its calendar date (UTC)
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
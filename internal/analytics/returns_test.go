@@ -0,0 +1,142 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+// stepPrices is a price source where each ticker's close changes on given days
+type stepPrices map[string][]struct {
+	from  time.Time
+	price string
+}
+
+func (s stepPrices) CloseOn(ticker string, on time.Time) (decimal.Decimal, bool) {
+	price, ok := decimal.Zero, false
+	for _, step := range s[ticker] {
+		if !on.Before(step.from) {
+			price, ok = decimal.RequireFromString(step.price), true
+		}
+	}
+	return price, ok
+}
+
+func TestComputeReturns(t *testing.T) {
+	// 100 @ 10 on Jan 10 and 100 @ 12 on Feb 15; R$ 50 of dividends on Mar 10
+	w := wallet.NewWallet([]parser.Transaction{
+		buy("PETR4", date(1, 10), 100, 10),
+		buy("PETR4", date(2, 15), 100, 12),
+	})
+	earning := parser.Earning{Date: date(3, 10), Type: "Dividendo", Ticker: "PETR4", Quantity: decimal.NewFromInt(200), UnitPrice: decimal.RequireFromString("0.25"), TotalAmount: decimal.NewFromInt(50)}
+	earning.Hash = parser.CalculateEarningHash(&earning)
+	if err := w.AddEarning(earning); err != nil {
+		t.Fatalf("AddEarning returned error: %v", err)
+	}
+
+	prices := stepPrices{"PETR4": {
+		{date(1, 1), "10"},
+		{date(1, 20), "11"},
+		{date(2, 14), "12"},
+		{date(3, 1), "13"},
+	}}
+
+	report, err := ComputeReturns(w, prices, ReturnsOptions{From: date(1, 1), To: date(3, 31), Frequency: Daily})
+	if err != nil {
+		t.Fatalf("ComputeReturns returned error: %v", err)
+	}
+
+	p := report.Portfolio
+	if !p.StartValue.IsZero() || !p.EndValue.Equal(decimal.NewFromInt(2600)) {
+		t.Errorf("values = %s → %s, expected 0 → 2600", p.StartValue, p.EndValue)
+	}
+	if !p.Invested.Equal(decimal.NewFromInt(2200)) || !p.Income.Equal(decimal.NewFromInt(50)) || !p.Withdrawn.IsZero() {
+		t.Errorf("flows = invested %s, income %s, withdrawn %s", p.Invested, p.Income, p.Withdrawn)
+	}
+	if !p.Gain().Equal(decimal.NewFromInt(450)) {
+		t.Errorf("gain = %s, expected 450", p.Gain())
+	}
+
+	// 10 → 11 → 13 plus a dividend of 50 on 2600: 1.3 × 2650/2600 - 1
+	if math.Abs(p.TWR-0.325) > 1e-9 {
+		t.Errorf("TWR = %.6f, expected 0.325", p.TWR)
+	}
+	if !p.HasXIRR || p.XIRR <= p.TWR {
+		t.Errorf("XIRR = %.4f (ok %v), expected an annual rate above the period TWR", p.XIRR, p.HasXIRR)
+	}
+
+	if len(report.Assets) != 1 || report.Assets[0].Ticker != "PETR4" || math.Abs(report.Assets[0].TWR-p.TWR) > 1e-9 {
+		t.Errorf("assets = %+v", report.Assets)
+	}
+	if len(report.Unpriced) != 0 {
+		t.Errorf("unpriced = %v", report.Unpriced)
+	}
+
+	// Month-end valuations approximate the same result
+	monthly, err := ComputeReturns(w, prices, ReturnsOptions{From: date(1, 1), To: date(3, 31)})
+	if err != nil {
+		t.Fatalf("ComputeReturns (monthly) returned error: %v", err)
+	}
+	if monthly.Valuations != 4 || math.Abs(monthly.Portfolio.TWR-0.325) > 0.05 {
+		t.Errorf("monthly: %d valuations, TWR %.4f", monthly.Valuations, monthly.Portfolio.TWR)
+	}
+}
+
+func TestComputeReturns_SplitWithAuction(t *testing.T) {
+	// 101 @ 10, then a 2:3 split on Feb 1: 151.5 shares, the half share is
+	// auctioned for R$ 3 (cash received) and the price goes to 7
+	w := wallet.NewWallet([]parser.Transaction{buy("ITSA4", date(1, 10), 101, 10)})
+	if _, err := w.AddCorporateEvent(wallet.CorporateEvent{
+		Type: wallet.EventSplit, Ticker: "ITSA4", Date: date(2, 1),
+		RatioFrom: decimal.NewFromInt(2), RatioTo: decimal.NewFromInt(3), AuctionAmount: decimal.NewFromInt(3),
+	}); err != nil {
+		t.Fatalf("AddCorporateEvent returned error: %v", err)
+	}
+
+	prices := stepPrices{"ITSA4": {
+		{date(1, 1), "10"},
+		{date(2, 1), "7"},
+	}}
+	report, err := ComputeReturns(w, prices, ReturnsOptions{From: date(1, 1), To: date(2, 29), Frequency: Daily})
+	if err != nil {
+		t.Fatalf("ComputeReturns returned error: %v", err)
+	}
+
+	// 151 × 7 = 1057 plus the R$ 3 of the auction, against 1010 invested
+	p := report.Portfolio
+	if !p.EndValue.Equal(decimal.NewFromInt(1057)) {
+		t.Errorf("end value = %s, expected 1057", p.EndValue)
+	}
+	if !p.Invested.Equal(decimal.NewFromInt(1010)) || !p.Withdrawn.Equal(decimal.NewFromInt(3)) {
+		t.Errorf("flows = invested %s, withdrawn %s; expected 1010, 3", p.Invested, p.Withdrawn)
+	}
+	if !p.Gain().Equal(decimal.NewFromInt(50)) {
+		t.Errorf("gain = %s, expected 50", p.Gain())
+	}
+	if math.Abs(p.TWR-50.0/1010) > 0.001 {
+		t.Errorf("TWR = %.4f, expected %.4f", p.TWR, 50.0/1010)
+	}
+}
+
+func TestComputeReturns_UnpricedAndInvalidPeriod(t *testing.T) {
+	w := wallet.NewWallet([]parser.Transaction{buy("ITSA4", date(1, 10), 100, 10)})
+
+	report, err := ComputeReturns(w, stepPrices{}, ReturnsOptions{From: date(1, 1), To: date(2, 29)})
+	if err != nil {
+		t.Fatalf("ComputeReturns returned error: %v", err)
+	}
+	if first, ok := report.Unpriced["ITSA4"]; !ok || !first.Equal(date(1, 31)) {
+		t.Errorf("unpriced = %v, expected ITSA4 from Jan 31", report.Unpriced)
+	}
+	if report.Portfolio.TWR != 0 {
+		t.Errorf("TWR at cost = %f, expected 0", report.Portfolio.TWR)
+	}
+
+	if _, err := ComputeReturns(w, stepPrices{}, ReturnsOptions{From: date(3, 1), To: date(2, 1)}); err == nil {
+		t.Error("expected error for end before start")
+	}
+}
@@ -0,0 +1,49 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+func date(month, dayOfMonth int) time.Time {
+	return time.Date(2024, time.Month(month), dayOfMonth, 0, 0, 0, 0, time.UTC)
+}
+
+func ymd(year, month, dayOfMonth int) time.Time {
+	return time.Date(year, time.Month(month), dayOfMonth, 0, 0, 0, 0, time.UTC)
+}
+
+func buy(ticker string, when time.Time, qty int, price float64) parser.Transaction {
+	tx := parser.Transaction{
+		Date:        when,
+		Type:        "Compra",
+		Institution: "XP",
+		Ticker:      ticker,
+		Quantity:    decimal.NewFromInt(int64(qty)),
+		Price:       decimal.NewFromFloat(price),
+	}
+	tx.Amount = tx.Quantity.Mul(tx.Price)
+	tx.Hash = parser.CalculateHash(&tx)
+	return tx
+}
+
+func addEarning(t *testing.T, w *wallet.Wallet, ticker string, when time.Time, qty int, total string) {
+	t.Helper()
+	amount := decimal.RequireFromString(total)
+	e := parser.Earning{
+		Date:        when,
+		Type:        "Dividendo",
+		Ticker:      ticker,
+		Quantity:    decimal.NewFromInt(int64(qty)),
+		UnitPrice:   amount.Div(decimal.NewFromInt(int64(qty))),
+		TotalAmount: amount,
+	}
+	e.Hash = parser.CalculateEarningHash(&e)
+	if err := w.AddEarning(e); err != nil {
+		t.Fatalf("AddEarning returned error: %v", err)
+	}
+}
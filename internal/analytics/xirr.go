@@ -0,0 +1,102 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrNoIRR is returned when the cash flows have no internal rate of return
+// (all flows with the same sign, or no root in the search range)
+var ErrNoIRR = errors.New("cash flows have no internal rate of return")
+
+// CashFlow is money moving between the investor and the portfolio, from the
+// investor's side: negative when invested, positive when received
+type CashFlow struct {
+	Date   time.Time
+	Amount float64
+}
+
+// XIRR returns the annual rate r that zeroes Σ amount / (1+r)^(days/365),
+// the money-weighted return of irregular cash flows
+func XIRR(flows []CashFlow) (float64, error) {
+	if len(flows) < 2 {
+		return 0, ErrNoIRR
+	}
+
+	hasIn, hasOut := false, false
+	first := flows[0].Date
+	for _, f := range flows {
+		if f.Amount < 0 {
+			hasIn = true
+		}
+		if f.Amount > 0 {
+			hasOut = true
+		}
+		if f.Date.Before(first) {
+			first = f.Date
+		}
+	}
+	if !hasIn || !hasOut {
+		return 0, ErrNoIRR
+	}
+
+	years := make([]float64, len(flows))
+	for i, f := range flows {
+		years[i] = f.Date.Sub(first).Hours() / 24 / 365
+	}
+
+	npv := func(rate float64) (value, derivative float64) {
+		for i, f := range flows {
+			discount := math.Pow(1+rate, years[i])
+			value += f.Amount / discount
+			derivative -= years[i] * f.Amount / (discount * (1 + rate))
+		}
+		return value, derivative
+	}
+
+	// Newton converges in a few steps for usual portfolios
+	rate := 0.1
+	for i := 0; i < 50; i++ {
+		value, derivative := npv(rate)
+		if math.Abs(value) < 1e-7 {
+			return rate, nil
+		}
+		if derivative == 0 || math.IsNaN(derivative) {
+			break
+		}
+		next := rate - value/derivative
+		if next <= -1 || math.IsNaN(next) || math.IsInf(next, 0) {
+			break
+		}
+		if math.Abs(next-rate) < 1e-12 {
+			return next, nil
+		}
+		rate = next
+	}
+
+	// Fallback: bisection over a wide bracket
+	low, high := -0.9999, 1.0
+	vLow, _ := npv(low)
+	vHigh, _ := npv(high)
+	for vLow*vHigh > 0 && high < 1e6 {
+		high *= 10
+		vHigh, _ = npv(high)
+	}
+	if vLow*vHigh > 0 {
+		return 0, ErrNoIRR
+	}
+	for i := 0; i < 200; i++ {
+		mid := (low + high) / 2
+		vMid, _ := npv(mid)
+		if math.Abs(vMid) < 1e-7 || high-low < 1e-12 {
+			return mid, nil
+		}
+		if vLow*vMid < 0 {
+			high = mid
+		} else {
+			low, vLow = mid, vMid
+		}
+	}
+	return (low + high) / 2, nil
+}
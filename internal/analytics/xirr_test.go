@@ -0,0 +1,46 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestXIRR(t *testing.T) {
+	jan := func(year int) time.Time { return time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		flows    []CashFlow
+		expected float64
+	}{
+		{"one year", []CashFlow{{jan(2023), -1000}, {jan(2024), 1100}}, 0.10},
+		{"loss", []CashFlow{{jan(2023), -1000}, {jan(2024), 800}}, -0.20},
+		{
+			"deposit in the middle",
+			[]CashFlow{
+				{jan(2023), -1000},
+				{time.Date(2023, 7, 2, 0, 0, 0, 0, time.UTC), -1000},
+				{jan(2024), 2150},
+			},
+			0.1007, // 1000×1.1007 + 1000×1.1007^(183/365) ≈ 2150
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := XIRR(tt.flows)
+			if err != nil {
+				t.Fatalf("XIRR returned error: %v", err)
+			}
+			if math.Abs(rate-tt.expected) > 0.0005 {
+				t.Errorf("XIRR = %.6f, expected %.4f", rate, tt.expected)
+			}
+		})
+	}
+
+	if _, err := XIRR([]CashFlow{{jan(2023), -1000}, {jan(2024), -100}}); !errors.Is(err, ErrNoIRR) {
+		t.Errorf("flows with one sign: err = %v, expected ErrNoIRR", err)
+	}
+}
@@ -0,0 +1,158 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+// AssetYield is the income of an active asset relative to what was paid for it
+// (yield on cost) and to its current price (trailing dividend yield)
+//
+// Income per share is each payment divided by the shares held on its date,
+// counted on today's share basis, so splits and bonus shares do not inflate
+// older payments. Amounts are net of withheld tax (JCP), the cash received.
+// Capital returns (amortization, redemption) are not income.
+type AssetYield struct {
+	Ticker       string
+	Quantity     int
+	AveragePrice decimal.Decimal
+	Price        decimal.Decimal
+	HasPrice     bool
+
+	Income12   decimal.Decimal // Received in the last 12 months
+	PerShare12 decimal.Decimal // Income per share in the last 12 months
+
+	YieldOnCost12 float64 // PerShare12 / average price (0.08 = 8%)
+	Yield12       float64 // PerShare12 / price; 0 without a price
+
+	Years map[int]YearYield // Per calendar year
+}
+
+// YearYield is the income of a calendar year
+type YearYield struct {
+	Income      decimal.Decimal
+	PerShare    decimal.Decimal
+	YieldOnCost float64 // PerShare / current average price
+}
+
+// YieldReport ranks the active assets by yield
+type YieldReport struct {
+	AsOf   time.Time
+	Assets []AssetYield // Highest 12-month yield on cost first
+	Years  []int        // Calendar years with income, oldest first
+
+	// Portfolio totals over the last 12 months
+	Income12     decimal.Decimal
+	CostBasis    decimal.Decimal
+	MarketValue  decimal.Decimal // Priced assets only
+	PricedIncome decimal.Decimal // Income12 of the priced assets
+}
+
+// YieldOnCost12 is the 12-month income of the portfolio over its cost basis
+func (r *YieldReport) YieldOnCost12() float64 {
+	return Ratio(r.Income12, r.CostBasis)
+}
+
+// Yield12 is the 12-month income of the priced assets over their market value
+func (r *YieldReport) Yield12() float64 {
+	return Ratio(r.PricedIncome, r.MarketValue)
+}
+
+// Yields computes the yield of each active asset on asOf, using prices
+// (ticker -> last price; may be empty) for the trailing dividend yield
+func Yields(w *wallet.Wallet, prices map[string]decimal.Decimal, asOf time.Time) *YieldReport {
+	asOf = day(asOf)
+	since := asOf.AddDate(-1, 0, 0)
+	report := &YieldReport{AsOf: asOf, Income12: decimal.Zero, CostBasis: decimal.Zero,
+		MarketValue: decimal.Zero, PricedIncome: decimal.Zero}
+	years := make(map[int]bool)
+
+	for ticker, asset := range w.GetActiveAssets() {
+		if asset.IsSubscription || asset.Quantity <= 0 {
+			continue
+		}
+		y := AssetYield{
+			Ticker:       ticker,
+			Quantity:     asset.Quantity,
+			AveragePrice: asset.AveragePrice,
+			Income12:     decimal.Zero,
+			PerShare12:   decimal.Zero,
+			Years:        make(map[int]YearYield),
+		}
+		y.Price, y.HasPrice = prices[ticker]
+		y.HasPrice = y.HasPrice && y.Price.IsPositive()
+
+		for _, e := range asset.EarningsHistory() {
+			d := day(e.Date)
+			if parser.IsCapitalReturn(e.Type) || d.After(asOf) {
+				continue
+			}
+			perShare := incomePerShare(asset, e)
+
+			year := y.Years[d.Year()]
+			year.Income = year.Income.Add(e.Net())
+			year.PerShare = year.PerShare.Add(perShare)
+			y.Years[d.Year()] = year
+			years[d.Year()] = true
+
+			if d.After(since) {
+				y.Income12 = y.Income12.Add(e.Net())
+				y.PerShare12 = y.PerShare12.Add(perShare)
+			}
+		}
+
+		for year, income := range y.Years {
+			income.YieldOnCost = Ratio(income.PerShare, y.AveragePrice)
+			y.Years[year] = income
+		}
+		y.YieldOnCost12 = Ratio(y.PerShare12, y.AveragePrice)
+		if y.HasPrice {
+			y.Yield12 = Ratio(y.PerShare12, y.Price)
+		}
+
+		cost := asset.CostBasis()
+		report.Income12 = report.Income12.Add(y.Income12)
+		report.CostBasis = report.CostBasis.Add(cost)
+		if y.HasPrice {
+			report.MarketValue = report.MarketValue.Add(asset.Valuate(y.Price).MarketValue)
+			report.PricedIncome = report.PricedIncome.Add(y.Income12)
+		}
+		report.Assets = append(report.Assets, y)
+	}
+
+	for year := range years {
+		report.Years = append(report.Years, year)
+	}
+	sort.Ints(report.Years)
+	SortYields(report.Assets, func(y AssetYield) float64 { return y.YieldOnCost12 })
+	return report
+}
+
+// SortYields orders the assets by a metric, highest first (ties by ticker)
+func SortYields(assets []AssetYield, metric func(AssetYield) float64) {
+	sort.SliceStable(assets, func(i, j int) bool {
+		a, b := metric(assets[i]), metric(assets[j])
+		if a != b {
+			return a > b
+		}
+		return assets[i].Ticker < assets[j].Ticker
+	})
+}
+
+// incomePerShare divides the net payment by the shares held at the end of its
+// date, on today's share basis; without a position (e.g., inherited from a
+// predecessor ticker) the quantity reported in the earning is used
+func incomePerShare(asset *wallet.Asset, e parser.Earning) decimal.Decimal {
+	held := asset.QuantityBefore(day(e.Date).AddDate(0, 0, 1))
+	if held.IsPositive() {
+		return e.Net().Div(held)
+	}
+	if e.Quantity.IsPositive() {
+		return e.Net().Div(e.Quantity)
+	}
+	return e.UnitPrice
+}
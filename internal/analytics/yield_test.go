@@ -0,0 +1,70 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+func TestYields(t *testing.T) {
+	w := wallet.NewWallet([]parser.Transaction{
+		buy("PETR4", date(1, 10), 100, 10),
+		buy("ITSA4", date(1, 10), 100, 10),
+	})
+
+	// R$ 50 on 100 shares, then a 1:2 split: on today's basis that is 0.25 per share
+	addEarning(t, w, "PETR4", date(3, 10), 100, "50")
+	if _, err := w.AddCorporateEvent(wallet.CorporateEvent{
+		Type: wallet.EventSplit, Ticker: "PETR4", Date: date(6, 1),
+		RatioFrom: decimal.NewFromInt(1), RatioTo: decimal.NewFromInt(2),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	addEarning(t, w, "PETR4", date(11, 10), 200, "40")
+	addEarning(t, w, "ITSA4", time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), 100, "100")
+
+	report := Yields(w, map[string]decimal.Decimal{"PETR4": decimal.NewFromInt(6)}, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	if len(report.Assets) != 2 || report.Assets[0].Ticker != "ITSA4" {
+		t.Fatalf("expected ITSA4 ranked first, got %+v", report.Assets)
+	}
+
+	itsa, petr := report.Assets[0], report.Assets[1]
+	checks := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"ITSA4 yield on cost", itsa.YieldOnCost12, 0.10},
+		{"ITSA4 yield without price", itsa.Yield12, 0},
+		{"PETR4 yield on cost", petr.YieldOnCost12, 0.45 / 5},
+		{"PETR4 trailing yield", petr.Yield12, 0.45 / 6},
+		{"PETR4 2024 yield on cost", petr.Years[2024].YieldOnCost, 0.45 / 5},
+		{"portfolio yield on cost", report.YieldOnCost12(), 190.0 / 2000},
+		{"portfolio trailing yield", report.Yield12(), 90.0 / 1200},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.expected) > 1e-9 {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, c.got)
+		}
+	}
+
+	if !petr.PerShare12.Equal(decimal.RequireFromString("0.45")) {
+		t.Errorf("expected PETR4 0.45 per share, got %s", petr.PerShare12)
+	}
+	if len(report.Years) != 2 || report.Years[0] != 2024 || report.Years[1] != 2025 {
+		t.Errorf("expected years 2024 and 2025, got %v", report.Years)
+	}
+
+	// Earlier reference date: only the March payment is in the window
+	report = Yields(w, nil, date(6, 30))
+	for _, y := range report.Assets {
+		if y.Ticker == "PETR4" && !y.Income12.Equal(decimal.NewFromInt(50)) {
+			t.Errorf("expected R$ 50 up to June, got %s", y.Income12)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,6 +12,73 @@ import (
 // Config representa a configuração do B3CLI
 type Config struct {
 	CurrentWallet string `yaml:"current_wallet"`
+
+	// SessionTTL é a validade do cache desbloqueado (ex: "8h", "30m")
+	// Vazio usa o padrão da wallet
+	SessionTTL string `yaml:"session_ttl,omitempty"`
+
+	// UndoDepth é a quantidade de operações que podem ser desfeitas com 'b3cli undo'
+	// Ausente usa o padrão da wallet; 0 desativa os snapshots
+	UndoDepth *int `yaml:"undo_depth,omitempty"`
+
+	// Quotes configura de onde 'b3cli quotes update' busca as cotações
+	Quotes *QuotesConfig `yaml:"quotes,omitempty"`
+}
+
+// QuotesConfig configura o provedor de cotações
+type QuotesConfig struct {
+	// Provider é "file" (arquivo CSV/JSON local) ou "http" (endpoint JSON)
+	Provider string `yaml:"provider"`
+
+	// File é o caminho do arquivo de preços (provider "file")
+	File string `yaml:"file,omitempty"`
+
+	// URL é o template do endpoint com {ticker} (provider "http")
+	URL string `yaml:"url,omitempty"`
+
+	// PriceField e TimeField são caminhos no JSON de resposta (ex: "results.0.regularMarketPrice")
+	PriceField string `yaml:"price_field,omitempty"`
+	TimeField  string `yaml:"time_field,omitempty"`
+
+	// Headers são enviados em cada requisição (ex: Authorization)
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// MaxAge é a idade máxima de uma cotação no cache antes de buscar de novo (ex: "15m")
+	// Vazio busca sempre
+	MaxAge string `yaml:"max_age,omitempty"`
+}
+
+// GetMaxAge retorna a idade máxima configurada para cotações em cache
+// Retorna 0 se não configurada (sempre busca)
+func (q *QuotesConfig) GetMaxAge() (time.Duration, error) {
+	if q.MaxAge == "" {
+		return 0, nil
+	}
+
+	maxAge, err := time.ParseDuration(q.MaxAge)
+	if err != nil || maxAge < 0 {
+		return 0, fmt.Errorf("quotes.max_age inválido em config.yaml (%q)", q.MaxAge)
+	}
+
+	return maxAge, nil
+}
+
+// GetSessionTTL retorna a validade configurada para sessões desbloqueadas
+// Retorna 0 se não configurada (o chamador aplica o padrão)
+func (c *Config) GetSessionTTL() (time.Duration, error) {
+	if c.SessionTTL == "" {
+		return 0, nil
+	}
+
+	ttl, err := time.ParseDuration(c.SessionTTL)
+	if err != nil {
+		return 0, fmt.Errorf("session_ttl inválido em config.yaml (%q): %w", c.SessionTTL, err)
+	}
+	if ttl <= 0 {
+		return 0, fmt.Errorf("session_ttl deve ser positivo em config.yaml (%q)", c.SessionTTL)
+	}
+
+	return ttl, nil
 }
 
 // configDir retorna o diretório de configuração do B3CLI
@@ -31,6 +99,23 @@ func configFile() (string, error) {
 	return filepath.Join(dir, "config.yaml"), nil
 }
 
+// AgentSocketEnv é a variável de ambiente que sobrescreve o caminho do socket do agente
+const AgentSocketEnv = "B3CLI_AGENT_SOCK"
+
+// AgentSocketPath retorna o caminho do socket Unix do agente de desbloqueio
+// Usa $B3CLI_AGENT_SOCK se definida, caso contrário ~/.b3cli/agent.sock
+func AgentSocketPath() (string, error) {
+	if path := os.Getenv(AgentSocketEnv); path != "" {
+		return path, nil
+	}
+
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "agent.sock"), nil
+}
+
 // Load carrega a configuração do arquivo
 func Load() (*Config, error) {
 	filePath, err := configFile()
@@ -58,15 +143,23 @@ func Load() (*Config, error) {
 	return &cfg, nil
 }
 
-// Save salva a configuração no arquivo
-func (c *Config) Save() error {
+// EnsureConfigDir cria ~/.b3cli acessível apenas pelo dono (0700)
+// O diretório guarda tokens dos headers de cotação e o socket do agente; versões
+// anteriores o criavam com 0755, então a permissão é restringida se já existir
+func EnsureConfigDir() error {
 	dir, err := configDir()
 	if err != nil {
 		return err
 	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	return os.Chmod(dir, 0700)
+}
 
-	// Criar diretório se não existir
-	if err := os.MkdirAll(dir, 0755); err != nil {
+// Save salva a configuração no arquivo
+func (c *Config) Save() error {
+	if err := EnsureConfigDir(); err != nil {
 		return err
 	}
 
@@ -81,8 +174,12 @@ func (c *Config) Save() error {
 		return err
 	}
 
-	// Salvar arquivo
-	return os.WriteFile(filePath, data, 0644)
+	// Salvar arquivo, legível apenas pelo usuário
+	if err := os.WriteFile(filePath, data, 0600); err != nil {
+		return err
+	}
+	// WriteFile não altera a permissão de um arquivo já existente
+	return os.Chmod(filePath, 0600)
 }
 
 // SetCurrentWallet define a wallet atual
@@ -93,9 +190,12 @@ func SetCurrentWallet(walletPath string) error {
 		return err
 	}
 
-	cfg := &Config{
-		CurrentWallet: absPath,
+	// Preservar as demais configurações
+	cfg, err := Load()
+	if err != nil {
+		return err
 	}
+	cfg.CurrentWallet = absPath
 
 	return cfg.Save()
 }
@@ -116,9 +216,12 @@ func GetCurrentWallet() (string, error) {
 
 // ClearCurrentWallet limpa a wallet atual
 func ClearCurrentWallet() error {
-	cfg := &Config{
-		CurrentWallet: "",
+	cfg, err := Load()
+	if err != nil {
+		return err
 	}
+	cfg.CurrentWallet = ""
+
 	return cfg.Save()
 }
 
@@ -13,11 +13,14 @@ import (
 // Earning representa um provento recebido (rendimento, dividendo, JCP)
 type Earning struct {
 	Date        time.Time       // Data do pagamento
-	Type        string          // Tipo: "Rendimento" | "Dividendo" | "Juros Sobre Capital Próprio"
+	Type        string          // Tipo: "Rendimento" | "Dividendo" | "Juros Sobre Capital Próprio" | "Resgate" | "Amortização"
 	Ticker      string          // Código do ativo (extraído do campo Produto)
 	Quantity    decimal.Decimal // Quantidade contabilizada
 	UnitPrice   decimal.Decimal // Valor por papel
-	TotalAmount decimal.Decimal // Valor total a receber
+	TotalAmount decimal.Decimal // Valor total a receber (como informado pela B3)
+	GrossAmount decimal.Decimal // Valor bruto, antes do IR retido na fonte
+	WithheldTax decimal.Decimal // IR retido na fonte (15% sobre JCP; zero nos isentos)
+	NetAmount   decimal.Decimal // Valor líquido creditado
 	Hash        string          // Hash SHA256 único para deduplicação
 }
 
@@ -91,7 +94,7 @@ func parseEarningsXLSX(filePath string) ([]Earning, error) {
 		}
 
 		// Coluna C (2): Movimentação (tipo de provento)
-		earningType := normalizeEarningType(row[2])
+		earningType := NormalizeEarningType(row[2])
 
 		// Coluna D (3): Produto (formato: "TICKER - Nome da empresa")
 		produto := row[3]
@@ -130,6 +133,9 @@ func parseEarningsXLSX(filePath string) ([]Earning, error) {
 			TotalAmount: totalAmount,
 		}
 
+		// Bruto, IR retido e líquido a partir do valor informado
+		InferEarningAmounts(&earning)
+
 		// Gerar hash
 		earning.Hash = generateEarningHash(&earning)
 
@@ -220,9 +226,9 @@ func removeCurrencySymbol(s string) string {
 	return result
 }
 
-// normalizeEarningType normaliza o tipo de provento para um dos três valores padrões
+// NormalizeEarningType normaliza o tipo de provento para um dos valores padrões
 // Aceita variações comuns de texto encontradas nos arquivos da B3
-func normalizeEarningType(rawType string) string {
+func NormalizeEarningType(rawType string) string {
 	// Remover espaços extras e converter para lowercase para comparação
 	normalized := trimSpaces(rawType)
 	lower := toLower(normalized)
@@ -243,11 +249,111 @@ func normalizeEarningType(rawType string) string {
 	if contains(lower, "resgate") {
 		return "Resgate"
 	}
+	if contains(lower, "amortiza") {
+		return "Amortização"
+	}
 
 	// Se não reconhecer, retornar o valor original (para que a validação pegue)
 	return normalized
 }
 
+// IsCapitalReturn indica se o tipo de provento é devolução de capital (amortização
+// ou resgate): reduz o custo do ativo em vez de contar como rendimento
+func IsCapitalReturn(earningType string) bool {
+	return earningType == "Amortização" || earningType == "Resgate"
+}
+
+// JCPWithholdingRate é a alíquota de IR retido na fonte sobre JCP
+var JCPWithholdingRate = decimal.RequireFromString("0.15")
+
+// HasWithholding indica se o tipo de provento tem IR retido na fonte (JCP)
+// Dividendos, rendimentos de FII e devoluções de capital são isentos
+func HasWithholding(earningType string) bool {
+	return earningType == "Juros Sobre Capital Próprio"
+}
+
+// Net retorna o valor líquido creditado (o dinheiro efetivamente recebido)
+// Proventos sem os valores separados são inferidos a partir do TotalAmount
+func (e Earning) Net() decimal.Decimal {
+	if !e.NetAmount.IsPositive() {
+		InferEarningAmounts(&e)
+	}
+	return e.NetAmount
+}
+
+// Gross retorna o valor bruto, antes do IR retido na fonte
+func (e Earning) Gross() decimal.Decimal {
+	if !e.GrossAmount.IsPositive() {
+		InferEarningAmounts(&e)
+	}
+	return e.GrossAmount
+}
+
+// NetOf retorna o líquido de um valor bruto do tipo de provento (ex: um anúncio)
+func NetOf(earningType string, gross decimal.Decimal) decimal.Decimal {
+	if !HasWithholding(earningType) {
+		return gross
+	}
+	return gross.Sub(gross.Mul(JCPWithholdingRate).Round(2))
+}
+
+// InferEarningAmounts completa bruto, IR retido e líquido a partir dos valores já
+// preenchidos (basta um deles; para JCP, líquido = bruto × 85%)
+//
+// Sem nenhum dos três, parte do TotalAmount: a B3 às vezes informa o JCP bruto,
+// às vezes o líquido. O preço unitário é o valor anunciado (bruto), então o total
+// é bruto quando fica mais perto de quantidade × preço unitário e líquido quando
+// fica mais perto de 85% disso; sem preço unitário, é tratado como líquido (o
+// valor creditado). Nos tipos isentos, bruto = líquido = total
+func InferEarningAmounts(e *Earning) {
+	gross, tax, net := e.GrossAmount, e.WithheldTax, e.NetAmount
+
+	if !HasWithholding(e.Type) {
+		switch {
+		case gross.IsPositive():
+			net = gross
+		case net.IsPositive():
+			gross = net
+		default:
+			gross, net = e.TotalAmount, e.TotalAmount
+		}
+		e.GrossAmount, e.WithheldTax, e.NetAmount = gross, decimal.Zero, net
+		return
+	}
+
+	netRate := decimal.NewFromInt(1).Sub(JCPWithholdingRate)
+	switch {
+	case gross.IsPositive() && net.IsPositive():
+		tax = gross.Sub(net)
+	case gross.IsPositive() && tax.IsPositive():
+		net = gross.Sub(tax)
+	case net.IsPositive() && tax.IsPositive():
+		gross = net.Add(tax)
+	case gross.IsPositive():
+		tax = gross.Mul(JCPWithholdingRate).Round(2)
+		net = gross.Sub(tax)
+	case net.IsPositive():
+		gross = net.Div(netRate).Round(2)
+		tax = gross.Sub(net)
+	case tax.IsPositive():
+		gross = tax.Div(JCPWithholdingRate).Round(2)
+		net = gross.Sub(tax)
+	default:
+		announced := e.Quantity.Mul(e.UnitPrice)
+		if announced.IsPositive() &&
+			e.TotalAmount.Sub(announced).Abs().LessThan(e.TotalAmount.Sub(announced.Mul(netRate)).Abs()) {
+			gross = e.TotalAmount
+			tax = gross.Mul(JCPWithholdingRate).Round(2)
+			net = gross.Sub(tax)
+		} else {
+			net = e.TotalAmount
+			gross = net.Div(netRate).Round(2)
+			tax = gross.Sub(net)
+		}
+	}
+	e.GrossAmount, e.WithheldTax, e.NetAmount = gross, tax, net
+}
+
 // toLower converte uma string para minúsculas (implementação simples)
 func toLower(s string) string {
 	result := ""
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestInferEarningAmounts(t *testing.T) {
+	d := decimal.RequireFromString
+	jcp := "Juros Sobre Capital Próprio"
+
+	tests := []struct {
+		name            string
+		earning         Earning
+		gross, tax, net string
+	}{
+		{
+			name:    "dividend is exempt",
+			earning: Earning{Type: "Dividendo", Quantity: d("100"), UnitPrice: d("0.5"), TotalAmount: d("50")},
+			gross:   "50", tax: "0", net: "50",
+		},
+		{
+			name:    "JCP reported gross",
+			earning: Earning{Type: jcp, Quantity: d("100"), UnitPrice: d("0.5"), TotalAmount: d("50")},
+			gross:   "50", tax: "7.5", net: "42.5",
+		},
+		{
+			name:    "JCP reported net",
+			earning: Earning{Type: jcp, Quantity: d("100"), UnitPrice: d("0.5"), TotalAmount: d("42.5")},
+			gross:   "50", tax: "7.5", net: "42.5",
+		},
+		{
+			name:    "JCP without unit price is taken as net",
+			earning: Earning{Type: jcp, Quantity: d("100"), TotalAmount: d("85")},
+			gross:   "100", tax: "15", net: "85",
+		},
+		{
+			name:    "JCP gross given",
+			earning: Earning{Type: jcp, TotalAmount: d("42.5"), GrossAmount: d("50")},
+			gross:   "50", tax: "7.5", net: "42.5",
+		},
+		{
+			name:    "JCP gross and net given (different rate)",
+			earning: Earning{Type: jcp, TotalAmount: d("50"), GrossAmount: d("50"), NetAmount: d("50")},
+			gross:   "50", tax: "0", net: "50",
+		},
+		{
+			name:    "JCP tax given",
+			earning: Earning{Type: jcp, TotalAmount: d("50"), WithheldTax: d("7.5")},
+			gross:   "50", tax: "7.5", net: "42.5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := tt.earning
+			InferEarningAmounts(&e)
+			if !e.GrossAmount.Equal(d(tt.gross)) || !e.WithheldTax.Equal(d(tt.tax)) || !e.NetAmount.Equal(d(tt.net)) {
+				t.Errorf("expected %s/%s/%s, got %s/%s/%s", tt.gross, tt.tax, tt.net, e.GrossAmount, e.WithheldTax, e.NetAmount)
+			}
+		})
+	}
+}
+
+func TestEarningNetAndGross(t *testing.T) {
+	d := decimal.RequireFromString
+
+	// Sem os valores separados, o líquido e o bruto são inferidos do total
+	e := Earning{Type: "Juros Sobre Capital Próprio", Quantity: d("100"), UnitPrice: d("0.5"), TotalAmount: d("50")}
+	if !e.Net().Equal(d("42.5")) || !e.Gross().Equal(d("50")) {
+		t.Errorf("expected net 42.5 and gross 50, got %s and %s", e.Net(), e.Gross())
+	}
+	if !e.NetAmount.IsZero() {
+		t.Error("Net must not change the earning")
+	}
+
+	if got := NetOf("Juros Sobre Capital Próprio", d("40")); !got.Equal(d("34")) {
+		t.Errorf("NetOf JCP = %s, expected 34", got)
+	}
+	if got := NetOf("Dividendo", d("40")); !got.Equal(d("40")) {
+		t.Errorf("NetOf dividend = %s, expected 40", got)
+	}
+}
@@ -69,3 +69,29 @@ func TestNormalizeTicker(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeEarningType(t *testing.T) {
+	tests := []struct {
+		input         string
+		expected      string
+		capitalReturn bool
+	}{
+		{"Rendimento", "Rendimento", false},
+		{"Dividendo", "Dividendo", false},
+		{"Juros Sobre Capital Próprio", "Juros Sobre Capital Próprio", false},
+		{"Resgate", "Resgate", true},
+		{"Amortização", "Amortização", true},
+		{"AMORTIZACAO", "Amortização", true},
+		{" Amortização de Cotas ", "Amortização", true},
+	}
+
+	for _, tt := range tests {
+		result := NormalizeEarningType(tt.input)
+		if result != tt.expected {
+			t.Errorf("NormalizeEarningType(%q) = %q, expected %q", tt.input, result, tt.expected)
+		}
+		if IsCapitalReturn(result) != tt.capitalReturn {
+			t.Errorf("IsCapitalReturn(%q) = %v, expected %v", result, !tt.capitalReturn, tt.capitalReturn)
+		}
+	}
+}
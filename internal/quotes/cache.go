@@ -0,0 +1,122 @@
+package quotes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CacheFileName is the quote cache file kept in the wallet directory
+const CacheFileName = "quotes.json"
+
+// Cache keeps the last known quote of each ticker in a JSON file
+type Cache struct {
+	path   string
+	quotes map[string]Quote
+}
+
+// cacheEntry is the JSON layout of a cached quote
+type cacheEntry struct {
+	Ticker string          `json:"ticker"`
+	Price  decimal.Decimal `json:"price"`
+	Time   time.Time       `json:"time"`
+	Source string          `json:"source,omitempty"`
+}
+
+// LoadCache reads the quote cache of a wallet directory
+// A missing file is an empty cache
+func LoadCache(dirPath string) (*Cache, error) {
+	cache := &Cache{
+		path:   filepath.Join(dirPath, CacheFileName),
+		quotes: make(map[string]Quote),
+	}
+
+	data, err := os.ReadFile(cache.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quote cache: %w", err)
+	}
+
+	var entries []cacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse quote cache %s: %w", cache.path, err)
+	}
+	for _, e := range entries {
+		cache.quotes[e.Ticker] = Quote{Ticker: e.Ticker, Price: e.Price, Time: e.Time, Source: e.Source}
+	}
+
+	return cache, nil
+}
+
+// Get returns the cached quote of a ticker
+func (c *Cache) Get(ticker string) (Quote, bool) {
+	q, ok := c.quotes[ticker]
+	return q, ok
+}
+
+// Put stores a quote, replacing the previous one unless it is older
+func (c *Cache) Put(q Quote) {
+	if current, ok := c.quotes[q.Ticker]; ok && current.Time.After(q.Time) {
+		return
+	}
+	c.quotes[q.Ticker] = q
+}
+
+// Fresh reports whether the cached quote of a ticker is younger than maxAge
+func (c *Cache) Fresh(ticker string, maxAge time.Duration, now time.Time) bool {
+	q, ok := c.quotes[ticker]
+	return ok && now.Sub(q.Time) < maxAge
+}
+
+// All returns the cached quotes sorted by ticker
+func (c *Cache) All() []Quote {
+	list := make([]Quote, 0, len(c.quotes))
+	for _, q := range c.quotes {
+		list = append(list, q)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Ticker < list[j].Ticker })
+	return list
+}
+
+// Prices returns ticker -> price for all cached quotes
+func (c *Cache) Prices() map[string]decimal.Decimal {
+	prices := make(map[string]decimal.Decimal, len(c.quotes))
+	for ticker, q := range c.quotes {
+		prices[ticker] = q.Price
+	}
+	return prices
+}
+
+// Save writes the cache back to the wallet directory
+func (c *Cache) Save() error {
+	entries := make([]cacheEntry, 0, len(c.quotes))
+	for _, q := range c.All() {
+		entries = append(entries, cacheEntry{Ticker: q.Ticker, Price: q.Price, Time: q.Time, Source: q.Source})
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode quote cache: %w", err)
+	}
+	if err := writePrivateFile(c.path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write quote cache: %w", err)
+	}
+	return nil
+}
+
+// writePrivateFile writes a file readable only by the owner, also tightening
+// files created by older versions with wider permissions
+func writePrivateFile(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0600)
+}
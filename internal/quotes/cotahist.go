@@ -0,0 +1,180 @@
+package quotes
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// COTAHIST is B3's public historical quotes file (COTAHIST_A2024.TXT, also
+// distributed zipped). Each line is a fixed-width 245-character record:
+// "00" header, "01" quote of a ticker on a day and "99" trailer.
+// Positions below are the 1-based columns from B3's layout document
+const (
+	cotahistRecordLength = 245
+	cotahistQuoteRecord  = "01"
+
+	// Market type of regular lot trading (TPMERC); fractional and options markets
+	// are skipped, their closes are not the ticker price
+	cotahistSpotMarket = "010"
+)
+
+// CotahistStats summarizes an import
+type CotahistStats struct {
+	Records int            // Quote records read (all markets)
+	Kept    int            // Closes kept after filtering
+	From    time.Time      // First trading day kept
+	To      time.Time      // Last trading day kept
+	Tickers map[string]int // Closes kept per ticker
+}
+
+// ReadCotahistFile parses a COTAHIST file (.TXT or .ZIP) keeping the spot-market
+// closes of the tickers accepted by keep (nil keeps all)
+func ReadCotahistFile(path string, keep func(ticker string) bool) (map[string][]DailyClose, CotahistStats, error) {
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		return readCotahistZip(path, keep)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, CotahistStats{}, fmt.Errorf("failed to open COTAHIST file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseCotahist(file, keep)
+}
+
+// readCotahistZip parses every file inside a zipped COTAHIST
+func readCotahistZip(path string, keep func(ticker string) bool) (map[string][]DailyClose, CotahistStats, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, CotahistStats{}, fmt.Errorf("failed to open COTAHIST zip: %w", err)
+	}
+	defer archive.Close()
+
+	all := make(map[string][]DailyClose)
+	stats := CotahistStats{Tickers: make(map[string]int)}
+	for _, f := range archive.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, CotahistStats{}, fmt.Errorf("failed to read %s in zip: %w", f.Name, err)
+		}
+		closes, s, err := ParseCotahist(r, keep)
+		r.Close()
+		if err != nil {
+			return nil, CotahistStats{}, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		for ticker, series := range closes {
+			all[ticker] = append(all[ticker], series...)
+		}
+		stats.add(s)
+	}
+
+	return all, stats, nil
+}
+
+// ParseCotahist reads COTAHIST records keeping the spot-market closes of the
+// tickers accepted by keep (nil keeps all)
+func ParseCotahist(r io.Reader, keep func(ticker string) bool) (map[string][]DailyClose, CotahistStats, error) {
+	closes := make(map[string][]DailyClose)
+	stats := CotahistStats{Tickers: make(map[string]int)}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 512), 4096)
+	line := 0
+	for scanner.Scan() {
+		line++
+		record := strings.TrimRight(scanner.Text(), "\r")
+		if !strings.HasPrefix(record, cotahistQuoteRecord) {
+			continue // header, trailer or blank line
+		}
+		stats.Records++
+
+		if len(record) < cotahistRecordLength {
+			return nil, CotahistStats{}, fmt.Errorf("line %d: record has %d characters, expected %d (is this a COTAHIST file?)", line, len(record), cotahistRecordLength)
+		}
+		if field(record, 25, 27) != cotahistSpotMarket {
+			continue
+		}
+		ticker := field(record, 13, 24)
+		if keep != nil && !keep(ticker) {
+			continue
+		}
+
+		c, err := parseCotahistClose(record)
+		if err != nil {
+			return nil, CotahistStats{}, fmt.Errorf("line %d (%s): %w", line, ticker, err)
+		}
+		closes[ticker] = append(closes[ticker], c)
+
+		stats.Kept++
+		stats.Tickers[ticker]++
+		if stats.From.IsZero() || c.Date.Before(stats.From) {
+			stats.From = c.Date
+		}
+		if c.Date.After(stats.To) {
+			stats.To = c.Date
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, CotahistStats{}, fmt.Errorf("failed to read COTAHIST: %w", err)
+	}
+	if line > 0 && stats.Records == 0 {
+		return nil, CotahistStats{}, fmt.Errorf("no quote records found (is this a COTAHIST file?)")
+	}
+
+	return closes, stats, nil
+}
+
+// parseCotahistClose reads the trading date (DATA, 3-10) and the close (PREULT,
+// 109-121, two implied decimals) divided by the quote factor (FATCOT, 211-217),
+// which is 1000 when prices are quoted per thousand shares
+func parseCotahistClose(record string) (DailyClose, error) {
+	date, err := time.Parse("20060102", field(record, 3, 10))
+	if err != nil {
+		return DailyClose{}, fmt.Errorf("invalid date %q", field(record, 3, 10))
+	}
+
+	cents, err := strconv.ParseInt(field(record, 109, 121), 10, 64)
+	if err != nil {
+		return DailyClose{}, fmt.Errorf("invalid close %q", field(record, 109, 121))
+	}
+	factor, err := strconv.ParseInt(field(record, 211, 217), 10, 64)
+	if err != nil || factor <= 0 {
+		factor = 1
+	}
+
+	price := decimal.New(cents, -2).Div(decimal.NewFromInt(factor))
+	return DailyClose{Date: date, Close: price}, nil
+}
+
+// field returns the trimmed content of 1-based inclusive columns
+func field(record string, from, to int) string {
+	return strings.TrimSpace(record[from-1 : to])
+}
+
+// add accumulates the stats of another file
+func (s *CotahistStats) add(other CotahistStats) {
+	s.Records += other.Records
+	s.Kept += other.Kept
+	for ticker, n := range other.Tickers {
+		s.Tickers[ticker] += n
+	}
+	if !other.From.IsZero() && (s.From.IsZero() || other.From.Before(s.From)) {
+		s.From = other.From
+	}
+	if other.To.After(s.To) {
+		s.To = other.To
+	}
+}
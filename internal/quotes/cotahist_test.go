@@ -0,0 +1,72 @@
+package quotes
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// cotahistRecord builds a 245-character quote record with the fields the parser reads
+func cotahistRecord(date, ticker, market string, closeCents, factor int) string {
+	record := []byte(strings.Repeat(" ", cotahistRecordLength))
+	put := func(from int, value string) { copy(record[from-1:], value) }
+
+	put(1, "01")
+	put(3, date)
+	put(11, "02")
+	put(13, fmt.Sprintf("%-12s", ticker))
+	put(25, market)
+	put(109, fmt.Sprintf("%013d", closeCents))
+	put(211, fmt.Sprintf("%07d", factor))
+	return string(record)
+}
+
+func TestParseCotahist(t *testing.T) {
+	lines := []string{
+		"00COTAHIST.2024BOVESPA 20241230",
+		cotahistRecord("20240102", "PETR4", "010", 3851, 1),
+		cotahistRecord("20240102", "PETR4F", "020", 3852, 1),
+		cotahistRecord("20240102", "VALE3", "010", 7000, 1),
+		cotahistRecord("20240103", "PETR4", "010", 3900, 1),
+		cotahistRecord("20240103", "OLDX3", "010", 1234500, 1000),
+		"99COTAHIST.2024BOVESPA 20241230",
+	}
+	input := strings.Join(lines, "\r\n") + "\r\n"
+
+	keep := func(ticker string) bool { return ticker != "VALE3" }
+	closes, stats, err := ParseCotahist(strings.NewReader(input), keep)
+	if err != nil {
+		t.Fatalf("ParseCotahist returned error: %v", err)
+	}
+
+	if stats.Records != 5 || stats.Kept != 3 {
+		t.Errorf("stats = %d records / %d kept, expected 5 / 3", stats.Records, stats.Kept)
+	}
+	if !stats.From.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) || !stats.To.Equal(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("period = %s..%s", stats.From, stats.To)
+	}
+	if _, ok := closes["PETR4F"]; ok {
+		t.Error("fractional market should be skipped")
+	}
+	if _, ok := closes["VALE3"]; ok {
+		t.Error("filtered ticker should be skipped")
+	}
+
+	petr := closes["PETR4"]
+	if len(petr) != 2 || petr[0].Close.String() != "38.51" || petr[1].Close.String() != "39" {
+		t.Errorf("PETR4 closes = %v", petr)
+	}
+	if old := closes["OLDX3"]; len(old) != 1 || old[0].Close.String() != "12.345" {
+		t.Errorf("quote factor not applied: %v", old)
+	}
+}
+
+func TestParseCotahist_RejectsOtherFiles(t *testing.T) {
+	if _, _, err := ParseCotahist(strings.NewReader("ticker,price\nPETR4,38.51\n"), nil); err == nil {
+		t.Error("expected error for a non-COTAHIST file")
+	}
+	if _, _, err := ParseCotahist(strings.NewReader("01short record\n"), nil); err == nil {
+		t.Error("expected error for a truncated record")
+	}
+}
@@ -0,0 +1,150 @@
+package quotes
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileProvider reads prices from a local CSV or JSON file, e.g. exported from a
+// spreadsheet. Accepted layouts:
+//
+//	CSV:  ticker,price[,date]   (optional header; ';' separator for "38,51" prices)
+//	JSON: {"PETR4": 38.51, ...}
+//	JSON: [{"ticker": "PETR4", "price": 38.51, "date": "2024-06-28"}, ...]
+//
+// Quotes without a date take the file modification time
+type FileProvider struct {
+	Path string
+}
+
+// Name identifies the provider in the cache
+func (p FileProvider) Name() string {
+	return "file:" + filepath.Base(p.Path)
+}
+
+// Quotes returns the prices of the requested tickers found in the file
+func (p FileProvider) Quotes(ctx context.Context, tickers []string) (map[string]Quote, error) {
+	all, err := p.readAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]Quote, len(tickers))
+	for _, ticker := range tickers {
+		if q, ok := all[ticker]; ok {
+			result[ticker] = q
+		}
+	}
+	return result, nil
+}
+
+// readAll parses every quote in the file
+func (p FileProvider) readAll() (map[string]Quote, error) {
+	info, err := os.Stat(p.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open price file: %w", err)
+	}
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price file: %w", err)
+	}
+
+	var rows [][3]string
+	switch strings.ToLower(filepath.Ext(p.Path)) {
+	case ".json":
+		rows, err = jsonPriceRows(data)
+	case ".csv", ".txt":
+		rows, err = csvPriceRows(data)
+	default:
+		return nil, fmt.Errorf("unsupported price file %q: use .csv or .json", p.Path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := make(map[string]Quote, len(rows))
+	for i, row := range rows {
+		ticker := strings.ToUpper(strings.TrimSpace(row[0]))
+		if ticker == "" {
+			continue
+		}
+		price, err := parsePrice(row[1])
+		if err != nil {
+			return nil, fmt.Errorf("price file entry %d (%s): %w", i+1, ticker, err)
+		}
+		date, err := parseQuoteDate(row[2])
+		if err != nil {
+			return nil, fmt.Errorf("price file entry %d (%s): %w", i+1, ticker, err)
+		}
+		if date.IsZero() {
+			date = info.ModTime()
+		}
+		quotes[ticker] = Quote{Ticker: ticker, Price: price, Time: date, Source: p.Name()}
+	}
+
+	return quotes, nil
+}
+
+// csvPriceRows reads ticker,price[,date] rows, skipping a header line
+func csvPriceRows(data []byte) ([][3]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if first, _, _ := bytes.Cut(data, []byte("\n")); bytes.Contains(first, []byte(";")) {
+		reader.Comma = ';'
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse price CSV: %w", err)
+	}
+
+	rows := make([][3]string, 0, len(records))
+	for i, record := range records {
+		if len(record) < 2 {
+			continue
+		}
+		if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "ticker") {
+			continue
+		}
+		row := [3]string{record[0], record[1]}
+		if len(record) > 2 {
+			row[2] = record[2]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// jsonPriceRows reads either a ticker -> price object or a list of quote objects
+func jsonPriceRows(data []byte) ([][3]string, error) {
+	var object map[string]json.Number
+	if err := json.Unmarshal(data, &object); err == nil {
+		rows := make([][3]string, 0, len(object))
+		for ticker, price := range object {
+			rows = append(rows, [3]string{ticker, price.String()})
+		}
+		return rows, nil
+	}
+
+	var list []struct {
+		Ticker string          `json:"ticker"`
+		Price  json.RawMessage `json:"price"`
+		Date   string          `json:"date"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse price JSON: expected {\"TICKER\": price} or a list of {ticker, price, date}: %w", err)
+	}
+
+	rows := make([][3]string, 0, len(list))
+	for _, item := range list {
+		rows = append(rows, [3]string{item.Ticker, strings.Trim(string(item.Price), `"`), item.Date})
+	}
+	return rows, nil
+}
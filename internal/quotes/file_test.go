@@ -0,0 +1,77 @@
+package quotes
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func writePriceFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFileProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"csv with header", "prices.csv", "ticker,price,date\nPETR4,38.51,2024-06-28\nitsa4,10.20,\n"},
+		{"csv semicolon", "prices.csv", "PETR4;38,51;28/06/2024\nITSA4;R$ 10,20\n"},
+		{"json object", "prices.json", `{"PETR4": 38.51, "ITSA4": 10.2}`},
+		{"json list", "prices.json", `[{"ticker": "PETR4", "price": "38.51", "date": "2024-06-28"}, {"ticker": "ITSA4", "price": 10.2}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := FileProvider{Path: writePriceFile(t, tt.file, tt.content)}
+
+			quotes, err := provider.Quotes(context.Background(), []string{"PETR4", "ITSA4", "VALE3"})
+			if err != nil {
+				t.Fatalf("Quotes returned error: %v", err)
+			}
+			if len(quotes) != 2 {
+				t.Fatalf("quotes = %v, expected PETR4 and ITSA4 only", quotes)
+			}
+			if !quotes["PETR4"].Price.Equal(decimal.RequireFromString("38.51")) {
+				t.Errorf("PETR4 = %s, expected 38.51", quotes["PETR4"].Price)
+			}
+			if !quotes["ITSA4"].Price.Equal(decimal.RequireFromString("10.2")) {
+				t.Errorf("ITSA4 = %s, expected 10.2", quotes["ITSA4"].Price)
+			}
+			if quotes["ITSA4"].Time.IsZero() {
+				t.Error("quote without date should take the file time")
+			}
+		})
+	}
+}
+
+func TestFileProvider_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"unsupported extension", "prices.xlsx", "x"},
+		{"invalid price", "prices.csv", "PETR4,abc\n"},
+		{"negative price", "prices.csv", "PETR4,-1\n"},
+		{"invalid json", "prices.json", "{"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := FileProvider{Path: writePriceFile(t, tt.file, tt.content)}
+			if _, err := provider.Quotes(context.Background(), []string{"PETR4"}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
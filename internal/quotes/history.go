@@ -0,0 +1,220 @@
+package quotes
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// HistoryDirName is the directory in the wallet that holds the daily close series
+const HistoryDirName = "prices"
+
+// MaxCloseGap is how far back CloseOn looks for the last trading day. Illiquid
+// assets may go days without trades; beyond this the price is considered unknown
+const MaxCloseGap = 31 * 24 * time.Hour
+
+// DailyClose is the closing price of a ticker on a trading day
+type DailyClose struct {
+	Date  time.Time
+	Close decimal.Decimal
+}
+
+// History is the local store of daily closes, one CSV file per ticker
+// (prices/PETR4.csv with "date,close" rows). Series are loaded on demand
+type History struct {
+	dir    string
+	series map[string][]DailyClose
+	dirty  map[string]bool
+}
+
+// OpenHistory opens the price history of a wallet directory
+// Nothing is read until a series is requested
+func OpenHistory(walletDir string) *History {
+	return &History{
+		dir:    filepath.Join(walletDir, HistoryDirName),
+		series: make(map[string][]DailyClose),
+		dirty:  make(map[string]bool),
+	}
+}
+
+// Tickers lists the tickers with a stored series
+func (h *History) Tickers() ([]string, error) {
+	seen := make(map[string]bool)
+	for ticker, closes := range h.series {
+		if len(closes) > 0 {
+			seen[ticker] = true
+		}
+	}
+
+	entries, err := os.ReadDir(h.dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read price history: %w", err)
+	}
+	for _, entry := range entries {
+		if name := entry.Name(); !entry.IsDir() && strings.HasSuffix(name, ".csv") {
+			seen[strings.TrimSuffix(name, ".csv")] = true
+		}
+	}
+
+	tickers := make([]string, 0, len(seen))
+	for ticker := range seen {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+	return tickers, nil
+}
+
+// Series returns the daily closes of a ticker in chronological order
+// A ticker without history returns an empty series
+func (h *History) Series(ticker string) ([]DailyClose, error) {
+	if closes, ok := h.series[ticker]; ok {
+		return closes, nil
+	}
+
+	path := h.path(ticker)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		h.series[ticker] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price history of %s: %w", ticker, err)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	closes := make([]DailyClose, 0, len(records))
+	for i, record := range records {
+		if i == 0 && record[0] == "date" {
+			continue
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("%s line %d: expected date,close", path, i+1)
+		}
+		date, err := time.Parse("2006-01-02", record[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: invalid date %q", path, i+1, record[0])
+		}
+		price, err := decimal.NewFromString(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: invalid close %q", path, i+1, record[1])
+		}
+		closes = append(closes, DailyClose{Date: date, Close: price})
+	}
+	sortCloses(closes)
+
+	h.series[ticker] = closes
+	return closes, nil
+}
+
+// Merge adds closes to a ticker series; a close on a date already stored replaces it
+// Returns how many trading days were new
+func (h *History) Merge(ticker string, closes []DailyClose) (added int, err error) {
+	current, err := h.Series(ticker)
+	if err != nil {
+		return 0, err
+	}
+
+	byDate := make(map[time.Time]int, len(current))
+	merged := append([]DailyClose(nil), current...)
+	for i, c := range merged {
+		byDate[c.Date] = i
+	}
+	for _, c := range closes {
+		c.Date = day(c.Date)
+		if i, ok := byDate[c.Date]; ok {
+			merged[i] = c
+			continue
+		}
+		byDate[c.Date] = len(merged)
+		merged = append(merged, c)
+		added++
+	}
+	sortCloses(merged)
+
+	h.series[ticker] = merged
+	h.dirty[ticker] = true
+	return added, nil
+}
+
+// LastClose returns the last close on or before date, up to MaxCloseGap earlier
+func (h *History) LastClose(ticker string, date time.Time) (DailyClose, bool, error) {
+	closes, err := h.Series(ticker)
+	if err != nil {
+		return DailyClose{}, false, err
+	}
+
+	target := day(date)
+	i := sort.Search(len(closes), func(i int) bool { return closes[i].Date.After(target) })
+	if i == 0 {
+		return DailyClose{}, false, nil
+	}
+	last := closes[i-1]
+	if target.Sub(last.Date) > MaxCloseGap {
+		return DailyClose{}, false, nil
+	}
+	return last, true, nil
+}
+
+// CloseOn returns the close of a ticker on date (or the last trading day before it)
+// An unreadable series counts as missing; use LastClose to get the error
+func (h *History) CloseOn(ticker string, date time.Time) (decimal.Decimal, bool) {
+	c, ok, err := h.LastClose(ticker, date)
+	if err != nil || !ok {
+		return decimal.Zero, false
+	}
+	return c.Close, true
+}
+
+// Save writes the series changed by Merge
+func (h *History) Save() error {
+	if len(h.dirty) == 0 {
+		return nil
+	}
+	// The file names are the tickers held: private, like the rest of the wallet
+	if err := os.MkdirAll(h.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create price history directory: %w", err)
+	}
+
+	for ticker := range h.dirty {
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		_ = writer.Write([]string{"date", "close"})
+		for _, c := range h.series[ticker] {
+			_ = writer.Write([]string{c.Date.Format("2006-01-02"), c.Close.String()})
+		}
+		writer.Flush()
+
+		if err := writePrivateFile(h.path(ticker), buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write price history of %s: %w", ticker, err)
+		}
+		delete(h.dirty, ticker)
+	}
+	return nil
+}
+
+// path returns the series file of a ticker
+func (h *History) path(ticker string) string {
+	return filepath.Join(h.dir, ticker+".csv")
+}
+
+// sortCloses orders a series by date
+func sortCloses(closes []DailyClose) {
+	sort.Slice(closes, func(i, j int) bool { return closes[i].Date.Before(closes[j].Date) })
+}
+
+// day truncates a time to its calendar date (UTC), the key of a series
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,86 @@
+package quotes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestHistory_MergeSaveAndLookup(t *testing.T) {
+	dir := t.TempDir()
+
+	h := OpenHistory(dir)
+	added, err := h.Merge("PETR4", []DailyClose{
+		{Date: date("2024-01-03"), Close: decimal.RequireFromString("39.00")},
+		{Date: date("2024-01-02"), Close: decimal.RequireFromString("38.51")},
+	})
+	if err != nil || added != 2 {
+		t.Fatalf("Merge = %d, %v", added, err)
+	}
+	if err := h.Save(); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(dir, HistoryDirName, "PETR4.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("series file mode = %v, expected 0600", info.Mode().Perm())
+	}
+
+	// Reopen: a repeated day replaces the stored close
+	h = OpenHistory(dir)
+	added, err = h.Merge("PETR4", []DailyClose{
+		{Date: date("2024-01-03"), Close: decimal.RequireFromString("39.10")},
+		{Date: date("2024-01-05"), Close: decimal.RequireFromString("40.00")},
+	})
+	if err != nil || added != 1 {
+		t.Fatalf("second Merge = %d, %v", added, err)
+	}
+	if err := h.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	h = OpenHistory(dir)
+	tickers, err := h.Tickers()
+	if err != nil || len(tickers) != 1 || tickers[0] != "PETR4" {
+		t.Fatalf("Tickers = %v, %v", tickers, err)
+	}
+
+	tests := []struct {
+		on       string
+		expected string // empty: no price
+	}{
+		{"2024-01-01", ""},
+		{"2024-01-02", "38.51"},
+		{"2024-01-03", "39.1"},
+		{"2024-01-04", "39.1"}, // holiday: last trading day
+		{"2024-02-05", "40"},
+		{"2024-02-06", ""}, // more than MaxCloseGap after the last close
+	}
+	for _, tt := range tests {
+		price, ok := h.CloseOn("PETR4", date(tt.on))
+		got := ""
+		if ok {
+			got = price.String()
+		}
+		if got != tt.expected {
+			t.Errorf("CloseOn(%s) = %q, expected %q", tt.on, got, tt.expected)
+		}
+	}
+
+	if _, ok := h.CloseOn("VALE3", date("2024-01-02")); ok {
+		t.Error("ticker without history should have no price")
+	}
+}
@@ -0,0 +1,160 @@
+package quotes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPriceField is the JSON field read when HTTPProvider.PriceField is empty
+const DefaultPriceField = "price"
+
+// HTTPProvider fetches one ticker per request from a JSON endpoint configured by a
+// URL template, e.g. "https://api.example.com/quote/{ticker}?token=XYZ".
+//
+// PriceField (and the optional TimeField) is a dotted path into the response,
+// with numeric segments indexing arrays: "results.0.regularMarketPrice"
+type HTTPProvider struct {
+	URLTemplate string
+	PriceField  string
+	TimeField   string
+	Headers     map[string]string
+	Client      *http.Client // nil uses a client with a 10s timeout
+}
+
+// Name identifies the provider in the cache
+func (p HTTPProvider) Name() string {
+	if u, err := url.Parse(p.URLTemplate); err == nil && u.Host != "" {
+		return "http:" + u.Host
+	}
+	return "http"
+}
+
+// Quotes fetches each ticker; tickers that fail are reported in a *FetchError
+// while the others are still returned. A 404 means the ticker is unknown
+func (p HTTPProvider) Quotes(ctx context.Context, tickers []string) (map[string]Quote, error) {
+	if !strings.Contains(p.URLTemplate, "{ticker}") {
+		return nil, fmt.Errorf("quote URL template must contain {ticker} (got %q)", p.URLTemplate)
+	}
+
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	result := make(map[string]Quote, len(tickers))
+	failures := make(map[string]error)
+	for _, ticker := range tickers {
+		quote, found, err := p.fetch(ctx, client, ticker)
+		if err != nil {
+			failures[ticker] = err
+			continue
+		}
+		if found {
+			result[ticker] = quote
+		}
+	}
+
+	if len(failures) > 0 {
+		return result, &FetchError{Errors: failures}
+	}
+	return result, nil
+}
+
+// fetch requests a single ticker
+func (p HTTPProvider) fetch(ctx context.Context, client *http.Client, ticker string) (Quote, bool, error) {
+	target := strings.ReplaceAll(p.URLTemplate, "{ticker}", url.PathEscape(ticker))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return Quote{}, false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for name, value := range p.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Quote{}, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return Quote{}, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Quote{}, false, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var body any
+	decoder := json.NewDecoder(io.LimitReader(resp.Body, 1<<20))
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		return Quote{}, false, fmt.Errorf("invalid JSON response: %w", err)
+	}
+
+	field := p.PriceField
+	if field == "" {
+		field = DefaultPriceField
+	}
+	raw, ok := lookupJSONPath(body, field)
+	if !ok || raw == nil {
+		return Quote{}, false, fmt.Errorf("field %q not found in response", field)
+	}
+	price, err := parsePrice(fmt.Sprint(raw))
+	if err != nil {
+		return Quote{}, false, err
+	}
+
+	quote := Quote{Ticker: ticker, Price: price, Source: p.Name()}
+	if p.TimeField != "" {
+		if raw, ok := lookupJSONPath(body, p.TimeField); ok && raw != nil {
+			quote.Time = parseResponseTime(raw)
+		}
+	}
+
+	return quote, true, nil
+}
+
+// lookupJSONPath walks a decoded JSON value following a dotted path
+func lookupJSONPath(value any, path string) (any, bool) {
+	for _, segment := range strings.Split(path, ".") {
+		switch node := value.(type) {
+		case map[string]any:
+			next, ok := node[segment]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			value = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// parseResponseTime reads a timestamp as Unix seconds or a date string
+// Unknown formats are ignored (the fetch time is used)
+func parseResponseTime(raw any) time.Time {
+	if n, ok := raw.(json.Number); ok {
+		if seconds, err := n.Int64(); err == nil {
+			return time.Unix(seconds, 0)
+		}
+	}
+	if t, err := parseQuoteDate(fmt.Sprint(raw)); err == nil {
+		return t
+	}
+	return time.Time{}
+}
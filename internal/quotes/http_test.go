@@ -0,0 +1,125 @@
+package quotes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestHTTPProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch strings.TrimPrefix(r.URL.Path, "/quote/") {
+		case "PETR4":
+			fmt.Fprint(w, `{"results": [{"symbol": "PETR4", "regularMarketPrice": 38.51, "regularMarketTime": 1719590400}]}`)
+		case "ITSA4":
+			fmt.Fprint(w, `{"results": [{"symbol": "ITSA4", "regularMarketPrice": "10.20"}]}`)
+		case "BROKEN":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider := HTTPProvider{
+		URLTemplate: server.URL + "/quote/{ticker}",
+		PriceField:  "results.0.regularMarketPrice",
+		TimeField:   "results.0.regularMarketTime",
+		Headers:     map[string]string{"Authorization": "Bearer secret"},
+	}
+
+	quotes, err := provider.Quotes(context.Background(), []string{"PETR4", "ITSA4", "XXXX3", "BROKEN"})
+
+	var fetchErr *FetchError
+	if !errors.As(err, &fetchErr) || len(fetchErr.Errors) != 1 || fetchErr.Errors["BROKEN"] == nil {
+		t.Fatalf("error = %v, expected a FetchError for BROKEN only", err)
+	}
+	if len(quotes) != 2 {
+		t.Fatalf("quotes = %v, expected PETR4 and ITSA4 (XXXX3 unknown)", quotes)
+	}
+
+	petr := quotes["PETR4"]
+	if !petr.Price.Equal(decimal.RequireFromString("38.51")) || !petr.Time.Equal(time.Unix(1719590400, 0)) {
+		t.Errorf("PETR4 = %s at %s", petr.Price, petr.Time)
+	}
+	if !quotes["ITSA4"].Price.Equal(decimal.RequireFromString("10.2")) {
+		t.Errorf("ITSA4 = %s, expected 10.2", quotes["ITSA4"].Price)
+	}
+
+	// Missing field is an error for that ticker
+	provider.PriceField = "price"
+	if _, err := provider.Quotes(context.Background(), []string{"PETR4"}); err == nil {
+		t.Error("expected error for missing price field")
+	}
+
+	// Template without placeholder
+	if _, err := (HTTPProvider{URLTemplate: server.URL}).Quotes(context.Background(), []string{"PETR4"}); err == nil {
+		t.Error("expected error for URL template without {ticker}")
+	}
+}
+
+func TestRefreshAndCache(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	cache, err := LoadCache(dir)
+	if err != nil {
+		t.Fatalf("LoadCache returned error: %v", err)
+	}
+	cache.Put(Quote{Ticker: "ITSA4", Price: decimal.NewFromInt(9), Time: now.Add(-time.Minute), Source: "manual"})
+
+	provider := FileProvider{Path: writePriceFile(t, "prices.csv", "PETR4,38.51,2024-07-01\nITSA4,10.20,2024-07-01\n")}
+
+	// ITSA4 is fresh for 15 minutes; VALE3 is not in the file
+	updated, missing, err := Refresh(context.Background(), provider, cache, []string{"PETR4", "ITSA4", "VALE3"}, 15*time.Minute, now)
+	if err != nil {
+		t.Fatalf("Refresh returned error: %v", err)
+	}
+	if updated != 1 || len(missing) != 1 || missing[0] != "VALE3" {
+		t.Errorf("updated %d, missing %v; expected 1, [VALE3]", updated, missing)
+	}
+	if q, _ := cache.Get("ITSA4"); !q.Price.Equal(decimal.NewFromInt(9)) {
+		t.Errorf("fresh ITSA4 was refreshed: %s", q.Price)
+	}
+
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	// The cached tickers reveal the holdings: owner only
+	info, err := os.Stat(filepath.Join(dir, CacheFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("quote cache mode = %v, expected 0600", info.Mode().Perm())
+	}
+	reloaded, err := LoadCache(dir)
+	if err != nil {
+		t.Fatalf("LoadCache returned error: %v", err)
+	}
+	if len(reloaded.All()) != 2 {
+		t.Fatalf("reloaded quotes = %v, expected 2", reloaded.All())
+	}
+	if q, _ := reloaded.Get("PETR4"); !q.Price.Equal(decimal.RequireFromString("38.51")) || q.Source != "file:prices.csv" {
+		t.Errorf("reloaded PETR4 = %+v", q)
+	}
+
+	// An older quote does not replace a newer one
+	reloaded.Put(Quote{Ticker: "PETR4", Price: decimal.NewFromInt(1), Time: now.AddDate(0, 0, -10)})
+	if q, _ := reloaded.Get("PETR4"); !q.Price.Equal(decimal.RequireFromString("38.51")) {
+		t.Errorf("older quote replaced the cached one: %s", q.Price)
+	}
+}
@@ -0,0 +1,125 @@
+// Package quotes fetches current prices for the wallet assets.
+//
+// Prices come from a QuoteProvider (a local CSV/JSON price file or an HTTP JSON
+// endpoint) and are kept in a cache file next to the wallet, so commands like
+// 'assets overview' work offline with the last known prices.
+//
+// Past prices live in a separate History store (daily closes per ticker) filled
+// from B3's COTAHIST files, to value the wallet on any past date. Prices are
+// public, but the set of cached tickers reveals the wallet holdings, so these
+// files are stored unencrypted and readable only by the owner (0600).
+package quotes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Quote is the price of a ticker at a point in time
+type Quote struct {
+	Ticker string
+	Price  decimal.Decimal
+	Time   time.Time // When the price was observed (trading date or fetch time)
+	Source string    // Provider name
+}
+
+// QuoteProvider returns the current price of a set of tickers
+// Tickers the provider does not know are left out of the result (not an error)
+type QuoteProvider interface {
+	Name() string
+	Quotes(ctx context.Context, tickers []string) (map[string]Quote, error)
+}
+
+// FetchError lists the tickers a provider failed to fetch
+type FetchError struct {
+	Errors map[string]error // ticker -> error
+}
+
+func (e *FetchError) Error() string {
+	tickers := make([]string, 0, len(e.Errors))
+	for ticker := range e.Errors {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+
+	parts := make([]string, 0, len(tickers))
+	for _, ticker := range tickers {
+		parts = append(parts, fmt.Sprintf("%s: %v", ticker, e.Errors[ticker]))
+	}
+	return "failed to fetch quotes: " + strings.Join(parts, "; ")
+}
+
+// Refresh fetches the tickers whose cached quote is missing or older than maxAge
+// (maxAge <= 0 refreshes all) and stores the new quotes in the cache.
+//
+// The cache is updated even when some tickers fail; the returned error is then a
+// *FetchError. Tickers the provider does not know are returned in missing
+func Refresh(ctx context.Context, provider QuoteProvider, cache *Cache, tickers []string, maxAge time.Duration, now time.Time) (updated int, missing []string, err error) {
+	stale := make([]string, 0, len(tickers))
+	for _, ticker := range tickers {
+		if maxAge > 0 && cache.Fresh(ticker, maxAge, now) {
+			continue
+		}
+		stale = append(stale, ticker)
+	}
+	if len(stale) == 0 {
+		return 0, nil, nil
+	}
+
+	fetched, fetchErr := provider.Quotes(ctx, stale)
+	for _, ticker := range stale {
+		quote, ok := fetched[ticker]
+		if !ok {
+			if fe, isFetch := fetchErr.(*FetchError); !isFetch || fe.Errors[ticker] == nil {
+				missing = append(missing, ticker)
+			}
+			continue
+		}
+		if quote.Time.IsZero() {
+			quote.Time = now
+		}
+		if quote.Source == "" {
+			quote.Source = provider.Name()
+		}
+		cache.Put(quote)
+		updated++
+	}
+
+	return updated, missing, fetchErr
+}
+
+// parsePrice parses a price typed in a file ("38.51", "38,51" or "R$ 38,51")
+func parsePrice(value string) (decimal.Decimal, error) {
+	cleaned := strings.TrimSpace(strings.ReplaceAll(value, "R$", ""))
+	if strings.Contains(cleaned, ",") {
+		cleaned = strings.ReplaceAll(strings.ReplaceAll(cleaned, ".", ""), ",", ".")
+	}
+
+	price, err := decimal.NewFromString(cleaned)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid price %q", value)
+	}
+	if !price.IsPositive() {
+		return decimal.Zero, fmt.Errorf("price must be positive (got %s)", value)
+	}
+	return price, nil
+}
+
+// parseQuoteDate accepts YYYY-MM-DD, DD/MM/YYYY or RFC 3339; empty is zero time
+func parseQuoteDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{"2006-01-02", "02/01/2006", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD)", value)
+}
@@ -0,0 +1,353 @@
+package rebalance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/john/b3-project/internal/analytics"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+// Side is the direction of an order
+type Side string
+
+const (
+	Buy  Side = "Compra"
+	Sell Side = "Venda"
+)
+
+// Options are the inputs of a rebalancing round
+type Options struct {
+	// Contribution is the money added to the wallet this round
+	Contribution decimal.Decimal
+
+	// AllowSells lets the plan sell overweight positions to fund the buys
+	AllowSells bool
+}
+
+// Order is a buy or sale suggested by the plan
+type Order struct {
+	Ticker   string
+	Key      string // Target key the ticker belongs to
+	Side     Side
+	Quantity int
+	Lot      int
+	Price    decimal.Decimal
+}
+
+// Value is quantity × price
+func (o Order) Value() decimal.Decimal {
+	return o.Price.Mul(decimal.NewFromInt(int64(o.Quantity))).Round(2)
+}
+
+// RoundLot is the part of the order traded in standard lots
+func (o Order) RoundLot() int {
+	return o.Quantity / o.Lot * o.Lot
+}
+
+// OddLot is the part of the order traded in the fractional market (ticker + "F")
+func (o Order) OddLot() int {
+	return o.Quantity % o.Lot
+}
+
+// GroupStatus compares a target key with the wallet before and after the orders
+type GroupStatus struct {
+	Key     string
+	Tickers []string
+
+	TargetWeight float64 // Normalized target (0.25 = 25%)
+	Weight       float64 // Current share of the targeted value
+	AfterWeight  float64 // Share after the orders
+
+	Value decimal.Decimal
+	After decimal.Decimal
+}
+
+// Plan is the result of a rebalancing round
+type Plan struct {
+	By           Level
+	Contribution decimal.Decimal
+
+	Orders []Order       // Sales first, then buys, by ticker
+	Groups []GroupStatus // Largest target first
+
+	Total      decimal.Decimal // Targeted value before the orders (without the contribution)
+	TotalAfter decimal.Decimal // Targeted value after the orders
+
+	Bought   decimal.Decimal
+	Sold     decimal.Decimal
+	Leftover decimal.Decimal // Cash not used (contribution + sales - buys)
+
+	// Ignored lists held tickers outside the targets; they are left untouched and
+	// do not count in the weights
+	Ignored []string
+
+	// Unpriced lists tickers without a quote: held ones count at cost, but none
+	// of them receives orders
+	Unpriced []string
+
+	// Warnings explain targets that could not be followed
+	Warnings []string
+}
+
+// position is a ticker taking part in the plan
+type position struct {
+	ticker   string
+	key      string
+	quantity int
+	lot      int
+	excluded bool
+
+	price  decimal.Decimal
+	priced bool
+	value  decimal.Decimal // Market value (cost when unpriced)
+	target decimal.Decimal // Target value after the contribution
+
+	bought int
+	sold   int
+}
+
+// after is the value of the position once the orders are executed
+func (p *position) after() decimal.Decimal {
+	return p.value.Add(p.price.Mul(decimal.NewFromInt(int64(p.bought - p.sold))))
+}
+
+// Build plans the orders that move the wallet toward the targets, pricing the
+// positions with prices (ticker -> last price)
+//
+// Within a segment or subtype the target is split evenly among its tickers.
+// Buys go, one lot at a time (one share in the fractional market), to the
+// position furthest below its target while the cash lasts; orders smaller than
+// the minimum order value are dropped and the money goes to the next position.
+func Build(w *wallet.Wallet, t *Targets, prices map[string]decimal.Decimal, opts Options) (*Plan, error) {
+	if opts.Contribution.IsNegative() {
+		return nil, fmt.Errorf("contribution must not be negative")
+	}
+
+	plan := &Plan{By: t.By, Contribution: opts.Contribution, Total: decimal.Zero}
+	positions := make(map[string]*position)
+
+	for ticker, asset := range w.GetActiveAssets() {
+		if asset.IsSubscription {
+			continue
+		}
+		key := t.keyOf(asset)
+		if key == "" {
+			plan.Ignored = append(plan.Ignored, ticker)
+			continue
+		}
+		p := &position{ticker: ticker, key: key, quantity: asset.Quantity, lot: t.Lot(ticker), excluded: t.Excluded(ticker)}
+		if price, ok := prices[ticker]; ok && price.IsPositive() {
+			p.price, p.priced = price, true
+			p.value = asset.Valuate(price).MarketValue
+		} else {
+			p.value = asset.CostBasis()
+			plan.Unpriced = append(plan.Unpriced, ticker)
+		}
+		positions[ticker] = p
+	}
+
+	// Targeted tickers not held yet can be bought when they have a price
+	if t.By == ByTicker {
+		for ticker := range t.Weights {
+			if _, held := positions[ticker]; held {
+				continue
+			}
+			price, ok := prices[ticker]
+			if !ok || !price.IsPositive() {
+				plan.Unpriced = append(plan.Unpriced, ticker)
+				continue
+			}
+			positions[ticker] = &position{ticker: ticker, key: ticker, lot: t.Lot(ticker), excluded: t.Excluded(ticker),
+				price: price, priced: true, value: decimal.Zero}
+		}
+	}
+
+	members := make(map[string][]*position)
+	for _, p := range sortedPositions(positions) {
+		members[p.key] = append(members[p.key], p)
+		plan.Total = plan.Total.Add(p.value)
+	}
+
+	// Keys without any ticker cannot be followed: the other weights are scaled up
+	placeable := decimal.Zero
+	for _, key := range t.Keys() {
+		if len(members[key]) == 0 {
+			if t.Weights[key].IsPositive() {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s: nenhum ativo em carteira ou com cotação para receber o peso-alvo", key))
+			}
+			continue
+		}
+		placeable = placeable.Add(t.Weights[key])
+	}
+	if !placeable.IsPositive() {
+		return nil, fmt.Errorf("no targeted asset in the wallet")
+	}
+
+	goal := plan.Total.Add(opts.Contribution)
+	for key, list := range members {
+		share := goal.Mul(t.Weights[key]).Div(placeable).Div(decimal.NewFromInt(int64(len(list))))
+		for _, p := range list {
+			p.target = share
+		}
+	}
+
+	cash := opts.Contribution
+	ordered := sortedPositions(positions)
+
+	if opts.AllowSells {
+		for _, p := range ordered {
+			if !p.priced || p.excluded || !p.value.GreaterThan(p.target) {
+				continue
+			}
+			quantity := int(p.value.Sub(p.target).Div(p.price).IntPart())
+			if !t.Fractional {
+				quantity -= quantity % p.lot
+			}
+			if quantity > p.quantity {
+				quantity = p.quantity
+			}
+			if quantity <= 0 || p.price.Mul(decimal.NewFromInt(int64(quantity))).LessThan(t.MinOrder) {
+				continue
+			}
+			p.sold = quantity
+			cash = cash.Add(p.price.Mul(decimal.NewFromInt(int64(quantity))))
+		}
+	}
+
+	// Buys below the minimum order value are undone and the position is left out
+	// of the next pass
+	blocked := make(map[string]bool)
+	for {
+		left := fill(ordered, cash, t.Fractional, blocked)
+		dropped := false
+		for _, p := range ordered {
+			if p.bought > 0 && p.price.Mul(decimal.NewFromInt(int64(p.bought))).LessThan(t.MinOrder) {
+				blocked[p.ticker] = true
+				dropped = true
+			}
+		}
+		if !dropped {
+			cash = left
+			break
+		}
+		for _, p := range ordered {
+			p.bought = 0
+		}
+	}
+	plan.Leftover = cash.Round(2)
+
+	for _, p := range ordered {
+		if p.sold > 0 {
+			order := Order{Ticker: p.ticker, Key: p.key, Side: Sell, Quantity: p.sold, Lot: p.lot, Price: p.price}
+			plan.Orders = append(plan.Orders, order)
+			plan.Sold = plan.Sold.Add(order.Value())
+		}
+	}
+	for _, p := range ordered {
+		if p.bought > 0 {
+			order := Order{Ticker: p.ticker, Key: p.key, Side: Buy, Quantity: p.bought, Lot: p.lot, Price: p.price}
+			plan.Orders = append(plan.Orders, order)
+			plan.Bought = plan.Bought.Add(order.Value())
+		}
+	}
+
+	plan.TotalAfter = decimal.Zero
+	for _, p := range ordered {
+		plan.TotalAfter = plan.TotalAfter.Add(p.after())
+	}
+	for _, key := range t.Keys() {
+		group := GroupStatus{Key: key, Value: decimal.Zero, After: decimal.Zero}
+		if list := members[key]; len(list) > 0 {
+			group.TargetWeight = t.Weights[key].Div(placeable).InexactFloat64()
+			for _, p := range list {
+				group.Tickers = append(group.Tickers, p.ticker)
+				group.Value = group.Value.Add(p.value)
+				group.After = group.After.Add(p.after())
+			}
+		}
+		group.After = group.After.Round(2)
+		group.Weight = analytics.Ratio(group.Value, plan.Total)
+		group.AfterWeight = analytics.Ratio(group.After, plan.TotalAfter)
+		plan.Groups = append(plan.Groups, group)
+	}
+	sort.SliceStable(plan.Groups, func(i, j int) bool { return plan.Groups[i].TargetWeight > plan.Groups[j].TargetWeight })
+
+	plan.TotalAfter = plan.TotalAfter.Round(2)
+	sort.Strings(plan.Ignored)
+	sort.Strings(plan.Unpriced)
+	return plan, nil
+}
+
+// fill buys, one step at a time, the position with the largest gap to its
+// target that the cash can pay for; it returns the cash left
+func fill(positions []*position, cash decimal.Decimal, fractional bool, blocked map[string]bool) decimal.Decimal {
+	for {
+		var best *position
+		var bestGap decimal.Decimal
+		for _, p := range positions {
+			if !p.priced || p.excluded || blocked[p.ticker] {
+				continue
+			}
+			if p.price.Mul(decimal.NewFromInt(int64(p.step(fractional)))).GreaterThan(cash) {
+				continue
+			}
+			gap := p.target.Sub(p.after())
+			if !gap.IsPositive() {
+				continue
+			}
+			if best == nil || gap.GreaterThan(bestGap) {
+				best, bestGap = p, gap
+			}
+		}
+		if best == nil {
+			return cash
+		}
+		step := best.step(fractional)
+		best.bought += step
+		cash = cash.Sub(best.price.Mul(decimal.NewFromInt(int64(step))))
+	}
+}
+
+// step is the smallest quantity that can be bought
+func (p *position) step(fractional bool) int {
+	if fractional {
+		return 1
+	}
+	return p.lot
+}
+
+// keyOf returns the target key of an asset (empty when it is not targeted)
+// Segments and subtypes are matched ignoring case
+func (t *Targets) keyOf(asset *wallet.Asset) string {
+	var value string
+	switch t.By {
+	case ByTicker:
+		value = asset.ID
+	case BySegment:
+		value = asset.Segment
+	case BySubType:
+		value = asset.SubType
+	}
+	if value == "" {
+		return ""
+	}
+	for key := range t.Weights {
+		if strings.EqualFold(key, value) {
+			return key
+		}
+	}
+	return ""
+}
+
+// sortedPositions returns the positions ordered by ticker
+func sortedPositions(positions map[string]*position) []*position {
+	list := make([]*position, 0, len(positions))
+	for _, p := range positions {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ticker < list[j].ticker })
+	return list
+}
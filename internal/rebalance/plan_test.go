@@ -0,0 +1,182 @@
+package rebalance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+func buy(ticker string, qty int, price float64) parser.Transaction {
+	tx := parser.Transaction{
+		Date:        time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+		Type:        "Compra",
+		Institution: "XP",
+		Ticker:      ticker,
+		Quantity:    decimal.NewFromInt(int64(qty)),
+		Price:       decimal.NewFromFloat(price),
+	}
+	tx.Amount = tx.Quantity.Mul(tx.Price)
+	tx.Hash = parser.CalculateHash(&tx)
+	return tx
+}
+
+func prices(values map[string]float64) map[string]decimal.Decimal {
+	result := make(map[string]decimal.Decimal, len(values))
+	for ticker, price := range values {
+		result[ticker] = decimal.NewFromFloat(price)
+	}
+	return result
+}
+
+func targets(t *testing.T, by Level, weights map[string]int64) *Targets {
+	t.Helper()
+	result := &Targets{By: by, Weights: make(map[string]decimal.Decimal)}
+	for key, weight := range weights {
+		result.Weights[key] = decimal.NewFromInt(weight)
+	}
+	if err := result.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func checkOrders(t *testing.T, plan *Plan, expected []Order) {
+	t.Helper()
+	if len(plan.Orders) != len(expected) {
+		t.Fatalf("expected %d orders, got %+v", len(expected), plan.Orders)
+	}
+	for i, want := range expected {
+		got := plan.Orders[i]
+		if got.Ticker != want.Ticker || got.Side != want.Side || got.Quantity != want.Quantity {
+			t.Errorf("order %d: expected %s %d %s, got %s %d %s", i, want.Side, want.Quantity, want.Ticker, got.Side, got.Quantity, got.Ticker)
+		}
+	}
+}
+
+func TestBuild_BySubTypeWithRoundLots(t *testing.T) {
+	w := wallet.NewWallet([]parser.Transaction{
+		buy("ITSA4", 100, 10),
+		buy("MXRF11", 300, 10),
+		buy("XPTO3", 10, 50),
+	})
+	for ticker, subType := range map[string]string{"ITSA4": "ações", "MXRF11": "fiis"} {
+		if err := w.UpdateAssetMetadata(ticker, "renda variável", subType, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tg := targets(t, BySubType, map[string]int64{"Ações": 50, "FIIs": 50})
+	tg.Lots = map[string]int{"MXRF11": 1}
+
+	plan, err := Build(w, tg, prices(map[string]float64{"ITSA4": 10, "MXRF11": 10, "XPTO3": 50}),
+		Options{Contribution: decimal.NewFromInt(2000)})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	// Goal 6000: ações must reach 3000 with lots of 100 shares
+	checkOrders(t, plan, []Order{{Ticker: "ITSA4", Side: Buy, Quantity: 200}})
+	if !plan.Leftover.IsZero() {
+		t.Errorf("expected no leftover, got %s", plan.Leftover)
+	}
+	for _, group := range plan.Groups {
+		if group.AfterWeight != 0.5 {
+			t.Errorf("%s: expected 50%% after the orders, got %v", group.Key, group.AfterWeight)
+		}
+	}
+
+	// XPTO3 has no subtype and stays out of the weights
+	if len(plan.Ignored) != 1 || plan.Ignored[0] != "XPTO3" {
+		t.Errorf("expected XPTO3 ignored, got %v", plan.Ignored)
+	}
+	if !plan.Total.Equal(decimal.NewFromInt(4000)) {
+		t.Errorf("expected targeted total 4000, got %s", plan.Total)
+	}
+}
+
+func TestBuild_FractionalAndMinimumOrder(t *testing.T) {
+	w := wallet.NewWallet([]parser.Transaction{
+		buy("BBAS3", 100, 20),
+		buy("ITSA4", 100, 10),
+	})
+
+	tg := targets(t, ByTicker, map[string]int64{"bbas3": 50, "ITSA4": 50})
+	tg.Fractional = true
+	tg.MinOrder = decimal.NewFromInt(100)
+
+	plan, err := Build(w, tg, prices(map[string]float64{"BBAS3": 20, "ITSA4": 10}),
+		Options{Contribution: decimal.NewFromInt(1050)})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	// The single BBAS3 share (R$ 20) is below the minimum order, so the money
+	// goes to ITSA4 until it reaches its target
+	checkOrders(t, plan, []Order{{Ticker: "ITSA4", Side: Buy, Quantity: 103}})
+	order := plan.Orders[0]
+	if order.RoundLot() != 100 || order.OddLot() != 3 {
+		t.Errorf("expected 100 in round lots and 3 fractional, got %d and %d", order.RoundLot(), order.OddLot())
+	}
+	if !plan.Leftover.Equal(decimal.NewFromInt(20)) {
+		t.Errorf("expected leftover 20, got %s", plan.Leftover)
+	}
+}
+
+func TestBuild_Sells(t *testing.T) {
+	w := wallet.NewWallet([]parser.Transaction{
+		buy("BBAS3", 300, 20),
+		buy("ITSA4", 100, 10),
+	})
+
+	tg := targets(t, ByTicker, map[string]int64{"BBAS3": 50, "ITSA4": 50, "WEGE3": 0})
+	p := prices(map[string]float64{"BBAS3": 20, "ITSA4": 10})
+
+	// Without sales the contribution-free round has nothing to do
+	plan, err := Build(w, tg, p, Options{})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	checkOrders(t, plan, nil)
+
+	// BBAS3 is 2500 above its target: 125 shares, rounded down to one lot
+	plan, err = Build(w, tg, p, Options{AllowSells: true})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	checkOrders(t, plan, []Order{
+		{Ticker: "BBAS3", Side: Sell, Quantity: 100},
+		{Ticker: "ITSA4", Side: Buy, Quantity: 200},
+	})
+	if !plan.Sold.Equal(decimal.NewFromInt(2000)) || !plan.Bought.Equal(decimal.NewFromInt(2000)) {
+		t.Errorf("expected 2000 sold and bought, got %s and %s", plan.Sold, plan.Bought)
+	}
+
+	// WEGE3 has no price and no position: it cannot be bought
+	if len(plan.Unpriced) != 1 || plan.Unpriced[0] != "WEGE3" {
+		t.Errorf("expected WEGE3 unpriced, got %v", plan.Unpriced)
+	}
+}
+
+func TestBuild_KeyWithoutAssets(t *testing.T) {
+	w := wallet.NewWallet([]parser.Transaction{buy("ITSA4", 100, 10)})
+	if err := w.UpdateAssetMetadata("ITSA4", "renda variável", "ações", "bancos"); err != nil {
+		t.Fatal(err)
+	}
+
+	tg := targets(t, BySegment, map[string]int64{"bancos": 60, "energia": 40})
+	tg.Fractional = true
+
+	plan, err := Build(w, tg, prices(map[string]float64{"ITSA4": 10}), Options{Contribution: decimal.NewFromInt(500)})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	// The energia weight cannot be placed, so bancos takes the whole contribution
+	checkOrders(t, plan, []Order{{Ticker: "ITSA4", Side: Buy, Quantity: 50}})
+	if len(plan.Warnings) != 1 {
+		t.Errorf("expected a warning about energia, got %v", plan.Warnings)
+	}
+}
@@ -0,0 +1,210 @@
+// Package rebalance plans the orders that bring the wallet closer to target
+// weights, using a monthly contribution and, optionally, sales.
+//
+// Targets live in targets.yaml in the wallet directory, a plain file meant to be
+// edited by hand (it holds weights, not positions).
+package rebalance
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+// TargetsFileName is the targets file kept in the wallet directory
+const TargetsFileName = "targets.yaml"
+
+// Level is the asset attribute the target weights refer to
+type Level string
+
+const (
+	ByTicker  Level = "ticker"
+	BySegment Level = "segment"
+	BySubType Level = "subtype"
+)
+
+// DefaultLotSize is the standard lot of B3 stocks
+const DefaultLotSize = 100
+
+// Targets is the content of targets.yaml
+type Targets struct {
+	// By is the level of the weights: ticker, segment or subtype
+	By Level `yaml:"by"`
+
+	// Weights maps each ticker, segment or subtype to its target weight in percent
+	// Weights that do not sum to 100 are normalized
+	Weights map[string]decimal.Decimal `yaml:"weights"`
+
+	// MinOrder is the smallest order worth sending (R$); smaller orders are dropped
+	MinOrder decimal.Decimal `yaml:"min_order,omitempty"`
+
+	// Fractional allows buying quantities below the lot in the fractional market
+	Fractional bool `yaml:"fractional"`
+
+	// LotSize is the standard lot (100 for stocks); Lots overrides it per ticker
+	// (e.g., FIIs and ETFs trade in lots of 1)
+	LotSize int            `yaml:"lot_size,omitempty"`
+	Lots    map[string]int `yaml:"lots,omitempty"`
+
+	// Exclude lists tickers that must not receive orders
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// LoadTargets reads targets.yaml from the wallet directory
+func LoadTargets(walletDir string) (*Targets, error) {
+	path := filepath.Join(walletDir, TargetsFileName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no %s in the wallet directory", TargetsFileName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", TargetsFileName, err)
+	}
+
+	var t Targets
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := t.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &t, nil
+}
+
+// Validate checks the level and weights and fills the defaults
+func (t *Targets) Validate() error {
+	switch t.By {
+	case ByTicker, BySegment, BySubType:
+	case "":
+		t.By = ByTicker
+	default:
+		return fmt.Errorf("unknown level %q in 'by' (use ticker, segment or subtype)", t.By)
+	}
+
+	if len(t.Weights) == 0 {
+		return fmt.Errorf("no target weights")
+	}
+	for key, weight := range t.Weights {
+		if weight.IsNegative() {
+			return fmt.Errorf("negative weight for %s", key)
+		}
+	}
+	if t.Sum().IsZero() {
+		return fmt.Errorf("target weights sum to zero")
+	}
+
+	if t.LotSize <= 0 {
+		t.LotSize = DefaultLotSize
+	}
+	for ticker, lot := range t.Lots {
+		if lot <= 0 {
+			return fmt.Errorf("lot size of %s must be positive", ticker)
+		}
+	}
+	if t.MinOrder.IsNegative() {
+		return fmt.Errorf("min_order must not be negative")
+	}
+
+	if t.By == ByTicker {
+		normalized := make(map[string]decimal.Decimal, len(t.Weights))
+		for ticker, weight := range t.Weights {
+			normalized[strings.ToUpper(ticker)] = weight
+		}
+		t.Weights = normalized
+	}
+	return nil
+}
+
+// Sum returns the sum of the weights (100 when they are percentages)
+func (t *Targets) Sum() decimal.Decimal {
+	sum := decimal.Zero
+	for _, weight := range t.Weights {
+		sum = sum.Add(weight)
+	}
+	return sum
+}
+
+// Weight returns the normalized target weight of a key (0.25 = 25%)
+func (t *Targets) Weight(key string) float64 {
+	weight, ok := t.Weights[key]
+	if !ok {
+		return 0
+	}
+	return weight.Div(t.Sum()).InexactFloat64()
+}
+
+// Keys returns the targeted keys sorted by name
+func (t *Targets) Keys() []string {
+	keys := make([]string, 0, len(t.Weights))
+	for key := range t.Weights {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Lot returns the lot size of a ticker
+func (t *Targets) Lot(ticker string) int {
+	if lot, ok := t.Lots[ticker]; ok {
+		return lot
+	}
+	return t.LotSize
+}
+
+// Excluded reports whether a ticker must not receive orders
+func (t *Targets) Excluded(ticker string) bool {
+	for _, excluded := range t.Exclude {
+		if strings.EqualFold(excluded, ticker) {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveTargets writes targets.yaml, refusing to overwrite an existing file
+func SaveTargets(walletDir string, t *Targets) error {
+	path := filepath.Join(walletDir, TargetsFileName)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+
+	// Weights and values are written as plain numbers, easier to edit than the
+	// quoted strings decimal.Decimal produces
+	file := struct {
+		By         Level              `yaml:"by"`
+		Weights    map[string]float64 `yaml:"weights"`
+		MinOrder   float64            `yaml:"min_order,omitempty"`
+		Fractional bool               `yaml:"fractional"`
+		LotSize    int                `yaml:"lot_size,omitempty"`
+		Lots       map[string]int     `yaml:"lots,omitempty"`
+		Exclude    []string           `yaml:"exclude,omitempty"`
+	}{
+		By:         t.By,
+		Weights:    make(map[string]float64, len(t.Weights)),
+		MinOrder:   t.MinOrder.InexactFloat64(),
+		Fractional: t.Fractional,
+		LotSize:    t.LotSize,
+		Lots:       t.Lots,
+		Exclude:    t.Exclude,
+	}
+	for key, weight := range t.Weights {
+		file.Weights[key] = weight.InexactFloat64()
+	}
+
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to encode targets: %w", err)
+	}
+	header := "# Pesos-alvo da carteira usados por 'b3cli rebalance'\n" +
+		"# by: ticker | segment | subtype — nível dos pesos em 'weights' (%)\n"
+	if err := os.WriteFile(path, append([]byte(header), data...), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", TargetsFileName, err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package rebalance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadTargets(t *testing.T) {
+	dir := t.TempDir()
+	content := `by: ticker
+weights:
+  bbas3: 30
+  ITSA4: 30
+  MXRF11: 40
+min_order: 50
+fractional: true
+lots:
+  MXRF11: 1
+exclude: [ITSA4]
+`
+	if err := os.WriteFile(filepath.Join(dir, TargetsFileName), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tg, err := LoadTargets(dir)
+	if err != nil {
+		t.Fatalf("LoadTargets returned error: %v", err)
+	}
+	if tg.Weight("BBAS3") != 0.3 {
+		t.Errorf("expected BBAS3 at 30%%, got %v", tg.Weight("BBAS3"))
+	}
+	if tg.Lot("MXRF11") != 1 || tg.Lot("BBAS3") != DefaultLotSize {
+		t.Errorf("unexpected lots: MXRF11 %d, BBAS3 %d", tg.Lot("MXRF11"), tg.Lot("BBAS3"))
+	}
+	if !tg.Excluded("itsa4") || tg.Excluded("BBAS3") {
+		t.Error("expected only ITSA4 excluded")
+	}
+
+	if err := SaveTargets(dir, tg); err == nil {
+		t.Error("expected SaveTargets to refuse overwriting the file")
+	}
+
+	other := t.TempDir()
+	if err := SaveTargets(other, tg); err != nil {
+		t.Fatalf("SaveTargets returned error: %v", err)
+	}
+	saved, err := LoadTargets(other)
+	if err != nil {
+		t.Fatalf("LoadTargets of the saved file returned error: %v", err)
+	}
+	if saved.Weight("MXRF11") != 0.4 || !saved.MinOrder.Equal(tg.MinOrder) || saved.Lot("MXRF11") != 1 {
+		t.Errorf("saved targets differ: %+v", saved)
+	}
+}
+
+func TestTargetsValidate(t *testing.T) {
+	tests := map[string]string{
+		"unknown level":  "by: sector\nweights: {a: 1}\n",
+		"no weights":     "by: segment\n",
+		"zero weights":   "weights: {a: 0}\n",
+		"negative lot":   "weights: {A: 1}\nlots: {A: -1}\n",
+		"negative value": "weights: {A: -1, B: 2}\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, TargetsFileName), []byte(content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadTargets(dir); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
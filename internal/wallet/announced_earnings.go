@@ -0,0 +1,199 @@
+package wallet
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/shopspring/decimal"
+)
+
+// ReceivedMatchWindow é a tolerância entre a data de pagamento anunciada e a data
+// do provento importado para considerar o anúncio recebido
+const ReceivedMatchWindow = 7 * 24 * time.Hour
+
+// AnnouncedEarning é um provento anunciado pela empresa e ainda não creditado
+// Fica pendente até aparecer um provento do mesmo tipo perto da data de pagamento
+type AnnouncedEarning struct {
+	// ID identifica o anúncio (hash dos campos, detecta duplicatas)
+	ID string
+
+	Ticker string
+
+	// Type usa os mesmos tipos de parser.Earning (Dividendo, Juros Sobre Capital Próprio...)
+	Type string
+
+	// RecordDate é a data-com: recebe quem tiver o papel ao final desse dia
+	RecordDate time.Time
+
+	// PaymentDate é a data prevista de pagamento
+	PaymentDate time.Time
+
+	// UnitPrice é o valor anunciado por papel
+	UnitPrice decimal.Decimal
+
+	Notes string
+}
+
+// CalculateAnnouncedEarningID gera o identificador de um anúncio a partir dos seus campos
+func CalculateAnnouncedEarningID(a *AnnouncedEarning) string {
+	data := strings.Join([]string{
+		a.Ticker,
+		a.Type,
+		a.RecordDate.Format("2006-01-02"),
+		a.PaymentDate.Format("2006-01-02"),
+		a.UnitPrice.String(),
+	}, "|")
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// Validate verifica os campos obrigatórios do anúncio
+func (a *AnnouncedEarning) Validate() error {
+	if a.Ticker == "" {
+		return fmt.Errorf("ticker is required")
+	}
+	if a.Type == "" {
+		return fmt.Errorf("earning type is required")
+	}
+	if parser.IsCapitalReturn(a.Type) {
+		return fmt.Errorf("%s is a capital return, not income", a.Type)
+	}
+	if a.RecordDate.IsZero() || a.PaymentDate.IsZero() {
+		return fmt.Errorf("record date and payment date are required")
+	}
+	if a.PaymentDate.Before(a.RecordDate) {
+		return fmt.Errorf("payment date %s is before the record date %s",
+			a.PaymentDate.Format("2006-01-02"), a.RecordDate.Format("2006-01-02"))
+	}
+	if !a.UnitPrice.IsPositive() {
+		return fmt.Errorf("unit price must be positive")
+	}
+	return nil
+}
+
+// AddAnnouncedEarning registra um provento anunciado
+// Retorna o anúncio com ID preenchido; anúncios duplicados retornam erro
+func (w *Wallet) AddAnnouncedEarning(a AnnouncedEarning) (*AnnouncedEarning, error) {
+	if err := a.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid announced earning: %w", err)
+	}
+
+	a.ID = CalculateAnnouncedEarningID(&a)
+	for _, existing := range w.AnnouncedEarnings {
+		if existing.ID == a.ID {
+			return nil, fmt.Errorf("duplicate announced earning detected (%s)", a.ID)
+		}
+	}
+
+	before := w.mutationHash()
+	w.AnnouncedEarnings = append(w.AnnouncedEarnings, a)
+	w.RecordMutation("AddAnnouncedEarning", announcedInputs(a), before)
+
+	return &a, nil
+}
+
+// RemoveAnnouncedEarning remove um anúncio pelo ID (ou prefixo único do ID)
+func (w *Wallet) RemoveAnnouncedEarning(id string) (*AnnouncedEarning, error) {
+	index := -1
+	for i, a := range w.AnnouncedEarnings {
+		if strings.HasPrefix(a.ID, id) {
+			if index >= 0 {
+				return nil, fmt.Errorf("ambiguous announced earning ID prefix %q", id)
+			}
+			index = i
+		}
+	}
+	if index < 0 || id == "" {
+		return nil, fmt.Errorf("announced earning %q not found", id)
+	}
+
+	before := w.mutationHash()
+	removed := w.AnnouncedEarnings[index]
+	w.AnnouncedEarnings = append(w.AnnouncedEarnings[:index:index], w.AnnouncedEarnings[index+1:]...)
+	w.RecordMutation("RemoveAnnouncedEarning", announcedInputs(removed), before)
+
+	return &removed, nil
+}
+
+// SortedAnnouncedEarnings retorna os anúncios por data de pagamento
+func (w *Wallet) SortedAnnouncedEarnings() []AnnouncedEarning {
+	result := make([]AnnouncedEarning, len(w.AnnouncedEarnings))
+	copy(result, w.AnnouncedEarnings)
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].PaymentDate.Equal(result[j].PaymentDate) {
+			return result[i].PaymentDate.Before(result[j].PaymentDate)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// IsReceived verifica se o anúncio já foi creditado: um provento do mesmo ativo e
+// tipo com data até ReceivedMatchWindow de distância do pagamento previsto
+func (w *Wallet) IsReceived(a AnnouncedEarning) bool {
+	asset, exists := w.Assets[a.Ticker]
+	if !exists {
+		return false
+	}
+	for _, e := range asset.Earnings {
+		if announcementMatches(a, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// announcementMatches verifica se o provento é o pagamento do anúncio
+func announcementMatches(a AnnouncedEarning, e parser.Earning) bool {
+	if e.Type != a.Type {
+		return false
+	}
+	gap := e.Date.Sub(a.PaymentDate)
+	if gap < 0 {
+		gap = -gap
+	}
+	return gap <= ReceivedMatchWindow
+}
+
+// PendingAnnouncedEarnings retorna os anúncios ainda não creditados, por data de pagamento
+func (w *Wallet) PendingAnnouncedEarnings() []AnnouncedEarning {
+	var pending []AnnouncedEarning
+	for _, a := range w.SortedAnnouncedEarnings() {
+		if !w.IsReceived(a) {
+			pending = append(pending, a)
+		}
+	}
+	return pending
+}
+
+// ExpectedAmount estima o valor a receber de um anúncio: a quantidade ao final da
+// data-com (ou a atual, se a data-com ainda não passou) × valor por papel
+func (w *Wallet) ExpectedAmount(a AnnouncedEarning, today time.Time) (quantity, amount decimal.Decimal) {
+	asset, exists := w.Assets[a.Ticker]
+	if !exists {
+		return decimal.Zero, decimal.Zero
+	}
+	if a.RecordDate.After(today) {
+		quantity = decimal.NewFromInt(int64(asset.Quantity))
+	} else {
+		quantity = heldUntil(asset.EffectiveNegotiations(), a.RecordDate)
+	}
+	return quantity, quantity.Mul(a.UnitPrice).Round(2)
+}
+
+// announcedInputs descreve um anúncio para o journal
+func announcedInputs(a AnnouncedEarning) map[string]string {
+	return map[string]string{
+		"id":           a.ID,
+		"ticker":       a.Ticker,
+		"type":         a.Type,
+		"record_date":  a.RecordDate.Format("2006-01-02"),
+		"payment_date": a.PaymentDate.Format("2006-01-02"),
+		"unit_price":   a.UnitPrice.String(),
+	}
+}
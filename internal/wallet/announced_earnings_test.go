@@ -0,0 +1,132 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/shopspring/decimal"
+)
+
+func TestAnnouncedEarnings(t *testing.T) {
+	// ITSA4: 100 em 10/01/2024 e mais 50 em 05/03/2024
+	w := NewWallet([]parser.Transaction{
+		testBuy("ITSA4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100),
+		testBuy("ITSA4", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), 50),
+	})
+
+	announced := AnnouncedEarning{
+		Ticker:      "ITSA4",
+		Type:        "Juros Sobre Capital Próprio",
+		RecordDate:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		PaymentDate: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		UnitPrice:   decimal.RequireFromString("0.02"),
+	}
+	added, err := w.AddAnnouncedEarning(announced)
+	if err != nil {
+		t.Fatalf("AddAnnouncedEarning returned error: %v", err)
+	}
+	if _, err := w.AddAnnouncedEarning(announced); err == nil {
+		t.Error("expected duplicate announced earning to be rejected")
+	}
+
+	// The shares bought after the record date do not receive it
+	quantity, amount := w.ExpectedAmount(*added, time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC))
+	if !quantity.Equal(decimal.NewFromInt(100)) || !amount.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("expected 100 shares and R$ 2, got %s and %s", quantity, amount)
+	}
+
+	if len(w.PendingAnnouncedEarnings()) != 1 {
+		t.Fatal("expected the announcement to be pending")
+	}
+
+	// A dividend on the payment date does not settle a JCP announcement
+	for _, e := range []parser.Earning{
+		{
+			Date:        time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			Type:        "Dividendo",
+			Ticker:      "ITSA4",
+			Quantity:    decimal.NewFromInt(100),
+			UnitPrice:   decimal.RequireFromString("0.01"),
+			TotalAmount: decimal.NewFromInt(1),
+		},
+		{
+			Date:        time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC),
+			Type:        "Juros Sobre Capital Próprio",
+			Ticker:      "ITSA4",
+			Quantity:    decimal.NewFromInt(100),
+			UnitPrice:   decimal.RequireFromString("0.017"),
+			TotalAmount: decimal.RequireFromString("1.70"),
+		},
+	} {
+		e.Hash = parser.CalculateEarningHash(&e)
+		if err := w.AddEarning(e); err != nil {
+			t.Fatal(err)
+		}
+		if e.Type == "Dividendo" && len(w.PendingAnnouncedEarnings()) != 1 {
+			t.Error("a dividend must not settle a JCP announcement")
+		}
+	}
+	if len(w.PendingAnnouncedEarnings()) != 0 {
+		t.Error("expected the JCP credited two days later to settle the announcement")
+	}
+
+	// Survives a save/load round trip
+	data := w.prepareVaultData()
+	loaded, err := fromVaultData(&data)
+	if err != nil {
+		t.Fatalf("fromVaultData returned error: %v", err)
+	}
+	if len(loaded.AnnouncedEarnings) != 1 || loaded.AnnouncedEarnings[0].ID != added.ID ||
+		!loaded.AnnouncedEarnings[0].UnitPrice.Equal(announced.UnitPrice) {
+		t.Errorf("announced earning not restored: %+v", loaded.AnnouncedEarnings)
+	}
+
+	if _, err := w.RemoveAnnouncedEarning(added.ID[:6]); err != nil {
+		t.Fatalf("RemoveAnnouncedEarning returned error: %v", err)
+	}
+	if len(w.AnnouncedEarnings) != 0 {
+		t.Error("expected the announcement to be removed")
+	}
+}
+
+func TestAnnouncedEarningValidate(t *testing.T) {
+	record := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	invalid := []AnnouncedEarning{
+		// Sem ticker
+		{
+			Type:        "Dividendo",
+			RecordDate:  record,
+			PaymentDate: record,
+			UnitPrice:   decimal.NewFromInt(1),
+		},
+		// Devolução de capital não é anunciada como provento
+		{
+			Ticker:      "ITSA4",
+			Type:        "Amortização",
+			RecordDate:  record,
+			PaymentDate: record,
+			UnitPrice:   decimal.NewFromInt(1),
+		},
+		// Pagamento antes da data-com
+		{
+			Ticker:      "ITSA4",
+			Type:        "Dividendo",
+			RecordDate:  record,
+			PaymentDate: record.AddDate(0, 0, -1),
+			UnitPrice:   decimal.NewFromInt(1),
+		},
+		// Sem valor por papel
+		{
+			Ticker:      "ITSA4",
+			Type:        "Dividendo",
+			RecordDate:  record,
+			PaymentDate: record,
+		},
+	}
+	for i, a := range invalid {
+		if err := a.Validate(); err == nil {
+			t.Errorf("case %d: expected a validation error", i)
+		}
+	}
+}
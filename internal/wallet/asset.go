@@ -1,6 +1,9 @@
 package wallet
 
 import (
+	"fmt"
+	"sort"
+
 	"github.com/john/b3-project/internal/parser"
 	"github.com/shopspring/decimal"
 )
@@ -13,13 +16,29 @@ type Asset struct {
 	// Negotiations são todas as negociações (compra/venda) feitas com esse ativo
 	Negotiations []parser.Transaction
 
+	// AdjustedNegotiations são as negociações com os eventos corporativos aplicados
+	// (quantidade e preço ajustados); Negotiations continua com o registro original
+	// Recalculado automaticamente a partir de Negotiations e Wallet.CorporateEvents
+	AdjustedNegotiations []parser.Transaction
+
 	// Earnings são todos os proventos (rendimentos, dividendos, JCP) recebidos deste ativo
 	Earnings []parser.Earning
 
+	// InheritedEarnings são os proventos dos ativos que este sucedeu
+	// (incorporação, mudança de ticker); calculado a partir dos eventos corporativos
+	InheritedEarnings []parser.Earning
+
+	// SucceededBy é o ticker que sucedeu este ativo (vazio se nenhum)
+	SucceededBy string
+
 	// TotalEarnings é o valor total de proventos recebidos deste ativo
-	// Calculado automaticamente baseado em todos os earnings
+	// Calculado automaticamente; não inclui devoluções de capital (amortização, resgate)
 	TotalEarnings decimal.Decimal
 
+	// TotalCapitalReturned é o valor total de amortizações e resgates recebidos
+	// Reduz o custo do ativo (ver applyAmortization) e não entra em TotalEarnings
+	TotalCapitalReturned decimal.Decimal
+
 	// Type representa o tipo de ativo - sempre será "renda variável"
 	Type string
 
@@ -51,3 +70,52 @@ type Asset struct {
 	// Só é preenchido se IsSubscription for true
 	SubscriptionOf string
 }
+
+// EffectiveNegotiations retorna as negociações usadas no cálculo da posição:
+// as ajustadas por eventos corporativos, ou as originais se ainda não calculadas
+func (a *Asset) EffectiveNegotiations() []parser.Transaction {
+	if a.AdjustedNegotiations != nil {
+		return a.AdjustedNegotiations
+	}
+	return a.Negotiations
+}
+
+// EarningsHistory retorna os proventos do ativo e os herdados de antecessores,
+// em ordem cronológica
+func (a *Asset) EarningsHistory() []parser.Earning {
+	history := make([]parser.Earning, 0, len(a.InheritedEarnings)+len(a.Earnings))
+	history = append(history, a.InheritedEarnings...)
+	history = append(history, a.Earnings...)
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Date.Before(history[j].Date)
+	})
+	return history
+}
+
+// UpdateAssetMetadata altera os campos de categorização definidos pelo usuário
+// (tipo, subtipo e segmento) e registra a alteração no journal
+func (w *Wallet) UpdateAssetMetadata(ticker, assetType, subType, segment string) error {
+	asset, exists := w.Assets[ticker]
+	if !exists {
+		return fmt.Errorf("ativo %s não encontrado", ticker)
+	}
+
+	before := w.mutationHash()
+	inputs := map[string]string{
+		"ticker":         ticker,
+		"type_before":    asset.Type,
+		"type":           assetType,
+		"subtype_before": asset.SubType,
+		"subtype":        subType,
+		"segment_before": asset.Segment,
+		"segment":        segment,
+	}
+
+	asset.Type = assetType
+	asset.SubType = subType
+	asset.Segment = segment
+
+	w.RecordMutation("UpdateAssetMetadata", inputs, before)
+
+	return nil
+}
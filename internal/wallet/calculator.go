@@ -12,7 +12,7 @@ func calculateAveragePrice(asset *Asset) decimal.Decimal {
 	totalCost := decimal.Zero
 	totalQuantity := decimal.Zero
 
-	for _, negotiation := range asset.Negotiations {
+	for _, negotiation := range asset.EffectiveNegotiations() {
 		// Considerar apenas compras para o cálculo do preço médio
 		if negotiation.Type == "Compra" {
 			totalCost = totalCost.Add(negotiation.Amount)
@@ -34,7 +34,7 @@ func calculateAveragePrice(asset *Asset) decimal.Decimal {
 func calculateTotalInvestedValue(asset *Asset) decimal.Decimal {
 	total := decimal.Zero
 
-	for _, negotiation := range asset.Negotiations {
+	for _, negotiation := range asset.EffectiveNegotiations() {
 		if negotiation.Type == "Compra" {
 			total = total.Add(negotiation.Amount)
 		}
@@ -49,7 +49,7 @@ func calculateTotalInvestedValue(asset *Asset) decimal.Decimal {
 func calculateQuantity(asset *Asset) int {
 	quantity := decimal.Zero
 
-	for _, negotiation := range asset.Negotiations {
+	for _, negotiation := range asset.EffectiveNegotiations() {
 		if negotiation.Type == "Compra" {
 			quantity = quantity.Add(negotiation.Quantity)
 		} else if negotiation.Type == "Venda" {
@@ -0,0 +1,969 @@
+package wallet
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/shopspring/decimal"
+)
+
+// Tipos de evento corporativo
+const (
+	// EventSplit é um desdobramento: cada ação vira RatioTo/RatioFrom ações
+	EventSplit = "desdobramento"
+
+	// EventGrouping é um grupamento: RatioFrom ações viram RatioTo ação
+	EventGrouping = "grupamento"
+
+	// EventBonus é uma bonificação: Percent% de ações novas ao custo UnitCost
+	EventBonus = "bonificação"
+
+	// EventMerge é uma incorporação: RatioFrom ações de Ticker viram RatioTo ações
+	// de TargetTicker, opcionalmente com CashPerShare em dinheiro por ação antiga
+	EventMerge = "incorporação"
+
+	// EventRename é uma mudança de ticker (ex: nova marca), sem alterar a posição
+	EventRename = "mudança de ticker"
+
+	// EventSpinOff é uma cisão: parte do custo de Ticker passa para os tickers
+	// novos de SpinOffs, que recebem ações na proporção de cada um
+	EventSpinOff = "cisão"
+
+	// EventRightsReceived registra direitos de subscrição (Ticker) recebidos
+	// sobre o ativo pai (TargetTicker), em Quantity e com custo UnitCost (normalmente zero)
+	EventRightsReceived = "direito recebido"
+
+	// EventRightsExercised registra o exercício de Quantity direitos ao preço UnitCost
+	// A compra do ativo pai é datada na liquidação (Date)
+	EventRightsExercised = "exercício de direito"
+
+	// EventRightsExpired baixa os direitos restantes na data de vencimento (prejuízo)
+	EventRightsExpired = "direito expirado"
+)
+
+// eventInstitution identifica as negociações sintéticas geradas por eventos
+const eventInstitution = "Evento corporativo"
+
+// IsEventTransaction indica se a negociação foi gerada por um evento corporativo
+// (não existe no arquivo importado da B3)
+func IsEventTransaction(tx parser.Transaction) bool {
+	return tx.Institution == eventInstitution
+}
+
+// CorporateEvent é um evento corporativo registrado na carteira
+//
+// Os eventos não alteram as transações importadas da B3: são aplicados no
+// cálculo das posições (ver Asset.AdjustedNegotiations), então o registro
+// original é preservado e reimportar o mesmo arquivo continua deduplicando.
+type CorporateEvent struct {
+	// ID identifica o evento (hash curto dos campos)
+	ID string
+
+	// Type é o tipo do evento (EventSplit, EventGrouping, ...)
+	Type string
+
+	// Ticker é o ativo afetado
+	Ticker string
+
+	// TargetTicker é o ativo que recebe a posição (incorporação, mudança de ticker)
+	TargetTicker string
+
+	// Date é a data a partir da qual o evento vale (data "ex")
+	// Negociações anteriores a essa data são ajustadas
+	Date time.Time
+
+	// RatioFrom e RatioTo definem a proporção: RatioFrom ações antigas viram RatioTo novas
+	RatioFrom decimal.Decimal
+	RatioTo   decimal.Decimal
+
+	// Percent é o percentual de ações novas (bonificação): 10 = 10%
+	Percent decimal.Decimal
+
+	// UnitCost é o custo unitário atribuído pela empresa às ações novas (bonificação)
+	UnitCost decimal.Decimal
+
+	// AuctionAmount é o valor recebido pela venda das frações em leilão (bonificação)
+	// Zero indica que as frações foram descartadas
+	AuctionAmount decimal.Decimal
+
+	// CashPerShare é o valor em dinheiro pago por ação antiga (incorporação)
+	// Reduz o custo de aquisição da posição convertida
+	CashPerShare decimal.Decimal
+
+	// Quantity é a quantidade de direitos recebidos ou exercidos (subscrição)
+	Quantity decimal.Decimal
+
+	// SpinOffs são os ativos criados na cisão (percentual do custo e proporção)
+	SpinOffs []SpinOffTarget
+
+	// Notes é uma observação livre do usuário
+	Notes string
+}
+
+// SpinOffTarget é um ativo novo criado por uma cisão
+type SpinOffTarget struct {
+	// Ticker é o ativo novo
+	Ticker string
+
+	// CostPercent é o percentual do custo do ativo original transferido (20 = 20%)
+	// Percentual publicado pela empresa, sempre sobre o custo anterior à cisão
+	CostPercent decimal.Decimal
+
+	// RatioFrom ações do ativo original dão direito a RatioTo ações novas
+	RatioFrom decimal.Decimal
+	RatioTo   decimal.Decimal
+}
+
+// eventHandler ajusta as negociações efetivas de cada ticker para um evento
+// positions mapeia ticker -> negociações já ajustadas pelos eventos anteriores
+type eventHandler func(positions map[string][]parser.Transaction, e CorporateEvent)
+
+// eventHandlers registra como cada tipo de evento é aplicado no cálculo das posições
+var eventHandlers = map[string]eventHandler{
+	EventSplit:    applyRatioEvent,
+	EventGrouping: applyRatioEvent,
+	EventBonus:    applyBonusEvent,
+	EventMerge:    applyConversionEvent,
+	EventRename:   applyConversionEvent,
+	EventSpinOff:  applySpinOffEvent,
+
+	EventRightsReceived:  applyRightsReceivedEvent,
+	EventRightsExercised: applyRightsExercisedEvent,
+	EventRightsExpired:   applyRightsExpiredEvent,
+}
+
+// successionEvents são os eventos em que TargetTicker sucede Ticker:
+// proventos e metadados do ativo antigo passam para o novo
+var successionEvents = map[string]bool{
+	EventMerge:  true,
+	EventRename: true,
+}
+
+// CalculateEventID gera o identificador de um evento a partir dos seus campos
+// Eventos iguais geram o mesmo ID, permitindo detectar duplicatas
+func CalculateEventID(e *CorporateEvent) string {
+	data := strings.Join([]string{
+		e.Type,
+		e.Ticker,
+		e.Date.Format("2006-01-02"),
+		e.RatioFrom.String(),
+		e.RatioTo.String(),
+	}, "|")
+
+	// Campos opcionais entram só quando preenchidos, mantendo os IDs já gravados
+	optional := []struct {
+		name  string
+		value decimal.Decimal
+	}{
+		{"percent", e.Percent},
+		{"unit_cost", e.UnitCost},
+		{"auction", e.AuctionAmount},
+		{"cash", e.CashPerShare},
+		{"qty", e.Quantity},
+	}
+	for _, field := range optional {
+		if !field.value.IsZero() {
+			data += "|" + field.name + "=" + field.value.String()
+		}
+	}
+	if e.TargetTicker != "" {
+		data += "|target=" + e.TargetTicker
+	}
+	for _, target := range e.SpinOffs {
+		data += fmt.Sprintf("|spin=%s:%s:%s:%s", target.Ticker, target.CostPercent, target.RatioFrom, target.RatioTo)
+	}
+
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// Validate verifica se o evento tem os campos necessários para o seu tipo
+func (e *CorporateEvent) Validate() error {
+	if e.Ticker == "" {
+		return fmt.Errorf("ticker is required")
+	}
+	if e.Date.IsZero() {
+		return fmt.Errorf("date is required")
+	}
+	if _, known := eventHandlers[e.Type]; !known {
+		return fmt.Errorf("unknown corporate event type %q", e.Type)
+	}
+
+	switch e.Type {
+	case EventSplit:
+		if !e.RatioFrom.IsPositive() || e.RatioTo.LessThanOrEqual(e.RatioFrom) {
+			return fmt.Errorf("split ratio must increase the number of shares (got %s:%s)", e.RatioFrom, e.RatioTo)
+		}
+		if e.AuctionAmount.IsNegative() {
+			return fmt.Errorf("auction amount cannot be negative (got %s)", e.AuctionAmount)
+		}
+	case EventGrouping:
+		if !e.RatioTo.IsPositive() || e.RatioFrom.LessThanOrEqual(e.RatioTo) {
+			return fmt.Errorf("grouping ratio must decrease the number of shares (got %s:%s)", e.RatioFrom, e.RatioTo)
+		}
+		if e.AuctionAmount.IsNegative() {
+			return fmt.Errorf("auction amount cannot be negative (got %s)", e.AuctionAmount)
+		}
+	case EventBonus:
+		if !e.Percent.IsPositive() {
+			return fmt.Errorf("bonus percent must be positive (got %s)", e.Percent)
+		}
+		if e.UnitCost.IsNegative() {
+			return fmt.Errorf("bonus unit cost cannot be negative (got %s)", e.UnitCost)
+		}
+		if e.AuctionAmount.IsNegative() {
+			return fmt.Errorf("auction amount cannot be negative (got %s)", e.AuctionAmount)
+		}
+	case EventMerge:
+		if !e.RatioFrom.IsPositive() || !e.RatioTo.IsPositive() {
+			return fmt.Errorf("merge ratio must be positive (got %s:%s)", e.RatioFrom, e.RatioTo)
+		}
+		if e.CashPerShare.IsNegative() {
+			return fmt.Errorf("cash per share cannot be negative (got %s)", e.CashPerShare)
+		}
+	case EventSpinOff:
+		if err := e.validateSpinOffs(); err != nil {
+			return err
+		}
+	case EventRightsReceived, EventRightsExercised:
+		if e.TargetTicker == "" {
+			return fmt.Errorf("parent ticker is required")
+		}
+		if e.TargetTicker == e.Ticker {
+			return fmt.Errorf("parent ticker must differ from %s", e.Ticker)
+		}
+		if !e.Quantity.IsPositive() {
+			return fmt.Errorf("rights quantity must be positive (got %s)", e.Quantity)
+		}
+		if e.UnitCost.IsNegative() {
+			return fmt.Errorf("unit price cannot be negative (got %s)", e.UnitCost)
+		}
+		if e.Type == EventRightsExercised && !e.UnitCost.IsPositive() {
+			return fmt.Errorf("exercise price must be positive (got %s)", e.UnitCost)
+		}
+	}
+
+	if successionEvents[e.Type] {
+		if e.TargetTicker == "" {
+			return fmt.Errorf("target ticker is required")
+		}
+		if e.TargetTicker == e.Ticker {
+			return fmt.Errorf("target ticker must differ from %s", e.Ticker)
+		}
+	}
+
+	return nil
+}
+
+// Targets retorna os tickers que recebem posição do evento (além de Ticker)
+func (e *CorporateEvent) Targets() []string {
+	targets := make([]string, 0, len(e.SpinOffs)+1)
+	if e.TargetTicker != "" {
+		targets = append(targets, e.TargetTicker)
+	}
+	for _, target := range e.SpinOffs {
+		targets = append(targets, target.Ticker)
+	}
+	return targets
+}
+
+// validateSpinOffs checks the new tickers of a spin-off
+func (e *CorporateEvent) validateSpinOffs() error {
+	if len(e.SpinOffs) == 0 {
+		return fmt.Errorf("spin-off requires at least one new ticker")
+	}
+
+	total := decimal.Zero
+	seen := make(map[string]bool)
+	for _, target := range e.SpinOffs {
+		if target.Ticker == "" || target.Ticker == e.Ticker {
+			return fmt.Errorf("invalid spin-off ticker %q", target.Ticker)
+		}
+		if seen[target.Ticker] {
+			return fmt.Errorf("spin-off ticker %s repeated", target.Ticker)
+		}
+		seen[target.Ticker] = true
+
+		if !target.CostPercent.IsPositive() {
+			return fmt.Errorf("cost percent for %s must be positive (got %s)", target.Ticker, target.CostPercent)
+		}
+		if !target.RatioFrom.IsPositive() || !target.RatioTo.IsPositive() {
+			return fmt.Errorf("ratio for %s must be positive (got %s:%s)", target.Ticker, target.RatioFrom, target.RatioTo)
+		}
+		total = total.Add(target.CostPercent)
+	}
+
+	if total.GreaterThanOrEqual(decimal.NewFromInt(100)) {
+		return fmt.Errorf("spin-off cost percentages must add up to less than 100%% (got %s%%)", total)
+	}
+
+	return nil
+}
+
+// Describe returns a short human readable description of the event
+func (e *CorporateEvent) Describe() string {
+	switch e.Type {
+	case EventSplit, EventGrouping:
+		description := fmt.Sprintf("%s %s:%s", e.Type, e.RatioFrom, e.RatioTo)
+		if e.AuctionAmount.IsPositive() {
+			description += fmt.Sprintf(" (frações vendidas: R$ %s)", e.AuctionAmount.StringFixed(2))
+		}
+		return description
+	case EventBonus:
+		description := fmt.Sprintf("%s %s%% @ R$ %s", e.Type, e.Percent, e.UnitCost.StringFixed(2))
+		if e.AuctionAmount.IsPositive() {
+			description += fmt.Sprintf(" (frações vendidas: R$ %s)", e.AuctionAmount.StringFixed(2))
+		}
+		return description
+	case EventMerge:
+		description := fmt.Sprintf("%s %s → %s %s:%s", e.Type, e.Ticker, e.TargetTicker, e.RatioFrom, e.RatioTo)
+		if e.CashPerShare.IsPositive() {
+			description += fmt.Sprintf(" + R$ %s/ação", e.CashPerShare.StringFixed(2))
+		}
+		return description
+	case EventRename:
+		return fmt.Sprintf("%s %s → %s", e.Type, e.Ticker, e.TargetTicker)
+	case EventRightsReceived:
+		return fmt.Sprintf("%s %s de %s (%s)", e.Type, e.Ticker, e.TargetTicker, e.Quantity)
+	case EventRightsExercised:
+		return fmt.Sprintf("%s %s → %s %s @ R$ %s", e.Type, e.Ticker, e.TargetTicker, e.Quantity, e.UnitCost.StringFixed(2))
+	case EventSpinOff:
+		parts := make([]string, 0, len(e.SpinOffs))
+		for _, target := range e.SpinOffs {
+			parts = append(parts, fmt.Sprintf("%s %s%% (%s:%s)", target.Ticker, target.CostPercent, target.RatioFrom, target.RatioTo))
+		}
+		return fmt.Sprintf("%s %s → %s", e.Type, e.Ticker, strings.Join(parts, ", "))
+	}
+	return e.Type
+}
+
+// AddCorporateEvent registra um evento corporativo e recalcula as posições
+// Retorna o evento com ID preenchido; eventos duplicados retornam erro
+func (w *Wallet) AddCorporateEvent(e CorporateEvent) (*CorporateEvent, error) {
+	if err := e.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid corporate event: %w", err)
+	}
+
+	e.ID = CalculateEventID(&e)
+	for _, existing := range w.CorporateEvents {
+		if existing.ID == e.ID {
+			return nil, fmt.Errorf("duplicate corporate event detected (%s)", e.ID)
+		}
+	}
+
+	before := w.mutationHash()
+
+	w.CorporateEvents = append(w.CorporateEvents, e)
+	w.RecalculateAssets()
+
+	w.RecordMutation("AddCorporateEvent", eventInputs(e), before)
+
+	return &e, nil
+}
+
+// RemoveCorporateEvent remove um evento pelo ID (ou prefixo único do ID)
+// As posições voltam a ser calculadas sem o evento
+func (w *Wallet) RemoveCorporateEvent(id string) (*CorporateEvent, error) {
+	index, err := w.findCorporateEvent(id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := w.mutationHash()
+
+	removed := w.CorporateEvents[index]
+	w.CorporateEvents = append(w.CorporateEvents[:index:index], w.CorporateEvents[index+1:]...)
+	w.RecalculateAssets()
+
+	w.RecordMutation("RemoveCorporateEvent", eventInputs(removed), before)
+
+	return &removed, nil
+}
+
+// FindCorporateEvent retorna o evento com o ID (ou prefixo único do ID)
+func (w *Wallet) FindCorporateEvent(id string) (*CorporateEvent, error) {
+	index, err := w.findCorporateEvent(id)
+	if err != nil {
+		return nil, err
+	}
+	event := w.CorporateEvents[index]
+	return &event, nil
+}
+
+// SortedCorporateEvents retorna os eventos em ordem cronológica
+// Se ticker não for vazio, retorna apenas os eventos que envolvem esse ativo
+func (w *Wallet) SortedCorporateEvents(ticker string) []CorporateEvent {
+	result := make([]CorporateEvent, 0, len(w.CorporateEvents))
+	for _, e := range w.CorporateEvents {
+		if ticker == "" || e.Ticker == ticker || containsTicker(e.Targets(), ticker) {
+			result = append(result, e)
+		}
+	}
+	sortCorporateEvents(result)
+	return result
+}
+
+// containsTicker verifica se o ticker está na lista
+func containsTicker(tickers []string, ticker string) bool {
+	for _, t := range tickers {
+		if t == ticker {
+			return true
+		}
+	}
+	return false
+}
+
+// findCorporateEvent localiza um evento pelo ID ou prefixo único
+func (w *Wallet) findCorporateEvent(id string) (int, error) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	if id == "" {
+		return -1, fmt.Errorf("event id is required")
+	}
+
+	found := -1
+	for i, e := range w.CorporateEvents {
+		if strings.HasPrefix(e.ID, id) {
+			if found >= 0 {
+				return -1, fmt.Errorf("event id %q is ambiguous", id)
+			}
+			found = i
+		}
+	}
+	if found < 0 {
+		return -1, fmt.Errorf("corporate event %q not found", id)
+	}
+
+	return found, nil
+}
+
+// applyCorporateEvents recalcula as negociações efetivas de cada ativo
+// aplicando os eventos em ordem cronológica sobre as transações originais
+func (w *Wallet) applyCorporateEvents() {
+	w.removeEventArtifacts()
+
+	sorted := w.SortedCorporateEvents("")
+	positions := w.computePositions(sorted)
+
+	for ticker, negotiations := range positions {
+		asset, exists := w.Assets[ticker]
+		if !exists {
+			// Ativo criado por um evento (ex: ticker novo após incorporação)
+			asset = &Asset{
+				ID:           ticker,
+				Negotiations: make([]parser.Transaction, 0),
+				Earnings:     make([]parser.Earning, 0),
+				Type:         "renda variável",
+			}
+			w.Assets[ticker] = asset
+		}
+		asset.AdjustedNegotiations = negotiations
+	}
+
+	w.applySuccessions(sorted)
+}
+
+// computePositions aplica os eventos (já ordenados) sobre cópias das negociações
+// originais e retorna as negociações efetivas por ticker. As devoluções de capital
+// registradas como proventos entram na mesma linha do tempo dos eventos
+func (w *Wallet) computePositions(sorted []CorporateEvent) map[string][]parser.Transaction {
+	return w.replayPositions(sorted, time.Time{})
+}
+
+// replayPositions aplica os eventos com data até until (zero aplica todos)
+// Sem os eventos posteriores, as quantidades e preços são os vigentes naquela data
+func (w *Wallet) replayPositions(sorted []CorporateEvent, until time.Time) map[string][]parser.Transaction {
+	positions := make(map[string][]parser.Transaction, len(w.Assets))
+	for ticker, asset := range w.Assets {
+		positions[ticker] = append([]parser.Transaction(nil), asset.Negotiations...)
+	}
+
+	timeline := append(append([]CorporateEvent(nil), sorted...), w.amortizationEvents()...)
+	sortCorporateEvents(timeline)
+
+	for _, e := range timeline {
+		if !until.IsZero() && e.Date.After(until) {
+			break
+		}
+		if e.Type == eventAmortization {
+			applyAmortization(positions, e)
+		} else if handler, ok := eventHandlers[e.Type]; ok {
+			handler(positions, e)
+		}
+	}
+
+	return positions
+}
+
+// eventAmortization identifica as devoluções de capital derivadas de proventos
+// Não é um tipo registrável: o registro é o próprio provento (amortização, resgate)
+const eventAmortization = "amortização"
+
+// amortizationEvents converte os proventos de devolução de capital em eventos
+func (w *Wallet) amortizationEvents() []CorporateEvent {
+	var list []CorporateEvent
+	for ticker, asset := range w.Assets {
+		for _, earning := range asset.Earnings {
+			if !parser.IsCapitalReturn(earning.Type) {
+				continue
+			}
+			list = append(list, CorporateEvent{
+				ID:            "earning:" + earning.Hash,
+				Type:          eventAmortization,
+				Ticker:        ticker,
+				Date:          earning.Date,
+				AuctionAmount: earning.TotalAmount,
+			})
+		}
+	}
+	return list
+}
+
+// PositionPreview é a posição calculada de um ativo em uma simulação
+type PositionPreview struct {
+	Quantity           int
+	AveragePrice       decimal.Decimal
+	TotalInvestedValue decimal.Decimal
+}
+
+// PreviewCorporateEvent calcula as posições com o evento aplicado, sem alterar a carteira
+// Retorna antes e depois para o ativo do evento e todos os ativos que recebem posição
+func (w *Wallet) PreviewCorporateEvent(e CorporateEvent) (before, after map[string]PositionPreview, err error) {
+	if err := e.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid corporate event: %w", err)
+	}
+	e.ID = CalculateEventID(&e)
+
+	events := append(w.SortedCorporateEvents(""), e)
+	sortCorporateEvents(events)
+	positions := w.computePositions(events)
+
+	before = make(map[string]PositionPreview)
+	after = make(map[string]PositionPreview)
+	for _, ticker := range append([]string{e.Ticker}, e.Targets()...) {
+		if asset, exists := w.Assets[ticker]; exists {
+			before[ticker] = PositionPreview{
+				Quantity:           asset.Quantity,
+				AveragePrice:       asset.AveragePrice,
+				TotalInvestedValue: asset.TotalInvestedValue,
+			}
+		} else {
+			before[ticker] = PositionPreview{}
+		}
+
+		simulated := &Asset{ID: ticker, AdjustedNegotiations: positions[ticker]}
+		if simulated.AdjustedNegotiations == nil {
+			simulated.AdjustedNegotiations = []parser.Transaction{}
+		}
+		after[ticker] = PositionPreview{
+			Quantity:           calculateQuantity(simulated),
+			AveragePrice:       calculateAveragePrice(simulated),
+			TotalInvestedValue: calculateTotalInvestedValue(simulated),
+		}
+	}
+
+	return before, after, nil
+}
+
+// applySuccessions liga ativos antigos aos seus sucessores (incorporação, mudança
+// de ticker): o sucessor herda o histórico de proventos e os metadados vazios
+func (w *Wallet) applySuccessions(sorted []CorporateEvent) {
+	for _, asset := range w.Assets {
+		asset.InheritedEarnings = nil
+		asset.SucceededBy = ""
+	}
+
+	for _, e := range sorted {
+		if !successionEvents[e.Type] {
+			continue
+		}
+		source, sourceExists := w.Assets[e.Ticker]
+		target, targetExists := w.Assets[e.TargetTicker]
+		if !sourceExists || !targetExists {
+			continue
+		}
+
+		source.SucceededBy = e.TargetTicker
+
+		// Cadeias (A → B → C) acumulam o histórico em ordem cronológica
+		target.InheritedEarnings = append(target.InheritedEarnings, source.InheritedEarnings...)
+		target.InheritedEarnings = append(target.InheritedEarnings, source.Earnings...)
+
+		if target.SubType == "" {
+			target.SubType = source.SubType
+		}
+		if target.Segment == "" {
+			target.Segment = source.Segment
+		}
+	}
+}
+
+// removeEventArtifacts descarta ativos vazios (sem negociações nem proventos)
+// que não são mais referenciados por nenhum evento, ex: após remover uma incorporação
+func (w *Wallet) removeEventArtifacts() {
+	targets := make(map[string]bool)
+	for _, e := range w.CorporateEvents {
+		targets[e.Ticker] = true
+		for _, ticker := range e.Targets() {
+			targets[ticker] = true
+		}
+	}
+
+	for ticker, asset := range w.Assets {
+		if len(asset.Negotiations) == 0 && len(asset.Earnings) == 0 &&
+			!asset.IsSubscription && !targets[ticker] {
+			delete(w.Assets, ticker)
+		}
+	}
+}
+
+// applyRatioEvent ajusta quantidade e preço das negociações anteriores ao evento
+// pela proporção RatioFrom:RatioTo (qualquer racional positivo, ex: 1:2, 3:2, 10:1)
+// O valor total de cada negociação não muda
+func applyRatioEvent(positions map[string][]parser.Transaction, e CorporateEvent) {
+	negotiations := positions[e.Ticker]
+	for i := range negotiations {
+		tx := &negotiations[i]
+		if !tx.Date.Before(e.Date) {
+			continue
+		}
+		// Multiplicar antes de dividir evita dízimas (ex: grupamento 15:1)
+		tx.Quantity = tx.Quantity.Mul(e.RatioTo).Div(e.RatioFrom)
+		tx.Price = tx.Price.Mul(e.RatioFrom).Div(e.RatioTo)
+	}
+
+	// Proporções como 3:2 ou 5:4 podem deixar uma fração de ação: ela sai da posição
+	// como venda pelo valor do leilão (AuctionAmount) ou por zero quando é truncada
+	// (arredondar antes do Floor ignora resíduos de dízimas somadas entre negociações)
+	held := heldBefore(negotiations, e.Date)
+	fraction := held.Sub(held.Round(8).Floor())
+	if held.IsPositive() && fraction.Round(8).IsPositive() {
+		negotiations = append(negotiations, eventTransaction(e, e.Ticker, "Venda", fraction, e.AuctionAmount))
+	}
+	positions[e.Ticker] = negotiations
+}
+
+// applyBonusEvent adiciona as ações bonificadas como uma compra ao custo atribuído
+// A quantidade é calculada sobre a posição anterior à data do evento; a fração
+// é comprada e vendida no leilão quando AuctionAmount é informado, senão descartada
+func applyBonusEvent(positions map[string][]parser.Transaction, e CorporateEvent) {
+	negotiations := positions[e.Ticker]
+
+	held := heldBefore(negotiations, e.Date)
+	if !held.IsPositive() {
+		return
+	}
+
+	bonus := held.Mul(e.Percent).Div(decimal.NewFromInt(100))
+	whole := bonus.Floor()
+	fraction := bonus.Sub(whole)
+
+	bought := whole
+	if fraction.IsPositive() && e.AuctionAmount.IsPositive() {
+		bought = bonus
+	}
+
+	if bought.IsPositive() {
+		negotiations = append(negotiations, eventTransaction(e, e.Ticker, "Compra", bought, bought.Mul(e.UnitCost).Round(2)))
+	}
+	if !bought.Equal(whole) {
+		negotiations = append(negotiations, eventTransaction(e, e.Ticker, "Venda", fraction, e.AuctionAmount))
+	}
+
+	positions[e.Ticker] = negotiations
+}
+
+// applyConversionEvent converte a posição anterior ao evento para TargetTicker
+// na proporção RatioFrom:RatioTo (1:1 na mudança de ticker), mantendo o custo.
+// O dinheiro pago por ação (CashPerShare) reduz o custo da posição convertida.
+func applyConversionEvent(positions map[string][]parser.Transaction, e CorporateEvent) {
+	ratioFrom, ratioTo := e.RatioFrom, e.RatioTo
+	if e.Type == EventRename || ratioFrom.IsZero() || ratioTo.IsZero() {
+		ratioFrom, ratioTo = decimal.NewFromInt(1), decimal.NewFromInt(1)
+	}
+
+	source := positions[e.Ticker]
+	held := heldBefore(source, e.Date)
+
+	kept := make([]parser.Transaction, 0, len(source))
+	converted := make([]parser.Transaction, 0, len(source))
+	buyAmount, buyQty := decimal.Zero, decimal.Zero
+	for _, tx := range source {
+		if !tx.Date.Before(e.Date) {
+			kept = append(kept, tx)
+			continue
+		}
+		tx.Ticker = e.TargetTicker
+		tx.Quantity = tx.Quantity.Mul(ratioTo).Div(ratioFrom)
+		tx.Price = tx.Price.Mul(ratioFrom).Div(ratioTo)
+		if tx.Type == "Compra" {
+			buyAmount = buyAmount.Add(tx.Amount)
+			buyQty = buyQty.Add(tx.Quantity)
+		}
+		converted = append(converted, tx)
+	}
+
+	// Custo remanescente = quantidade convertida × preço médio; o dinheiro recebido
+	// é abatido escalando todas as compras pelo mesmo fator
+	cash := held.Mul(e.CashPerShare)
+	if cash.IsPositive() && buyQty.IsPositive() {
+		remaining := held.Mul(ratioTo).Div(ratioFrom).Mul(buyAmount.Div(buyQty))
+		factor := decimal.Zero
+		if remaining.GreaterThan(cash) {
+			factor = remaining.Sub(cash).Div(remaining)
+		}
+		for i := range converted {
+			if converted[i].Type == "Compra" {
+				converted[i].Amount = converted[i].Amount.Mul(factor).Round(4)
+				converted[i].Price = converted[i].Price.Mul(factor)
+			}
+		}
+	}
+
+	positions[e.Ticker] = kept
+	positions[e.TargetTicker] = append(positions[e.TargetTicker], converted...)
+}
+
+// applySpinOffEvent transfere parte do custo da posição anterior à cisão para os
+// ativos novos. As compras anteriores à data têm o custo reduzido pela soma dos
+// percentuais; cada ativo novo recebe uma compra com as ações (inteiras) na
+// proporção informada e o custo correspondente ao seu percentual
+func applySpinOffEvent(positions map[string][]parser.Transaction, e CorporateEvent) {
+	parent := positions[e.Ticker]
+
+	held := heldBefore(parent, e.Date)
+	if !held.IsPositive() {
+		return
+	}
+
+	// Custo da posição antes da cisão = quantidade × preço médio das compras anteriores
+	buyAmount, buyQty := decimal.Zero, decimal.Zero
+	for _, tx := range parent {
+		if tx.Date.Before(e.Date) && tx.Type == "Compra" {
+			buyAmount = buyAmount.Add(tx.Amount)
+			buyQty = buyQty.Add(tx.Quantity)
+		}
+	}
+	if !buyQty.IsPositive() {
+		return
+	}
+	costBasis := held.Mul(buyAmount).Div(buyQty)
+
+	hundred := decimal.NewFromInt(100)
+	totalPercent := decimal.Zero
+	for _, target := range e.SpinOffs {
+		totalPercent = totalPercent.Add(target.CostPercent)
+	}
+	keep := hundred.Sub(totalPercent).Div(hundred)
+
+	adjusted := make([]parser.Transaction, len(parent))
+	copy(adjusted, parent)
+	for i := range adjusted {
+		if adjusted[i].Date.Before(e.Date) && adjusted[i].Type == "Compra" {
+			adjusted[i].Amount = adjusted[i].Amount.Mul(keep).Round(4)
+			adjusted[i].Price = adjusted[i].Price.Mul(keep)
+		}
+	}
+	positions[e.Ticker] = adjusted
+
+	for _, target := range e.SpinOffs {
+		shares := held.Mul(target.RatioTo).Div(target.RatioFrom).Floor()
+		if !shares.IsPositive() {
+			continue
+		}
+		cost := costBasis.Mul(target.CostPercent).Div(hundred).Round(2)
+		positions[target.Ticker] = append(positions[target.Ticker], eventTransaction(e, target.Ticker, "Compra", shares, cost))
+	}
+}
+
+// applyAmortization reduz o custo da posição mantida antes da data pelo valor
+// devolvido (AuctionAmount): as compras anteriores têm valor e preço reduzidos na
+// mesma proporção, então a quantidade não muda e o preço médio cai
+func applyAmortization(positions map[string][]parser.Transaction, e CorporateEvent) {
+	negotiations := positions[e.Ticker]
+
+	held := heldBefore(negotiations, e.Date)
+	if !held.IsPositive() {
+		return
+	}
+
+	buyAmount, buyQty := decimal.Zero, decimal.Zero
+	for _, tx := range negotiations {
+		if tx.Date.Before(e.Date) && tx.Type == "Compra" {
+			buyAmount = buyAmount.Add(tx.Amount)
+			buyQty = buyQty.Add(tx.Quantity)
+		}
+	}
+	if !buyQty.IsPositive() || !buyAmount.IsPositive() {
+		return
+	}
+
+	costBasis := held.Mul(buyAmount).Div(buyQty)
+	factor := costBasis.Sub(e.AuctionAmount).Div(costBasis)
+	if factor.IsNegative() {
+		factor = decimal.Zero
+	}
+
+	adjusted := make([]parser.Transaction, len(negotiations))
+	copy(adjusted, negotiations)
+	for i := range adjusted {
+		if adjusted[i].Date.Before(e.Date) && adjusted[i].Type == "Compra" {
+			adjusted[i].Amount = adjusted[i].Amount.Mul(factor).Round(4)
+			adjusted[i].Price = adjusted[i].Price.Mul(factor)
+		}
+	}
+	positions[e.Ticker] = adjusted
+}
+
+// applyRightsReceivedEvent credita os direitos recebidos como compra ao custo informado
+func applyRightsReceivedEvent(positions map[string][]parser.Transaction, e CorporateEvent) {
+	amount := e.Quantity.Mul(e.UnitCost).Round(2)
+	positions[e.Ticker] = append(positions[e.Ticker], eventTransaction(e, e.Ticker, "Compra", e.Quantity, amount))
+}
+
+// applyRightsExercisedEvent converte os direitos exercidos em compra do ativo pai na
+// data de liquidação: custo = preço de exercício + custo médio dos direitos baixados.
+// Os direitos saem da posição pelo próprio custo, sem gerar resultado
+func applyRightsExercisedEvent(positions map[string][]parser.Transaction, e CorporateEvent) {
+	rights := positions[e.Ticker]
+
+	consumed := decimal.Min(e.Quantity, heldUntil(rights, e.Date))
+	rightsCost := decimal.Zero
+	if consumed.IsPositive() {
+		rightsCost = consumed.Mul(averageCostUntil(rights, e.Date)).Round(2)
+		positions[e.Ticker] = append(rights, eventTransaction(e, e.Ticker, "Venda", consumed, rightsCost))
+	}
+
+	amount := e.Quantity.Mul(e.UnitCost).Round(2).Add(rightsCost)
+	positions[e.TargetTicker] = append(positions[e.TargetTicker], eventTransaction(e, e.TargetTicker, "Compra", e.Quantity, amount))
+}
+
+// applyRightsExpiredEvent baixa os direitos não exercidos nem vendidos sem valor
+// recebido: o custo deles vira prejuízo
+func applyRightsExpiredEvent(positions map[string][]parser.Transaction, e CorporateEvent) {
+	rights := positions[e.Ticker]
+
+	remaining := heldUntil(rights, e.Date)
+	if remaining.IsPositive() {
+		positions[e.Ticker] = append(rights, eventTransaction(e, e.Ticker, "Venda", remaining, decimal.Zero))
+	}
+}
+
+// heldUntil calcula a quantidade mantida até a data (inclusive)
+func heldUntil(negotiations []parser.Transaction, date time.Time) decimal.Decimal {
+	return heldBefore(negotiations, date.AddDate(0, 0, 1))
+}
+
+// averageCostUntil calcula o custo médio das compras até a data (inclusive)
+func averageCostUntil(negotiations []parser.Transaction, date time.Time) decimal.Decimal {
+	amount, quantity := decimal.Zero, decimal.Zero
+	for _, tx := range negotiations {
+		if tx.Type == "Compra" && !tx.Date.After(date) {
+			amount = amount.Add(tx.Amount)
+			quantity = quantity.Add(tx.Quantity)
+		}
+	}
+	if quantity.IsZero() {
+		return decimal.Zero
+	}
+	return amount.Div(quantity)
+}
+
+// heldBefore calcula a quantidade em carteira antes de uma data
+func heldBefore(negotiations []parser.Transaction, date time.Time) decimal.Decimal {
+	held := decimal.Zero
+	for _, tx := range negotiations {
+		if !tx.Date.Before(date) {
+			continue
+		}
+		switch tx.Type {
+		case "Compra":
+			held = held.Add(tx.Quantity)
+		case "Venda":
+			held = held.Sub(tx.Quantity)
+		}
+	}
+	return held
+}
+
+// eventTransaction cria uma negociação sintética gerada por um evento
+// O hash identifica o evento de origem e nunca colide com transações importadas
+func eventTransaction(e CorporateEvent, ticker, txType string, quantity, amount decimal.Decimal) parser.Transaction {
+	return parser.Transaction{
+		Date:        e.Date,
+		Type:        txType,
+		Institution: eventInstitution,
+		Ticker:      ticker,
+		Quantity:    quantity,
+		Price:       amount.Div(quantity),
+		Amount:      amount,
+		Hash:        eventTransactionHash(e, ticker, txType),
+	}
+}
+
+// eventTransactionHash identifica a negociação sintética de um evento
+func eventTransactionHash(e CorporateEvent, ticker, txType string) string {
+	return "event:" + e.ID + ":" + ticker + ":" + strings.ToLower(txType)
+}
+
+// QuantityBefore retorna a quantidade em carteira antes da data (com eventos aplicados)
+func (a *Asset) QuantityBefore(date time.Time) decimal.Decimal {
+	return heldBefore(a.EffectiveNegotiations(), date)
+}
+
+// sortCorporateEvents ordena eventos por data (e ID para desempate estável)
+func sortCorporateEvents(list []CorporateEvent) {
+	sort.SliceStable(list, func(i, j int) bool {
+		if !list[i].Date.Equal(list[j].Date) {
+			return list[i].Date.Before(list[j].Date)
+		}
+		return list[i].ID < list[j].ID
+	})
+}
+
+// eventInputs describes an event for the journal
+func eventInputs(e CorporateEvent) map[string]string {
+	inputs := map[string]string{
+		"id":     e.ID,
+		"type":   e.Type,
+		"ticker": e.Ticker,
+		"date":   e.Date.Format("2006-01-02"),
+	}
+	if !e.RatioFrom.IsZero() || !e.RatioTo.IsZero() {
+		inputs["ratio"] = e.RatioFrom.String() + ":" + e.RatioTo.String()
+	}
+	if !e.Percent.IsZero() {
+		inputs["percent"] = e.Percent.String()
+		inputs["unit_cost"] = e.UnitCost.String()
+	}
+	if !e.AuctionAmount.IsZero() {
+		inputs["auction_amount"] = e.AuctionAmount.String()
+	}
+	if e.TargetTicker != "" {
+		inputs["target"] = e.TargetTicker
+	}
+	if !e.CashPerShare.IsZero() {
+		inputs["cash_per_share"] = e.CashPerShare.String()
+	}
+	if !e.Quantity.IsZero() {
+		inputs["quantity"] = e.Quantity.String()
+	}
+	if len(e.SpinOffs) > 0 {
+		tickers := make([]string, 0, len(e.SpinOffs))
+		details := make([]string, 0, len(e.SpinOffs))
+		for _, target := range e.SpinOffs {
+			tickers = append(tickers, target.Ticker)
+			details = append(details, fmt.Sprintf("%s %s%% %s:%s", target.Ticker, target.CostPercent, target.RatioFrom, target.RatioTo))
+		}
+		inputs["targets"] = strings.Join(tickers, ",")
+		inputs["spin_offs"] = strings.Join(details, "; ")
+	}
+	if e.Notes != "" {
+		inputs["notes"] = e.Notes
+	}
+	return inputs
+}
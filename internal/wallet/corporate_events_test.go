@@ -0,0 +1,360 @@
+package wallet
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/shopspring/decimal"
+)
+
+func splitEvent(ticker string, day int, to int64) CorporateEvent {
+	return CorporateEvent{
+		Type:      EventSplit,
+		Ticker:    ticker,
+		Date:      time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC),
+		RatioFrom: decimal.NewFromInt(1),
+		RatioTo:   decimal.NewFromInt(to),
+	}
+}
+
+func TestCorporateEvents_AppliedDuringCalculation(t *testing.T) {
+	w, _ := newTestWallet(t)
+
+	// 100 @ 10 before the split, 100 @ 10 after it
+	if err := w.AddTransaction(testBuy("PETR4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+	if err := w.AddTransaction(testBuy("PETR4", time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+	original := w.Assets["PETR4"].Negotiations[0]
+
+	event, err := w.AddCorporateEvent(splitEvent("PETR4", 20, 2))
+	if err != nil {
+		t.Fatalf("AddCorporateEvent returned error: %v", err)
+	}
+	if event.ID == "" {
+		t.Fatal("event ID not set")
+	}
+
+	asset := w.Assets["PETR4"]
+	if asset.Quantity != 300 {
+		t.Errorf("quantity = %d, expected 300", asset.Quantity)
+	}
+	if !asset.AveragePrice.Equal(decimal.RequireFromString("6.6667")) {
+		t.Errorf("average price = %s, expected 6.6667", asset.AveragePrice)
+	}
+	if !asset.TotalInvestedValue.Equal(decimal.NewFromInt(2000)) {
+		t.Errorf("total invested = %s, expected 2000", asset.TotalInvestedValue)
+	}
+
+	stored := asset.Negotiations[0]
+	if !stored.Quantity.Equal(original.Quantity) || stored.Hash != original.Hash {
+		t.Errorf("imported transaction was modified: %+v", stored)
+	}
+
+	// Duplicates are rejected
+	if _, err := w.AddCorporateEvent(splitEvent("PETR4", 20, 2)); err == nil {
+		t.Error("expected error for duplicate event")
+	}
+
+	// Removing the event restores the original position
+	if _, err := w.RemoveCorporateEvent(event.ID[:6]); err != nil {
+		t.Fatalf("RemoveCorporateEvent returned error: %v", err)
+	}
+	if asset.Quantity != 200 || !asset.AveragePrice.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("after remove: quantity %d, average %s", asset.Quantity, asset.AveragePrice)
+	}
+
+	ops := make([]string, 0)
+	for _, entry := range w.PendingJournal() {
+		ops = append(ops, entry.Operation)
+	}
+	if got := strings.Join(ops, ","); !strings.HasSuffix(got, "AddCorporateEvent,RemoveCorporateEvent") {
+		t.Errorf("journal operations = %s", got)
+	}
+}
+
+func TestCorporateEvents_ChronologicalOrder(t *testing.T) {
+	w, _ := newTestWallet(t)
+	if err := w.AddTransaction(testBuy("ITSA4", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+
+	// Registered out of order: grouping 10:1 on day 20 after split 1:5 on day 10
+	grouping := CorporateEvent{
+		Type:      EventGrouping,
+		Ticker:    "ITSA4",
+		Date:      time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC),
+		RatioFrom: decimal.NewFromInt(10),
+		RatioTo:   decimal.NewFromInt(1),
+	}
+	if _, err := w.AddCorporateEvent(grouping); err != nil {
+		t.Fatalf("AddCorporateEvent returned error: %v", err)
+	}
+	if _, err := w.AddCorporateEvent(splitEvent("ITSA4", 10, 5)); err != nil {
+		t.Fatalf("AddCorporateEvent returned error: %v", err)
+	}
+
+	// 100 × 5 ÷ 10 = 50
+	if q := w.Assets["ITSA4"].Quantity; q != 50 {
+		t.Errorf("quantity = %d, expected 50", q)
+	}
+
+	events := w.SortedCorporateEvents("ITSA4")
+	if len(events) != 2 || events[0].Type != EventSplit {
+		t.Errorf("events not sorted chronologically: %+v", events)
+	}
+}
+
+func TestCorporateEvents_Validation(t *testing.T) {
+	w, _ := newTestWallet(t)
+
+	invalid := []CorporateEvent{
+		{Type: EventSplit, Date: time.Now(), RatioFrom: decimal.NewFromInt(1), RatioTo: decimal.NewFromInt(2)},
+		{Type: "unknown", Ticker: "PETR4", Date: time.Now()},
+		splitEvent("PETR4", 10, 1),
+		{Type: EventGrouping, Ticker: "PETR4", Date: time.Now(), RatioFrom: decimal.NewFromInt(1), RatioTo: decimal.NewFromInt(10)},
+	}
+	for i, e := range invalid {
+		if _, err := w.AddCorporateEvent(e); err == nil {
+			t.Errorf("event %d: expected validation error", i)
+		}
+	}
+
+	if _, err := w.RemoveCorporateEvent("abc"); err == nil {
+		t.Error("expected error removing unknown event")
+	}
+}
+
+func TestCorporateEvents_Persistence(t *testing.T) {
+	w, dir := newTestWallet(t)
+	if err := w.AddTransaction(testBuy("PETR4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+	event := splitEvent("PETR4", 20, 3)
+	event.Notes = "desdobramento 1:3"
+	added, err := w.AddCorporateEvent(event)
+	if err != nil {
+		t.Fatalf("AddCorporateEvent returned error: %v", err)
+	}
+	bonus := CorporateEvent{
+		Type:     EventBonus,
+		Ticker:   "PETR4",
+		Date:     time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		Percent:  decimal.NewFromInt(10),
+		UnitCost: decimal.RequireFromString("4.25"),
+	}
+	if _, err := w.AddCorporateEvent(bonus); err != nil {
+		t.Fatalf("AddCorporateEvent returned error: %v", err)
+	}
+	if err := w.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	reloaded, err := LoadWithKey(dir, append([]byte(nil), w.GetEncryptionKey()...))
+	if err != nil {
+		t.Fatalf("LoadWithKey returned error: %v", err)
+	}
+
+	if len(reloaded.CorporateEvents) != 2 {
+		t.Fatalf("reloaded %d events, expected 2", len(reloaded.CorporateEvents))
+	}
+	got := reloaded.CorporateEvents[0]
+	if got.ID != added.ID || got.Notes != event.Notes || !got.RatioTo.Equal(event.RatioTo) {
+		t.Errorf("reloaded event = %+v", got)
+	}
+	if b := reloaded.CorporateEvents[1]; !b.Percent.Equal(bonus.Percent) || !b.UnitCost.Equal(bonus.UnitCost) || !b.RatioFrom.IsZero() {
+		t.Errorf("reloaded bonus = %+v", b)
+	}
+
+	// 100 × 3 = 300, plus 10% bonus = 330
+	if reloaded.Assets["PETR4"].Quantity != 330 {
+		t.Errorf("reloaded quantity = %d, expected 330", reloaded.Assets["PETR4"].Quantity)
+	}
+	if !reloaded.Transactions[0].Quantity.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("stored transaction quantity = %s, expected 100", reloaded.Transactions[0].Quantity)
+	}
+}
+
+func TestCorporateEvents_MergePersistence(t *testing.T) {
+	w, dir := newTestWallet(t)
+	if err := w.AddTransaction(testBuy("OLDC3", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+	merge := CorporateEvent{
+		Type:         EventMerge,
+		Ticker:       "OLDC3",
+		TargetTicker: "NEWC3",
+		Date:         time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		RatioFrom:    decimal.NewFromInt(2),
+		RatioTo:      decimal.NewFromInt(1),
+		CashPerShare: decimal.RequireFromString("0.5"),
+	}
+	if _, err := w.AddCorporateEvent(merge); err != nil {
+		t.Fatalf("AddCorporateEvent returned error: %v", err)
+	}
+	if err := w.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	reloaded, err := LoadWithKey(dir, append([]byte(nil), w.GetEncryptionKey()...))
+	if err != nil {
+		t.Fatalf("LoadWithKey returned error: %v", err)
+	}
+
+	got := reloaded.CorporateEvents[0]
+	if got.TargetTicker != "NEWC3" || !got.CashPerShare.Equal(merge.CashPerShare) {
+		t.Errorf("reloaded merge = %+v", got)
+	}
+
+	// 100 old shares → 50 new; cost 1000 − 50 cash = 950
+	target := reloaded.Assets["NEWC3"]
+	if target == nil || target.Quantity != 50 || !target.TotalInvestedValue.Equal(decimal.NewFromInt(950)) {
+		t.Errorf("reloaded target = %+v", target)
+	}
+	if reloaded.Assets["OLDC3"].SucceededBy != "NEWC3" {
+		t.Error("reloaded source is not linked to its successor")
+	}
+}
+
+func TestCorporateEvents_SpinOffPersistence(t *testing.T) {
+	w, dir := newTestWallet(t)
+	if err := w.AddTransaction(testBuy("OLDC3", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+	spinOff := CorporateEvent{
+		Type:   EventSpinOff,
+		Ticker: "OLDC3",
+		Date:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		SpinOffs: []SpinOffTarget{
+			{Ticker: "NEWA3", CostPercent: decimal.NewFromInt(25), RatioFrom: decimal.NewFromInt(2), RatioTo: decimal.NewFromInt(1)},
+		},
+	}
+	if _, err := w.AddCorporateEvent(spinOff); err != nil {
+		t.Fatalf("AddCorporateEvent returned error: %v", err)
+	}
+	if err := w.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	reloaded, err := LoadWithKey(dir, append([]byte(nil), w.GetEncryptionKey()...))
+	if err != nil {
+		t.Fatalf("LoadWithKey returned error: %v", err)
+	}
+
+	got := reloaded.CorporateEvents[0]
+	if len(got.SpinOffs) != 1 || got.SpinOffs[0].Ticker != "NEWA3" || !got.SpinOffs[0].RatioFrom.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("reloaded spin-off = %+v", got)
+	}
+
+	// 100 shares at 10: NEWA3 gets 50 shares with 25% of the cost (250)
+	target := reloaded.Assets["NEWA3"]
+	if target == nil || target.Quantity != 50 || !target.TotalInvestedValue.Equal(decimal.NewFromInt(250)) {
+		t.Errorf("reloaded spin-off target = %+v", target)
+	}
+	if parent := reloaded.Assets["OLDC3"]; parent.Quantity != 100 || !parent.AveragePrice.Equal(decimal.RequireFromString("7.5")) {
+		t.Errorf("reloaded parent: quantity %d, avg %s", parent.Quantity, parent.AveragePrice)
+	}
+}
+
+func TestCorporateEvents_SubscriptionPersistence(t *testing.T) {
+	w, dir := newTestWallet(t)
+	if err := w.AddTransaction(testBuy("MXRF11", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+	steps := []CorporateEvent{
+		{Type: EventRightsReceived, Ticker: "MXRF12", TargetTicker: "MXRF11", Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Quantity: decimal.NewFromInt(20)},
+		{Type: EventRightsExercised, Ticker: "MXRF12", TargetTicker: "MXRF11", Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Quantity: decimal.NewFromInt(12), UnitCost: decimal.NewFromInt(9)},
+		{Type: EventRightsExpired, Ticker: "MXRF12", Date: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, e := range steps {
+		if _, err := w.AddCorporateEvent(e); err != nil {
+			t.Fatalf("AddCorporateEvent(%s) returned error: %v", e.Type, err)
+		}
+	}
+	if err := w.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	reloaded, err := LoadWithKey(dir, append([]byte(nil), w.GetEncryptionKey()...))
+	if err != nil {
+		t.Fatalf("LoadWithKey returned error: %v", err)
+	}
+
+	status, err := reloaded.SubscriptionStatus("MXRF12")
+	if err != nil {
+		t.Fatalf("SubscriptionStatus returned error: %v", err)
+	}
+	if status.ParentTicker != "MXRF11" || !status.Exercised.Equal(decimal.NewFromInt(12)) || !status.Expired.Equal(decimal.NewFromInt(8)) {
+		t.Errorf("reloaded status = %+v", status)
+	}
+	// 100 @ 10 plus 12 @ 9 (rights received for free)
+	if parent := reloaded.Assets["MXRF11"]; parent.Quantity != 112 || !parent.TotalInvestedValue.Equal(decimal.NewFromInt(1108)) {
+		t.Errorf("reloaded parent: quantity %d, invested %s", parent.Quantity, parent.TotalInvestedValue)
+	}
+	if got := reloaded.SubscriptionRights(); len(got) != 1 || got[0] != "MXRF12" {
+		t.Errorf("SubscriptionRights = %v", got)
+	}
+}
+
+func TestCorporateEvents_CapitalReturnReducesCost(t *testing.T) {
+	w, _ := newTestWallet(t)
+
+	// 200 @ 10 before the amortization, 100 @ 10 after it
+	for _, day := range []int{5, 10, 25} {
+		if err := w.AddTransaction(testBuy("HGLG11", time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+			t.Fatalf("AddTransaction returned error: %v", err)
+		}
+	}
+
+	income := parser.Earning{
+		Date:        time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Type:        "Rendimento",
+		Ticker:      "HGLG11",
+		Quantity:    decimal.NewFromInt(200),
+		UnitPrice:   decimal.NewFromInt(1),
+		TotalAmount: decimal.NewFromInt(200),
+	}
+	income.Hash = parser.CalculateEarningHash(&income)
+
+	amortization := parser.Earning{
+		Date:        time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC),
+		Type:        "Amortização",
+		Ticker:      "HGLG11",
+		Quantity:    decimal.NewFromInt(200),
+		UnitPrice:   decimal.NewFromInt(2),
+		TotalAmount: decimal.NewFromInt(400),
+	}
+	amortization.Hash = parser.CalculateEarningHash(&amortization)
+
+	for _, e := range []parser.Earning{income, amortization} {
+		if err := w.AddEarning(e); err != nil {
+			t.Fatalf("AddEarning returned error: %v", err)
+		}
+	}
+
+	asset := w.Assets["HGLG11"]
+	if !asset.TotalEarnings.Equal(decimal.NewFromInt(200)) {
+		t.Errorf("total earnings = %s, expected 200 (amortization excluded)", asset.TotalEarnings)
+	}
+	if !asset.TotalCapitalReturned.Equal(decimal.NewFromInt(400)) {
+		t.Errorf("capital returned = %s, expected 400", asset.TotalCapitalReturned)
+	}
+
+	// Cost of the 200 shares drops from 2000 to 1600; the later buy is unaffected
+	if asset.Quantity != 300 {
+		t.Errorf("quantity = %d, expected 300", asset.Quantity)
+	}
+	if !asset.TotalInvestedValue.Equal(decimal.NewFromInt(2600)) {
+		t.Errorf("total invested = %s, expected 2600", asset.TotalInvestedValue)
+	}
+	if !asset.AveragePrice.Equal(decimal.RequireFromString("8.6667")) {
+		t.Errorf("average price = %s, expected 8.6667", asset.AveragePrice)
+	}
+	if !asset.Negotiations[0].Amount.Equal(decimal.NewFromInt(1000)) {
+		t.Error("imported transaction was modified")
+	}
+}
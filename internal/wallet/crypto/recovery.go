@@ -0,0 +1,158 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GenerateRecoveryCode creates a new high-entropy recovery code
+// Returns the word-list encoded code that must be shown to the user exactly once
+func GenerateRecoveryCode() (string, error) {
+	entropy := make([]byte, RecoveryEntropySize)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", fmt.Errorf("failed to generate recovery code: %w", err)
+	}
+	defer ZeroBytes(entropy)
+
+	return EncodeRecoveryCode(entropy), nil
+}
+
+// EncodeRecoveryCode encodes entropy bytes as words, appending a checksum
+// Each byte maps to one word of recoveryWordList
+func EncodeRecoveryCode(entropy []byte) string {
+	checksum := sha256.Sum256(entropy)
+
+	payload := make([]byte, 0, len(entropy)+RecoveryChecksumSize)
+	payload = append(payload, entropy...)
+	payload = append(payload, checksum[:RecoveryChecksumSize]...)
+
+	words := make([]string, len(payload))
+	for i, b := range payload {
+		words[i] = recoveryWordList[b]
+	}
+
+	return strings.Join(words, " ")
+}
+
+// DecodeRecoveryCode decodes a word-list encoded recovery code back to its entropy
+// Accepts any mix of spaces, dashes and letter case, and validates the checksum
+func DecodeRecoveryCode(code string) ([]byte, error) {
+	fields := strings.FieldsFunc(strings.ToLower(code), func(r rune) bool {
+		return r == ' ' || r == '-' || r == '\t' || r == '\n' || r == ','
+	})
+
+	expectedWords := RecoveryEntropySize + RecoveryChecksumSize
+	if len(fields) != expectedWords {
+		return nil, fmt.Errorf("invalid recovery code: expected %d words, got %d", expectedWords, len(fields))
+	}
+
+	payload := make([]byte, len(fields))
+	for i, word := range fields {
+		b, ok := recoveryWordIndex[word]
+		if !ok {
+			return nil, fmt.Errorf("invalid recovery code: unknown word %q at position %d", word, i+1)
+		}
+		payload[i] = b
+	}
+
+	entropy := payload[:RecoveryEntropySize]
+	checksum := sha256.Sum256(entropy)
+	if !bytes.Equal(payload[RecoveryEntropySize:], checksum[:RecoveryChecksumSize]) {
+		return nil, fmt.Errorf("invalid recovery code: checksum mismatch (check for typos)")
+	}
+
+	return entropy, nil
+}
+
+// deriveRecoveryKey derives the key that wraps the encryption key from a recovery code
+// Uses the same Argon2id parameters as the master password
+func deriveRecoveryKey(code string, salt []byte) ([]byte, error) {
+	entropy, err := DecodeRecoveryCode(code)
+	if err != nil {
+		return nil, err
+	}
+	defer ZeroBytes(entropy)
+
+	return DeriveKey(hex.EncodeToString(entropy), salt), nil
+}
+
+// InitializeRecovery wraps the encryption key with a newly generated recovery code
+// The wrapped key is stored in recovery_key.bin as salt + encrypted key
+// Returns the recovery code, which is never stored on disk
+func InitializeRecovery(dirPath string, encryptionKey []byte) (string, error) {
+	code, err := GenerateRecoveryCode()
+	if err != nil {
+		return "", err
+	}
+
+	// Generate a salt independent from the master password salt
+	salt, err := GenerateSalt()
+	if err != nil {
+		return "", err
+	}
+
+	recoveryKey, err := deriveRecoveryKey(code, salt)
+	if err != nil {
+		return "", err
+	}
+	defer ZeroBytes(recoveryKey)
+
+	// Encrypt the encryption key with the recovery key
+	encryptedKey, err := Encrypt(encryptionKey, recoveryKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt encryption key: %w", err)
+	}
+
+	content := make([]byte, 0, len(salt)+len(encryptedKey))
+	content = append(content, salt...)
+	content = append(content, encryptedKey...)
+
+	recoveryPath := filepath.Join(dirPath, RecoveryKeyFileName)
+	if err := os.WriteFile(recoveryPath, content, 0600); err != nil {
+		return "", fmt.Errorf("failed to save recovery key: %w", err)
+	}
+
+	return code, nil
+}
+
+// RecoverEncryptionKey unwraps the encryption key using the recovery code
+// Returns the encryption key that should be kept in memory
+func RecoverEncryptionKey(dirPath, code string) ([]byte, error) {
+	recoveryPath := filepath.Join(dirPath, RecoveryKeyFileName)
+	content, err := os.ReadFile(recoveryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recovery key: %w", err)
+	}
+
+	if len(content) <= SaltSize {
+		return nil, fmt.Errorf("recovery key file is corrupted")
+	}
+
+	salt := content[:SaltSize]
+	encryptedKey := content[SaltSize:]
+
+	recoveryKey, err := deriveRecoveryKey(code, salt)
+	if err != nil {
+		return nil, err
+	}
+	defer ZeroBytes(recoveryKey)
+
+	encryptionKey, err := Decrypt(encryptedKey, recoveryKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock vault (incorrect recovery code?): %w", err)
+	}
+
+	return encryptionKey, nil
+}
+
+// HasRecoveryKey checks if a recovery key was generated for the wallet
+func HasRecoveryKey(dirPath string) bool {
+	_, err := os.Stat(filepath.Join(dirPath, RecoveryKeyFileName))
+	return err == nil
+}
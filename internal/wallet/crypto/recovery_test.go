@@ -0,0 +1,149 @@
+package crypto
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecoveryCodeRoundTrip(t *testing.T) {
+	entropy := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 250, 251, 252, 253, 254, 255}
+
+	code := EncodeRecoveryCode(entropy)
+	words := strings.Fields(code)
+	if len(words) != RecoveryEntropySize+RecoveryChecksumSize {
+		t.Fatalf("code has %d words, expected %d", len(words), RecoveryEntropySize+RecoveryChecksumSize)
+	}
+
+	decoded, err := DecodeRecoveryCode(code)
+	if err != nil {
+		t.Fatalf("DecodeRecoveryCode returned error: %v", err)
+	}
+	if !bytes.Equal(decoded, entropy) {
+		t.Errorf("decoded = %v, expected %v", decoded, entropy)
+	}
+
+	// Case and separators should not matter
+	loose := strings.ToUpper(strings.Join(words, "-"))
+	if _, err := DecodeRecoveryCode(loose); err != nil {
+		t.Errorf("DecodeRecoveryCode(%q) returned error: %v", loose, err)
+	}
+}
+
+func TestDecodeRecoveryCode_Errors(t *testing.T) {
+	code := EncodeRecoveryCode(make([]byte, RecoveryEntropySize))
+	words := strings.Fields(code)
+
+	t.Run("missing word", func(t *testing.T) {
+		if _, err := DecodeRecoveryCode(strings.Join(words[1:], " ")); err == nil {
+			t.Error("expected error for missing word")
+		}
+	})
+
+	t.Run("unknown word", func(t *testing.T) {
+		broken := append([]string{"notaword"}, words[1:]...)
+		if _, err := DecodeRecoveryCode(strings.Join(broken, " ")); err == nil {
+			t.Error("expected error for unknown word")
+		}
+	})
+
+	t.Run("typo caught by checksum", func(t *testing.T) {
+		swapped := append([]string{recoveryWordList[1]}, words[1:]...)
+		if _, err := DecodeRecoveryCode(strings.Join(swapped, " ")); err == nil {
+			t.Error("expected checksum error")
+		}
+	})
+}
+
+func TestRecoverAndChangePassword(t *testing.T) {
+	dir := t.TempDir()
+
+	encryptionKey, err := InitializeVault(dir, "original-password")
+	if err != nil {
+		t.Fatalf("InitializeVault returned error: %v", err)
+	}
+
+	code, err := InitializeRecovery(dir, encryptionKey)
+	if err != nil {
+		t.Fatalf("InitializeRecovery returned error: %v", err)
+	}
+	if !HasRecoveryKey(dir) {
+		t.Fatal("recovery key file was not created")
+	}
+
+	recovered, err := RecoverEncryptionKey(dir, code)
+	if err != nil {
+		t.Fatalf("RecoverEncryptionKey returned error: %v", err)
+	}
+	if !bytes.Equal(recovered, encryptionKey) {
+		t.Fatal("recovered key does not match encryption key")
+	}
+
+	if err := ChangePassword(dir, recovered, "brand-new-password"); err != nil {
+		t.Fatalf("ChangePassword returned error: %v", err)
+	}
+
+	if _, err := UnlockVault(dir, "original-password"); err == nil {
+		t.Error("old password should no longer unlock the vault")
+	}
+
+	unlocked, err := UnlockVault(dir, "brand-new-password")
+	if err != nil {
+		t.Fatalf("new password should unlock the vault: %v", err)
+	}
+	if !bytes.Equal(unlocked, encryptionKey) {
+		t.Error("new password unwrapped a different key")
+	}
+
+	// Vault content must still decrypt with the same key
+	if _, err := LoadVault(dir, unlocked); err != nil {
+		t.Errorf("LoadVault after password change returned error: %v", err)
+	}
+}
+
+func TestChangePassword_RollsBackKeyWhenSaltFails(t *testing.T) {
+	dir := t.TempDir()
+
+	encryptionKey, err := InitializeVault(dir, "original-password")
+	if err != nil {
+		t.Fatalf("InitializeVault returned error: %v", err)
+	}
+
+	// A non-empty directory in place of the salt makes the salt rename fail
+	// after the key rename succeeded
+	saltPath := filepath.Join(dir, SaltFileName)
+	oldSalt, err := os.ReadFile(saltPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(saltPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(saltPath, "blocker"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ChangePassword(dir, encryptionKey, "brand-new-password"); err == nil {
+		t.Fatal("expected ChangePassword to fail")
+	}
+	if _, err := os.Stat(filepath.Join(dir, EncryptedKeyFileName+".tmp")); !os.IsNotExist(err) {
+		t.Error("temporary key file left behind")
+	}
+
+	// The previous key must be back in place: the old salt and password unlock it
+	if err := os.RemoveAll(saltPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(saltPath, oldSalt, 0600); err != nil {
+		t.Fatal(err)
+	}
+	unlocked, err := UnlockVault(dir, "original-password")
+	if err != nil {
+		t.Fatalf("old password should still unlock the vault: %v", err)
+	}
+	if !bytes.Equal(unlocked, encryptionKey) {
+		t.Error("old password unwrapped a different key")
+	}
+}
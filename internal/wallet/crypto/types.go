@@ -13,16 +13,22 @@ const (
 	EncryptionKeySize = 32 // 256 bits for AES-256
 	NonceSize         = 12 // 96 bits for GCM
 
+	// Recovery code sizes (one word per byte)
+	RecoveryEntropySize  = 16 // 128 bits
+	RecoveryChecksumSize = 2  // detects typos when the code is typed back
+
 	// File names
 	VaultFileName        = "vault.enc"
 	SaltFileName         = "salt.bin"
 	EncryptedKeyFileName = "encrypted_key.bin"
 	MetadataFileName     = "metadata.yaml"
+	RecoveryKeyFileName  = "recovery_key.bin"
 )
 
-// Metadata stores non-sensitive information about the encrypted wallet
+// Metadata stores non-sensitive information about the encryption of the wallet
+// It carries no format version: the layout of the vault is identified only by
+// schema_version inside the encrypted vault (see VaultData)
 type Metadata struct {
-	Version   string `yaml:"version"`
 	Algorithm string `yaml:"algorithm"`
 	KDF       string `yaml:"kdf"`
 }
@@ -30,7 +36,6 @@ type Metadata struct {
 // DefaultMetadata returns the current encryption metadata
 func DefaultMetadata() Metadata {
 	return Metadata{
-		Version:   "1.0",
 		Algorithm: "AES-256-GCM",
 		KDF:       "Argon2id",
 	}
@@ -9,9 +9,13 @@ import (
 )
 
 // VaultData represents the complete wallet data to be encrypted
+// The crypto layer does not interpret the content: SchemaVersion is owned by the
+// wallet package and any other top-level sections are kept in Extra
 type VaultData struct {
-	Transactions interface{} `yaml:"transactions"`
-	Assets       interface{} `yaml:"assets"`
+	SchemaVersion int                    `yaml:"schema_version,omitempty"`
+	Transactions  interface{}            `yaml:"transactions"`
+	Assets        interface{}            `yaml:"assets"`
+	Extra         map[string]interface{} `yaml:",inline"`
 }
 
 // InitializeVault creates a new encrypted vault with the given password
@@ -122,6 +126,78 @@ func UnlockVault(dirPath, password string) ([]byte, error) {
 	return encryptionKey, nil
 }
 
+// ChangePassword wraps the existing encryption key with a new master password
+// A fresh salt is generated, so the old password can no longer unlock the vault.
+// The vault itself is not re-encrypted because the encryption key does not change
+func ChangePassword(dirPath string, encryptionKey []byte, newPassword string) error {
+	// Validate password strength
+	if len(newPassword) < 12 {
+		return fmt.Errorf("password must be at least 12 characters long")
+	}
+
+	// Generate salt
+	salt, err := GenerateSalt()
+	if err != nil {
+		return err
+	}
+
+	// Derive master key from the new password
+	masterKey := DeriveKey(newPassword, salt)
+	defer ZeroBytes(masterKey)
+
+	// Encrypt the encryption key with the new master key
+	encryptedKey, err := Encrypt(encryptionKey, masterKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt encryption key: %w", err)
+	}
+
+	// Write both files to temporary paths first so a failed write
+	// never leaves a truncated key or salt behind
+	keyPath := filepath.Join(dirPath, EncryptedKeyFileName)
+	saltPath := filepath.Join(dirPath, SaltFileName)
+
+	// The current wrapped key is kept to roll back: a new key next to the old
+	// salt would be unlockable by no password
+	oldKey, err := os.ReadFile(keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to read encrypted key: %w", err)
+	}
+
+	if err := os.WriteFile(keyPath+".tmp", encryptedKey, 0600); err != nil {
+		os.Remove(keyPath + ".tmp")
+		return fmt.Errorf("failed to save encrypted key: %w", err)
+	}
+	if err := os.WriteFile(saltPath+".tmp", salt, 0600); err != nil {
+		os.Remove(keyPath + ".tmp")
+		os.Remove(saltPath + ".tmp")
+		return fmt.Errorf("failed to save salt: %w", err)
+	}
+
+	if err := os.Rename(keyPath+".tmp", keyPath); err != nil {
+		os.Remove(keyPath + ".tmp")
+		os.Remove(saltPath + ".tmp")
+		return fmt.Errorf("failed to save encrypted key: %w", err)
+	}
+	if err := os.Rename(saltPath+".tmp", saltPath); err != nil {
+		os.Remove(saltPath + ".tmp")
+		if rollbackErr := restoreFile(keyPath, oldKey); rollbackErr != nil {
+			return fmt.Errorf("failed to save salt: %w (restoring the previous key also failed: %v)", err, rollbackErr)
+		}
+		return fmt.Errorf("failed to save salt: %w", err)
+	}
+
+	return nil
+}
+
+// restoreFile atomically puts previous content back in place
+func restoreFile(path string, content []byte) error {
+	if err := os.WriteFile(path+".tmp", content, 0600); err != nil {
+		os.Remove(path + ".tmp")
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
 // SaveVault encrypts and saves the vault data
 func SaveVault(dirPath string, data VaultData, encryptionKey []byte) error {
 	// Serialize to YAML
@@ -154,6 +230,11 @@ func LoadVault(dirPath string, encryptionKey []byte) (*VaultData, error) {
 		return nil, fmt.Errorf("failed to load vault: %w", err)
 	}
 
+	return DecryptVault(encryptedData, encryptionKey)
+}
+
+// DecryptVault decrypts vault content read from a vault file (or a copy of one)
+func DecryptVault(encryptedData, encryptionKey []byte) (*VaultData, error) {
 	// Decrypt
 	yamlBytes, err := Decrypt(encryptedData, encryptionKey)
 	if err != nil {
@@ -169,6 +250,23 @@ func LoadVault(dirPath string, encryptionKey []byte) (*VaultData, error) {
 	return &data, nil
 }
 
+// BackupVault copies the encrypted vault file to a new file in the same directory
+// The copy stays encrypted with the same key. Returns the backup path
+func BackupVault(dirPath, suffix string) (string, error) {
+	vaultPath := filepath.Join(dirPath, VaultFileName)
+	encryptedData, err := os.ReadFile(vaultPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault for backup: %w", err)
+	}
+
+	backupPath := vaultPath + "." + suffix + ".bak"
+	if err := os.WriteFile(backupPath, encryptedData, 0600); err != nil {
+		return "", fmt.Errorf("failed to write vault backup: %w", err)
+	}
+
+	return backupPath, nil
+}
+
 // IsEncryptedWallet checks if a directory contains an encrypted wallet
 func IsEncryptedWallet(dirPath string) bool {
 	saltPath := filepath.Join(dirPath, SaltFileName)
@@ -0,0 +1,47 @@
+package crypto
+
+// recoveryWordList is the 256-word dictionary used to encode recovery codes
+// Each word represents exactly one byte, so the index of a word is its value
+var recoveryWordList = [256]string{
+	"abacus", "acorn", "actor", "adobe", "agent", "album", "alpha", "amber",
+	"anchor", "angle", "apple", "april", "arena", "armor", "arrow", "atlas",
+	"audio", "autumn", "avenue", "bacon", "badge", "bagel", "baker", "bamboo",
+	"banana", "banjo", "barrel", "basil", "basket", "beach", "beacon", "beaver",
+	"berry", "bicycle", "bishop", "blanket", "blossom", "bonus", "border", "bottle",
+	"branch", "breeze", "brick", "bridge", "bronze", "bubble", "bucket", "buffalo",
+	"butter", "cabin", "cactus", "camera", "camel", "canal", "candle", "canoe",
+	"canvas", "canyon", "captain", "carbon", "carpet", "castle", "cedar", "cello",
+	"cherry", "chess", "circle", "citrus", "clover", "cobalt", "coconut", "comet",
+	"copper", "coral", "cotton", "cougar", "crater", "crayon", "cricket", "crystal",
+	"cubic", "dahlia", "daisy", "dancer", "delta", "denim", "desert", "diamond",
+	"dinner", "dolphin", "domino", "donkey", "dragon", "dream", "drum", "eagle",
+	"earth", "easel", "echo", "eclipse", "elbow", "ember", "emerald", "engine",
+	"enigma", "falcon", "feather", "fence", "ferry", "fiber", "fiddle", "flame",
+	"flute", "forest", "fossil", "fountain", "fox", "galaxy", "garden", "garlic",
+	"gazelle", "geyser", "ginger", "glacier", "globe", "goblet", "granite", "grape",
+	"gravel", "guitar", "hammer", "harbor", "harvest", "hazel", "helmet", "heron",
+	"hollow", "honey", "horizon", "hunter", "igloo", "indigo", "island", "ivory",
+	"jacket", "jaguar", "jasmine", "jelly", "jigsaw", "jungle", "kayak", "kernel",
+	"kettle", "kiwi", "koala", "ladder", "lagoon", "lantern", "lemon", "lentil",
+	"lilac", "lime", "linen", "lizard", "lobster", "locket", "lotus", "magnet",
+	"mango", "maple", "marble", "meadow", "melon", "meteor", "mint", "mirror",
+	"mosaic", "mountain", "muffin", "napkin", "nectar", "needle", "nickel", "noodle",
+	"nutmeg", "oasis", "ocean", "olive", "onion", "opal", "orbit", "orchid",
+	"otter", "oyster", "paddle", "palace", "panda", "papaya", "parrot", "peach",
+	"pebble", "pepper", "piano", "pillow", "pirate", "planet", "plum", "pocket",
+	"polar", "poppy", "prism", "pumpkin", "puzzle", "quartz", "quill", "rabbit",
+	"radar", "raven", "ribbon", "river", "rocket", "saddle", "salmon", "satin",
+	"scarf", "shadow", "silver", "sketch", "socket", "spider", "spruce", "squid",
+	"stable", "summit", "sunset", "tablet", "tango", "temple", "thunder", "tiger",
+	"timber", "toast", "tomato", "topaz", "tractor", "tulip", "tunnel", "turtle",
+	"umbrella", "valley", "velvet", "violin", "volcano", "walnut", "willow", "zebra",
+}
+
+// recoveryWordIndex maps each word back to its byte value
+var recoveryWordIndex = func() map[string]byte {
+	index := make(map[string]byte, len(recoveryWordList))
+	for i, word := range recoveryWordList {
+		index[word] = byte(i)
+	}
+	return index
+}()
@@ -0,0 +1,214 @@
+package wallet
+
+import (
+	"sort"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/shopspring/decimal"
+)
+
+// EarningIssue classifica a divergência entre a quantidade de um provento e a
+// posição da carteira na data-com
+type EarningIssue string
+
+const (
+	// IssueNotHeld: nenhuma posição no período da data-com (ticker errado ou
+	// compras não importadas)
+	IssueNotHeld EarningIssue = "not_held"
+
+	// IssueRatio: a quantidade do provento é um múltiplo (ou fração) inteiro da
+	// posição (desdobramento, grupamento ou bonificação não registrado)
+	IssueRatio EarningIssue = "ratio"
+
+	// IssueMissingBuys: o provento foi pago sobre mais papéis do que a carteira tinha
+	IssueMissingBuys EarningIssue = "missing_buys"
+
+	// IssueMissingSells: o provento foi pago sobre menos papéis do que a carteira tinha
+	IssueMissingSells EarningIssue = "missing_sells"
+)
+
+// Janelas de busca da data-com antes do pagamento, quando ela não é conhecida.
+// O arquivo de proventos da B3 traz apenas a data de pagamento: FIIs pagam até
+// umas duas semanas depois da data-com, ações podem levar meses (JCP)
+const (
+	IncomeRecordDateWindow   = 45
+	DividendRecordDateWindow = 180
+)
+
+// earningQuantityTolerance absorve frações de bonificação que a B3 não paga
+var earningQuantityTolerance = decimal.NewFromInt(1)
+
+// EarningCheck é a divergência encontrada em um provento
+type EarningCheck struct {
+	Ticker  string
+	Earning parser.Earning
+
+	// RecordDate é a data-com comparada: a do anúncio registrado ou, sem anúncio,
+	// a data da janela com a posição mais próxima da quantidade do provento
+	RecordDate time.Time
+
+	// Announced indica que a data-com veio de um provento anunciado
+	Announced bool
+
+	// Held é a quantidade em carteira ao final da data-com, com os eventos
+	// corporativos vigentes naquela data (a mesma base do provento)
+	Held decimal.Decimal
+
+	Issue EarningIssue
+
+	// Factor é a proporção provento/posição (ex: 2 em um desdobramento 1:2 não
+	// registrado; 0.5 em um grupamento 2:1); zero fora de IssueRatio
+	Factor decimal.Decimal
+}
+
+// Difference é quantidade do provento − quantidade em carteira
+func (c EarningCheck) Difference() decimal.Decimal {
+	return c.Earning.Quantity.Sub(c.Held)
+}
+
+// RecordDateWindow retorna quantos dias antes do pagamento a data-com pode estar
+func RecordDateWindow(earningType string) int {
+	if earningType == "Rendimento" {
+		return IncomeRecordDateWindow
+	}
+	return DividendRecordDateWindow
+}
+
+// CheckEarnings compara a quantidade de cada provento com a posição da carteira
+// na data-com e retorna as divergências, por data de pagamento
+//
+// A data-com é a de um provento anunciado correspondente (mesmo ativo e tipo, veja
+// IsReceived); sem anúncio, o provento é aceito se a quantidade coincidir com a
+// posição ao final de qualquer dia da janela antes do pagamento. As posições são
+// recalculadas a partir das negociações com os eventos até cada data, então um
+// desdobramento entre a data-com e o pagamento não gera divergência
+func (w *Wallet) CheckEarnings(earnings []parser.Earning) []EarningCheck {
+	checker := newEarningChecker(w)
+	var result []EarningCheck
+	for _, e := range earnings {
+		if check, found := checker.check(e); found {
+			result = append(result, check)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].Earning.Date.Equal(result[j].Earning.Date) {
+			return result[i].Earning.Date.Before(result[j].Earning.Date)
+		}
+		return result[i].Ticker < result[j].Ticker
+	})
+	return result
+}
+
+// CheckAllEarnings verifica todos os proventos registrados na carteira
+func (w *Wallet) CheckAllEarnings() []EarningCheck {
+	var earnings []parser.Earning
+	for _, asset := range w.Assets {
+		earnings = append(earnings, asset.Earnings...)
+	}
+	return w.CheckEarnings(earnings)
+}
+
+// earningChecker guarda as posições recalculadas por data entre as verificações
+type earningChecker struct {
+	wallet    *Wallet
+	events    []CorporateEvent
+	positions map[time.Time]map[string][]parser.Transaction
+}
+
+func newEarningChecker(w *Wallet) *earningChecker {
+	return &earningChecker{
+		wallet:    w,
+		events:    w.SortedCorporateEvents(""),
+		positions: make(map[time.Time]map[string][]parser.Transaction),
+	}
+}
+
+// heldAt calcula a quantidade ao final da data, com os eventos vigentes nela
+func (c *earningChecker) heldAt(ticker string, date time.Time) decimal.Decimal {
+	positions, ok := c.positions[date]
+	if !ok {
+		positions = c.wallet.replayPositions(c.events, date)
+		c.positions[date] = positions
+	}
+	return heldUntil(positions[ticker], date)
+}
+
+// check compara um provento com as datas-com candidatas
+func (c *earningChecker) check(e parser.Earning) (EarningCheck, bool) {
+	asset := c.wallet.Assets[e.Ticker]
+	if asset != nil && asset.IsSubscription || !e.Quantity.IsPositive() {
+		return EarningCheck{}, false
+	}
+
+	candidates, announced := c.recordDates(e)
+	best := EarningCheck{Ticker: e.Ticker, Earning: e, Announced: announced}
+	bestGap := decimal.Zero
+	for i, date := range candidates {
+		held := c.heldAt(e.Ticker, date)
+		gap := e.Quantity.Sub(held).Abs()
+		if gap.LessThan(earningQuantityTolerance) {
+			return EarningCheck{}, false
+		}
+		// Mais próxima da quantidade do provento; no empate, a mais recente
+		if i == 0 || !gap.GreaterThan(bestGap) {
+			best.RecordDate, best.Held, bestGap = date, held, gap
+		}
+	}
+
+	best.Factor = decimal.Zero
+	switch {
+	case !best.Held.IsPositive():
+		best.Issue = IssueNotHeld
+	case integerFactor(e.Quantity.Div(best.Held)):
+		best.Issue, best.Factor = IssueRatio, e.Quantity.Div(best.Held).Round(0)
+	case integerFactor(best.Held.Div(e.Quantity)):
+		best.Issue, best.Factor = IssueRatio, decimal.NewFromInt(1).Div(best.Held.Div(e.Quantity).Round(0))
+	case e.Quantity.GreaterThan(best.Held):
+		best.Issue = IssueMissingBuys
+	default:
+		best.Issue = IssueMissingSells
+	}
+	return best, true
+}
+
+// recordDates retorna as datas-com possíveis de um provento, em ordem cronológica:
+// a do anúncio correspondente ou o início da janela e cada dia em que a posição
+// mudou dentro dela
+func (c *earningChecker) recordDates(e parser.Earning) ([]time.Time, bool) {
+	for _, a := range c.wallet.AnnouncedEarnings {
+		if a.Ticker == e.Ticker && announcementMatches(a, e) {
+			return []time.Time{a.RecordDate}, true
+		}
+	}
+
+	start := e.Date.AddDate(0, 0, -RecordDateWindow(e.Type))
+	seen := map[time.Time]bool{start: true}
+	dates := []time.Time{start}
+	add := func(date time.Time) {
+		if date.After(start) && date.Before(e.Date) && !seen[date] {
+			seen[date] = true
+			dates = append(dates, date)
+		}
+	}
+	if asset := c.wallet.Assets[e.Ticker]; asset != nil {
+		for _, tx := range asset.EffectiveNegotiations() {
+			add(tx.Date)
+		}
+	}
+	for _, event := range c.events {
+		add(event.Date)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates, false
+}
+
+// integerFactor verifica se x é um inteiro ≥ 2 (tolerância de 2%, frações de
+// bonificação e arredondamentos da B3)
+func integerFactor(x decimal.Decimal) bool {
+	n := x.Round(0)
+	if n.LessThan(decimal.NewFromInt(2)) {
+		return false
+	}
+	return x.Sub(n).Abs().Div(n).LessThanOrEqual(decimal.NewFromFloat(0.02))
+}
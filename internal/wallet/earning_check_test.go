@@ -0,0 +1,114 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/shopspring/decimal"
+)
+
+func checkEarning(ticker, earningType string, paid time.Time, quantity string) parser.Earning {
+	e := parser.Earning{
+		Date:        paid,
+		Type:        earningType,
+		Ticker:      ticker,
+		Quantity:    decimal.RequireFromString(quantity),
+		UnitPrice:   decimal.RequireFromString("0.10"),
+		TotalAmount: decimal.RequireFromString(quantity).Mul(decimal.RequireFromString("0.10")),
+	}
+	e.Hash = parser.CalculateEarningHash(&e)
+	return e
+}
+
+func TestCheckEarnings(t *testing.T) {
+	// ITSA4: 100 em 10/01/2024 e mais 50 em 05/03/2024
+	w := NewWallet([]parser.Transaction{
+		testBuy("ITSA4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100),
+		testBuy("ITSA4", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), 50),
+	})
+	paid := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		earning    parser.Earning
+		issue      EarningIssue
+		held       int64
+		consistent bool
+	}{
+		{name: "current position", earning: checkEarning("ITSA4", "Dividendo", paid, "150"), consistent: true},
+		{name: "record date before the last buy", earning: checkEarning("ITSA4", "Dividendo", paid, "100"), consistent: true},
+		{name: "fractions not paid", earning: checkEarning("ITSA4", "Dividendo", paid, "150.6"), consistent: true},
+		{name: "missing buys", earning: checkEarning("ITSA4", "Dividendo", paid, "180"), issue: IssueMissingBuys, held: 150},
+		{name: "missing sells", earning: checkEarning("ITSA4", "Dividendo", paid, "60"), issue: IssueMissingSells, held: 100},
+		{name: "unregistered split", earning: checkEarning("ITSA4", "Dividendo", paid, "300"), issue: IssueRatio, held: 150},
+		{name: "wrong ticker", earning: checkEarning("ITSA3", "Dividendo", paid, "100"), issue: IssueNotHeld},
+		{name: "before the first buy", earning: checkEarning("ITSA4", "Dividendo", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), "100"), issue: IssueNotHeld},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := w.CheckEarnings([]parser.Earning{tt.earning})
+			if tt.consistent {
+				if len(found) != 0 {
+					t.Fatalf("expected no findings, got %+v", found)
+				}
+				return
+			}
+			if len(found) != 1 {
+				t.Fatalf("expected one finding, got %+v", found)
+			}
+			if found[0].Issue != tt.issue || !found[0].Held.Equal(decimal.NewFromInt(tt.held)) {
+				t.Errorf("expected %s holding %d, got %s holding %s", tt.issue, tt.held, found[0].Issue, found[0].Held)
+			}
+		})
+	}
+
+	found := w.CheckEarnings([]parser.Earning{checkEarning("ITSA4", "Dividendo", paid, "300")})
+	if len(found) == 1 && !found[0].Factor.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("expected factor 2, got %s", found[0].Factor)
+	}
+}
+
+func TestCheckEarnings_AnnouncedRecordDate(t *testing.T) {
+	// ITSA4: 100 em 10/01/2024 e mais 50 em 05/03/2024
+	w := NewWallet([]parser.Transaction{
+		testBuy("ITSA4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100),
+		testBuy("ITSA4", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), 50),
+	})
+	split := CorporateEvent{
+		Type:      EventSplit,
+		Ticker:    "ITSA4",
+		Date:      time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+		RatioFrom: decimal.NewFromInt(1),
+		RatioTo:   decimal.NewFromInt(2),
+	}
+	if _, err := w.AddCorporateEvent(split); err != nil {
+		t.Fatalf("AddCorporateEvent returned error: %v", err)
+	}
+	paid := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	// Sem anúncio, qualquer posição da janela é aceita (inclusive a pós-desdobramento)
+	if found := w.CheckEarnings([]parser.Earning{checkEarning("ITSA4", "Juros Sobre Capital Próprio", paid, "300")}); len(found) != 0 {
+		t.Fatalf("expected no findings without an announcement, got %+v", found)
+	}
+
+	if _, err := w.AddAnnouncedEarning(AnnouncedEarning{
+		Ticker: "ITSA4", Type: "Juros Sobre Capital Próprio",
+		RecordDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), PaymentDate: paid,
+		UnitPrice: decimal.RequireFromString("0.10"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Na data-com anunciada havia 100 papéis, antes do desdobramento e da segunda compra
+	if found := w.CheckEarnings([]parser.Earning{checkEarning("ITSA4", "Juros Sobre Capital Próprio", paid, "100")}); len(found) != 0 {
+		t.Fatalf("expected the record date position to match, got %+v", found)
+	}
+	found := w.CheckEarnings([]parser.Earning{checkEarning("ITSA4", "Juros Sobre Capital Próprio", paid, "300")})
+	if len(found) != 1 || !found[0].Announced || found[0].Issue != IssueRatio || !found[0].Held.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("expected a ratio finding on the announced record date, got %+v", found)
+	}
+	if !found[0].Factor.Equal(decimal.NewFromInt(3)) {
+		t.Errorf("expected factor 3, got %s", found[0].Factor)
+	}
+}
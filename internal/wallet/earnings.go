@@ -12,6 +12,9 @@ import (
 // and recalculates all asset values.
 // Returns an error if the earning is invalid or already exists.
 func (w *Wallet) AddEarning(earning parser.Earning) error {
+	// Complete gross, withheld tax and net amounts (JCP)
+	parser.InferEarningAmounts(&earning)
+
 	// Validate earning
 	if err := ValidateEarning(&earning); err != nil {
 		return fmt.Errorf("invalid earning: %w", err)
@@ -22,6 +25,8 @@ func (w *Wallet) AddEarning(earning parser.Earning) error {
 		earning.Hash = parser.CalculateEarningHash(&earning)
 	}
 
+	before := w.mutationHash()
+
 	// Create or update asset
 	asset, exists := w.Assets[earning.Ticker]
 	if !exists {
@@ -50,14 +55,35 @@ func (w *Wallet) AddEarning(earning parser.Earning) error {
 	// Recalculate all asset values
 	w.RecalculateAssets()
 
+	w.RecordMutation("AddEarning", earningInputs(earning), before)
+
 	return nil
 }
 
+// earningInputs describes an earning for the journal
+func earningInputs(e parser.Earning) map[string]string {
+	return map[string]string{
+		"date":         e.Date.Format("2006-01-02"),
+		"type":         e.Type,
+		"ticker":       e.Ticker,
+		"quantity":     e.Quantity.String(),
+		"unit_price":   e.UnitPrice.String(),
+		"total_amount": e.TotalAmount.String(),
+		"gross_amount": e.GrossAmount.String(),
+		"withheld_tax": e.WithheldTax.String(),
+		"net_amount":   e.NetAmount.String(),
+		"hash":         e.Hash,
+	}
+}
+
 // AddEarnings adds multiple earnings to the wallet in batch.
 // It returns the number of earnings added, the number of duplicates skipped,
 // and any error that occurred during validation.
 // If an earning is invalid, the entire operation is aborted and an error is returned.
 func (w *Wallet) AddEarnings(earnings []parser.Earning) (added int, duplicates int, err error) {
+	before := w.mutationHash()
+	tickers := make(map[string]bool)
+
 	// Track seen hashes across all assets
 	seenHashes := make(map[string]bool)
 
@@ -69,6 +95,9 @@ func (w *Wallet) AddEarnings(earnings []parser.Earning) (added int, duplicates i
 	}
 
 	for _, earning := range earnings {
+		// Complete gross, withheld tax and net amounts (JCP), as AddEarning does
+		parser.InferEarningAmounts(&earning)
+
 		// Calculate hash if not already set
 		if earning.Hash == "" {
 			earning.Hash = parser.CalculateEarningHash(&earning)
@@ -103,12 +132,18 @@ func (w *Wallet) AddEarnings(earnings []parser.Earning) (added int, duplicates i
 		// Add earning to asset
 		asset.Earnings = append(asset.Earnings, earning)
 		seenHashes[earning.Hash] = true
+		tickers[earning.Ticker] = true
 		added++
 	}
 
 	// Recalculate all asset values after adding all earnings
 	if added > 0 {
 		w.RecalculateAssets()
+		w.RecordMutation("AddEarnings", map[string]string{
+			"added":      fmt.Sprint(added),
+			"duplicates": fmt.Sprint(duplicates),
+			"tickers":    joinTickers(tickers),
+		}, before)
 	}
 
 	return added, duplicates, nil
@@ -125,16 +160,17 @@ func ValidateEarning(e *parser.Earning) error {
 		return fmt.Errorf("type is required")
 	}
 
-	// Validate type is one of the four expected values
+	// Validate type is one of the expected values
 	validTypes := map[string]bool{
 		"Rendimento":                  true,
 		"Dividendo":                   true,
 		"Juros Sobre Capital Próprio": true,
 		"Resgate":                     true,
+		"Amortização":                 true,
 	}
 
 	if !validTypes[e.Type] {
-		return fmt.Errorf("type must be 'Rendimento', 'Dividendo', 'Juros Sobre Capital Próprio', or 'Resgate' (received: '%s')", e.Type)
+		return fmt.Errorf("type must be 'Rendimento', 'Dividendo', 'Juros Sobre Capital Próprio', 'Resgate' or 'Amortização' (received: '%s')", e.Type)
 	}
 
 	if e.Quantity.LessThanOrEqual(decimal.Zero) {
@@ -149,16 +185,41 @@ func ValidateEarning(e *parser.Earning) error {
 		return fmt.Errorf("total amount must be greater than zero")
 	}
 
+	// Gross, withheld tax and net are optional, but must add up when present
+	if e.WithheldTax.IsNegative() || e.NetAmount.IsNegative() || e.GrossAmount.IsNegative() {
+		return fmt.Errorf("gross, withheld tax and net amounts cannot be negative")
+	}
+	if !e.GrossAmount.IsZero() && e.GrossAmount.Sub(e.WithheldTax).Sub(e.NetAmount).Abs().GreaterThan(decimal.RequireFromString("0.01")) {
+		return fmt.Errorf("gross amount %s must equal withheld tax %s plus net amount %s",
+			e.GrossAmount, e.WithheldTax, e.NetAmount)
+	}
+
 	return nil
 }
 
 // calculateTotalEarnings calcula o valor total de proventos recebidos de um ativo
-// Soma todos os valores de earnings
+// Soma o líquido dos earnings de renda; devoluções de capital ficam de fora
 func calculateTotalEarnings(asset *Asset) decimal.Decimal {
 	total := decimal.Zero
 
 	for _, earning := range asset.Earnings {
-		total = total.Add(earning.TotalAmount)
+		if parser.IsCapitalReturn(earning.Type) {
+			continue
+		}
+		total = total.Add(earning.Net())
+	}
+
+	return total.Round(4)
+}
+
+// calculateTotalCapitalReturned soma as amortizações e resgates recebidos de um ativo
+func calculateTotalCapitalReturned(asset *Asset) decimal.Decimal {
+	total := decimal.Zero
+
+	for _, earning := range asset.Earnings {
+		if parser.IsCapitalReturn(earning.Type) {
+			total = total.Add(earning.Net())
+		}
 	}
 
 	return total.Round(4)
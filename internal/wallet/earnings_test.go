@@ -0,0 +1,133 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/shopspring/decimal"
+)
+
+func TestEarningAmounts_Persistence(t *testing.T) {
+	// ITSA4: 100 em 10/01/2024 e mais 50 em 05/03/2024
+	w := NewWallet([]parser.Transaction{
+		testBuy("ITSA4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100),
+		testBuy("ITSA4", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), 50),
+	})
+
+	// JCP informado líquido: 100 × R$ 0,50 anunciado, R$ 42,50 creditado
+	jcp := parser.Earning{
+		Date:        time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		Type:        "Juros Sobre Capital Próprio",
+		Ticker:      "ITSA4",
+		Quantity:    decimal.NewFromInt(100),
+		UnitPrice:   decimal.RequireFromString("0.5"),
+		TotalAmount: decimal.RequireFromString("42.5"),
+	}
+	if err := w.AddEarning(jcp); err != nil {
+		t.Fatalf("AddEarning returned error: %v", err)
+	}
+
+	data := w.prepareVaultData()
+	saved := data.Assets[0].Earnings[0]
+	if saved.GrossAmount != "50" || saved.WithheldTax != "7.5" || saved.NetAmount != "42.5" {
+		t.Errorf("unexpected saved amounts: %+v", saved)
+	}
+
+	// Vaults antigos não têm os campos: os valores são inferidos no carregamento
+	data.Assets[0].Earnings[0].GrossAmount = ""
+	data.Assets[0].Earnings[0].WithheldTax = ""
+	data.Assets[0].Earnings[0].NetAmount = ""
+	loaded, err := fromVaultData(&data)
+	if err != nil {
+		t.Fatalf("fromVaultData returned error: %v", err)
+	}
+	e := loaded.Assets["ITSA4"].Earnings[0]
+	if !e.GrossAmount.Equal(decimal.NewFromInt(50)) || !e.WithheldTax.Equal(decimal.RequireFromString("7.5")) ||
+		!e.NetAmount.Equal(decimal.RequireFromString("42.5")) {
+		t.Errorf("expected 50/7.5/42.5 inferred, got %s/%s/%s", e.GrossAmount, e.WithheldTax, e.NetAmount)
+	}
+}
+
+func TestValidateEarning_Amounts(t *testing.T) {
+	e := parser.Earning{
+		Date:        time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		Type:        "Juros Sobre Capital Próprio",
+		Ticker:      "ITSA4",
+		Quantity:    decimal.NewFromInt(100),
+		UnitPrice:   decimal.RequireFromString("0.5"),
+		TotalAmount: decimal.NewFromInt(50),
+		GrossAmount: decimal.NewFromInt(50),
+		WithheldTax: decimal.NewFromInt(5),
+		NetAmount:   decimal.RequireFromString("42.5"),
+	}
+	if err := ValidateEarning(&e); err == nil {
+		t.Error("expected gross != tax + net to be rejected")
+	}
+}
+
+func TestTotalEarnings_Net(t *testing.T) {
+	// ITSA4: 100 em 10/01/2024 e mais 50 em 05/03/2024
+	w := NewWallet([]parser.Transaction{
+		testBuy("ITSA4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100),
+		testBuy("ITSA4", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), 50),
+	})
+
+	// JCP informado bruto (R$ 50, R$ 42,50 creditados) e um dividendo isento de R$ 10
+	for _, e := range []parser.Earning{
+		{
+			Date:        time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			Type:        "Juros Sobre Capital Próprio",
+			Ticker:      "ITSA4",
+			Quantity:    decimal.NewFromInt(100),
+			UnitPrice:   decimal.RequireFromString("0.5"),
+			TotalAmount: decimal.NewFromInt(50),
+		},
+		{
+			Date:        time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			Type:        "Dividendo",
+			Ticker:      "ITSA4",
+			Quantity:    decimal.NewFromInt(100),
+			UnitPrice:   decimal.RequireFromString("0.1"),
+			TotalAmount: decimal.NewFromInt(10),
+		},
+	} {
+		if err := w.AddEarning(e); err != nil {
+			t.Fatalf("AddEarning returned error: %v", err)
+		}
+	}
+
+	if total := w.Assets["ITSA4"].TotalEarnings; !total.Equal(decimal.RequireFromString("52.5")) {
+		t.Errorf("expected R$ 52.50 received, got %s", total)
+	}
+}
+
+func TestAddEarnings_SameAmountsAsAddEarning(t *testing.T) {
+	// JCP informado líquido: R$ 42,50 creditados sobre R$ 50 anunciados
+	jcp := parser.Earning{
+		Date:        time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		Type:        "Juros Sobre Capital Próprio",
+		Ticker:      "ITSA4",
+		Quantity:    decimal.NewFromInt(100),
+		UnitPrice:   decimal.RequireFromString("0.5"),
+		TotalAmount: decimal.RequireFromString("42.5"),
+	}
+
+	single := NewWallet([]parser.Transaction{testBuy("ITSA4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100)})
+	if err := single.AddEarning(jcp); err != nil {
+		t.Fatalf("AddEarning returned error: %v", err)
+	}
+	batch := NewWallet([]parser.Transaction{testBuy("ITSA4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100)})
+	if added, _, err := batch.AddEarnings([]parser.Earning{jcp}); err != nil || added != 1 {
+		t.Fatalf("AddEarnings = %d, %v; expected 1 added", added, err)
+	}
+
+	s, b := single.Assets["ITSA4"].Earnings[0], batch.Assets["ITSA4"].Earnings[0]
+	if s.Hash != b.Hash || !s.GrossAmount.Equal(b.GrossAmount) || !s.WithheldTax.Equal(b.WithheldTax) || !s.NetAmount.Equal(b.NetAmount) {
+		t.Errorf("single %s/%s/%s (%s) != batch %s/%s/%s (%s)",
+			s.GrossAmount, s.WithheldTax, s.NetAmount, s.Hash, b.GrossAmount, b.WithheldTax, b.NetAmount, b.Hash)
+	}
+	if !b.GrossAmount.Equal(decimal.NewFromInt(50)) || !b.NetAmount.Equal(decimal.RequireFromString("42.5")) {
+		t.Errorf("batch amounts = %s/%s/%s, expected 50/7.5/42.5", b.GrossAmount, b.WithheldTax, b.NetAmount)
+	}
+}
@@ -0,0 +1,163 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+// BonusParams describes a bonus share distribution (bonificação)
+type BonusParams struct {
+	Percent       decimal.Decimal // New shares as a percentage of the position (10 = 10%)
+	UnitCost      decimal.Decimal // Cost per new share declared by the company
+	AuctionAmount decimal.Decimal // Cash received for fractions sold in auction (zero = discarded)
+}
+
+// BonusResult contains statistics about the bonus operation
+type BonusResult struct {
+	Ticker          string
+	Params          BonusParams
+	EventDate       time.Time
+	EventID         string
+	EligibleShares  decimal.Decimal
+	BonusShares     decimal.Decimal
+	FractionShares  decimal.Decimal
+	QuantityBefore  int
+	QuantityAfter   int
+	PriceBefore     decimal.Decimal
+	PriceAfter      decimal.Decimal
+	InvestedBefore  decimal.Decimal
+	InvestedAfter   decimal.Decimal
+	AttributedCost  decimal.Decimal
+	FractionSoldFor decimal.Decimal
+}
+
+// PreviewBonus calculates the effect of a bonus without changing the wallet
+// Eligible shares are those held before the event date
+func PreviewBonus(w *wallet.Wallet, ticker string, params BonusParams, eventDate time.Time) (*BonusResult, error) {
+	asset, exists := w.Assets[ticker]
+	if !exists {
+		return nil, fmt.Errorf("asset %s not found", ticker)
+	}
+
+	if !params.Percent.IsPositive() {
+		return nil, fmt.Errorf("invalid percent: must be positive (got %s)", params.Percent)
+	}
+	if params.UnitCost.IsNegative() {
+		return nil, fmt.Errorf("invalid unit cost: cannot be negative (got %s)", params.UnitCost)
+	}
+	if params.AuctionAmount.IsNegative() {
+		return nil, fmt.Errorf("invalid auction amount: cannot be negative (got %s)", params.AuctionAmount)
+	}
+
+	eligible := asset.QuantityBefore(eventDate)
+	if !eligible.IsPositive() {
+		return nil, fmt.Errorf("no %s shares held before %s", ticker, eventDate.Format("2006-01-02"))
+	}
+
+	bonus := eligible.Mul(params.Percent).Div(decimal.NewFromInt(100))
+	whole := bonus.Floor()
+
+	result := &BonusResult{
+		Ticker:         ticker,
+		Params:         params,
+		EventDate:      eventDate,
+		EligibleShares: eligible,
+		BonusShares:    whole,
+		FractionShares: bonus.Sub(whole),
+		QuantityBefore: asset.Quantity,
+		QuantityAfter:  asset.Quantity + int(whole.IntPart()),
+		PriceBefore:    asset.AveragePrice,
+		InvestedBefore: asset.TotalInvestedValue,
+		AttributedCost: whole.Mul(params.UnitCost).Round(2),
+	}
+	if result.FractionShares.IsPositive() && params.AuctionAmount.IsPositive() {
+		result.FractionSoldFor = params.AuctionAmount
+	}
+
+	// Projected position: the new shares are a purchase at the declared cost
+	// (a fraction sold in auction is also bought at that cost, then sold)
+	boughtQty := whole
+	if result.FractionSoldFor.IsPositive() {
+		boughtQty = bonus
+	}
+	buyAmount, buyQty := decimal.Zero, decimal.Zero
+	for _, tx := range asset.EffectiveNegotiations() {
+		if tx.Type == "Compra" {
+			buyAmount = buyAmount.Add(tx.Amount)
+			buyQty = buyQty.Add(tx.Quantity)
+		}
+	}
+	buyAmount = buyAmount.Add(boughtQty.Mul(params.UnitCost).Round(2))
+	buyQty = buyQty.Add(boughtQty)
+
+	result.InvestedAfter = buyAmount.Round(4)
+	if buyQty.IsPositive() {
+		result.PriceAfter = buyAmount.Div(buyQty).Round(4)
+	}
+
+	return result, nil
+}
+
+// ApplyBonus registers a bonus share distribution (bonificação) as a purchase at the declared unit cost
+func ApplyBonus(w *wallet.Wallet, ticker string, params BonusParams, eventDate time.Time) (*BonusResult, error) {
+	result, err := PreviewBonus(w, ticker, params, eventDate)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := w.AddCorporateEvent(wallet.CorporateEvent{
+		Type:          wallet.EventBonus,
+		Ticker:        ticker,
+		Date:          eventDate,
+		Percent:       params.Percent,
+		UnitCost:      params.UnitCost,
+		AuctionAmount: params.AuctionAmount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.EventID = event.ID
+
+	// Update result with new values
+	if updatedAsset, exists := w.Assets[ticker]; exists {
+		result.QuantityAfter = updatedAsset.Quantity
+		result.PriceAfter = updatedAsset.AveragePrice
+		result.InvestedAfter = updatedAsset.TotalInvestedValue
+	}
+
+	return result, nil
+}
+
+// ParseBonusPercent parses a percentage like "10", "10%" or "2,5"
+func ParseBonusPercent(value string) (decimal.Decimal, error) {
+	return parseDecimalInput(value, "%")
+}
+
+// ParseAmount parses a monetary value like "12.34", "12,34" or "R$ 12,34"
+// An empty string is zero
+func ParseAmount(value string) (decimal.Decimal, error) {
+	return parseDecimalInput(value, "R$")
+}
+
+// parseDecimalInput parses a user typed number, accepting a decimal comma and
+// an optional symbol (e.g. "%" or "R$")
+func parseDecimalInput(value, symbol string) (decimal.Decimal, error) {
+	cleaned := strings.TrimSpace(value)
+	if symbol != "" {
+		cleaned = strings.TrimSpace(strings.ReplaceAll(cleaned, symbol, ""))
+	}
+	if cleaned == "" {
+		return decimal.Zero, nil
+	}
+	cleaned = strings.ReplaceAll(cleaned, ",", ".")
+
+	d, err := decimal.NewFromString(cleaned)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid number %q", value)
+	}
+	return d, nil
+}
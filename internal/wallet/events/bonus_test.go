@@ -0,0 +1,202 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+func TestApplyBonus(t *testing.T) {
+	// 105 shares before the event, 50 after it (not eligible)
+	transactions := []parser.Transaction{
+		testTransaction("Compra", "ITSA4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 105, "10"),
+		testTransaction("Compra", "ITSA4", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 50, "10"),
+	}
+	w := wallet.NewWallet(transactions)
+
+	eventDate := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	params := BonusParams{
+		Percent:  decimal.NewFromInt(10),
+		UnitCost: decimal.NewFromFloat(12.50),
+	}
+
+	preview, err := PreviewBonus(w, "ITSA4", params, eventDate)
+	if err != nil {
+		t.Fatalf("Error previewing bonus: %v", err)
+	}
+	if len(w.CorporateEvents) != 0 {
+		t.Fatal("PreviewBonus must not change the wallet")
+	}
+
+	result, err := ApplyBonus(w, "ITSA4", params, eventDate)
+	if err != nil {
+		t.Fatalf("Error applying bonus: %v", err)
+	}
+
+	// The preview matches the applied result
+	if preview.QuantityAfter != result.QuantityAfter || !preview.PriceAfter.Equal(result.PriceAfter) || !preview.InvestedAfter.Equal(result.InvestedAfter) {
+		t.Errorf("Preview %d/%s/%s differs from result %d/%s/%s",
+			preview.QuantityAfter, preview.PriceAfter, preview.InvestedAfter,
+			result.QuantityAfter, result.PriceAfter, result.InvestedAfter)
+	}
+
+	// 10% of 105 = 10.5 → 10 new shares, 0.5 discarded
+	if !result.EligibleShares.Equal(decimal.NewFromInt(105)) {
+		t.Errorf("EligibleShares = %s, expected 105", result.EligibleShares)
+	}
+	if !result.BonusShares.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("BonusShares = %s, expected 10", result.BonusShares)
+	}
+	if !result.FractionShares.Equal(decimal.NewFromFloat(0.5)) {
+		t.Errorf("FractionShares = %s, expected 0.5", result.FractionShares)
+	}
+
+	asset := w.Assets["ITSA4"]
+	if asset.Quantity != 165 {
+		t.Errorf("Quantity = %d, expected 165", asset.Quantity)
+	}
+
+	// Invested value grows by the attributed cost: 1550 + 10 × 12.50
+	if !asset.TotalInvestedValue.Equal(decimal.NewFromInt(1675)) {
+		t.Errorf("TotalInvestedValue = %s, expected 1675", asset.TotalInvestedValue)
+	}
+	if !result.AttributedCost.Equal(decimal.NewFromInt(125)) {
+		t.Errorf("AttributedCost = %s, expected 125", result.AttributedCost)
+	}
+
+	// 1675 / 165
+	if !asset.AveragePrice.Equal(decimal.RequireFromString("10.1515")) {
+		t.Errorf("AveragePrice = %s, expected 10.1515", asset.AveragePrice)
+	}
+
+	// Imported transactions are untouched
+	if len(asset.Negotiations) != 2 || len(w.Transactions) != 2 {
+		t.Errorf("Negotiations = %d, Transactions = %d, expected 2 each", len(asset.Negotiations), len(w.Transactions))
+	}
+	for i, tx := range transactions {
+		if asset.Negotiations[i].Hash != tx.Hash {
+			t.Errorf("Negotiation %d hash changed", i+1)
+		}
+	}
+
+	if result.EventID == "" || len(w.CorporateEvents) != 1 {
+		t.Errorf("Event not registered: id %q, events %d", result.EventID, len(w.CorporateEvents))
+	}
+}
+
+func TestApplyBonus_FractionSoldInAuction(t *testing.T) {
+	// 105 shares before the event, 50 after it (not eligible)
+	w := wallet.NewWallet([]parser.Transaction{
+		testTransaction("Compra", "ITSA4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 105, "10"),
+		testTransaction("Compra", "ITSA4", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 50, "10"),
+	})
+
+	eventDate := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	params := BonusParams{
+		Percent:       decimal.NewFromInt(10),
+		UnitCost:      decimal.NewFromFloat(12.50),
+		AuctionAmount: decimal.NewFromFloat(7.10),
+	}
+
+	result, err := ApplyBonus(w, "ITSA4", params, eventDate)
+	if err != nil {
+		t.Fatalf("Error applying bonus: %v", err)
+	}
+
+	if !result.FractionSoldFor.Equal(decimal.NewFromFloat(7.10)) {
+		t.Errorf("FractionSoldFor = %s, expected 7.10", result.FractionSoldFor)
+	}
+
+	asset := w.Assets["ITSA4"]
+	if asset.Quantity != 165 {
+		t.Errorf("Quantity = %d, expected 165", asset.Quantity)
+	}
+
+	// The fraction is bought at the attributed cost and sold in the auction
+	var sale *parser.Transaction
+	for i, tx := range asset.AdjustedNegotiations {
+		if tx.Type == "Venda" {
+			sale = &asset.AdjustedNegotiations[i]
+		}
+	}
+	if sale == nil {
+		t.Fatal("Auction sale not registered")
+	}
+	if !sale.Quantity.Equal(decimal.NewFromFloat(0.5)) || !sale.Amount.Equal(decimal.NewFromFloat(7.10)) {
+		t.Errorf("Auction sale = %s shares for %s, expected 0.5 for 7.10", sale.Quantity, sale.Amount)
+	}
+}
+
+func TestApplyBonus_Errors(t *testing.T) {
+	// 105 shares before the event, 50 after it (not eligible)
+	w := wallet.NewWallet([]parser.Transaction{
+		testTransaction("Compra", "ITSA4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 105, "10"),
+		testTransaction("Compra", "ITSA4", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 50, "10"),
+	})
+	eventDate := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	valid := BonusParams{Percent: decimal.NewFromInt(10), UnitCost: decimal.NewFromInt(1)}
+
+	tests := []struct {
+		name   string
+		ticker string
+		params BonusParams
+		date   time.Time
+	}{
+		{"asset not found", "XXXX3", valid, eventDate},
+		{"zero percent", "ITSA4", BonusParams{UnitCost: decimal.NewFromInt(1)}, eventDate},
+		{"negative cost", "ITSA4", BonusParams{Percent: decimal.NewFromInt(10), UnitCost: decimal.NewFromInt(-1)}, eventDate},
+		{"no shares before date", "ITSA4", valid, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ApplyBonus(w, tt.ticker, tt.params, tt.date); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+
+	// Registering the same bonus twice is rejected
+	if _, err := ApplyBonus(w, "ITSA4", valid, eventDate); err != nil {
+		t.Fatalf("Error applying bonus: %v", err)
+	}
+	if _, err := ApplyBonus(w, "ITSA4", valid, eventDate); err == nil {
+		t.Error("Expected error for duplicate bonus")
+	}
+}
+
+func TestParseBonusPercent(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"10", "10", false},
+		{"10%", "10", false},
+		{" 2,5 % ", "2.5", false},
+		{"abc", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseBonusPercent(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseBonusPercent(%q) expected error", tt.input)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.expected {
+			t.Errorf("ParseBonusPercent(%q) = %s, %v; expected %s", tt.input, got, err, tt.expected)
+		}
+	}
+
+	if got, err := ParseAmount("R$ 12,34"); err != nil || got.String() != "12.34" {
+		t.Errorf("ParseAmount = %s, %v; expected 12.34", got, err)
+	}
+	if got, err := ParseAmount(""); err != nil || !got.IsZero() {
+		t.Errorf("ParseAmount(\"\") = %s, %v; expected 0", got, err)
+	}
+}
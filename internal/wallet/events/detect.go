@@ -0,0 +1,219 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+// Kinds of evidence for a missed split or grouping
+const (
+	SuspicionPriceJump       = "price jump"            // Price changed by a near-integer factor between trades
+	SuspicionOversold        = "sale exceeds holdings" // Sold more shares than held
+	SuspicionEarningQuantity = "earning quantity"      // Earning paid on a multiple of the held quantity
+)
+
+const (
+	// suspicionTolerance is how far (relative) a factor may be from an integer
+	suspicionTolerance = 0.1
+
+	// suspicionMaxFactor ignores factors above this (usually data errors, not events)
+	suspicionMaxFactor = 100
+
+	// priceJumpMaxGap ignores price changes between trades too far apart, where a
+	// real market move is as likely as a missed event
+	priceJumpMaxGap = 365 * 24 * time.Hour
+)
+
+// Suspicion is a sign of a split or grouping that was not registered
+type Suspicion struct {
+	Ticker string
+	Kind   string
+	Date   time.Time // Suggested event date (trades before it are adjusted)
+	Ratio  Ratio     // Suggested ratio; zero when the evidence does not point to one
+	Detail string    // Human readable evidence
+}
+
+// HasSuggestion reports whether the suspicion suggests a split or grouping ratio
+func (s Suspicion) HasSuggestion() bool {
+	return s.Ratio.From > 0 && s.Ratio.To > 0
+}
+
+// DetectMissedEvents scans the negotiations and earnings of each asset for signs of
+// an unregistered split or grouping:
+//   - a price change by a near-integer factor between consecutive trades
+//   - a sale of more shares than held
+//   - an earning paid on a near-integer multiple (or fraction) of the held quantity
+//
+// The positions already include the registered events, so registering the
+// suggested event makes the suspicion go away. Subscription rights are skipped
+func DetectMissedEvents(w *wallet.Wallet) []Suspicion {
+	var result []Suspicion
+
+	for ticker, asset := range w.Assets {
+		if asset.IsSubscription {
+			continue
+		}
+
+		negotiations := make([]parser.Transaction, 0, len(asset.EffectiveNegotiations()))
+		for _, tx := range asset.EffectiveNegotiations() {
+			if !wallet.IsEventTransaction(tx) {
+				negotiations = append(negotiations, tx)
+			}
+		}
+		sort.SliceStable(negotiations, func(i, j int) bool {
+			return negotiations[i].Date.Before(negotiations[j].Date)
+		})
+
+		result = append(result, detectPriceJumps(ticker, negotiations)...)
+		if s, found := detectOversold(ticker, asset.EffectiveNegotiations()); found {
+			result = append(result, s)
+		}
+		result = append(result, detectEarningQuantities(ticker, asset)...)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Ticker != result[j].Ticker {
+			return result[i].Ticker < result[j].Ticker
+		}
+		return result[i].Date.Before(result[j].Date)
+	})
+
+	return result
+}
+
+// detectPriceJumps compares the price of consecutive trades (sorted by date)
+func detectPriceJumps(ticker string, negotiations []parser.Transaction) []Suspicion {
+	var result []Suspicion
+
+	var previous *parser.Transaction
+	for i := range negotiations {
+		tx := &negotiations[i]
+		if !tx.Price.IsPositive() {
+			continue
+		}
+		if previous != nil && tx.Date.Sub(previous.Date) <= priceJumpMaxGap {
+			if n, ok := nearIntegerFactor(previous.Price.Div(tx.Price)); ok {
+				// Price divided by n: split 1:n
+				result = append(result, Suspicion{
+					Ticker: ticker,
+					Kind:   SuspicionPriceJump,
+					Date:   tx.Date,
+					Ratio:  Ratio{From: 1, To: n},
+					Detail: fmt.Sprintf("price fell from R$ %s (%s) to R$ %s (%s), about 1/%d",
+						previous.Price.StringFixed(2), previous.Date.Format("2006-01-02"),
+						tx.Price.StringFixed(2), tx.Date.Format("2006-01-02"), n),
+				})
+			} else if n, ok := nearIntegerFactor(tx.Price.Div(previous.Price)); ok {
+				// Price multiplied by n: grouping n:1
+				result = append(result, Suspicion{
+					Ticker: ticker,
+					Kind:   SuspicionPriceJump,
+					Date:   tx.Date,
+					Ratio:  Ratio{From: n, To: 1},
+					Detail: fmt.Sprintf("price rose from R$ %s (%s) to R$ %s (%s), about %dx",
+						previous.Price.StringFixed(2), previous.Date.Format("2006-01-02"),
+						tx.Price.StringFixed(2), tx.Date.Format("2006-01-02"), n),
+				})
+			}
+		}
+		previous = tx
+	}
+
+	return result
+}
+
+// detectOversold finds the first sale of more shares than held. When the sale is a
+// near-integer multiple of the position, a split is suggested on the sale date
+func detectOversold(ticker string, negotiations []parser.Transaction) (Suspicion, bool) {
+	sorted := append([]parser.Transaction(nil), negotiations...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		// Same day: buys first, so a day trade is not reported
+		if sorted[i].Date.Equal(sorted[j].Date) {
+			return sorted[i].Type == "Compra" && sorted[j].Type != "Compra"
+		}
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	held := decimal.Zero
+	for _, tx := range sorted {
+		switch tx.Type {
+		case "Compra":
+			held = held.Add(tx.Quantity)
+			continue
+		case "Venda":
+		default:
+			continue
+		}
+
+		if tx.Quantity.Sub(held).Round(4).IsPositive() {
+			s := Suspicion{
+				Ticker: ticker,
+				Kind:   SuspicionOversold,
+				Date:   tx.Date,
+				Detail: fmt.Sprintf("sold %s shares on %s holding %s", tx.Quantity, tx.Date.Format("2006-01-02"), held),
+			}
+			if held.IsPositive() {
+				if n, ok := nearIntegerFactor(tx.Quantity.Div(held)); ok {
+					s.Ratio = Ratio{From: 1, To: n}
+				}
+			}
+			return s, true
+		}
+		held = held.Sub(tx.Quantity)
+	}
+
+	return Suspicion{}, false
+}
+
+// detectEarningQuantities compares each earning quantity with the position held
+// before the payment date
+func detectEarningQuantities(ticker string, asset *wallet.Asset) []Suspicion {
+	var result []Suspicion
+
+	for _, earning := range asset.Earnings {
+		held := asset.QuantityBefore(earning.Date)
+		if !held.IsPositive() || !earning.Quantity.IsPositive() {
+			continue
+		}
+
+		if n, ok := nearIntegerFactor(earning.Quantity.Div(held)); ok {
+			result = append(result, Suspicion{
+				Ticker: ticker,
+				Kind:   SuspicionEarningQuantity,
+				Date:   earning.Date,
+				Ratio:  Ratio{From: 1, To: n},
+				Detail: fmt.Sprintf("%s on %s paid on %s shares, %s held",
+					earning.Type, earning.Date.Format("2006-01-02"), earning.Quantity, held),
+			})
+		} else if n, ok := nearIntegerFactor(held.Div(earning.Quantity)); ok {
+			result = append(result, Suspicion{
+				Ticker: ticker,
+				Kind:   SuspicionEarningQuantity,
+				Date:   earning.Date,
+				Ratio:  Ratio{From: n, To: 1},
+				Detail: fmt.Sprintf("%s on %s paid on %s shares, %s held",
+					earning.Type, earning.Date.Format("2006-01-02"), earning.Quantity, held),
+			})
+		}
+	}
+
+	return result
+}
+
+// nearIntegerFactor returns n when x is within suspicionTolerance of an integer n ≥ 2
+func nearIntegerFactor(x decimal.Decimal) (int, bool) {
+	n := x.Round(0)
+	if n.LessThan(decimal.NewFromInt(2)) || n.GreaterThan(decimal.NewFromInt(suspicionMaxFactor)) {
+		return 0, false
+	}
+	deviation := x.Sub(n).Abs().Div(n)
+	if deviation.GreaterThan(decimal.NewFromFloat(suspicionTolerance)) {
+		return 0, false
+	}
+	return int(n.IntPart()), true
+}
@@ -0,0 +1,104 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+func TestDetectMissedEvents_Split(t *testing.T) {
+	// A 1:2 split in February was never registered
+	w := wallet.NewWallet([]parser.Transaction{
+		testTransaction("Compra", "BBAS3", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100, "20"),
+		testTransaction("Compra", "BBAS3", time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), 50, "10.2"),
+		testTransaction("Venda", "BBAS3", time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC), 250, "11"),
+	})
+
+	earning := parser.Earning{
+		Date:        time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC),
+		Type:        "Dividendo",
+		Ticker:      "BBAS3",
+		Quantity:    decimal.NewFromInt(200),
+		UnitPrice:   decimal.NewFromFloat(0.5),
+		TotalAmount: decimal.NewFromInt(100),
+	}
+	earning.Hash = parser.CalculateEarningHash(&earning)
+	if err := w.AddEarning(earning); err != nil {
+		t.Fatalf("AddEarning returned error: %v", err)
+	}
+
+	found := DetectMissedEvents(w)
+	if len(found) != 3 {
+		t.Fatalf("suspicions = %+v, expected 3", found)
+	}
+
+	expected := []struct {
+		kind  string
+		month time.Month
+		ratio Ratio
+	}{
+		{SuspicionEarningQuantity, time.February, Ratio{From: 1, To: 2}},
+		{SuspicionPriceJump, time.March, Ratio{From: 1, To: 2}},
+		{SuspicionOversold, time.April, Ratio{}}, // 250 sold holding 150: no integer ratio
+	}
+	for i, want := range expected {
+		got := found[i]
+		if got.Kind != want.kind || got.Date.Month() != want.month || got.Ratio != want.ratio {
+			t.Errorf("suspicion[%d] = %s %s %s, expected %s %s %s",
+				i, got.Kind, got.Date.Month(), got.Ratio, want.kind, want.month, want.ratio)
+		}
+	}
+	if found[2].HasSuggestion() {
+		t.Error("oversold without integer ratio should not suggest an event")
+	}
+
+	// Registering the event clears every suspicion
+	if _, err := ApplySplit(w, "BBAS3", SplitRatio{From: 1, To: 2}, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("ApplySplit returned error: %v", err)
+	}
+	if found := DetectMissedEvents(w); len(found) != 0 {
+		t.Errorf("suspicions after split = %+v, expected none", found)
+	}
+}
+
+func TestDetectMissedEvents_Grouping(t *testing.T) {
+	w := wallet.NewWallet([]parser.Transaction{
+		testTransaction("Compra", "MGLU3", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 1000, "1"),
+		testTransaction("Compra", "MGLU3", time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), 10, "10.2"),
+		testTransaction("Venda", "MGLU3", time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC), 50, "11"),
+	})
+
+	found := DetectMissedEvents(w)
+	if len(found) != 1 {
+		t.Fatalf("suspicions = %+v, expected 1", found)
+	}
+	if found[0].Kind != SuspicionPriceJump || found[0].Ratio != (Ratio{From: 10, To: 1}) {
+		t.Errorf("suspicion = %s %s, expected price jump 10:1", found[0].Kind, found[0].Ratio)
+	}
+}
+
+func TestNearIntegerFactor(t *testing.T) {
+	tests := []struct {
+		input string
+		n     int
+		ok    bool
+	}{
+		{"2", 2, true},
+		{"1.96", 2, true},
+		{"10.4", 10, true},
+		{"1.5", 0, false},
+		{"1.1", 0, false},
+		{"2.5", 0, false},
+		{"250", 0, false},
+	}
+
+	for _, tt := range tests {
+		n, ok := nearIntegerFactor(decimal.RequireFromString(tt.input))
+		if n != tt.n || ok != tt.ok {
+			t.Errorf("nearIntegerFactor(%s) = %d, %v; expected %d, %v", tt.input, n, ok, tt.n, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,412 @@
+package events
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/john/b3-project/internal/wallet"
+	"gopkg.in/yaml.v3"
+)
+
+// Import statuses of an events file entry
+const (
+	ImportPending   = "to apply"           // Ticker held and event not registered yet
+	ImportApplied   = "applied"            // Registered by ApplyImport
+	ImportDuplicate = "already registered" // Same event already in the wallet (or repeated in the file)
+	ImportNotHeld   = "not held"           // No shares of the ticker before the event date
+	ImportInvalid   = "invalid"            // Entry could not be parsed or registered (see Err)
+)
+
+// ImportEntry is one event read from an events file
+type ImportEntry struct {
+	Line   int                   // Line (CSV) or position (YAML) in the file, starting at 1
+	Event  wallet.CorporateEvent // Event with ID filled (zero value when invalid)
+	Status string
+	Err    error
+}
+
+// eventRecord is a raw entry of an events file, shared by the YAML and CSV formats
+type eventRecord struct {
+	Type          string   `yaml:"type"`
+	Ticker        string   `yaml:"ticker"`
+	Date          string   `yaml:"date"`
+	Ratio         string   `yaml:"ratio"`
+	Target        string   `yaml:"target"`
+	Percent       string   `yaml:"percent"`
+	UnitCost      string   `yaml:"unit_cost"`
+	AuctionAmount string   `yaml:"auction_amount"`
+	CashPerShare  string   `yaml:"cash_per_share"`
+	SpinOffs      []string `yaml:"spin_offs"`
+	Notes         string   `yaml:"notes"`
+}
+
+// eventsFile is the layout of a YAML events file
+type eventsFile struct {
+	Events []eventRecord `yaml:"events"`
+}
+
+// csvColumns are the columns accepted in a CSV events file (header required)
+// spin_offs holds one or more "TICKER:PERCENT[:N:M]" separated by ';' or '|'
+var csvColumns = []string{
+	"type", "ticker", "date", "ratio", "target", "percent",
+	"unit_cost", "auction_amount", "cash_per_share", "spin_offs", "notes",
+}
+
+// ParseEventsFile reads an events file (.yaml/.yml or .csv)
+// Entries that cannot be parsed are returned with status ImportInvalid; only
+// errors that affect the whole file (unreadable, unknown format) are returned
+func ParseEventsFile(path string) ([]ImportEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open events file: %w", err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParseEventsYAML(file)
+	case ".csv":
+		return ParseEventsCSV(file)
+	}
+	return nil, fmt.Errorf("unsupported events file %q: use .yaml, .yml or .csv", path)
+}
+
+// ParseEventsYAML reads events from a YAML document with a top-level "events" list
+func ParseEventsYAML(r io.Reader) ([]ImportEntry, error) {
+	var doc eventsFile
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse events YAML: %w", err)
+	}
+
+	entries := make([]ImportEntry, 0, len(doc.Events))
+	for i, record := range doc.Events {
+		entries = append(entries, newImportEntry(i+1, record))
+	}
+	return entries, nil
+}
+
+// ParseEventsCSV reads events from a CSV file with a header row (see csvColumns)
+// Both ',' and ';' are accepted as separators
+func ParseEventsCSV(r io.Reader) ([]ImportEntry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events CSV: %w", err)
+	}
+
+	reader := csv.NewReader(strings.NewReader(string(data)))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if header, _, _ := strings.Cut(string(data), "\n"); !strings.Contains(header, ",") && strings.Contains(header, ";") {
+		reader.Comma = ';'
+	}
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse events CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"type", "ticker", "date"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("events CSV header must have the columns %s (missing %q)", strings.Join(csvColumns, ","), required)
+		}
+	}
+
+	entries := make([]ImportEntry, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		field := func(name string) string {
+			if index, ok := columns[name]; ok && index < len(row) {
+				return strings.TrimSpace(row[index])
+			}
+			return ""
+		}
+		if strings.Join(row, "") == "" {
+			continue
+		}
+
+		record := eventRecord{
+			Type:          field("type"),
+			Ticker:        field("ticker"),
+			Date:          field("date"),
+			Ratio:         field("ratio"),
+			Target:        field("target"),
+			Percent:       field("percent"),
+			UnitCost:      field("unit_cost"),
+			AuctionAmount: field("auction_amount"),
+			CashPerShare:  field("cash_per_share"),
+			Notes:         field("notes"),
+		}
+		for _, spec := range strings.FieldsFunc(field("spin_offs"), func(r rune) bool { return r == ';' || r == '|' }) {
+			if strings.TrimSpace(spec) != "" {
+				record.SpinOffs = append(record.SpinOffs, spec)
+			}
+		}
+
+		entries = append(entries, newImportEntry(i+2, record))
+	}
+	return entries, nil
+}
+
+// newImportEntry converts a raw record into an entry, marking it invalid on error
+func newImportEntry(line int, record eventRecord) ImportEntry {
+	event, err := record.toEvent()
+	if err != nil {
+		return ImportEntry{Line: line, Status: ImportInvalid, Err: err}
+	}
+	return ImportEntry{Line: line, Event: event}
+}
+
+// toEvent builds the corporate event exactly as the corresponding command would,
+// so an event already entered by hand has the same ID and is not duplicated
+func (r eventRecord) toEvent() (wallet.CorporateEvent, error) {
+	ticker := strings.ToUpper(strings.TrimSpace(r.Ticker))
+	if ticker == "" {
+		return wallet.CorporateEvent{}, fmt.Errorf("ticker is required")
+	}
+
+	date, err := parseEventDate(r.Date)
+	if err != nil {
+		return wallet.CorporateEvent{}, err
+	}
+
+	auction, err := ParseAmount(r.AuctionAmount)
+	if err != nil {
+		return wallet.CorporateEvent{}, fmt.Errorf("invalid auction_amount: %w", err)
+	}
+
+	var event wallet.CorporateEvent
+	switch normalizeImportType(r.Type) {
+	case wallet.EventSplit, wallet.EventGrouping:
+		ratio, err := parseRatio(r.Ratio, "'N:M' (e.g., '1:2' or '10:1')")
+		if err != nil {
+			return wallet.CorporateEvent{}, err
+		}
+		if err := ratio.Validate(); err != nil {
+			return wallet.CorporateEvent{}, err
+		}
+		event = ratioEvent(ticker, ratio, auction, date)
+		if event.Type != normalizeImportType(r.Type) {
+			return wallet.CorporateEvent{}, fmt.Errorf("ratio %s does not match a %s", ratio, normalizeImportType(r.Type))
+		}
+
+	case wallet.EventBonus:
+		percent, err := ParseBonusPercent(r.Percent)
+		if err != nil {
+			return wallet.CorporateEvent{}, fmt.Errorf("invalid percent: %w", err)
+		}
+		unitCost, err := ParseAmount(r.UnitCost)
+		if err != nil {
+			return wallet.CorporateEvent{}, fmt.Errorf("invalid unit_cost: %w", err)
+		}
+		event = wallet.CorporateEvent{
+			Type:          wallet.EventBonus,
+			Ticker:        ticker,
+			Date:          date,
+			Percent:       percent,
+			UnitCost:      unitCost,
+			AuctionAmount: auction,
+		}
+
+	case wallet.EventRename:
+		event = wallet.CorporateEvent{
+			Type:         wallet.EventRename,
+			Ticker:       ticker,
+			TargetTicker: strings.ToUpper(strings.TrimSpace(r.Target)),
+			Date:         date,
+		}
+
+	case wallet.EventMerge:
+		from, to, err := ParseConversionRatio(r.Ratio)
+		if err != nil {
+			return wallet.CorporateEvent{}, err
+		}
+		cash, err := ParseAmount(r.CashPerShare)
+		if err != nil {
+			return wallet.CorporateEvent{}, fmt.Errorf("invalid cash_per_share: %w", err)
+		}
+		event = wallet.CorporateEvent{
+			Type:         wallet.EventMerge,
+			Ticker:       ticker,
+			TargetTicker: strings.ToUpper(strings.TrimSpace(r.Target)),
+			Date:         date,
+			RatioFrom:    from,
+			RatioTo:      to,
+			CashPerShare: cash,
+		}
+
+	case wallet.EventSpinOff:
+		targets := make([]wallet.SpinOffTarget, 0, len(r.SpinOffs))
+		for _, spec := range r.SpinOffs {
+			target, err := ParseSpinOffTarget(spec)
+			if err != nil {
+				return wallet.CorporateEvent{}, err
+			}
+			targets = append(targets, target)
+		}
+		event = spinOffEvent(ticker, targets, date)
+
+	default:
+		return wallet.CorporateEvent{}, fmt.Errorf("unsupported event type %q (use split, grouping, bonus, rename, merge or spinoff)", r.Type)
+	}
+
+	if err := event.Validate(); err != nil {
+		return wallet.CorporateEvent{}, err
+	}
+	event.ID = wallet.CalculateEventID(&event)
+	event.Notes = strings.TrimSpace(r.Notes)
+
+	return event, nil
+}
+
+// normalizeImportType maps the type names accepted in events files (English or
+// Portuguese, with or without accents) to the wallet event types
+func normalizeImportType(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "split", "desdobramento":
+		return wallet.EventSplit
+	case "grouping", "reverse split", "reverse-split", "grupamento", "agrupamento":
+		return wallet.EventGrouping
+	case "bonus", "bonificação", "bonificacao":
+		return wallet.EventBonus
+	case "rename", "ticker change", "mudança de ticker", "mudanca de ticker":
+		return wallet.EventRename
+	case "merge", "incorporação", "incorporacao":
+		return wallet.EventMerge
+	case "spinoff", "spin-off", "cisão", "cisao":
+		return wallet.EventSpinOff
+	}
+	return ""
+}
+
+// parseEventDate accepts YYYY-MM-DD or DD/MM/YYYY (as in B3 reports)
+func parseEventDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("date is required")
+	}
+	for _, layout := range []string{"2006-01-02", "02/01/2006"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD)", value)
+}
+
+// PlanImport sorts the entries chronologically and sets the status of each valid
+// one against the wallet, without changing it. Tickers created by earlier entries
+// of the file (rename, merge, spin-off) count as held from the event date
+func PlanImport(w *wallet.Wallet, entries []ImportEntry) []ImportEntry {
+	plan := make([]ImportEntry, len(entries))
+	copy(plan, entries)
+
+	// Chronological order; invalid entries last, in file order
+	sort.SliceStable(plan, func(i, j int) bool {
+		if (plan[i].Status == ImportInvalid) != (plan[j].Status == ImportInvalid) {
+			return plan[j].Status == ImportInvalid
+		}
+		return plan[i].Event.Date.Before(plan[j].Event.Date)
+	})
+
+	registered := make(map[string]bool, len(w.CorporateEvents))
+	for _, e := range w.CorporateEvents {
+		registered[e.ID] = true
+	}
+	created := make(map[string]time.Time)
+
+	for i := range plan {
+		entry := &plan[i]
+		if entry.Status == ImportInvalid {
+			continue
+		}
+
+		switch {
+		case registered[entry.Event.ID]:
+			entry.Status = ImportDuplicate
+		case !heldForImport(w, created, entry.Event):
+			entry.Status = ImportNotHeld
+		default:
+			entry.Status = ImportPending
+			registered[entry.Event.ID] = true
+			for _, target := range entry.Event.Targets() {
+				if _, exists := created[target]; !exists {
+					created[target] = entry.Event.Date
+				}
+			}
+		}
+	}
+
+	return plan
+}
+
+// heldForImport reports whether the event ticker has shares before the event date,
+// either in the wallet or created by an earlier entry of the file
+func heldForImport(w *wallet.Wallet, created map[string]time.Time, e wallet.CorporateEvent) bool {
+	if asset, exists := w.Assets[e.Ticker]; exists && asset.QuantityBefore(e.Date).IsPositive() {
+		return true
+	}
+	if since, exists := created[e.Ticker]; exists && since.Before(e.Date) {
+		return true
+	}
+	return false
+}
+
+// ApplyImport registers the entries planned as ImportPending, in chronological
+// order. Importing the same file again registers nothing: the events are
+// recognized by ID and reported as ImportDuplicate
+func ApplyImport(w *wallet.Wallet, entries []ImportEntry) []ImportEntry {
+	plan := PlanImport(w, entries)
+
+	// The whole import is one journal entry, so the wallet is not hashed per event
+	w.JournalBatch("ImportCorporateEvents", func() map[string]string {
+		var applied []string
+		for i := range plan {
+			entry := &plan[i]
+			if entry.Status != ImportPending {
+				continue
+			}
+
+			// Re-check with the positions updated by the previous events
+			if asset, exists := w.Assets[entry.Event.Ticker]; !exists || !asset.QuantityBefore(entry.Event.Date).IsPositive() {
+				entry.Status = ImportNotHeld
+				continue
+			}
+
+			if _, err := w.AddCorporateEvent(entry.Event); err != nil {
+				entry.Status = ImportInvalid
+				entry.Err = err
+				continue
+			}
+			entry.Status = ImportApplied
+			applied = append(applied, entry.Event.ID)
+		}
+		return map[string]string{
+			"applied": fmt.Sprint(len(applied)),
+			"events":  strings.Join(applied, ","),
+		}
+	})
+
+	return plan
+}
+
+// CountImportStatus counts the entries with the given status
+func CountImportStatus(entries []ImportEntry, status string) int {
+	count := 0
+	for _, entry := range entries {
+		if entry.Status == status {
+			count++
+		}
+	}
+	return count
+}
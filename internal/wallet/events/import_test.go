@@ -0,0 +1,162 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+const importTestYAML = `events:
+  - type: bonus
+    ticker: ITSA4
+    date: 2024-05-01
+    percent: 10
+    unit_cost: "12,50"
+  - type: split
+    ticker: itsa4
+    date: 2024-03-01
+    ratio: "1:2"
+  - type: rename
+    ticker: ITSA4
+    target: ITSA3
+    date: 2024-07-01
+  - type: grouping
+    ticker: ITSA3
+    date: 2024-08-01
+    ratio: "10:1"
+  - type: desdobramento
+    ticker: PETR4
+    date: 2024-03-01
+    ratio: "1:2"
+  - type: split
+    ticker: ITSA4
+    date: 2024-04-01
+    ratio: "2:1"
+`
+
+func TestParseEventsYAML(t *testing.T) {
+	entries, err := ParseEventsYAML(strings.NewReader(importTestYAML))
+	if err != nil {
+		t.Fatalf("ParseEventsYAML returned error: %v", err)
+	}
+	if len(entries) != 6 {
+		t.Fatalf("entries = %d, expected 6", len(entries))
+	}
+
+	bonus := entries[0].Event
+	if bonus.Type != wallet.EventBonus || !bonus.UnitCost.Equal(decimal.NewFromFloat(12.5)) || bonus.ID == "" {
+		t.Errorf("bonus entry = %+v", bonus)
+	}
+	if entries[1].Event.Ticker != "ITSA4" || entries[1].Event.Date.Format("2006-01-02") != "2024-03-01" {
+		t.Errorf("split entry = %+v", entries[1].Event)
+	}
+
+	// A "split" with a grouping ratio is rejected
+	if entries[5].Status != ImportInvalid || entries[5].Err == nil {
+		t.Errorf("wrong direction entry status = %q, expected invalid", entries[5].Status)
+	}
+}
+
+func TestParseEventsCSV(t *testing.T) {
+	data := `type;ticker;date;ratio;target;spin_offs
+spinoff;OLDC3;2024-06-01;;;NEWA3:20:2:1|NEWB3:10
+rename;OLDC3;01/07/2024;;NEWC3;
+unknown;OLDC3;2024-06-01;;;
+`
+	entries, err := ParseEventsCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseEventsCSV returned error: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("entries = %d, expected 3", len(entries))
+	}
+
+	spin := entries[0].Event
+	if spin.Type != wallet.EventSpinOff || len(spin.SpinOffs) != 2 || spin.SpinOffs[1].Ticker != "NEWB3" {
+		t.Errorf("spin-off entry = %+v (%v)", spin, entries[0].Err)
+	}
+	if entries[1].Event.TargetTicker != "NEWC3" || entries[1].Event.Date.Month() != time.July {
+		t.Errorf("rename entry = %+v", entries[1].Event)
+	}
+	if entries[2].Status != ImportInvalid || entries[2].Line != 4 {
+		t.Errorf("unknown type entry = line %d, status %q", entries[2].Line, entries[2].Status)
+	}
+
+	if _, err := ParseEventsCSV(strings.NewReader("ticker,date\nPETR4,2024-01-01\n")); err == nil {
+		t.Error("expected error for header without type column")
+	}
+}
+
+func TestApplyImport(t *testing.T) {
+	// 105 shares before the event, 50 after it (not eligible)
+	w := wallet.NewWallet([]parser.Transaction{
+		testTransaction("Compra", "ITSA4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 105, "10"),
+		testTransaction("Compra", "ITSA4", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 50, "10"),
+	})
+
+	// The split was already entered by hand: same ID, not registered twice
+	if _, err := ApplySplit(w, "ITSA4", SplitRatio{From: 1, To: 2}, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("ApplySplit returned error: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "events.yaml")
+	if err := os.WriteFile(path, []byte(importTestYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ParseEventsFile(path)
+	if err != nil {
+		t.Fatalf("ParseEventsFile returned error: %v", err)
+	}
+
+	plan := PlanImport(w, entries)
+	if len(w.CorporateEvents) != 1 {
+		t.Fatal("PlanImport must not change the wallet")
+	}
+
+	// Chronological order, invalid entries last
+	wantStatus := []struct {
+		ticker string
+		status string
+	}{
+		{"ITSA4", ImportDuplicate}, // split 2024-03-01
+		{"PETR4", ImportNotHeld},   // split 2024-03-01
+		{"ITSA4", ImportPending},   // bonus 2024-05-01
+		{"ITSA4", ImportPending},   // rename 2024-07-01
+		{"ITSA3", ImportPending},   // grouping 2024-08-01, created by the rename
+		{"", ImportInvalid},
+	}
+	for i, want := range wantStatus {
+		if plan[i].Event.Ticker != want.ticker || plan[i].Status != want.status {
+			t.Errorf("plan[%d] = %s %q, expected %s %q", i, plan[i].Event.Ticker, plan[i].Status, want.ticker, want.status)
+		}
+	}
+
+	applied := ApplyImport(w, entries)
+	if got := CountImportStatus(applied, ImportApplied); got != 3 {
+		t.Errorf("applied = %d, expected 3", got)
+	}
+
+	// 105 → 210 (split) → 231 (bonus) + 50 bought after = 281 → ITSA3 → 28 (grouping)
+	target := w.Assets["ITSA3"]
+	if target == nil || target.Quantity != 28 {
+		t.Fatalf("ITSA3 = %+v, expected 28 shares", target)
+	}
+
+	// Importing the same file again changes nothing
+	again := ApplyImport(w, entries)
+	if got := CountImportStatus(again, ImportApplied); got != 0 {
+		t.Errorf("second import applied %d events, expected 0", got)
+	}
+	if got := CountImportStatus(again, ImportDuplicate); got != 4 {
+		t.Errorf("second import duplicates = %d, expected 4", got)
+	}
+	if len(w.CorporateEvents) != 4 {
+		t.Errorf("registered events = %d, expected 4", len(w.CorporateEvents))
+	}
+}
@@ -0,0 +1,179 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+// MergeParams describes an incorporation: From old shares become To new shares
+type MergeParams struct {
+	TargetTicker string
+	From         decimal.Decimal
+	To           decimal.Decimal
+	CashPerShare decimal.Decimal // Cash paid per old share (zero = none)
+}
+
+// MergeResult contains statistics about a merge or ticker change
+type MergeResult struct {
+	Ticker                string
+	TargetTicker          string
+	Params                MergeParams
+	EventDate             time.Time
+	EventID               string
+	TransactionsConverted int
+	SharesConverted       decimal.Decimal
+	SharesReceived        decimal.Decimal
+	CashReceived          decimal.Decimal
+	EarningsCarried       int
+	SourceQuantityBefore  int
+	SourceQuantityAfter   int
+	TargetQuantityBefore  int
+	TargetQuantityAfter   int
+	TargetPriceBefore     decimal.Decimal
+	TargetPriceAfter      decimal.Decimal
+	InvestedBefore        decimal.Decimal
+	InvestedAfter         decimal.Decimal
+}
+
+// PreviewMerge calculates the effect of an incorporation without changing the wallet
+// Shares held before the event date are converted into the target ticker
+func PreviewMerge(w *wallet.Wallet, ticker string, params MergeParams, eventDate time.Time) (*MergeResult, error) {
+	source, exists := w.Assets[ticker]
+	if !exists {
+		return nil, fmt.Errorf("asset %s not found", ticker)
+	}
+
+	params.TargetTicker = strings.ToUpper(strings.TrimSpace(params.TargetTicker))
+	if params.TargetTicker == "" {
+		return nil, fmt.Errorf("target ticker is required")
+	}
+	if params.TargetTicker == ticker {
+		return nil, fmt.Errorf("target ticker must differ from %s", ticker)
+	}
+	if !params.From.IsPositive() || !params.To.IsPositive() {
+		return nil, fmt.Errorf("invalid ratio: both sides must be positive (got %s:%s)", params.From, params.To)
+	}
+	if params.CashPerShare.IsNegative() {
+		return nil, fmt.Errorf("invalid cash per share: cannot be negative (got %s)", params.CashPerShare)
+	}
+
+	held := source.QuantityBefore(eventDate)
+	if !held.IsPositive() {
+		return nil, fmt.Errorf("no %s shares held before %s", ticker, eventDate.Format("2006-01-02"))
+	}
+
+	result := &MergeResult{
+		Ticker:               ticker,
+		TargetTicker:         params.TargetTicker,
+		Params:               params,
+		EventDate:            eventDate,
+		SharesConverted:      held,
+		SharesReceived:       held.Mul(params.To).Div(params.From),
+		CashReceived:         held.Mul(params.CashPerShare).Round(2),
+		EarningsCarried:      len(source.EarningsHistory()),
+		SourceQuantityBefore: source.Quantity,
+		SourceQuantityAfter:  source.Quantity - int(held.Round(0).IntPart()),
+		InvestedBefore:       source.TotalInvestedValue,
+	}
+
+	for _, tx := range source.EffectiveNegotiations() {
+		if tx.Date.Before(eventDate) {
+			result.TransactionsConverted++
+		}
+	}
+
+	result.TargetQuantityAfter = int(result.SharesReceived.Round(0).IntPart())
+	if target, exists := w.Assets[params.TargetTicker]; exists {
+		result.TargetQuantityBefore = target.Quantity
+		result.TargetPriceBefore = target.AveragePrice
+		result.TargetQuantityAfter += target.Quantity
+	}
+
+	return result, nil
+}
+
+// ApplyMerge registers an incorporation (incorporação) into the target ticker, keeping the cost basis
+func ApplyMerge(w *wallet.Wallet, ticker string, params MergeParams, eventDate time.Time) (*MergeResult, error) {
+	result, err := PreviewMerge(w, ticker, params, eventDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return registerConversion(w, result, wallet.CorporateEvent{
+		Type:         wallet.EventMerge,
+		Ticker:       ticker,
+		TargetTicker: result.TargetTicker,
+		Date:         eventDate,
+		RatioFrom:    params.From,
+		RatioTo:      params.To,
+		CashPerShare: params.CashPerShare,
+	})
+}
+
+// PreviewRename calculates the effect of a ticker change without changing the wallet
+func PreviewRename(w *wallet.Wallet, ticker, newTicker string, eventDate time.Time) (*MergeResult, error) {
+	return PreviewMerge(w, ticker, MergeParams{
+		TargetTicker: newTicker,
+		From:         decimal.NewFromInt(1),
+		To:           decimal.NewFromInt(1),
+	}, eventDate)
+}
+
+// ApplyRename registers a pure ticker change (e.g., company rebrand): the
+// position, cost basis, earnings history and metadata move to the new ticker
+func ApplyRename(w *wallet.Wallet, ticker, newTicker string, eventDate time.Time) (*MergeResult, error) {
+	result, err := PreviewRename(w, ticker, newTicker, eventDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return registerConversion(w, result, wallet.CorporateEvent{
+		Type:         wallet.EventRename,
+		Ticker:       ticker,
+		TargetTicker: result.TargetTicker,
+		Date:         eventDate,
+	})
+}
+
+// registerConversion stores a merge/rename event and fills the result with the new positions
+func registerConversion(w *wallet.Wallet, result *MergeResult, e wallet.CorporateEvent) (*MergeResult, error) {
+	event, err := w.AddCorporateEvent(e)
+	if err != nil {
+		return nil, err
+	}
+	result.EventID = event.ID
+
+	if source, exists := w.Assets[result.Ticker]; exists {
+		result.SourceQuantityAfter = source.Quantity
+	}
+	if target, exists := w.Assets[result.TargetTicker]; exists {
+		result.TargetQuantityAfter = target.Quantity
+		result.TargetPriceAfter = target.AveragePrice
+		result.InvestedAfter = target.TotalInvestedValue
+	}
+
+	return result, nil
+}
+
+// ParseConversionRatio parses a ratio like "1:2", "3:1" or "1:0,5" into its two sides
+func ParseConversionRatio(ratioStr string) (decimal.Decimal, decimal.Decimal, error) {
+	parts := strings.Split(ratioStr, ":")
+	if len(parts) != 2 {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("invalid ratio format: expected 'N:M' (e.g., '1:0.5'), got '%s'", ratioStr)
+	}
+
+	from, err := parseDecimalInput(parts[0], "")
+	if err != nil || !from.IsPositive() {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("invalid ratio format: expected 'N:M' (e.g., '1:0.5'), got '%s'", ratioStr)
+	}
+	to, err := parseDecimalInput(parts[1], "")
+	if err != nil || !to.IsPositive() {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("invalid ratio format: expected 'N:M' (e.g., '1:0.5'), got '%s'", ratioStr)
+	}
+
+	return from, to, nil
+}
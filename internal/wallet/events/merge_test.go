@@ -0,0 +1,233 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+func TestApplyMerge(t *testing.T) {
+	// 200 shares bought at R$ 10, 100 sold at R$ 12
+	w := wallet.NewWallet([]parser.Transaction{
+		testTransaction("Compra", "OLDC3", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 200, "10"),
+		testTransaction("Venda", "OLDC3", time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), 100, "12"),
+	})
+	w.Assets["OLDC3"].SubType = "ações"
+	w.Assets["OLDC3"].Segment = "bancos"
+	earning := parser.Earning{
+		Date:        time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Type:        "Dividendo",
+		Ticker:      "OLDC3",
+		Quantity:    decimal.NewFromInt(100),
+		UnitPrice:   decimal.NewFromFloat(0.5),
+		TotalAmount: decimal.NewFromInt(50),
+	}
+	earning.Hash = parser.CalculateEarningHash(&earning)
+	if err := w.AddEarning(earning); err != nil {
+		t.Fatalf("AddEarning returned error: %v", err)
+	}
+	eventDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// 1 old share = 0.5 new share
+	params := MergeParams{TargetTicker: "newc3", From: decimal.NewFromInt(1), To: decimal.NewFromFloat(0.5)}
+	result, err := ApplyMerge(w, "OLDC3", params, eventDate)
+	if err != nil {
+		t.Fatalf("Error applying merge: %v", err)
+	}
+
+	if result.TargetTicker != "NEWC3" {
+		t.Errorf("TargetTicker = %s, expected NEWC3", result.TargetTicker)
+	}
+	if result.TransactionsConverted != 2 {
+		t.Errorf("TransactionsConverted = %d, expected 2 (sales are kept)", result.TransactionsConverted)
+	}
+
+	source := w.Assets["OLDC3"]
+	target, exists := w.Assets["NEWC3"]
+	if !exists {
+		t.Fatal("Target asset not created")
+	}
+
+	if source.Quantity != 0 {
+		t.Errorf("Source quantity = %d, expected 0", source.Quantity)
+	}
+	if target.Quantity != 50 {
+		t.Errorf("Target quantity = %d, expected 50", target.Quantity)
+	}
+
+	// Cost basis carried over: 2000 invested, average 10 → 20 per new share
+	if !target.TotalInvestedValue.Equal(decimal.NewFromInt(2000)) {
+		t.Errorf("Target invested = %s, expected 2000", target.TotalInvestedValue)
+	}
+	if !target.AveragePrice.Equal(decimal.NewFromInt(20)) {
+		t.Errorf("Target average price = %s, expected 20", target.AveragePrice)
+	}
+
+	// Earnings history and metadata
+	if source.SucceededBy != "NEWC3" {
+		t.Errorf("Source SucceededBy = %q, expected NEWC3", source.SucceededBy)
+	}
+	if len(target.EarningsHistory()) != 1 || len(target.Earnings) != 0 {
+		t.Errorf("Target earnings: history %d, own %d; expected 1 inherited", len(target.EarningsHistory()), len(target.Earnings))
+	}
+	if target.SubType != "ações" || target.Segment != "bancos" {
+		t.Errorf("Target metadata = %q/%q, expected ações/bancos", target.SubType, target.Segment)
+	}
+
+	// Imported records untouched
+	if len(source.Negotiations) != 2 || source.Negotiations[0].Ticker != "OLDC3" {
+		t.Error("Source negotiations were modified")
+	}
+
+	// Removing the event restores the original position and drops the empty target
+	if _, err := w.RemoveCorporateEvent(result.EventID); err != nil {
+		t.Fatalf("RemoveCorporateEvent returned error: %v", err)
+	}
+	if w.Assets["OLDC3"].Quantity != 100 {
+		t.Errorf("Source quantity after remove = %d, expected 100", w.Assets["OLDC3"].Quantity)
+	}
+	if _, exists := w.Assets["NEWC3"]; exists {
+		t.Error("Empty target asset should be removed with the event")
+	}
+}
+
+func TestApplyMerge_WithCash(t *testing.T) {
+	// 200 shares bought at R$ 10, 100 sold at R$ 12
+	w := wallet.NewWallet([]parser.Transaction{
+		testTransaction("Compra", "OLDC3", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 200, "10"),
+		testTransaction("Venda", "OLDC3", time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), 100, "12"),
+	})
+	eventDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// 1:1 plus R$ 2.00 per old share on 100 shares held
+	params := MergeParams{
+		TargetTicker: "NEWC3",
+		From:         decimal.NewFromInt(1),
+		To:           decimal.NewFromInt(1),
+		CashPerShare: decimal.NewFromInt(2),
+	}
+	result, err := ApplyMerge(w, "OLDC3", params, eventDate)
+	if err != nil {
+		t.Fatalf("Error applying merge: %v", err)
+	}
+
+	if !result.CashReceived.Equal(decimal.NewFromInt(200)) {
+		t.Errorf("CashReceived = %s, expected 200", result.CashReceived)
+	}
+
+	// Remaining cost 100 × 10 = 1000, minus 200 cash → average 8
+	target := w.Assets["NEWC3"]
+	if target.Quantity != 100 {
+		t.Errorf("Target quantity = %d, expected 100", target.Quantity)
+	}
+	if !target.AveragePrice.Equal(decimal.NewFromInt(8)) {
+		t.Errorf("Target average price = %s, expected 8", target.AveragePrice)
+	}
+}
+
+func TestApplyRename(t *testing.T) {
+	// 200 shares bought at R$ 10, 100 sold at R$ 12
+	w := wallet.NewWallet([]parser.Transaction{
+		testTransaction("Compra", "OLDC3", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 200, "10"),
+		testTransaction("Venda", "OLDC3", time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), 100, "12"),
+	})
+	earning := parser.Earning{
+		Date:        time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Type:        "Dividendo",
+		Ticker:      "OLDC3",
+		Quantity:    decimal.NewFromInt(100),
+		UnitPrice:   decimal.NewFromFloat(0.5),
+		TotalAmount: decimal.NewFromInt(50),
+	}
+	earning.Hash = parser.CalculateEarningHash(&earning)
+	if err := w.AddEarning(earning); err != nil {
+		t.Fatalf("AddEarning returned error: %v", err)
+	}
+	eventDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	result, err := ApplyRename(w, "OLDC3", "NEWC3", eventDate)
+	if err != nil {
+		t.Fatalf("Error applying rename: %v", err)
+	}
+
+	target := w.Assets["NEWC3"]
+	if target.Quantity != 100 || !target.AveragePrice.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("Target = %d @ %s, expected 100 @ 10", target.Quantity, target.AveragePrice)
+	}
+	if result.EarningsCarried != 1 {
+		t.Errorf("EarningsCarried = %d, expected 1", result.EarningsCarried)
+	}
+
+	// Chained rename carries the history along
+	if _, err := ApplyRename(w, "NEWC3", "NEWR3", eventDate.AddDate(0, 1, 0)); err != nil {
+		t.Fatalf("Error applying second rename: %v", err)
+	}
+	final := w.Assets["NEWR3"]
+	if final.Quantity != 100 || len(final.EarningsHistory()) != 1 {
+		t.Errorf("Final = %d shares, %d earnings; expected 100, 1", final.Quantity, len(final.EarningsHistory()))
+	}
+}
+
+func TestApplyMerge_Errors(t *testing.T) {
+	// 200 shares bought at R$ 10, 100 sold at R$ 12
+	w := wallet.NewWallet([]parser.Transaction{
+		testTransaction("Compra", "OLDC3", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 200, "10"),
+		testTransaction("Venda", "OLDC3", time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), 100, "12"),
+	})
+	eventDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	one := decimal.NewFromInt(1)
+
+	tests := []struct {
+		name   string
+		ticker string
+		params MergeParams
+		date   time.Time
+	}{
+		{"asset not found", "XXXX3", MergeParams{TargetTicker: "NEWC3", From: one, To: one}, eventDate},
+		{"missing target", "OLDC3", MergeParams{From: one, To: one}, eventDate},
+		{"same ticker", "OLDC3", MergeParams{TargetTicker: "OLDC3", From: one, To: one}, eventDate},
+		{"zero ratio", "OLDC3", MergeParams{TargetTicker: "NEWC3", From: one}, eventDate},
+		{"negative cash", "OLDC3", MergeParams{TargetTicker: "NEWC3", From: one, To: one, CashPerShare: one.Neg()}, eventDate},
+		{"no shares before date", "OLDC3", MergeParams{TargetTicker: "NEWC3", From: one, To: one}, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ApplyMerge(w, tt.ticker, tt.params, tt.date); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestParseConversionRatio(t *testing.T) {
+	tests := []struct {
+		input   string
+		from    string
+		to      string
+		wantErr bool
+	}{
+		{"1:2", "1", "2", false},
+		{"1:0,5", "1", "0.5", false},
+		{" 3 : 1 ", "3", "1", false},
+		{"1", "", "", true},
+		{"0:1", "", "", true},
+		{"a:b", "", "", true},
+	}
+
+	for _, tt := range tests {
+		from, to, err := ParseConversionRatio(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseConversionRatio(%q) expected error", tt.input)
+			}
+			continue
+		}
+		if err != nil || from.String() != tt.from || to.String() != tt.to {
+			t.Errorf("ParseConversionRatio(%q) = %s:%s, %v; expected %s:%s", tt.input, from, to, err, tt.from, tt.to)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Package events registers corporate events (splits, groupings, bonus shares,
+// mergers, spin-offs, subscription rights) in a wallet.
+//
+// Each event is stored as a wallet.CorporateEvent record and applied when
+// positions are calculated: imported transactions keep their original quantity,
+// price and hash, so reimporting B3 files still deduplicates and removing an
+// event restores the original position.
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+// Ratio represents a share ratio From:To (old shares : new shares)
+// Any positive rational ratio is accepted: 1:2 and 3:2 are splits, 10:1 and 5:4 are groupings
+type Ratio struct {
+	From int // Number of old shares (e.g., 1 in 1:2, 3 in 3:2)
+	To   int // Number of new shares (e.g., 2 in 1:2, 2 in 3:2)
+}
+
+// RatioResult contains statistics about a split or grouping
+type RatioResult struct {
+	Ticker               string
+	Ratio                Ratio
+	EventDate            time.Time
+	EventID              string
+	TransactionsAdjusted int
+	EligibleShares       decimal.Decimal // Shares held before the event date
+	FractionShares       decimal.Decimal // Leftover fraction after the ratio (e.g., 101 at 3:2 → 0.33)
+	FractionSoldFor      decimal.Decimal // Auction credit for the fraction (zero = truncated)
+	QuantityBefore       int
+	QuantityAfter        int
+	PriceBefore          decimal.Decimal
+	PriceAfter           decimal.Decimal
+}
+
+// String formats the ratio (e.g., "3:2")
+func (r Ratio) String() string {
+	return fmt.Sprintf("%d:%d", r.From, r.To)
+}
+
+// IsSplit reports whether the ratio increases the number of shares
+func (r Ratio) IsSplit() bool {
+	return r.To > r.From
+}
+
+// Validate checks that both sides are positive and the ratio changes the share count
+func (r Ratio) Validate() error {
+	if r.From <= 0 || r.To <= 0 {
+		return fmt.Errorf("invalid ratio: both sides must be positive (got %s)", r)
+	}
+	if r.From == r.To {
+		return fmt.Errorf("invalid ratio: %s does not change the number of shares", r)
+	}
+	return nil
+}
+
+// PreviewRatio calculates the effect of a split or grouping without changing the wallet
+// The event type follows the direction of the ratio. A leftover fraction is sold for
+// auctionAmount (the cash credited by the company) or truncated when it is zero
+func PreviewRatio(w *wallet.Wallet, ticker string, ratio Ratio, auctionAmount decimal.Decimal, eventDate time.Time) (*RatioResult, error) {
+	asset, exists := w.Assets[ticker]
+	if !exists {
+		return nil, fmt.Errorf("asset %s not found", ticker)
+	}
+	if err := ratio.Validate(); err != nil {
+		return nil, err
+	}
+	if auctionAmount.IsNegative() {
+		return nil, fmt.Errorf("invalid auction amount: cannot be negative (got %s)", auctionAmount)
+	}
+
+	from, to := decimal.NewFromInt(int64(ratio.From)), decimal.NewFromInt(int64(ratio.To))
+	eligible := asset.QuantityBefore(eventDate)
+	scaled := eligible.Mul(to).Div(from)
+
+	result := &RatioResult{
+		Ticker:         ticker,
+		Ratio:          ratio,
+		EventDate:      eventDate,
+		EligibleShares: eligible,
+		FractionShares: scaled.Sub(scaled.Round(8).Floor()).Round(8),
+		QuantityBefore: asset.Quantity,
+		PriceBefore:    asset.AveragePrice,
+	}
+	if result.FractionShares.IsPositive() {
+		result.FractionSoldFor = auctionAmount
+	}
+
+	// Count the negotiations affected by the event (those BEFORE the event date)
+	for _, tx := range asset.Negotiations {
+		if tx.Date.Before(eventDate) {
+			result.TransactionsAdjusted++
+		}
+	}
+
+	_, after, err := w.PreviewCorporateEvent(ratioEvent(ticker, ratio, auctionAmount, eventDate))
+	if err != nil {
+		return nil, err
+	}
+	result.QuantityAfter = after[ticker].Quantity
+	result.PriceAfter = after[ticker].AveragePrice
+
+	return result, nil
+}
+
+// ApplyRatio registers a split or grouping with any positive rational ratio
+func ApplyRatio(w *wallet.Wallet, ticker string, ratio Ratio, auctionAmount decimal.Decimal, eventDate time.Time) (*RatioResult, error) {
+	result, err := PreviewRatio(w, ticker, ratio, auctionAmount, eventDate)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := w.AddCorporateEvent(ratioEvent(ticker, ratio, auctionAmount, eventDate))
+	if err != nil {
+		return nil, err
+	}
+	result.EventID = event.ID
+
+	// Update result with new values
+	if updatedAsset, exists := w.Assets[ticker]; exists {
+		result.QuantityAfter = updatedAsset.Quantity
+		result.PriceAfter = updatedAsset.AveragePrice
+	}
+
+	return result, nil
+}
+
+func ratioEvent(ticker string, ratio Ratio, auctionAmount decimal.Decimal, eventDate time.Time) wallet.CorporateEvent {
+	eventType := wallet.EventGrouping
+	if ratio.IsSplit() {
+		eventType = wallet.EventSplit
+	}
+
+	return wallet.CorporateEvent{
+		Type:          eventType,
+		Ticker:        ticker,
+		Date:          eventDate,
+		RatioFrom:     decimal.NewFromInt(int64(ratio.From)),
+		RatioTo:       decimal.NewFromInt(int64(ratio.To)),
+		AuctionAmount: auctionAmount,
+	}
+}
+
+// maxRatioSide bounds each side of a ratio; larger values are typos, not real events
+const maxRatioSide = 1_000_000
+
+// parseRatio parses "N:M" into a Ratio. Integer sides are kept as typed ("2:4"
+// stays 2:4); decimal sides are converted to the smallest integer ratio
+// ("1:1,5" becomes 2:3). example is shown in the error message
+func parseRatio(ratioStr, example string) (Ratio, error) {
+	invalid := fmt.Errorf("invalid ratio format: expected %s, got '%s'", example, ratioStr)
+	tooLarge := fmt.Errorf("invalid ratio '%s': each side must be at most %d", ratioStr, maxRatioSide)
+
+	parts := strings.Split(ratioStr, ":")
+	if len(parts) != 2 {
+		return Ratio{}, invalid
+	}
+
+	from, errFrom := strconv.Atoi(strings.TrimSpace(parts[0]))
+	to, errTo := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if errFrom == nil && errTo == nil {
+		if from > maxRatioSide || to > maxRatioSide {
+			return Ratio{}, tooLarge
+		}
+		return Ratio{From: from, To: to}, nil
+	}
+
+	fromDec, err := parseDecimalInput(parts[0], "")
+	if err != nil || strings.TrimSpace(parts[0]) == "" {
+		return Ratio{}, invalid
+	}
+	toDec, err := parseDecimalInput(parts[1], "")
+	if err != nil || strings.TrimSpace(parts[1]) == "" {
+		return Ratio{}, invalid
+	}
+
+	// Scale both sides by the same power of ten so they become integers
+	exp := fromDec.Exponent()
+	if toDec.Exponent() < exp {
+		exp = toDec.Exponent()
+	}
+	if exp < -6 {
+		return Ratio{}, invalid
+	}
+	if exp < 0 {
+		scale := decimal.New(1, -exp)
+		fromDec, toDec = fromDec.Mul(scale), toDec.Mul(scale)
+	}
+	if !fromDec.IsInteger() || !toDec.IsInteger() {
+		return Ratio{}, invalid
+	}
+	limit := decimal.NewFromInt(maxRatioSide)
+	if fromDec.GreaterThan(limit) || toDec.GreaterThan(limit) {
+		return Ratio{}, tooLarge
+	}
+
+	a, b := fromDec.IntPart(), toDec.IntPart()
+	if g := gcd(a, b); g > 1 {
+		a, b = a/g, b/g
+	}
+
+	return Ratio{From: int(a), To: int(b)}, nil
+}
+
+func gcd(a, b int64) int64 {
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,157 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+func TestApplyRatio_FractionTruncated(t *testing.T) {
+	// 105 shares before the event, 50 after it (not eligible)
+	transactions := []parser.Transaction{
+		testTransaction("Compra", "ITSA4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 105, "10"),
+		testTransaction("Compra", "ITSA4", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 50, "10"),
+	}
+	w := wallet.NewWallet(transactions)
+	eventDate := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	// 2:3 split on 105 shares = 157.5: the half share is truncated
+	ratio := Ratio{From: 2, To: 3}
+	preview, err := PreviewRatio(w, "ITSA4", ratio, decimal.Zero, eventDate)
+	if err != nil {
+		t.Fatalf("PreviewRatio returned error: %v", err)
+	}
+
+	result, err := ApplySplit(w, "ITSA4", ratio, eventDate)
+	if err != nil {
+		t.Fatalf("ApplySplit returned error: %v", err)
+	}
+
+	if !result.FractionShares.Equal(decimal.RequireFromString("0.5")) || !result.FractionSoldFor.IsZero() {
+		t.Errorf("fraction = %s sold for %s, expected 0.5 truncated", result.FractionShares, result.FractionSoldFor)
+	}
+	// 157 + 50 bought after the event
+	if result.QuantityAfter != 207 {
+		t.Errorf("QuantityAfter = %d, expected 207", result.QuantityAfter)
+	}
+	if preview.QuantityAfter != result.QuantityAfter || !preview.PriceAfter.Equal(result.PriceAfter) {
+		t.Errorf("preview %d @ %s differs from result %d @ %s",
+			preview.QuantityAfter, preview.PriceAfter, result.QuantityAfter, result.PriceAfter)
+	}
+
+	events := w.SortedCorporateEvents("ITSA4")
+	if len(events) != 1 || events[0].Type != "desdobramento" {
+		t.Fatalf("events = %+v, expected one split", events)
+	}
+	if w.Assets["ITSA4"].Negotiations[0].Hash != transactions[0].Hash {
+		t.Error("imported transaction was modified")
+	}
+}
+
+func TestApplyRatio_FractionSoldInAuction(t *testing.T) {
+	// 105 shares before the event, 50 after it (not eligible)
+	w := wallet.NewWallet([]parser.Transaction{
+		testTransaction("Compra", "ITSA4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 105, "10"),
+		testTransaction("Compra", "ITSA4", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 50, "10"),
+	})
+	eventDate := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	// 10:1 grouping on 105 shares = 10.5: the half share is sold for R$ 55.00
+	result, err := ApplyRatio(w, "ITSA4", Ratio{From: 10, To: 1}, decimal.NewFromInt(55), eventDate)
+	if err != nil {
+		t.Fatalf("ApplyRatio returned error: %v", err)
+	}
+
+	if result.QuantityAfter != 60 {
+		t.Errorf("QuantityAfter = %d, expected 60", result.QuantityAfter)
+	}
+	if !result.FractionSoldFor.Equal(decimal.NewFromInt(55)) {
+		t.Errorf("FractionSoldFor = %s, expected 55", result.FractionSoldFor)
+	}
+
+	events := w.SortedCorporateEvents("ITSA4")
+	if len(events) != 1 || events[0].Type != "grupamento" || !events[0].AuctionAmount.Equal(decimal.NewFromInt(55)) {
+		t.Fatalf("events = %+v, expected one grouping with auction amount", events)
+	}
+
+	var sold decimal.Decimal
+	for _, tx := range w.Assets["ITSA4"].EffectiveNegotiations() {
+		if tx.Type == "Venda" {
+			sold = sold.Add(tx.Amount)
+		}
+	}
+	if !sold.Equal(decimal.NewFromInt(55)) {
+		t.Errorf("fraction sale amount = %s, expected 55", sold)
+	}
+}
+
+func TestApplyRatio_Errors(t *testing.T) {
+	// 105 shares before the event, 50 after it (not eligible)
+	w := wallet.NewWallet([]parser.Transaction{
+		testTransaction("Compra", "ITSA4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 105, "10"),
+		testTransaction("Compra", "ITSA4", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), 50, "10"),
+	})
+	eventDate := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		ticker  string
+		ratio   Ratio
+		auction decimal.Decimal
+	}{
+		{"unknown asset", "XXXX3", Ratio{From: 1, To: 2}, decimal.Zero},
+		{"same sides", "ITSA4", Ratio{From: 3, To: 3}, decimal.Zero},
+		{"negative side", "ITSA4", Ratio{From: -1, To: 2}, decimal.Zero},
+		{"negative auction", "ITSA4", Ratio{From: 3, To: 2}, decimal.NewFromInt(-1)},
+	}
+
+	for _, tt := range tests {
+		if _, err := ApplyRatio(w, tt.ticker, tt.ratio, tt.auction, eventDate); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+	if len(w.CorporateEvents) != 0 {
+		t.Errorf("failed events registered %d records", len(w.CorporateEvents))
+	}
+}
+
+func TestParseRatio_Decimal(t *testing.T) {
+	tests := []struct {
+		input   string
+		from    int
+		to      int
+		wantErr bool
+	}{
+		{"3:2", 3, 2, false},
+		{"5:4", 5, 4, false},
+		{"1:1,5", 2, 3, false},
+		{"1.5:1", 3, 2, false},
+		{"0.25:1", 1, 4, false},
+		{"1:1.0000001", 0, 0, true},
+		{"1,5:", 0, 0, true},
+		{"1:1000000", 1, 1000000, false},
+		{"1:1000001", 0, 0, true},
+		{"1:99999999999999999999", 0, 0, true},
+		{"1:1500000,5", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		ratio, err := ParseSplitRatio(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseSplitRatio(%q) expected error", tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSplitRatio(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if ratio.From != tt.from || ratio.To != tt.to {
+			t.Errorf("ParseSplitRatio(%q) = %s, expected %d:%d", tt.input, ratio, tt.from, tt.to)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+// SpinOffResult contains the positions before and after a spin-off (cisão)
+type SpinOffResult struct {
+	Ticker     string
+	Targets    []wallet.SpinOffTarget
+	EventDate  time.Time
+	EventID    string
+	SharesHeld decimal.Decimal
+	Tickers    []string // Parent first, then the new tickers in the given order
+	Before     map[string]wallet.PositionPreview
+	After      map[string]wallet.PositionPreview
+}
+
+// PreviewSpinOff calculates the effect of a spin-off without changing the wallet
+// Shares held before the event date define the shares received and the cost transferred
+func PreviewSpinOff(w *wallet.Wallet, ticker string, targets []wallet.SpinOffTarget, eventDate time.Time) (*SpinOffResult, error) {
+	parent, exists := w.Assets[ticker]
+	if !exists {
+		return nil, fmt.Errorf("asset %s not found", ticker)
+	}
+
+	normalized := make([]wallet.SpinOffTarget, len(targets))
+	for i, target := range targets {
+		target.Ticker = strings.ToUpper(strings.TrimSpace(target.Ticker))
+		normalized[i] = target
+	}
+
+	held := parent.QuantityBefore(eventDate)
+	if !held.IsPositive() {
+		return nil, fmt.Errorf("no %s shares held before %s", ticker, eventDate.Format("2006-01-02"))
+	}
+
+	event := spinOffEvent(ticker, normalized, eventDate)
+	before, after, err := w.PreviewCorporateEvent(event)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SpinOffResult{
+		Ticker:     ticker,
+		Targets:    normalized,
+		EventDate:  eventDate,
+		SharesHeld: held,
+		Tickers:    append([]string{ticker}, event.Targets()...),
+		Before:     before,
+		After:      after,
+	}
+
+	return result, nil
+}
+
+// ApplySpinOff registers a spin-off (cisão), moving part of the cost basis to each new ticker
+func ApplySpinOff(w *wallet.Wallet, ticker string, targets []wallet.SpinOffTarget, eventDate time.Time) (*SpinOffResult, error) {
+	result, err := PreviewSpinOff(w, ticker, targets, eventDate)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := w.AddCorporateEvent(spinOffEvent(ticker, result.Targets, eventDate))
+	if err != nil {
+		return nil, err
+	}
+	result.EventID = event.ID
+
+	for _, t := range result.Tickers {
+		if asset, exists := w.Assets[t]; exists {
+			result.After[t] = wallet.PositionPreview{
+				Quantity:           asset.Quantity,
+				AveragePrice:       asset.AveragePrice,
+				TotalInvestedValue: asset.TotalInvestedValue,
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func spinOffEvent(ticker string, targets []wallet.SpinOffTarget, eventDate time.Time) wallet.CorporateEvent {
+	return wallet.CorporateEvent{
+		Type:     wallet.EventSpinOff,
+		Ticker:   ticker,
+		Date:     eventDate,
+		SpinOffs: targets,
+	}
+}
+
+// ParseSpinOffTarget parses a spin-off spec like "NEWC3:20:1:0.5" (ticker, percent
+// of the cost basis, ratio). The ratio defaults to 1:1 when omitted ("NEWC3:20%")
+func ParseSpinOffTarget(spec string) (wallet.SpinOffTarget, error) {
+	invalid := fmt.Errorf("invalid spin-off format: expected 'TICKER:PERCENT[:N:M]' (e.g., 'NEWC3:20:1:0.5'), got '%s'", spec)
+
+	parts := strings.Split(spec, ":")
+	if len(parts) != 2 && len(parts) != 4 {
+		return wallet.SpinOffTarget{}, invalid
+	}
+
+	target := wallet.SpinOffTarget{
+		Ticker:    strings.ToUpper(strings.TrimSpace(parts[0])),
+		RatioFrom: decimal.NewFromInt(1),
+		RatioTo:   decimal.NewFromInt(1),
+	}
+	if target.Ticker == "" {
+		return wallet.SpinOffTarget{}, invalid
+	}
+
+	percent, err := ParseBonusPercent(parts[1])
+	if err != nil || !percent.IsPositive() {
+		return wallet.SpinOffTarget{}, invalid
+	}
+	target.CostPercent = percent
+
+	if len(parts) == 4 {
+		from, to, err := ParseConversionRatio(parts[2] + ":" + parts[3])
+		if err != nil {
+			return wallet.SpinOffTarget{}, invalid
+		}
+		target.RatioFrom, target.RatioTo = from, to
+	}
+
+	return target, nil
+}
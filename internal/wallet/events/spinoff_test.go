@@ -0,0 +1,161 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+func spinOffTargets() []wallet.SpinOffTarget {
+	return []wallet.SpinOffTarget{
+		{Ticker: "newa3", CostPercent: decimal.NewFromInt(20), RatioFrom: decimal.NewFromInt(1), RatioTo: decimal.RequireFromString("0.5")},
+		{Ticker: "NEWB3", CostPercent: decimal.NewFromInt(10), RatioFrom: decimal.NewFromInt(1), RatioTo: decimal.NewFromInt(1)},
+	}
+}
+
+func TestApplySpinOff(t *testing.T) {
+	// 200 shares bought at R$ 10, 100 sold at R$ 12
+	w := wallet.NewWallet([]parser.Transaction{
+		testTransaction("Compra", "OLDC3", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 200, "10"),
+		testTransaction("Venda", "OLDC3", time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), 100, "12"),
+	})
+	eventDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	preview, err := PreviewSpinOff(w, "OLDC3", spinOffTargets(), eventDate)
+	if err != nil {
+		t.Fatalf("PreviewSpinOff returned error: %v", err)
+	}
+	if _, exists := w.Assets["NEWA3"]; exists {
+		t.Fatal("preview must not change the wallet")
+	}
+
+	result, err := ApplySpinOff(w, "OLDC3", spinOffTargets(), eventDate)
+	if err != nil {
+		t.Fatalf("ApplySpinOff returned error: %v", err)
+	}
+	if result.EventID == "" {
+		t.Error("event ID not set")
+	}
+
+	// 100 shares held at avg 10 = cost 1000; 70% stays, 20% and 10% move
+	// (invested value sums all purchases, so the parent keeps 70% of 2000)
+	expected := map[string]wallet.PositionPreview{
+		"OLDC3": {Quantity: 100, AveragePrice: decimal.NewFromInt(7), TotalInvestedValue: decimal.NewFromInt(1400)},
+		"NEWA3": {Quantity: 50, AveragePrice: decimal.NewFromInt(4), TotalInvestedValue: decimal.NewFromInt(200)},
+		"NEWB3": {Quantity: 100, AveragePrice: decimal.NewFromInt(1), TotalInvestedValue: decimal.NewFromInt(100)},
+	}
+	for ticker, want := range expected {
+		asset := w.Assets[ticker]
+		if asset == nil {
+			t.Fatalf("asset %s not created", ticker)
+		}
+		if asset.Quantity != want.Quantity || !asset.AveragePrice.Equal(want.AveragePrice) || !asset.TotalInvestedValue.Equal(want.TotalInvestedValue) {
+			t.Errorf("%s: quantity %d avg %s invested %s, expected %d / %s / %s", ticker,
+				asset.Quantity, asset.AveragePrice, asset.TotalInvestedValue,
+				want.Quantity, want.AveragePrice, want.TotalInvestedValue)
+		}
+
+		got := preview.After[ticker]
+		if got.Quantity != want.Quantity || !got.TotalInvestedValue.Equal(want.TotalInvestedValue) {
+			t.Errorf("%s preview = %+v, expected %+v", ticker, got, want)
+		}
+	}
+
+	if before := preview.Before["NEWA3"]; before.Quantity != 0 {
+		t.Errorf("NEWA3 before = %+v, expected empty", before)
+	}
+	if before := preview.Before["OLDC3"]; before.Quantity != 100 || !before.AveragePrice.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("OLDC3 before = %+v", before)
+	}
+
+	// Imported transactions are untouched
+	if !w.Assets["OLDC3"].Negotiations[0].Price.Equal(decimal.NewFromInt(10)) {
+		t.Error("imported transaction was modified")
+	}
+
+	// Removing the event restores the parent and drops the new tickers
+	if _, err := w.RemoveCorporateEvent(result.EventID); err != nil {
+		t.Fatalf("RemoveCorporateEvent returned error: %v", err)
+	}
+	if _, exists := w.Assets["NEWA3"]; exists {
+		t.Error("NEWA3 should be removed with the event")
+	}
+	if !w.Assets["OLDC3"].AveragePrice.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("OLDC3 average after remove = %s, expected 10", w.Assets["OLDC3"].AveragePrice)
+	}
+}
+
+func TestApplySpinOff_Errors(t *testing.T) {
+	// 200 shares bought at R$ 10, 100 sold at R$ 12
+	w := wallet.NewWallet([]parser.Transaction{
+		testTransaction("Compra", "OLDC3", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 200, "10"),
+		testTransaction("Venda", "OLDC3", time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), 100, "12"),
+	})
+	eventDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := ApplySpinOff(w, "XXXX3", spinOffTargets(), eventDate); err == nil {
+		t.Error("expected error for unknown asset")
+	}
+	if _, err := ApplySpinOff(w, "OLDC3", spinOffTargets(), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("expected error when no shares are held before the date")
+	}
+	if _, err := ApplySpinOff(w, "OLDC3", nil, eventDate); err == nil {
+		t.Error("expected error without new tickers")
+	}
+
+	tooMuch := spinOffTargets()
+	tooMuch[0].CostPercent = decimal.NewFromInt(95)
+	if _, err := ApplySpinOff(w, "OLDC3", tooMuch, eventDate); err == nil {
+		t.Error("expected error when percentages reach 100%")
+	}
+
+	repeated := spinOffTargets()
+	repeated[1].Ticker = "NEWA3"
+	if _, err := ApplySpinOff(w, "OLDC3", repeated, eventDate); err == nil {
+		t.Error("expected error for repeated ticker")
+	}
+
+	if len(w.CorporateEvents) != 0 {
+		t.Errorf("failed spin-offs registered %d events", len(w.CorporateEvents))
+	}
+}
+
+func TestParseSpinOffTarget(t *testing.T) {
+	tests := []struct {
+		spec    string
+		ticker  string
+		percent string
+		from    string
+		to      string
+		wantErr bool
+	}{
+		{"newa3:20:1:0,5", "NEWA3", "20", "1", "0.5", false},
+		{"NEWB3:12.5%", "NEWB3", "12.5", "1", "1", false},
+		{"NEWB3", "", "", "", "", true},
+		{"NEWB3:0", "", "", "", "", true},
+		{":10", "", "", "", "", true},
+		{"NEWB3:10:1", "", "", "", "", true},
+		{"NEWB3:10:0:1", "", "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSpinOffTarget(tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseSpinOffTarget(%q) expected error", tt.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSpinOffTarget(%q) returned error: %v", tt.spec, err)
+			continue
+		}
+		if got.Ticker != tt.ticker || got.CostPercent.String() != tt.percent ||
+			got.RatioFrom.String() != tt.from || got.RatioTo.String() != tt.to {
+			t.Errorf("ParseSpinOffTarget(%q) = %+v", tt.spec, got)
+		}
+	}
+}
@@ -82,7 +82,7 @@ func TestApplySplit(t *testing.T) {
 	}
 
 	// Verify first transaction (before event) was adjusted
-	adjustedTx1 := asset.Negotiations[0]
+	adjustedTx1 := asset.AdjustedNegotiations[0]
 	expectedQty1 := decimal.NewFromInt(200) // 100 × 2 = 200
 	if !adjustedTx1.Quantity.Equal(expectedQty1) {
 		t.Errorf("Transaction 1 quantity = %s, expected %s", adjustedTx1.Quantity, expectedQty1)
@@ -100,7 +100,7 @@ func TestApplySplit(t *testing.T) {
 	}
 
 	// Verify second transaction (before event) was adjusted
-	adjustedTx2 := asset.Negotiations[1]
+	adjustedTx2 := asset.AdjustedNegotiations[1]
 	expectedQty2 := decimal.NewFromInt(100) // 50 × 2 = 100
 	if !adjustedTx2.Quantity.Equal(expectedQty2) {
 		t.Errorf("Transaction 2 quantity = %s, expected %s", adjustedTx2.Quantity, expectedQty2)
@@ -112,7 +112,7 @@ func TestApplySplit(t *testing.T) {
 	}
 
 	// Verify third transaction (after event) was NOT adjusted
-	unadjustedTx3 := asset.Negotiations[2]
+	unadjustedTx3 := asset.AdjustedNegotiations[2]
 	if !unadjustedTx3.Quantity.Equal(decimal.NewFromInt(100)) {
 		t.Errorf("Transaction 3 quantity should not change, got %s", unadjustedTx3.Quantity)
 	}
@@ -128,20 +128,45 @@ func TestApplySplit(t *testing.T) {
 		t.Errorf("Total quantity = %d, expected %d", asset.Quantity, expectedTotalQty)
 	}
 
-	// Verify hashes were recalculated for adjusted transactions
-	if adjustedTx1.Hash == tx1.Hash {
-		t.Error("Transaction 1 hash should have been recalculated")
+	// Adjusted view keeps the original hashes (the B3 record is the same)
+	if adjustedTx1.Hash != tx1.Hash {
+		t.Error("Transaction 1 hash should NOT change")
 	}
 
-	if adjustedTx2.Hash == tx2.Hash {
-		t.Error("Transaction 2 hash should have been recalculated")
+	if adjustedTx2.Hash != tx2.Hash {
+		t.Error("Transaction 2 hash should NOT change")
 	}
 
 	if unadjustedTx3.Hash != tx3.Hash {
 		t.Error("Transaction 3 hash should NOT have been recalculated")
 	}
 
-	// Verify wallet-level transactions were also updated
+	// Imported transactions must stay untouched
+	for i, original := range []parser.Transaction{tx1, tx2, tx3} {
+		stored := asset.Negotiations[i]
+		if !stored.Quantity.Equal(original.Quantity) || !stored.Price.Equal(original.Price) || stored.Hash != original.Hash {
+			t.Errorf("Negotiation %d was modified: %+v", i+1, stored)
+		}
+		if _, exists := w.TransactionsByHash[original.Hash]; !exists {
+			t.Errorf("Transaction %d hash missing from wallet", i+1)
+		}
+	}
+
+	// The event is stored as a record
+	if len(w.CorporateEvents) != 1 || w.CorporateEvents[0].ID != result.EventID {
+		t.Errorf("CorporateEvents = %+v, expected the applied event", w.CorporateEvents)
+	}
+
+	// Reimporting the same file must not create duplicates
+	added, duplicates, err := w.AddTransactions([]parser.Transaction{tx1, tx2, tx3})
+	if err != nil {
+		t.Fatalf("Error reimporting: %v", err)
+	}
+	if added != 0 || duplicates != 3 {
+		t.Errorf("Reimport added %d (duplicates %d), expected 0 (3)", added, duplicates)
+	}
+
+	// Verify wallet-level transactions are still all there
 	walletTxCount := 0
 	for _, tx := range w.Transactions {
 		if tx.Ticker == "ITSA4" {
@@ -229,7 +254,7 @@ func TestApplySplit_DifferentRatios(t *testing.T) {
 			}
 
 			// Verify adjusted transaction
-			adjustedTx := asset.Negotiations[0]
+			adjustedTx := asset.AdjustedNegotiations[0]
 			if !adjustedTx.Quantity.Equal(decimal.NewFromInt(tt.expectedQty)) {
 				t.Errorf("Adjusted quantity = %s, expected %d", adjustedTx.Quantity, tt.expectedQty)
 			}
@@ -271,12 +296,12 @@ func TestApplySplit_Errors(t *testing.T) {
 		}
 	})
 
-	t.Run("Invalid ratio - From not 1", func(t *testing.T) {
-		ratio := SplitRatio{From: 2, To: 4}
+	t.Run("Invalid ratio - decreases shares", func(t *testing.T) {
+		ratio := SplitRatio{From: 4, To: 2}
 		_, err := ApplySplit(w, "PETR4", ratio, eventDate)
 
 		if err == nil {
-			t.Error("Should return error for From != 1")
+			t.Error("Should return error for a ratio that decreases shares")
 		}
 	})
 
@@ -0,0 +1,148 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+// SubscriptionResult contains the state of a subscription right and its parent
+// after a lifecycle step (rights received, exercised or expired)
+type SubscriptionResult struct {
+	RightTicker          string
+	ParentTicker         string
+	EventDate            time.Time
+	EventID              string
+	Quantity             decimal.Decimal // Rights received, exercised or expired
+	Status               *wallet.SubscriptionStatus
+	ParentQuantityBefore int
+	ParentQuantityAfter  int
+	ParentPriceBefore    decimal.Decimal
+	ParentPriceAfter     decimal.Decimal
+}
+
+// ReceiveRights registers subscription rights credited by the company over the
+// parent asset. unitCost is usually zero (rights received for free)
+func ReceiveRights(w *wallet.Wallet, rightTicker, parentTicker string, quantity, unitCost decimal.Decimal, eventDate time.Time) (*SubscriptionResult, error) {
+	rightTicker = strings.ToUpper(strings.TrimSpace(rightTicker))
+	parentTicker = strings.ToUpper(strings.TrimSpace(parentTicker))
+	if _, exists := w.Assets[parentTicker]; !exists {
+		return nil, fmt.Errorf("asset %s not found", parentTicker)
+	}
+
+	return registerSubscription(w, wallet.CorporateEvent{
+		Type:         wallet.EventRightsReceived,
+		Ticker:       rightTicker,
+		TargetTicker: parentTicker,
+		Date:         eventDate,
+		Quantity:     quantity,
+		UnitCost:     unitCost,
+	})
+}
+
+// ExerciseRights registers the exercise of subscription rights at the exercise
+// price. The parent purchase is dated on the settlement date and costs the
+// exercise price plus the average cost of the rights consumed. Partial exercise
+// is supported: the remaining rights stay in the wallet
+//
+// When parentTicker is empty, the parent of the received rights is used
+func ExerciseRights(w *wallet.Wallet, rightTicker, parentTicker string, quantity, price decimal.Decimal, settlementDate time.Time) (*SubscriptionResult, error) {
+	rightTicker = strings.ToUpper(strings.TrimSpace(rightTicker))
+	parentTicker = strings.ToUpper(strings.TrimSpace(parentTicker))
+
+	status, err := w.SubscriptionStatus(rightTicker)
+	if err != nil {
+		return nil, err
+	}
+	if parentTicker == "" {
+		parentTicker = status.ParentTicker
+	}
+	if parentTicker == "" {
+		return nil, fmt.Errorf("parent ticker of %s is unknown: register the received rights or inform it", rightTicker)
+	}
+
+	held := heldUntil(w.Assets[rightTicker], settlementDate)
+	if quantity.GreaterThan(held) {
+		return nil, fmt.Errorf("cannot exercise %s rights: only %s %s held on %s",
+			quantity, held, rightTicker, settlementDate.Format("2006-01-02"))
+	}
+
+	return registerSubscription(w, wallet.CorporateEvent{
+		Type:         wallet.EventRightsExercised,
+		Ticker:       rightTicker,
+		TargetTicker: parentTicker,
+		Date:         settlementDate,
+		Quantity:     quantity,
+		UnitCost:     price,
+	})
+}
+
+// ExpireRights registers the expiry of the rights still held on the date: they
+// leave the wallet without proceeds and their cost becomes a realized loss
+func ExpireRights(w *wallet.Wallet, rightTicker string, eventDate time.Time) (*SubscriptionResult, error) {
+	rightTicker = strings.ToUpper(strings.TrimSpace(rightTicker))
+
+	asset, exists := w.Assets[rightTicker]
+	if !exists {
+		return nil, fmt.Errorf("asset %s not found", rightTicker)
+	}
+	if !heldUntil(asset, eventDate).IsPositive() {
+		return nil, fmt.Errorf("no %s rights held on %s", rightTicker, eventDate.Format("2006-01-02"))
+	}
+
+	return registerSubscription(w, wallet.CorporateEvent{
+		Type:   wallet.EventRightsExpired,
+		Ticker: rightTicker,
+		Date:   eventDate,
+	})
+}
+
+// registerSubscription stores a subscription event and fills the result with the new state
+func registerSubscription(w *wallet.Wallet, e wallet.CorporateEvent) (*SubscriptionResult, error) {
+	result := &SubscriptionResult{
+		RightTicker:  e.Ticker,
+		ParentTicker: e.TargetTicker,
+		EventDate:    e.Date,
+		Quantity:     e.Quantity,
+	}
+	if e.Type == wallet.EventRightsExpired {
+		result.Quantity = heldUntil(w.Assets[e.Ticker], e.Date)
+		if status, err := w.SubscriptionStatus(e.Ticker); err == nil {
+			result.ParentTicker = status.ParentTicker
+		}
+	}
+	if parent, exists := w.Assets[result.ParentTicker]; exists {
+		result.ParentQuantityBefore = parent.Quantity
+		result.ParentPriceBefore = parent.AveragePrice
+	}
+
+	event, err := w.AddCorporateEvent(e)
+	if err != nil {
+		return nil, err
+	}
+	result.EventID = event.ID
+
+	status, err := w.SubscriptionStatus(e.Ticker)
+	if err != nil {
+		return nil, err
+	}
+	result.Status = status
+
+	if parent, exists := w.Assets[result.ParentTicker]; exists {
+		result.ParentQuantityAfter = parent.Quantity
+		result.ParentPriceAfter = parent.AveragePrice
+	}
+
+	return result, nil
+}
+
+// heldUntil returns the quantity held on the date (inclusive)
+func heldUntil(asset *wallet.Asset, date time.Time) decimal.Decimal {
+	if asset == nil {
+		return decimal.Zero
+	}
+	return asset.QuantityBefore(date.AddDate(0, 0, 1))
+}
@@ -0,0 +1,121 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/john/b3-project/internal/wallet"
+	"github.com/shopspring/decimal"
+)
+
+func TestSubscriptionLifecycle(t *testing.T) {
+	// 100 MXRF11 held; 10 rights bought and 5 sold on the market
+	w := wallet.NewWallet([]parser.Transaction{
+		testTransaction("Compra", "MXRF11", time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC), 100, "10"),
+		testTransaction("Compra", "MXRF12", time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), 10, "0.50"),
+		testTransaction("Venda", "MXRF12", time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC), 5, "1.00"),
+	})
+
+	received, err := ReceiveRights(w, "mxrf12", "MXRF11", decimal.NewFromInt(20), decimal.Zero, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ReceiveRights returned error: %v", err)
+	}
+	if received.Status.Received.String() != "20" || received.Status.Remaining.String() != "25" {
+		t.Errorf("after receive: received %s, remaining %s", received.Status.Received, received.Status.Remaining)
+	}
+
+	// Partial exercise: 15 of 25 rights at R$ 9.00, settled on April 1st
+	exercised, err := ExerciseRights(w, "MXRF12", "", decimal.NewFromInt(15), decimal.NewFromInt(9), time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ExerciseRights returned error: %v", err)
+	}
+	if exercised.ParentTicker != "MXRF11" || exercised.ParentQuantityBefore != 100 || exercised.ParentQuantityAfter != 115 {
+		t.Errorf("parent %s: %d → %d, expected MXRF11 100 → 115",
+			exercised.ParentTicker, exercised.ParentQuantityBefore, exercised.ParentQuantityAfter)
+	}
+
+	// Parent purchase: 15 × 9.00 + 15 rights at 5/30 = 137.50, dated on settlement
+	var purchase *parser.Transaction
+	for _, tx := range w.Assets["MXRF11"].EffectiveNegotiations() {
+		if tx.Date.Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)) {
+			tx := tx
+			purchase = &tx
+		}
+	}
+	if purchase == nil || !purchase.Amount.Equal(decimal.RequireFromString("137.5")) {
+		t.Fatalf("parent purchase = %+v, expected 137.50 on the settlement date", purchase)
+	}
+
+	expired, err := ExpireRights(w, "MXRF12", time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ExpireRights returned error: %v", err)
+	}
+
+	status := expired.Status
+	checks := []struct {
+		name     string
+		got      decimal.Decimal
+		expected string
+	}{
+		{"received", status.Received, "20"},
+		{"bought", status.Bought, "10"},
+		{"sold", status.Sold, "5"},
+		{"sale proceeds", status.SaleProceeds, "5"},
+		{"sale result", status.SaleResult, "4.17"},
+		{"exercised", status.Exercised, "15"},
+		{"exercised paid", status.ExercisedPaid, "135"},
+		{"expired", status.Expired, "10"},
+		{"expired loss", status.ExpiredLoss, "1.67"},
+		{"remaining", status.Remaining, "0"},
+		{"realized total", status.RealizedTotal, "2.5"},
+	}
+	for _, c := range checks {
+		if !c.got.Equal(decimal.RequireFromString(c.expected)) {
+			t.Errorf("%s = %s, expected %s", c.name, c.got, c.expected)
+		}
+	}
+	if expired.Quantity.String() != "10" || w.Assets["MXRF12"].Quantity != 0 {
+		t.Errorf("expired %s rights, %d left", expired.Quantity, w.Assets["MXRF12"].Quantity)
+	}
+
+	// Removing the exercise restores the parent position
+	if _, err := w.RemoveCorporateEvent(exercised.EventID); err != nil {
+		t.Fatalf("RemoveCorporateEvent returned error: %v", err)
+	}
+	if q := w.Assets["MXRF11"].Quantity; q != 100 {
+		t.Errorf("parent quantity after removing exercise = %d, expected 100", q)
+	}
+}
+
+func TestSubscriptionLifecycle_Errors(t *testing.T) {
+	// 100 MXRF11 held; 10 rights bought and 5 sold on the market
+	w := wallet.NewWallet([]parser.Transaction{
+		testTransaction("Compra", "MXRF11", time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC), 100, "10"),
+		testTransaction("Compra", "MXRF12", time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), 10, "0.50"),
+		testTransaction("Venda", "MXRF12", time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC), 5, "1.00"),
+	})
+	date := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := ReceiveRights(w, "XXXX12", "XXXX11", decimal.NewFromInt(10), decimal.Zero, date); err == nil {
+		t.Error("expected error for unknown parent")
+	}
+	if _, err := ReceiveRights(w, "MXRF12", "MXRF11", decimal.Zero, decimal.Zero, date); err == nil {
+		t.Error("expected error for zero rights")
+	}
+	if _, err := ExerciseRights(w, "MXRF12", "", decimal.NewFromInt(5), decimal.NewFromInt(9), date); err == nil {
+		t.Error("expected error when the parent is unknown")
+	}
+	if _, err := ExerciseRights(w, "MXRF12", "MXRF11", decimal.NewFromInt(6), decimal.NewFromInt(9), date); err == nil {
+		t.Error("expected error exercising more rights than held")
+	}
+	if _, err := ExerciseRights(w, "MXRF12", "MXRF11", decimal.NewFromInt(5), decimal.Zero, date); err == nil {
+		t.Error("expected error for zero exercise price")
+	}
+	if _, err := ExpireRights(w, "MXRF12", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("expected error when no rights are held")
+	}
+	if len(w.CorporateEvents) != 0 {
+		t.Errorf("failed operations registered %d events", len(w.CorporateEvents))
+	}
+}
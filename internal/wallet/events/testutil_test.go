@@ -0,0 +1,24 @@
+package events
+
+import (
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/shopspring/decimal"
+)
+
+// testTransaction builds a hashed XP transaction with Amount = quantity × price.
+func testTransaction(txType, ticker string, date time.Time, quantity int64, price string) parser.Transaction {
+	p := decimal.RequireFromString(price)
+	tx := parser.Transaction{
+		Date:        date,
+		Type:        txType,
+		Institution: "XP",
+		Ticker:      ticker,
+		Quantity:    decimal.NewFromInt(quantity),
+		Price:       p,
+		Amount:      p.Mul(decimal.NewFromInt(quantity)),
+	}
+	tx.Hash = parser.CalculateHash(&tx)
+	return tx
+}
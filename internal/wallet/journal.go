@@ -0,0 +1,369 @@
+package wallet
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	wcrypto "github.com/john/b3-project/internal/wallet/crypto"
+	"gopkg.in/yaml.v3"
+)
+
+// JournalFileName is the append-only audit journal stored next to the vault
+// Each line is one entry, encrypted with the wallet key
+const JournalFileName = "journal.enc"
+
+// journalSigningContext separates the journal signing key from the encryption key
+const journalSigningContext = "b3cli journal signing key v1"
+
+// JournalEntry records one mutating operation on the wallet
+type JournalEntry struct {
+	// Seq é a posição da entrada no journal (começa em 1)
+	Seq int `json:"seq"`
+
+	// Timestamp é o momento em que a operação foi feita
+	Timestamp time.Time `json:"timestamp"`
+
+	// Operation é a operação da wallet (ex: AddTransaction, ApplySplit)
+	Operation string `json:"operation"`
+
+	// Command é a linha de comando que originou a operação
+	Command string `json:"command"`
+
+	// Inputs são os parâmetros da operação
+	Inputs map[string]string `json:"inputs,omitempty"`
+
+	// BeforeHash e AfterHash identificam o estado da wallet antes e depois
+	BeforeHash string `json:"before_hash"`
+	AfterHash  string `json:"after_hash"`
+
+	// Signature encadeia a entrada com a anterior (HMAC-SHA256)
+	// Alterar, remover ou reordenar entradas invalida as assinaturas seguintes
+	Signature string `json:"signature"`
+}
+
+// JournalHead identifies the newest journal entry
+// The vault stores it on each Save, so entries removed from the end of the
+// journal (which leaves a valid chain behind) are still detected
+type JournalHead struct {
+	Seq       int    `yaml:"seq"`
+	Signature string `yaml:"signature"`
+}
+
+// JournalIssue describes an entry that failed verification
+type JournalIssue struct {
+	Line   int
+	Reason string
+}
+
+// StateHash returns a hash of the complete wallet state as it would be saved
+func (w *Wallet) StateHash() string {
+	// The journal head changes on every save, not with the wallet data
+	data := w.prepareVaultData()
+	data.Journal = nil
+
+	yamlBytes, err := yaml.Marshal(data)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(yamlBytes)
+	return hex.EncodeToString(sum[:])
+}
+
+// RecordMutation queues a journal entry for an operation that changed the wallet
+// beforeHash must be taken with StateHash before the change. Nothing is recorded
+// if the state did not change. Entries are written to disk on the next Save
+func (w *Wallet) RecordMutation(operation string, inputs map[string]string, beforeHash string) {
+	if w.batching {
+		return
+	}
+
+	afterHash := w.StateHash()
+	if afterHash == beforeHash {
+		return
+	}
+
+	w.pendingJournal = append(w.pendingJournal, JournalEntry{
+		Timestamp:  time.Now().UTC(),
+		Operation:  operation,
+		Command:    commandLine(),
+		Inputs:     inputs,
+		BeforeHash: beforeHash,
+		AfterHash:  afterHash,
+	})
+}
+
+// mutationHash returns the hash to pass to RecordMutation before a change
+// Inside a JournalBatch the state is not hashed: the batch records a single entry
+func (w *Wallet) mutationHash() string {
+	if w.batching {
+		return ""
+	}
+	return w.StateHash()
+}
+
+// JournalBatch runs fn as one journal entry for operation, with the inputs it returns
+// Mutations made by fn are not recorded one by one, so the wallet is hashed once
+// before and once after instead of twice per item. Nested batches join the outer one
+func (w *Wallet) JournalBatch(operation string, fn func() map[string]string) {
+	if w.batching {
+		fn()
+		return
+	}
+
+	before := w.StateHash()
+	w.batching = true
+	inputs := func() map[string]string {
+		defer func() { w.batching = false }()
+		return fn()
+	}()
+	w.RecordMutation(operation, inputs, before)
+}
+
+// PendingJournal returns the entries recorded since the last Save
+func (w *Wallet) PendingJournal() []JournalEntry {
+	return w.pendingJournal
+}
+
+// History reads and verifies the journal of the wallet
+// Returns all readable entries (oldest first) and the verification issues found
+func (w *Wallet) History() ([]JournalEntry, []JournalIssue, error) {
+	if w.IsLocked() {
+		return nil, nil, fmt.Errorf("wallet is locked - cannot read journal without encryption key")
+	}
+
+	entries, issues, err := readJournal(w.dirPath, w.encryptionKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if issue := missingJournalHead(entries, w.journalHead); issue != nil {
+		issues = append(issues, *issue)
+	}
+	return entries, issues, nil
+}
+
+// missingJournalHead reports when the newest entry recorded in the vault is not in the journal
+// Vaults saved before the head was recorded (nil head) are not checked
+func missingJournalHead(entries []JournalEntry, head *JournalHead) *JournalIssue {
+	if head == nil || head.Seq == 0 {
+		return nil
+	}
+	for _, entry := range entries {
+		if entry.Seq == head.Seq && hmac.Equal([]byte(entry.Signature), []byte(head.Signature)) {
+			return nil
+		}
+	}
+	return &JournalIssue{
+		Line:   head.Seq,
+		Reason: fmt.Sprintf("entry %d recorded in the vault is missing (newest entries removed)", head.Seq),
+	}
+}
+
+// signPendingJournal numbers and signs the pending entries and returns the new head
+// Entries continue from the newest of the journal file and the head recorded in the
+// vault, so entries written after a truncation do not hide the gap
+func (w *Wallet) signPendingJournal(dirPath string) (*JournalHead, error) {
+	if len(w.pendingJournal) == 0 {
+		return w.journalHead, nil
+	}
+
+	lastSeq, lastSignature, err := lastJournalEntry(dirPath, w.encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	if w.journalHead != nil && w.journalHead.Seq > lastSeq {
+		lastSeq, lastSignature = w.journalHead.Seq, w.journalHead.Signature
+	}
+
+	signingKey := journalSigningKey(w.encryptionKey)
+	for i := range w.pendingJournal {
+		lastSeq++
+		w.pendingJournal[i].Seq = lastSeq
+		w.pendingJournal[i].Signature = signJournalEntry(signingKey, lastSignature, w.pendingJournal[i])
+		lastSignature = w.pendingJournal[i].Signature
+	}
+
+	return &JournalHead{Seq: lastSeq, Signature: lastSignature}, nil
+}
+
+// flushJournal appends the pending entries, signed by signPendingJournal, to the journal file
+func (w *Wallet) flushJournal(dirPath string) error {
+	if len(w.pendingJournal) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, entry := range w.pendingJournal {
+		line, err := encryptJournalEntry(entry, w.encryptionKey)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+
+	f, err := os.OpenFile(getJournalPath(dirPath), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to append to journal: %w", err)
+	}
+
+	w.pendingJournal = nil
+	return nil
+}
+
+// readJournal decrypts every entry and verifies the signature chain
+func readJournal(dirPath string, encryptionKey []byte) ([]JournalEntry, []JournalIssue, error) {
+	lines, err := readJournalLines(dirPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signingKey := journalSigningKey(encryptionKey)
+	entries := make([]JournalEntry, 0, len(lines))
+	var issues []JournalIssue
+	previousSignature := ""
+
+	for i, line := range lines {
+		entry, err := decryptJournalEntry(line, encryptionKey)
+		if err != nil {
+			issues = append(issues, JournalIssue{Line: i + 1, Reason: err.Error()})
+			continue
+		}
+
+		expected := signJournalEntry(signingKey, previousSignature, entry)
+		if !hmac.Equal([]byte(expected), []byte(entry.Signature)) {
+			issues = append(issues, JournalIssue{Line: i + 1, Reason: "signature mismatch (entry altered, removed or reordered)"})
+		}
+		if entry.Seq != i+1 {
+			issues = append(issues, JournalIssue{Line: i + 1, Reason: fmt.Sprintf("unexpected sequence number %d", entry.Seq)})
+		}
+
+		previousSignature = entry.Signature
+		entries = append(entries, entry)
+	}
+
+	return entries, issues, nil
+}
+
+// lastJournalEntry returns the sequence and signature of the newest entry
+func lastJournalEntry(dirPath string, encryptionKey []byte) (int, string, error) {
+	lines, err := readJournalLines(dirPath)
+	if err != nil || len(lines) == 0 {
+		return 0, "", err
+	}
+
+	entry, err := decryptJournalEntry(lines[len(lines)-1], encryptionKey)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read last journal entry: %w", err)
+	}
+
+	return entry.Seq, entry.Signature, nil
+}
+
+// readJournalLines returns the raw encrypted lines of the journal
+func readJournalLines(dirPath string) ([]string, error) {
+	f, err := os.Open(getJournalPath(dirPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	return lines, nil
+}
+
+// encryptJournalEntry serializes and encrypts an entry into a single line
+func encryptJournalEntry(entry JournalEntry, encryptionKey []byte) (string, error) {
+	plaintext, err := json.Marshal(entry)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize journal entry: %w", err)
+	}
+
+	ciphertext, err := wcrypto.Encrypt(plaintext, encryptionKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt journal entry: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// decryptJournalEntry decrypts and parses a journal line
+func decryptJournalEntry(line string, encryptionKey []byte) (JournalEntry, error) {
+	var entry JournalEntry
+
+	ciphertext, err := base64.StdEncoding.DecodeString(line)
+	if err != nil {
+		return entry, fmt.Errorf("invalid journal line: %w", err)
+	}
+
+	plaintext, err := wcrypto.Decrypt(ciphertext, encryptionKey)
+	if err != nil {
+		return entry, fmt.Errorf("failed to decrypt journal entry: %w", err)
+	}
+
+	if err := json.Unmarshal(plaintext, &entry); err != nil {
+		return entry, fmt.Errorf("failed to parse journal entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// journalSigningKey derives the HMAC key used to sign journal entries
+func journalSigningKey(encryptionKey []byte) []byte {
+	mac := hmac.New(sha256.New, encryptionKey)
+	mac.Write([]byte(journalSigningContext))
+	return mac.Sum(nil)
+}
+
+// signJournalEntry signs an entry chained to the previous signature
+func signJournalEntry(signingKey []byte, previousSignature string, entry JournalEntry) string {
+	entry.Signature = ""
+	payload, _ := json.Marshal(entry)
+
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write([]byte(previousSignature))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// getJournalPath returns the path to the journal file
+func getJournalPath(dirPath string) string {
+	return filepath.Join(dirPath, JournalFileName)
+}
+
+// commandLine returns the command line of the running process for the journal
+func commandLine() string {
+	if len(os.Args) == 0 {
+		return ""
+	}
+	args := append([]string{filepath.Base(os.Args[0])}, os.Args[1:]...)
+	return strings.Join(args, " ")
+}
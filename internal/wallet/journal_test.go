@@ -0,0 +1,221 @@
+package wallet
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+)
+
+func TestJournal_RecordsMutationsOnSave(t *testing.T) {
+	w, dir := newTestWallet(t)
+
+	before := w.mutationHash()
+	if err := w.AddTransaction(testBuy("PETR4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+	if err := w.UpdateAssetMetadata("PETR4", "renda variável", "ações", "petróleo"); err != nil {
+		t.Fatalf("UpdateAssetMetadata returned error: %v", err)
+	}
+
+	// Nothing is written before Save
+	if _, err := os.Stat(getJournalPath(dir)); !os.IsNotExist(err) {
+		t.Fatal("journal written before Save")
+	}
+	if err := w.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if len(w.PendingJournal()) != 0 {
+		t.Error("pending entries not cleared after Save")
+	}
+
+	entries, issues, err := w.History()
+	if err != nil {
+		t.Fatalf("History returned error: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("unexpected verification issues: %+v", issues)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, expected 2", len(entries))
+	}
+
+	first, second := entries[0], entries[1]
+	if first.Operation != "AddTransaction" || first.Inputs["ticker"] != "PETR4" {
+		t.Errorf("first entry = %s %v", first.Operation, first.Inputs)
+	}
+	if first.BeforeHash != before {
+		t.Errorf("first entry before hash = %s, expected %s", first.BeforeHash, before)
+	}
+	if second.Operation != "UpdateAssetMetadata" || second.Inputs["segment"] != "petróleo" {
+		t.Errorf("second entry = %s %v", second.Operation, second.Inputs)
+	}
+	if second.BeforeHash != first.AfterHash {
+		t.Error("consecutive entries should chain state hashes")
+	}
+	if second.AfterHash != w.StateHash() {
+		t.Error("last entry after hash should match current state")
+	}
+
+	// Entries are encrypted on disk
+	raw, _ := os.ReadFile(getJournalPath(dir))
+	if strings.Contains(string(raw), "PETR4") {
+		t.Error("journal is stored in plaintext")
+	}
+}
+
+func TestJournal_AppendsAcrossSaves(t *testing.T) {
+	w, dir := newTestWallet(t)
+
+	if err := w.AddTransaction(testBuy("PETR4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+	if err := w.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	// Batch with only duplicates does not change state and is not recorded
+	if _, _, err := w.AddTransactions([]parser.Transaction{testBuy("PETR4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100)}); err != nil {
+		t.Fatalf("AddTransactions returned error: %v", err)
+	}
+	if len(w.PendingJournal()) != 0 {
+		t.Error("no-op batch should not be recorded")
+	}
+
+	if _, _, err := w.AddTransactions([]parser.Transaction{testBuy("VALE3", time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC), 100)}); err != nil {
+		t.Fatalf("AddTransactions returned error: %v", err)
+	}
+	if err := w.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	entries, issues, err := w.History()
+	if err != nil {
+		t.Fatalf("History returned error: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("unexpected verification issues: %+v", issues)
+	}
+	if len(entries) != 2 || entries[1].Seq != 2 || entries[1].Operation != "AddTransactions" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if entries[1].Inputs["tickers"] != "VALE3" {
+		t.Errorf("tickers input = %q, expected VALE3", entries[1].Inputs["tickers"])
+	}
+}
+
+func TestJournal_DetectsTampering(t *testing.T) {
+	w, dir := newTestWallet(t)
+
+	for day := 1; day <= 3; day++ {
+		if err := w.AddTransaction(testBuy("PETR4", time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+			t.Fatalf("AddTransaction returned error: %v", err)
+		}
+	}
+	if err := w.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	// Remove the middle entry
+	lines, err := readJournalLines(dir)
+	if err != nil {
+		t.Fatalf("readJournalLines returned error: %v", err)
+	}
+	tampered := lines[0] + "\n" + lines[2] + "\n"
+	if err := os.WriteFile(getJournalPath(dir), []byte(tampered), 0600); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+
+	_, issues, err := w.History()
+	if err != nil {
+		t.Fatalf("History returned error: %v", err)
+	}
+	if len(issues) == 0 {
+		t.Fatal("removing an entry should be detected")
+	}
+	if issues[0].Line != 2 {
+		t.Errorf("first issue at line %d, expected 2", issues[0].Line)
+	}
+}
+
+func TestJournal_DetectsTruncatedTail(t *testing.T) {
+	w, dir := newTestWallet(t)
+
+	for day := 1; day <= 3; day++ {
+		if err := w.AddTransaction(testBuy("PETR4", time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+			t.Fatalf("AddTransaction returned error: %v", err)
+		}
+	}
+	if err := w.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	// Drop the newest entry: the remaining chain is still valid
+	lines, err := readJournalLines(dir)
+	if err != nil {
+		t.Fatalf("readJournalLines returned error: %v", err)
+	}
+	truncated := lines[0] + "\n" + lines[1] + "\n"
+	if err := os.WriteFile(getJournalPath(dir), []byte(truncated), 0600); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+
+	loaded, err := Load(dir, "test-password")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	_, issues, err := loaded.History()
+	if err != nil {
+		t.Fatalf("History returned error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Line != 3 {
+		t.Fatalf("issues = %+v, expected the missing entry 3", issues)
+	}
+
+	// New entries continue after the recorded head, so the gap stays visible
+	if err := loaded.AddTransaction(testBuy("VALE3", time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+	if err := loaded.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if _, issues, _ := loaded.History(); len(issues) == 0 {
+		t.Error("entries written after a truncation should not hide it")
+	}
+}
+
+func TestJournal_BatchRecordsOneEntry(t *testing.T) {
+	w, _ := newTestWallet(t)
+
+	before := w.StateHash()
+	w.JournalBatch("ImportTest", func() map[string]string {
+		for day := 10; day < 13; day++ {
+			if err := w.AddTransaction(testBuy("PETR4", time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+				t.Fatalf("AddTransaction returned error: %v", err)
+			}
+		}
+		return map[string]string{"added": "3"}
+	})
+
+	pending := w.PendingJournal()
+	if len(pending) != 1 {
+		t.Fatalf("got %d pending entries, expected 1", len(pending))
+	}
+	entry := pending[0]
+	if entry.Operation != "ImportTest" || entry.Inputs["added"] != "3" {
+		t.Errorf("entry = %s %v", entry.Operation, entry.Inputs)
+	}
+	if entry.BeforeHash != before || entry.AfterHash != w.StateHash() {
+		t.Error("batch entry should span the state before and after the batch")
+	}
+
+	// After the batch, mutations are recorded one by one again
+	if err := w.AddTransaction(testBuy("VALE3", time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+	if len(w.PendingJournal()) != 2 {
+		t.Errorf("got %d pending entries, expected 2", len(w.PendingJournal()))
+	}
+}
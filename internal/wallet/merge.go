@@ -55,6 +55,7 @@ func (w *Wallet) MergeFractionalAsset(fractionalTicker string) (*MergeResult, er
 
 	// Guardar quantidade antes do merge
 	result.TargetQuantityBefore = targetAsset.Quantity
+	before := w.mutationHash()
 
 	// Mover transações
 	for i := range sourceAsset.Negotiations {
@@ -108,9 +109,22 @@ func (w *Wallet) MergeFractionalAsset(fractionalTicker string) (*MergeResult, er
 	// Obter quantidade atualizada após recálculo
 	result.TargetQuantityAfter = w.Assets[normalizedTicker].Quantity
 
+	w.RecordMutation("MergeFractionalAsset", result.journalInputs(), before)
+
 	return result, nil
 }
 
+// journalInputs describes the merge for the journal
+func (r *MergeResult) journalInputs() map[string]string {
+	return map[string]string{
+		"source":             r.SourceTicker,
+		"target":             r.TargetTicker,
+		"target_created":     fmt.Sprint(r.TargetCreated),
+		"transactions_moved": fmt.Sprint(r.TransactionsMoved),
+		"earnings_moved":     fmt.Sprint(r.EarningsMoved),
+	}
+}
+
 // CreateAndMergeFractionalAsset cria o ativo original e mescla o fracionário
 // Usado quando o usuário confirma que quer criar o ativo original
 func (w *Wallet) CreateAndMergeFractionalAsset(fractionalTicker string) (*MergeResult, error) {
@@ -128,6 +142,8 @@ func (w *Wallet) CreateAndMergeFractionalAsset(fractionalTicker string) (*MergeR
 	// Obter ticker normalizado
 	normalizedTicker := parser.NormalizeTicker(fractionalTicker)
 
+	before := w.mutationHash()
+
 	// Criar ativo de destino com metadados do source
 	targetAsset := &Asset{
 		ID:           normalizedTicker,
@@ -199,6 +215,8 @@ func (w *Wallet) CreateAndMergeFractionalAsset(fractionalTicker string) (*MergeR
 	// Obter quantidade atualizada após recálculo
 	result.TargetQuantityAfter = w.Assets[normalizedTicker].Quantity
 
+	w.RecordMutation("CreateAndMergeFractionalAsset", result.journalInputs(), before)
+
 	return result, nil
 }
 
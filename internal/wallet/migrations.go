@@ -0,0 +1,223 @@
+package wallet
+
+import (
+	"fmt"
+	"time"
+
+	wcrypto "github.com/john/b3-project/internal/wallet/crypto"
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentSchemaVersion is the version of the VaultData layout written by this build
+// Bump it together with a new entry in migrations whenever the layout changes
+const CurrentSchemaVersion = 5
+
+// legacySchemaVersion is assumed for vaults written before versioning existed
+const legacySchemaVersion = 1
+
+// vaultDocument is the raw, untyped vault content that migrations operate on
+type vaultDocument map[string]interface{}
+
+// migration upgrades a vault document from version From to From+1
+type migration struct {
+	From        int
+	Description string
+	Apply       func(doc vaultDocument) error
+}
+
+// migrations is the ordered registry of schema upgrades
+// Each step only needs to know about its own version; older vaults are
+// upgraded by applying every step in sequence
+var migrations = []migration{
+	{
+		From:        1,
+		Description: "add schema_version to the vault",
+		Apply:       func(doc vaultDocument) error { return nil },
+	},
+	{
+		// Desdobramentos/grupamentos antigos já foram gravados nas transações;
+		// a partir daqui os eventos ficam em corporate_events
+		From:        2,
+		Description: "add corporate_events registry",
+		Apply: func(doc vaultDocument) error {
+			if _, exists := doc["corporate_events"]; !exists {
+				doc["corporate_events"] = []interface{}{}
+			}
+			return nil
+		},
+	},
+	{
+		// Proventos anunciados (pendentes) ficam em announced_earnings
+		From:        3,
+		Description: "add announced_earnings registry",
+		Apply: func(doc vaultDocument) error {
+			if _, exists := doc["announced_earnings"]; !exists {
+				doc["announced_earnings"] = []interface{}{}
+			}
+			return nil
+		},
+	},
+	{
+		// Proventos ganham gross_amount, withheld_tax e net_amount (opcionais);
+		// os ausentes são inferidos no carregamento (parser.InferEarningAmounts)
+		From:        4,
+		Description: "add gross, withheld tax and net amounts to earnings",
+		Apply:       func(doc vaultDocument) error { return nil },
+	},
+}
+
+// MigrationResult describes the upgrade applied to a vault on load
+type MigrationResult struct {
+	FromVersion int
+	ToVersion   int
+	Steps       []string
+	BackupPath  string
+}
+
+// schemaVersion returns the version stored in the document (legacy if absent)
+func (doc vaultDocument) schemaVersion() (int, error) {
+	raw, exists := doc["schema_version"]
+	if !exists || raw == nil {
+		return legacySchemaVersion, nil
+	}
+
+	version, ok := raw.(int)
+	if !ok || version < legacySchemaVersion {
+		return 0, fmt.Errorf("invalid schema_version %v", raw)
+	}
+
+	return version, nil
+}
+
+// migrateDocument applies every migration needed to bring doc to CurrentSchemaVersion
+// Returns the descriptions of the applied steps
+func migrateDocument(doc vaultDocument) (from int, steps []string, err error) {
+	version, err := doc.schemaVersion()
+	if err != nil {
+		return 0, nil, err
+	}
+	from = version
+
+	if version > CurrentSchemaVersion {
+		return from, nil, fmt.Errorf("vault schema version %d is newer than supported version %d - upgrade b3cli", version, CurrentSchemaVersion)
+	}
+
+	for version < CurrentSchemaVersion {
+		step, found := findMigration(version)
+		if !found {
+			return from, steps, fmt.Errorf("no migration registered from schema version %d", version)
+		}
+
+		if err := step.Apply(doc); err != nil {
+			return from, steps, fmt.Errorf("migration %d -> %d (%s) failed: %w", version, version+1, step.Description, err)
+		}
+
+		version++
+		doc["schema_version"] = version
+		steps = append(steps, fmt.Sprintf("v%d -> v%d: %s", version-1, version, step.Description))
+	}
+
+	return from, steps, nil
+}
+
+// findMigration returns the registered migration starting at version
+func findMigration(version int) (migration, bool) {
+	for _, m := range migrations {
+		if m.From == version {
+			return m, true
+		}
+	}
+	return migration{}, false
+}
+
+// toVaultDocument converts the decrypted vault into a raw document
+func toVaultDocument(data *wcrypto.VaultData) (vaultDocument, error) {
+	yamlBytes, err := yaml.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process vault data: %w", err)
+	}
+
+	doc := vaultDocument{}
+	if err := yaml.Unmarshal(yamlBytes, &doc); err != nil {
+		return nil, fmt.Errorf("failed to process vault data: %w", err)
+	}
+
+	return doc, nil
+}
+
+// migrateVault upgrades the vault stored in dirPath to CurrentSchemaVersion if needed
+// An encrypted copy of the original vault is written before anything changes
+// Returns the typed vault data and the applied migration (nil if already current)
+func migrateVault(dirPath string, data *wcrypto.VaultData) (*VaultData, *MigrationResult, error) {
+	doc, err := toVaultDocument(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	version, err := doc.schemaVersion()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result *MigrationResult
+	if version != CurrentSchemaVersion {
+		// Backup first so a failed or buggy migration never loses data
+		var backupPath string
+		if version < CurrentSchemaVersion {
+			suffix := fmt.Sprintf("v%d-%s", version, time.Now().Format("20060102-150405"))
+			backupPath, err = wcrypto.BackupVault(dirPath, suffix)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to back up vault before migration: %w", err)
+			}
+		}
+
+		from, steps, err := migrateDocument(doc)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		result = &MigrationResult{
+			FromVersion: from,
+			ToVersion:   CurrentSchemaVersion,
+			Steps:       steps,
+			BackupPath:  backupPath,
+		}
+	}
+
+	vaultData, err := doc.typed()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return vaultData, result, nil
+}
+
+// migrateInMemory upgrades vault data without touching any file
+// Used to read copies of the vault (e.g. undo snapshots)
+func migrateInMemory(data *wcrypto.VaultData) (*VaultData, error) {
+	doc, err := toVaultDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err := migrateDocument(doc); err != nil {
+		return nil, err
+	}
+
+	return doc.typed()
+}
+
+// typed converts the raw document into VaultData
+func (doc vaultDocument) typed() (*VaultData, error) {
+	yamlBytes, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process vault data: %w", err)
+	}
+
+	var vaultData VaultData
+	if err := yaml.Unmarshal(yamlBytes, &vaultData); err != nil {
+		return nil, fmt.Errorf("failed to parse vault data: %w", err)
+	}
+
+	return &vaultData, nil
+}
@@ -0,0 +1,141 @@
+package wallet
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	wcrypto "github.com/john/b3-project/internal/wallet/crypto"
+)
+
+// writeRawVault creates an encrypted wallet whose vault holds the given raw content
+func writeRawVault(t *testing.T, data wcrypto.VaultData) (string, []byte) {
+	t.Helper()
+
+	dir := t.TempDir()
+	key, err := wcrypto.InitializeVault(dir, "test-password")
+	if err != nil {
+		t.Fatalf("InitializeVault returned error: %v", err)
+	}
+	if err := wcrypto.SaveVault(dir, data, key); err != nil {
+		t.Fatalf("SaveVault returned error: %v", err)
+	}
+
+	return dir, key
+}
+
+func TestLoad_MigratesLegacyVault(t *testing.T) {
+	// Vault written before schema versioning existed
+	dir, key := writeRawVault(t, wcrypto.VaultData{
+		Transactions: []map[string]string{{
+			"date": "2024-01-15", "type": "Compra", "institution": "XP", "ticker": "PETR4",
+			"quantity": "100.0000", "price": "30.0000", "amount": "3000.0000", "hash": "abc",
+		}},
+		Assets: []interface{}{},
+	})
+
+	w, err := LoadWithKey(dir, append([]byte(nil), key...))
+	if err != nil {
+		t.Fatalf("LoadWithKey returned error: %v", err)
+	}
+
+	m := w.Migration()
+	if m == nil {
+		t.Fatal("expected a migration for a legacy vault")
+	}
+	if m.FromVersion != legacySchemaVersion || m.ToVersion != CurrentSchemaVersion {
+		t.Errorf("migration = v%d -> v%d, expected v%d -> v%d", m.FromVersion, m.ToVersion, legacySchemaVersion, CurrentSchemaVersion)
+	}
+
+	if filepath.Dir(m.BackupPath) != dir {
+		t.Errorf("backup written to %s, expected inside %s", m.BackupPath, dir)
+	}
+
+	// The upgraded layout is persisted
+	current, err := wcrypto.LoadVault(dir, key)
+	if err != nil {
+		t.Fatalf("LoadVault returned error: %v", err)
+	}
+	if current.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("vault schema version = %d after migration, expected %d", current.SchemaVersion, CurrentSchemaVersion)
+	}
+
+	if asset := w.Assets["PETR4"]; asset == nil || asset.Quantity != 100 {
+		t.Errorf("migrated wallet lost data: %+v", asset)
+	}
+
+	// Loading again must not migrate twice
+	again, err := LoadWithKey(dir, append([]byte(nil), key...))
+	if err != nil {
+		t.Fatalf("second LoadWithKey returned error: %v", err)
+	}
+	if again.Migration() != nil {
+		t.Error("vault was migrated again on second load")
+	}
+}
+
+func TestLoad_RejectsNewerSchema(t *testing.T) {
+	dir, key := writeRawVault(t, wcrypto.VaultData{
+		SchemaVersion: CurrentSchemaVersion + 1,
+		Transactions:  []interface{}{},
+		Assets:        []interface{}{},
+	})
+
+	_, err := LoadWithKey(dir, key)
+	if err == nil || !strings.Contains(err.Error(), "newer than supported") {
+		t.Errorf("LoadWithKey returned %v, expected newer schema error", err)
+	}
+}
+
+func TestLoad_ParseErrorsIncludeRowContext(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        wcrypto.VaultData
+		errContains []string
+	}{
+		{
+			name: "invalid transaction date",
+			data: wcrypto.VaultData{
+				SchemaVersion: CurrentSchemaVersion,
+				Transactions: []map[string]string{
+					{"date": "2024-01-15", "type": "Compra", "ticker": "PETR4", "quantity": "1", "price": "1", "amount": "1", "hash": "a"},
+					{"date": "15/01/2024", "type": "Compra", "ticker": "VALE3", "quantity": "1", "price": "1", "amount": "1", "hash": "b"},
+				},
+				Assets: []interface{}{},
+			},
+			errContains: []string{"transaction #2", "VALE3", "date", "15/01/2024"},
+		},
+		{
+			name: "invalid earning amount",
+			data: wcrypto.VaultData{
+				SchemaVersion: CurrentSchemaVersion,
+				Transactions:  []interface{}{},
+				Assets: []map[string]interface{}{{
+					"ticker": "ITSA4",
+					"type":   "renda variável",
+					"earnings": []map[string]string{{
+						"date": "2024-03-01", "type": "Dividendo", "ticker": "ITSA4",
+						"quantity": "100", "unit_price": "0.1", "total_amount": "ten", "hash": "c",
+					}},
+				}},
+			},
+			errContains: []string{"earning #1 of ITSA4", "total_amount", "ten"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, key := writeRawVault(t, tt.data)
+
+			_, err := LoadWithKey(dir, key)
+			if err == nil {
+				t.Fatal("expected parse error")
+			}
+			for _, want := range tt.errContains {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not contain %q", err, want)
+				}
+			}
+		})
+	}
+}
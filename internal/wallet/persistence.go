@@ -1,6 +1,7 @@
 package wallet
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -51,38 +52,116 @@ type EarningYAML struct {
 	Quantity    string `yaml:"quantity"`
 	UnitPrice   string `yaml:"unit_price"`
 	TotalAmount string `yaml:"total_amount"`
+	GrossAmount string `yaml:"gross_amount,omitempty"`
+	WithheldTax string `yaml:"withheld_tax,omitempty"`
+	NetAmount   string `yaml:"net_amount,omitempty"`
 	Hash        string `yaml:"hash"`
 }
 
+// CorporateEventYAML representa um evento corporativo para serialização YAML
+// A proporção é armazenada como string para manter precisão decimal
+type CorporateEventYAML struct {
+	ID            string        `yaml:"id"`
+	Type          string        `yaml:"type"`
+	Ticker        string        `yaml:"ticker"`
+	TargetTicker  string        `yaml:"target_ticker,omitempty"`
+	Date          string        `yaml:"date"`
+	RatioFrom     string        `yaml:"ratio_from,omitempty"`
+	RatioTo       string        `yaml:"ratio_to,omitempty"`
+	Percent       string        `yaml:"percent,omitempty"`
+	UnitCost      string        `yaml:"unit_cost,omitempty"`
+	AuctionAmount string        `yaml:"auction_amount,omitempty"`
+	CashPerShare  string        `yaml:"cash_per_share,omitempty"`
+	Quantity      string        `yaml:"quantity,omitempty"`
+	SpinOffs      []SpinOffYAML `yaml:"spin_offs,omitempty"`
+	Notes         string        `yaml:"notes,omitempty"`
+}
+
+// SpinOffYAML representa um ativo novo de uma cisão para serialização YAML
+type SpinOffYAML struct {
+	Ticker      string `yaml:"ticker"`
+	CostPercent string `yaml:"cost_percent"`
+	RatioFrom   string `yaml:"ratio_from"`
+	RatioTo     string `yaml:"ratio_to"`
+}
+
+// AnnouncedEarningYAML representa um provento anunciado para serialização YAML
+type AnnouncedEarningYAML struct {
+	ID          string `yaml:"id"`
+	Ticker      string `yaml:"ticker"`
+	Type        string `yaml:"type"`
+	RecordDate  string `yaml:"record_date"`
+	PaymentDate string `yaml:"payment_date"`
+	UnitPrice   string `yaml:"unit_price"`
+	Notes       string `yaml:"notes,omitempty"`
+}
+
 // VaultData representa os dados completos da wallet que serão criptografados
+// SchemaVersion identifica o layout; vaults antigos são migrados no Load (ver migrations.go)
 type VaultData struct {
-	Transactions []TransactionYAML `yaml:"transactions"`
-	Assets       []AssetYAML       `yaml:"assets"`
+	SchemaVersion   int                  `yaml:"schema_version"`
+	Transactions    []TransactionYAML    `yaml:"transactions"`
+	Assets          []AssetYAML          `yaml:"assets"`
+	CorporateEvents []CorporateEventYAML `yaml:"corporate_events,omitempty"`
+
+	AnnouncedEarnings []AnnouncedEarningYAML `yaml:"announced_earnings,omitempty"`
+
+	// Journal é a entrada mais recente do journal de auditoria (ver journal.go)
+	Journal *JournalHead `yaml:"journal,omitempty"`
 }
 
 // Save encrypts and saves the wallet to disk
 // Also updates the unlocked cache if it exists (for session persistence)
 // The wallet must have an encryption key set (unlocked) to be saved
 func (w *Wallet) Save(dirPath string) error {
+	return w.save(dirPath, true)
+}
+
+// save writes the vault and the journal; keepUndo stores the previous vault on the
+// undo stack (undo and redo manage the stacks themselves)
+func (w *Wallet) save(dirPath string, keepUndo bool) error {
 	// Check if wallet is locked
 	if w.IsLocked() {
 		return fmt.Errorf("wallet is locked - cannot save without encryption key")
 	}
 
-	// Prepare vault data
+	// Sign the new journal entries first: the vault records the newest one
+	head, err := w.signPendingJournal(dirPath)
+	if err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+
+	// Prepare vault data and convert to crypto.VaultData format
 	vaultData := w.prepareVaultData()
+	vaultData.Journal = head
+	cryptoVaultData, err := toCryptoVaultData(vaultData)
+	if err != nil {
+		return err
+	}
 
-	// Convert to crypto.VaultData format
-	cryptoVaultData := wcrypto.VaultData{
-		Transactions: vaultData.Transactions,
-		Assets:       vaultData.Assets,
+	// Keep the previous vault content for undo (only when something changed)
+	var previousVault []byte
+	if keepUndo && len(w.pendingJournal) > 0 {
+		previousVault, _ = os.ReadFile(filepath.Join(dirPath, wcrypto.VaultFileName))
 	}
 
 	// Save encrypted vault
-	if err := wcrypto.SaveVault(dirPath, cryptoVaultData, w.encryptionKey); err != nil {
+	if err := wcrypto.SaveVault(dirPath, *cryptoVaultData, w.encryptionKey); err != nil {
 		return fmt.Errorf("failed to save wallet: %w", err)
 	}
 
+	if keepUndo {
+		if err := w.takeSnapshot(dirPath, previousVault); err != nil {
+			return fmt.Errorf("failed to store undo snapshot: %w", err)
+		}
+	}
+
+	// Append the operations made since the last save to the audit journal
+	if err := w.flushJournal(dirPath); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	w.journalHead = head
+
 	// If unlocked cache exists, update it too
 	if IsUnlocked(dirPath) {
 		if err := w.SaveUnlocked(dirPath); err != nil {
@@ -99,8 +178,10 @@ func (w *Wallet) Save(dirPath string) error {
 // prepareVaultData converts wallet data to VaultData for serialization
 func (w *Wallet) prepareVaultData() VaultData {
 	vaultData := VaultData{
-		Transactions: make([]TransactionYAML, 0, len(w.Transactions)),
-		Assets:       make([]AssetYAML, 0, len(w.Assets)),
+		SchemaVersion: CurrentSchemaVersion,
+		Transactions:  make([]TransactionYAML, 0, len(w.Transactions)),
+		Assets:        make([]AssetYAML, 0, len(w.Assets)),
+		Journal:       w.journalHead,
 	}
 
 	// Convert transactions
@@ -143,6 +224,9 @@ func (w *Wallet) prepareVaultData() VaultData {
 				Quantity:    e.Quantity.StringFixed(4),
 				UnitPrice:   e.UnitPrice.StringFixed(4),
 				TotalAmount: e.TotalAmount.StringFixed(4),
+				GrossAmount: formatOptionalDecimal(e.GrossAmount),
+				WithheldTax: formatOptionalDecimal(e.WithheldTax),
+				NetAmount:   formatOptionalDecimal(e.NetAmount),
 				Hash:        e.Hash,
 			})
 		}
@@ -164,6 +248,49 @@ func (w *Wallet) prepareVaultData() VaultData {
 		vaultData.Assets = append(vaultData.Assets, assetYAML)
 	}
 
+	// Convert corporate events (chronological)
+	for _, e := range w.SortedCorporateEvents("") {
+		var spinOffs []SpinOffYAML
+		for _, target := range e.SpinOffs {
+			spinOffs = append(spinOffs, SpinOffYAML{
+				Ticker:      target.Ticker,
+				CostPercent: target.CostPercent.String(),
+				RatioFrom:   target.RatioFrom.String(),
+				RatioTo:     target.RatioTo.String(),
+			})
+		}
+
+		vaultData.CorporateEvents = append(vaultData.CorporateEvents, CorporateEventYAML{
+			ID:            e.ID,
+			Type:          e.Type,
+			Ticker:        e.Ticker,
+			TargetTicker:  e.TargetTicker,
+			Date:          e.Date.Format("2006-01-02"),
+			RatioFrom:     formatOptionalDecimal(e.RatioFrom),
+			RatioTo:       formatOptionalDecimal(e.RatioTo),
+			Percent:       formatOptionalDecimal(e.Percent),
+			UnitCost:      formatOptionalDecimal(e.UnitCost),
+			AuctionAmount: formatOptionalDecimal(e.AuctionAmount),
+			CashPerShare:  formatOptionalDecimal(e.CashPerShare),
+			Quantity:      formatOptionalDecimal(e.Quantity),
+			SpinOffs:      spinOffs,
+			Notes:         e.Notes,
+		})
+	}
+
+	// Convert announced earnings (by payment date)
+	for _, a := range w.SortedAnnouncedEarnings() {
+		vaultData.AnnouncedEarnings = append(vaultData.AnnouncedEarnings, AnnouncedEarningYAML{
+			ID:          a.ID,
+			Ticker:      a.Ticker,
+			Type:        a.Type,
+			RecordDate:  a.RecordDate.Format("2006-01-02"),
+			PaymentDate: a.PaymentDate.Format("2006-01-02"),
+			UnitPrice:   a.UnitPrice.String(),
+			Notes:       a.Notes,
+		})
+	}
+
 	return vaultData
 }
 
@@ -181,6 +308,11 @@ func Create(dirPath, password string) (*Wallet, error) {
 	w.SetEncryptionKey(encryptionKey)
 	w.SetDirPath(dirPath)
 
+	// Rewrite the empty vault stamped with the current schema version
+	if err := w.Save(dirPath); err != nil {
+		return nil, err
+	}
+
 	return w, nil
 }
 
@@ -193,6 +325,12 @@ func Load(dirPath, password string) (*Wallet, error) {
 		return nil, fmt.Errorf("failed to unlock wallet: %w", err)
 	}
 
+	return LoadWithKey(dirPath, encryptionKey)
+}
+
+// LoadWithKey loads and decrypts a wallet from disk using an already unwrapped encryption key
+// The wallet takes ownership of the key; it is zeroed if loading fails
+func LoadWithKey(dirPath string, encryptionKey []byte) (*Wallet, error) {
 	// Load encrypted vault
 	cryptoVaultData, err := wcrypto.LoadVault(dirPath, encryptionKey)
 	if err != nil {
@@ -200,28 +338,79 @@ func Load(dirPath, password string) (*Wallet, error) {
 		return nil, fmt.Errorf("failed to load wallet: %w", err)
 	}
 
-	// Convert from interface{} to proper types
-	var vaultData VaultData
-
-	// Marshal and unmarshal to convert interface{} to typed structs
-	yamlBytes, err := yaml.Marshal(cryptoVaultData)
+	// Upgrade older vault layouts (backup is written before any change)
+	vaultData, migration, err := migrateVault(dirPath, cryptoVaultData)
 	if err != nil {
 		wcrypto.ZeroBytes(encryptionKey)
-		return nil, fmt.Errorf("failed to process vault data: %w", err)
+		return nil, fmt.Errorf("failed to migrate wallet: %w", err)
 	}
 
-	if err := yaml.Unmarshal(yamlBytes, &vaultData); err != nil {
+	w, err := fromVaultData(vaultData)
+	if err != nil {
 		wcrypto.ZeroBytes(encryptionKey)
-		return nil, fmt.Errorf("failed to parse vault data: %w", err)
+		return nil, fmt.Errorf("failed to load wallet: %w", err)
 	}
 
+	// Set encryption key and path
+	w.SetEncryptionKey(encryptionKey)
+	w.SetDirPath(dirPath)
+
+	// Persist the migrated layout so the upgrade only runs once
+	if migration != nil {
+		if err := w.Save(dirPath); err != nil {
+			wcrypto.ZeroBytes(encryptionKey)
+			return nil, fmt.Errorf("failed to save migrated wallet (backup at %s): %w", migration.BackupPath, err)
+		}
+		w.migration = migration
+	}
+
+	return w, nil
+}
+
+// Migration returns the schema upgrade applied when the wallet was loaded (nil if none)
+func (w *Wallet) Migration() *MigrationResult {
+	return w.migration
+}
+
+// toCryptoVaultData converts typed vault data into the format stored by the crypto package
+func toCryptoVaultData(vaultData VaultData) (*wcrypto.VaultData, error) {
+	yamlBytes, err := yaml.Marshal(vaultData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize wallet: %w", err)
+	}
+
+	var cryptoVaultData wcrypto.VaultData
+	if err := yaml.Unmarshal(yamlBytes, &cryptoVaultData); err != nil {
+		return nil, fmt.Errorf("failed to serialize wallet: %w", err)
+	}
+
+	return &cryptoVaultData, nil
+}
+
+// fromVaultData builds a wallet from serialized vault data
+// Any field that fails to parse is a hard error that identifies the offending row
+func fromVaultData(vaultData *VaultData) (*Wallet, error) {
 	// Convert transactions
 	transactions := make([]parser.Transaction, 0, len(vaultData.Transactions))
-	for _, ty := range vaultData.Transactions {
-		date, _ := time.Parse("2006-01-02", ty.Date)
-		quantity, _ := decimal.NewFromString(ty.Quantity)
-		price, _ := decimal.NewFromString(ty.Price)
-		amount, _ := decimal.NewFromString(ty.Amount)
+	for i, ty := range vaultData.Transactions {
+		row := fmt.Sprintf("transaction #%d (%s %s %s)", i+1, ty.Date, ty.Type, ty.Ticker)
+
+		date, err := parseVaultDate(row, "date", ty.Date)
+		if err != nil {
+			return nil, err
+		}
+		quantity, err := parseVaultDecimal(row, "quantity", ty.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		price, err := parseVaultDecimal(row, "price", ty.Price)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := parseVaultDecimal(row, "amount", ty.Amount)
+		if err != nil {
+			return nil, err
+		}
 
 		transactions = append(transactions, parser.Transaction{
 			Date:        date,
@@ -261,11 +450,25 @@ func Load(dirPath, password string) (*Wallet, error) {
 		asset.SubscriptionOf = ay.SubscriptionOf
 
 		// Restore earnings
-		for _, ey := range ay.Earnings {
-			date, _ := time.Parse("2006-01-02", ey.Date)
-			quantity, _ := decimal.NewFromString(ey.Quantity)
-			unitPrice, _ := decimal.NewFromString(ey.UnitPrice)
-			totalAmount, _ := decimal.NewFromString(ey.TotalAmount)
+		for i, ey := range ay.Earnings {
+			row := fmt.Sprintf("earning #%d of %s (%s %s)", i+1, ay.Ticker, ey.Date, ey.Type)
+
+			date, err := parseVaultDate(row, "date", ey.Date)
+			if err != nil {
+				return nil, err
+			}
+			quantity, err := parseVaultDecimal(row, "quantity", ey.Quantity)
+			if err != nil {
+				return nil, err
+			}
+			unitPrice, err := parseVaultDecimal(row, "unit_price", ey.UnitPrice)
+			if err != nil {
+				return nil, err
+			}
+			totalAmount, err := parseVaultDecimal(row, "total_amount", ey.TotalAmount)
+			if err != nil {
+				return nil, err
+			}
 
 			earning := parser.Earning{
 				Date:        date,
@@ -277,20 +480,156 @@ func Load(dirPath, password string) (*Wallet, error) {
 				Hash:        ey.Hash,
 			}
 
+			// Bruto, IR retido e líquido são opcionais; os ausentes são inferidos
+			amounts := []struct {
+				name  string
+				value string
+				dest  *decimal.Decimal
+			}{
+				{"gross_amount", ey.GrossAmount, &earning.GrossAmount},
+				{"withheld_tax", ey.WithheldTax, &earning.WithheldTax},
+				{"net_amount", ey.NetAmount, &earning.NetAmount},
+			}
+			for _, v := range amounts {
+				if v.value == "" {
+					continue
+				}
+				if *v.dest, err = parseVaultDecimal(row, v.name, v.value); err != nil {
+					return nil, err
+				}
+			}
+			parser.InferEarningAmounts(&earning)
+
 			asset.Earnings = append(asset.Earnings, earning)
 		}
 	}
 
+	// Restore corporate events
+	for i, ey := range vaultData.CorporateEvents {
+		row := fmt.Sprintf("corporate event #%d (%s %s %s)", i+1, ey.Date, ey.Type, ey.Ticker)
+
+		date, err := parseVaultDate(row, "date", ey.Date)
+		if err != nil {
+			return nil, err
+		}
+
+		event := CorporateEvent{
+			ID:           ey.ID,
+			Type:         ey.Type,
+			Ticker:       ey.Ticker,
+			TargetTicker: ey.TargetTicker,
+			Date:         date,
+			Notes:        ey.Notes,
+		}
+
+		// Campos numéricos opcionais: cada tipo de evento usa apenas alguns
+		values := []struct {
+			name  string
+			value string
+			dest  *decimal.Decimal
+		}{
+			{"ratio_from", ey.RatioFrom, &event.RatioFrom},
+			{"ratio_to", ey.RatioTo, &event.RatioTo},
+			{"percent", ey.Percent, &event.Percent},
+			{"unit_cost", ey.UnitCost, &event.UnitCost},
+			{"auction_amount", ey.AuctionAmount, &event.AuctionAmount},
+			{"cash_per_share", ey.CashPerShare, &event.CashPerShare},
+			{"quantity", ey.Quantity, &event.Quantity},
+		}
+		for _, v := range values {
+			if v.value == "" {
+				continue
+			}
+			parsed, err := parseVaultDecimal(row, v.name, v.value)
+			if err != nil {
+				return nil, err
+			}
+			*v.dest = parsed
+		}
+
+		for j, sy := range ey.SpinOffs {
+			target := SpinOffTarget{Ticker: sy.Ticker}
+			spinRow := fmt.Sprintf("%s spin-off #%d", row, j+1)
+			if target.CostPercent, err = parseVaultDecimal(spinRow, "cost_percent", sy.CostPercent); err != nil {
+				return nil, err
+			}
+			if target.RatioFrom, err = parseVaultDecimal(spinRow, "ratio_from", sy.RatioFrom); err != nil {
+				return nil, err
+			}
+			if target.RatioTo, err = parseVaultDecimal(spinRow, "ratio_to", sy.RatioTo); err != nil {
+				return nil, err
+			}
+			event.SpinOffs = append(event.SpinOffs, target)
+		}
+
+		if err := event.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", row, err)
+		}
+		if event.ID == "" {
+			event.ID = CalculateEventID(&event)
+		}
+
+		w.CorporateEvents = append(w.CorporateEvents, event)
+	}
+
+	// Restore announced earnings
+	for i, ay := range vaultData.AnnouncedEarnings {
+		row := fmt.Sprintf("announced earning #%d (%s %s %s)", i+1, ay.PaymentDate, ay.Type, ay.Ticker)
+
+		announced := AnnouncedEarning{ID: ay.ID, Ticker: ay.Ticker, Type: ay.Type, Notes: ay.Notes}
+		var err error
+		if announced.RecordDate, err = parseVaultDate(row, "record_date", ay.RecordDate); err != nil {
+			return nil, err
+		}
+		if announced.PaymentDate, err = parseVaultDate(row, "payment_date", ay.PaymentDate); err != nil {
+			return nil, err
+		}
+		if announced.UnitPrice, err = parseVaultDecimal(row, "unit_price", ay.UnitPrice); err != nil {
+			return nil, err
+		}
+		if err := announced.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", row, err)
+		}
+		if announced.ID == "" {
+			announced.ID = CalculateAnnouncedEarningID(&announced)
+		}
+
+		w.AnnouncedEarnings = append(w.AnnouncedEarnings, announced)
+	}
+	w.journalHead = vaultData.Journal
+
 	// Recalculate derived fields
 	w.RecalculateAssets()
 
-	// Set encryption key and path
-	w.SetEncryptionKey(encryptionKey)
-	w.SetDirPath(dirPath)
-
 	return w, nil
 }
 
+// parseVaultDate parses a stored date, reporting the row and field on failure
+func parseVaultDate(row, field, value string) (time.Time, error) {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: invalid %s %q: %w", row, field, value, err)
+	}
+	return date, nil
+}
+
+// formatOptionalDecimal stores zero as an empty (omitted) field
+func formatOptionalDecimal(d decimal.Decimal) string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.String()
+}
+
+// parseVaultDecimal parses a stored decimal, reporting the row and field on failure
+func parseVaultDecimal(row, field, value string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%s: invalid %s %q: %w", row, field, value, err)
+	}
+	return d, nil
+}
+
 // Exists checks if an encrypted wallet exists at the given directory
 func Exists(dirPath string) bool {
 	return wcrypto.IsEncryptedWallet(dirPath)
@@ -306,15 +645,73 @@ func getSessionKeyPath(dirPath string) string {
 	return filepath.Join(dirPath, "session.key")
 }
 
+// DefaultSessionTTL is how long an unlocked cache stays valid when no TTL is configured
+const DefaultSessionTTL = 8 * time.Hour
+
+// ErrSessionExpired is returned by LoadUnlocked when the unlocked cache is older than its TTL
+// The stale cache and session key are wiped before the error is returned
+var ErrSessionExpired = errors.New("unlocked session expired")
+
+// Session describes an unlocked cache: when it was created and how long it stays valid
+type Session struct {
+	CreatedAt time.Time
+	TTL       time.Duration
+}
+
+// ExpiresAt returns when the session stops being valid
+func (s Session) ExpiresAt() time.Time {
+	return s.CreatedAt.Add(s.TTL)
+}
+
+// Expired reports whether the session is no longer valid at the given time
+// Sessions without creation timestamp or TTL (written by older versions) are always expired
+func (s Session) Expired(now time.Time) bool {
+	if s.CreatedAt.IsZero() || s.TTL <= 0 {
+		return true
+	}
+	return now.After(s.ExpiresAt())
+}
+
+// unlockedCache is the on-disk format of vault.unlocked
+type unlockedCache struct {
+	CreatedAt time.Time `yaml:"created_at"`
+	TTL       string    `yaml:"ttl"`
+	VaultData `yaml:",inline"`
+}
+
+// StartSession starts a new unlocked session with the given TTL
+// The next SaveUnlocked writes a cache valid for ttl from now
+// A non-positive ttl falls back to DefaultSessionTTL
+func (w *Wallet) StartSession(ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultSessionTTL
+	}
+	w.session = Session{CreatedAt: time.Now(), TTL: ttl}
+}
+
+// Session returns the unlocked session the wallet belongs to (zero if none)
+func (w *Wallet) Session() Session {
+	return w.session
+}
+
 // SaveUnlocked saves an unencrypted copy of the wallet for session persistence
 // This allows commands to access the wallet without requiring password entry each time
+// The cache keeps the session creation time and TTL, so updating it (e.g. on Save)
+// never extends the session. Starts a default session if none was started
 // WARNING: This file contains sensitive unencrypted data - should only exist during active session
 func (w *Wallet) SaveUnlocked(dirPath string) error {
-	// Prepare vault data
-	vaultData := w.prepareVaultData()
+	if w.session.CreatedAt.IsZero() {
+		w.StartSession(DefaultSessionTTL)
+	}
+
+	cache := unlockedCache{
+		CreatedAt: w.session.CreatedAt.UTC(),
+		TTL:       w.session.TTL.String(),
+		VaultData: w.prepareVaultData(),
+	}
 
 	// Serialize to YAML
-	yamlBytes, err := yaml.Marshal(vaultData)
+	yamlBytes, err := yaml.Marshal(cache)
 	if err != nil {
 		return fmt.Errorf("failed to serialize wallet: %w", err)
 	}
@@ -338,6 +735,7 @@ func (w *Wallet) SaveUnlocked(dirPath string) error {
 
 // LoadUnlocked loads the wallet from the unlocked cache file
 // Returns error if cache doesn't exist or is invalid
+// Returns ErrSessionExpired (after wiping the cache) if the session is older than its TTL
 func LoadUnlocked(dirPath string) (*Wallet, error) {
 	unlockedPath := getUnlockedPath(dirPath)
 
@@ -348,82 +746,36 @@ func LoadUnlocked(dirPath string) (*Wallet, error) {
 	}
 
 	// Deserialize YAML
-	var vaultData VaultData
-	if err := yaml.Unmarshal(yamlBytes, &vaultData); err != nil {
+	var cache unlockedCache
+	if err := yaml.Unmarshal(yamlBytes, &cache); err != nil {
 		return nil, fmt.Errorf("failed to parse unlocked wallet: %w", err)
 	}
 
-	// Convert transactions
-	transactions := make([]parser.Transaction, 0, len(vaultData.Transactions))
-	for _, ty := range vaultData.Transactions {
-		date, _ := time.Parse("2006-01-02", ty.Date)
-		quantity, _ := decimal.NewFromString(ty.Quantity)
-		price, _ := decimal.NewFromString(ty.Price)
-		amount, _ := decimal.NewFromString(ty.Amount)
-
-		transactions = append(transactions, parser.Transaction{
-			Date:        date,
-			Type:        ty.Type,
-			Institution: ty.Institution,
-			Ticker:      ty.Ticker,
-			Quantity:    quantity,
-			Price:       price,
-			Amount:      amount,
-			Hash:        ty.Hash,
-		})
-	}
-
-	// Create wallet from transactions
-	w := NewWallet(transactions)
-
-	// Restore asset metadata and earnings
-	for _, ay := range vaultData.Assets {
-		asset, exists := w.Assets[ay.Ticker]
-		if !exists {
-			// Create asset if it doesn't exist (e.g., has only earnings)
-			asset = &Asset{
-				ID:           ay.Ticker,
-				Negotiations: make([]parser.Transaction, 0),
-				Earnings:     make([]parser.Earning, 0),
-				Type:         ay.Type,
-				SubType:      ay.SubType,
-				Segment:      ay.Segment,
-			}
-			w.Assets[ay.Ticker] = asset
+	// Refuse and wipe stale sessions
+	session := parseSession(cache.CreatedAt, cache.TTL)
+	if session.Expired(time.Now()) {
+		if err := ClearUnlocked(dirPath); err != nil {
+			return nil, fmt.Errorf("%w (failed to wipe stale cache: %v)", ErrSessionExpired, err)
 		}
+		return nil, ErrSessionExpired
+	}
 
-		// Restore metadata
-		asset.SubType = ay.SubType
-		asset.Segment = ay.Segment
-		asset.IsSubscription = ay.IsSubscription
-		asset.SubscriptionOf = ay.SubscriptionOf
-
-		// Restore earnings
-		for _, ey := range ay.Earnings {
-			date, _ := time.Parse("2006-01-02", ey.Date)
-			quantity, _ := decimal.NewFromString(ey.Quantity)
-			unitPrice, _ := decimal.NewFromString(ey.UnitPrice)
-			totalAmount, _ := decimal.NewFromString(ey.TotalAmount)
-
-			earning := parser.Earning{
-				Date:        date,
-				Type:        ey.Type,
-				Ticker:      ey.Ticker,
-				Quantity:    quantity,
-				UnitPrice:   unitPrice,
-				TotalAmount: totalAmount,
-				Hash:        ey.Hash,
-			}
-
-			asset.Earnings = append(asset.Earnings, earning)
+	// The cache is written by the running build; an older layout means it is stale
+	if cache.SchemaVersion != CurrentSchemaVersion {
+		if err := ClearUnlocked(dirPath); err != nil {
+			return nil, fmt.Errorf("failed to wipe outdated unlocked cache: %w", err)
 		}
+		return nil, fmt.Errorf("unlocked cache uses schema version %d (expected %d)", cache.SchemaVersion, CurrentSchemaVersion)
 	}
 
-	// Recalculate derived fields
-	w.RecalculateAssets()
+	w, err := fromVaultData(&cache.VaultData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load unlocked wallet: %w", err)
+	}
 
-	// Set dir path
+	// Set dir path and keep the session timestamps
 	w.SetDirPath(dirPath)
+	w.session = session
 
 	// Try to load session encryption key if it exists
 	sessionKeyPath := getSessionKeyPath(dirPath)
@@ -436,6 +788,33 @@ func LoadUnlocked(dirPath string) (*Wallet, error) {
 	return w, nil
 }
 
+// UnlockedSession reads the session timestamps of the unlocked cache without loading the wallet
+func UnlockedSession(dirPath string) (Session, error) {
+	yamlBytes, err := os.ReadFile(getUnlockedPath(dirPath))
+	if err != nil {
+		return Session{}, fmt.Errorf("failed to read unlocked wallet: %w", err)
+	}
+
+	var header struct {
+		CreatedAt time.Time `yaml:"created_at"`
+		TTL       string    `yaml:"ttl"`
+	}
+	if err := yaml.Unmarshal(yamlBytes, &header); err != nil {
+		return Session{}, fmt.Errorf("failed to parse unlocked wallet: %w", err)
+	}
+
+	return parseSession(header.CreatedAt, header.TTL), nil
+}
+
+// parseSession builds a Session from the cache fields; an invalid TTL yields an expired session
+func parseSession(createdAt time.Time, ttl string) Session {
+	session := Session{CreatedAt: createdAt}
+	if d, err := time.ParseDuration(ttl); err == nil {
+		session.TTL = d
+	}
+	return session
+}
+
 // IsUnlocked checks if an unlocked cache file exists
 func IsUnlocked(dirPath string) bool {
 	unlockedPath := getUnlockedPath(dirPath)
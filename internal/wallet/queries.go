@@ -16,11 +16,13 @@ func (w *Wallet) GetActiveAssets() map[string]*Asset {
 
 // GetSoldAssets returns all assets that have been completely sold (quantity == 0).
 // These assets have transaction history but are no longer held.
+// Assets converted into another ticker (incorporation, ticker change) were not sold
+// and are left out.
 func (w *Wallet) GetSoldAssets() map[string]*Asset {
 	soldAssets := make(map[string]*Asset)
 
 	for ticker, asset := range w.Assets {
-		if asset.Quantity == 0 {
+		if asset.Quantity == 0 && asset.SucceededBy == "" {
 			soldAssets[ticker] = asset
 		}
 	}
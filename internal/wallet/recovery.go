@@ -0,0 +1,55 @@
+package wallet
+
+import (
+	"fmt"
+
+	wcrypto "github.com/john/b3-project/internal/wallet/crypto"
+)
+
+// EnableRecovery generates a recovery code that can reset the master password
+// The code wraps the same encryption key as the password and is returned only once
+// The wallet must be unlocked and have a directory path set
+func (w *Wallet) EnableRecovery() (string, error) {
+	if w.IsLocked() {
+		return "", fmt.Errorf("wallet is locked - cannot generate recovery code without encryption key")
+	}
+
+	if w.dirPath == "" {
+		return "", fmt.Errorf("wallet directory is not set")
+	}
+
+	code, err := wcrypto.InitializeRecovery(w.dirPath, w.encryptionKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate recovery code: %w", err)
+	}
+
+	return code, nil
+}
+
+// HasRecovery checks if a recovery code was generated for the wallet at dirPath
+func HasRecovery(dirPath string) bool {
+	return wcrypto.HasRecoveryKey(dirPath)
+}
+
+// Recover unlocks a wallet with its recovery code and sets a new master password
+// Returns the unlocked wallet ready to use
+func Recover(dirPath, recoveryCode, newPassword string) (*Wallet, error) {
+	encryptionKey, err := wcrypto.RecoverEncryptionKey(dirPath, recoveryCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover wallet: %w", err)
+	}
+
+	// Load before changing the password so a broken vault is detected
+	// while the old password is still valid
+	w, err := LoadWithKey(dirPath, encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wcrypto.ChangePassword(dirPath, w.encryptionKey, newPassword); err != nil {
+		w.Lock()
+		return nil, fmt.Errorf("failed to set new password: %w", err)
+	}
+
+	return w, nil
+}
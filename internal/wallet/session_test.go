@@ -0,0 +1,103 @@
+package wallet
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+)
+
+func TestLoadUnlocked_ValidSession(t *testing.T) {
+	dir := t.TempDir()
+
+	w := NewWallet([]parser.Transaction{})
+	w.SetEncryptionKey(make([]byte, 32))
+	w.StartSession(time.Hour)
+	if err := w.SaveUnlocked(dir); err != nil {
+		t.Fatalf("SaveUnlocked returned error: %v", err)
+	}
+
+	loaded, err := LoadUnlocked(dir)
+	if err != nil {
+		t.Fatalf("LoadUnlocked returned error: %v", err)
+	}
+	if loaded.Session().TTL != time.Hour {
+		t.Errorf("session TTL = %s, expected 1h", loaded.Session().TTL)
+	}
+	if !loaded.Session().CreatedAt.Equal(w.Session().CreatedAt) {
+		t.Errorf("session CreatedAt = %s, expected %s", loaded.Session().CreatedAt, w.Session().CreatedAt)
+	}
+	if loaded.IsLocked() {
+		t.Error("wallet loaded from a valid session should be unlocked")
+	}
+}
+
+func TestLoadUnlocked_ExpiredSessionIsWiped(t *testing.T) {
+	dir := t.TempDir()
+
+	w := NewWallet([]parser.Transaction{})
+	w.SetEncryptionKey(make([]byte, 32))
+	w.session = Session{CreatedAt: time.Now().Add(-2 * time.Hour), TTL: time.Hour}
+	if err := w.SaveUnlocked(dir); err != nil {
+		t.Fatalf("SaveUnlocked returned error: %v", err)
+	}
+
+	if _, err := LoadUnlocked(dir); !errors.Is(err, ErrSessionExpired) {
+		t.Fatalf("LoadUnlocked returned %v, expected ErrSessionExpired", err)
+	}
+	if IsUnlocked(dir) {
+		t.Error("stale unlocked cache was not removed")
+	}
+	if _, err := os.Stat(getSessionKeyPath(dir)); !os.IsNotExist(err) {
+		t.Error("stale session key was not removed")
+	}
+}
+
+func TestLoadUnlocked_LegacyCacheWithoutTimestamp(t *testing.T) {
+	dir := t.TempDir()
+
+	legacy := []byte("transactions: []\nassets: []\n")
+	if err := os.WriteFile(getUnlockedPath(dir), legacy, 0600); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+
+	if _, err := LoadUnlocked(dir); !errors.Is(err, ErrSessionExpired) {
+		t.Fatalf("LoadUnlocked returned %v, expected ErrSessionExpired", err)
+	}
+	if IsUnlocked(dir) {
+		t.Error("legacy unlocked cache was not removed")
+	}
+}
+
+func TestSave_DoesNotExtendSession(t *testing.T) {
+	dir := t.TempDir()
+
+	w, err := Create(dir, "test-password")
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	createdAt := time.Now().Add(-30 * time.Minute)
+	w.session = Session{CreatedAt: createdAt, TTL: time.Hour}
+	if err := w.SaveUnlocked(dir); err != nil {
+		t.Fatalf("SaveUnlocked returned error: %v", err)
+	}
+
+	// Saving the vault refreshes the cache content but keeps the session timestamps
+	if err := w.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	session, err := UnlockedSession(dir)
+	if err != nil {
+		t.Fatalf("UnlockedSession returned error: %v", err)
+	}
+	if !session.CreatedAt.Equal(createdAt) {
+		t.Errorf("session CreatedAt = %s, expected %s", session.CreatedAt, createdAt)
+	}
+	if session.TTL != time.Hour {
+		t.Errorf("session TTL = %s, expected 1h", session.TTL)
+	}
+}
@@ -0,0 +1,135 @@
+package wallet
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/shopspring/decimal"
+)
+
+// SubscriptionStatus resume o ciclo de vida de um direito de subscrição:
+// direitos recebidos, comprados, exercidos, vendidos e expirados
+type SubscriptionStatus struct {
+	RightTicker  string
+	ParentTicker string
+
+	Received decimal.Decimal // Direitos recebidos da empresa
+	Bought   decimal.Decimal // Direitos comprados em bolsa
+
+	Exercised     decimal.Decimal // Quantidade exercida (somando todos os exercícios)
+	ExercisedPaid decimal.Decimal // Valor pago no exercício (quantidade × preço)
+	Exercises     []CorporateEvent
+
+	Sold          decimal.Decimal // Direitos vendidos em bolsa
+	SaleProceeds  decimal.Decimal // Valor recebido nas vendas
+	SaleResult    decimal.Decimal // Resultado realizado nas vendas (tributável)
+	Expired       decimal.Decimal // Direitos que venceram sem exercício
+	ExpiredLoss   decimal.Decimal // Custo dos direitos expirados (prejuízo)
+	Remaining     decimal.Decimal // Direitos ainda em carteira
+	AverageCost   decimal.Decimal // Custo médio atual dos direitos em carteira
+	RealizedTotal decimal.Decimal // SaleResult − ExpiredLoss
+}
+
+// SubscriptionRights retorna os tickers de direitos com eventos de subscrição registrados
+func (w *Wallet) SubscriptionRights() []string {
+	seen := make(map[string]bool)
+	var tickers []string
+	for _, e := range w.CorporateEvents {
+		if isSubscriptionEvent(e.Type) && !seen[e.Ticker] {
+			seen[e.Ticker] = true
+			tickers = append(tickers, e.Ticker)
+		}
+	}
+	sort.Strings(tickers)
+	return tickers
+}
+
+// SubscriptionStatus calcula a situação de um direito de subscrição
+// O resultado das vendas usa o custo médio móvel dos direitos (recebidos a custo
+// zero ou comprados em bolsa); exercícios baixam os direitos pelo custo, sem resultado
+func (w *Wallet) SubscriptionStatus(rightTicker string) (*SubscriptionStatus, error) {
+	asset, exists := w.Assets[rightTicker]
+	if !exists {
+		return nil, fmt.Errorf("direito de subscrição %s não encontrado", rightTicker)
+	}
+
+	status := &SubscriptionStatus{RightTicker: rightTicker, ParentTicker: asset.SubscriptionOf}
+
+	// Hashes das negociações sintéticas de cada evento
+	received := make(map[string]bool)
+	exercised := make(map[string]bool)
+	expired := make(map[string]bool)
+	for _, e := range w.SortedCorporateEvents(rightTicker) {
+		if e.Ticker != rightTicker {
+			continue
+		}
+		switch e.Type {
+		case EventRightsReceived:
+			received[eventTransaction(e, rightTicker, "Compra", e.Quantity, decimal.Zero).Hash] = true
+			status.ParentTicker = e.TargetTicker
+		case EventRightsExercised:
+			exercised[eventTransaction(e, rightTicker, "Venda", e.Quantity, decimal.Zero).Hash] = true
+			status.ParentTicker = e.TargetTicker
+			status.Exercised = status.Exercised.Add(e.Quantity)
+			status.ExercisedPaid = status.ExercisedPaid.Add(e.Quantity.Mul(e.UnitCost).Round(2))
+			status.Exercises = append(status.Exercises, e)
+		case EventRightsExpired:
+			expired[eventTransaction(e, rightTicker, "Venda", decimal.NewFromInt(1), decimal.Zero).Hash] = true
+		}
+	}
+
+	negotiations := append([]parser.Transaction(nil), asset.EffectiveNegotiations()...)
+	sort.SliceStable(negotiations, func(i, j int) bool {
+		return negotiations[i].Date.Before(negotiations[j].Date)
+	})
+
+	held, cost := decimal.Zero, decimal.Zero
+	for _, tx := range negotiations {
+		switch tx.Type {
+		case "Compra":
+			if received[tx.Hash] {
+				status.Received = status.Received.Add(tx.Quantity)
+			} else {
+				status.Bought = status.Bought.Add(tx.Quantity)
+			}
+			held = held.Add(tx.Quantity)
+			cost = cost.Add(tx.Amount)
+		case "Venda":
+			if !held.IsPositive() {
+				continue
+			}
+			quantity := decimal.Min(tx.Quantity, held)
+			soldCost := cost.Mul(quantity).Div(held).Round(2)
+			switch {
+			case exercised[tx.Hash]:
+				// Custo transferido para o ativo pai
+			case expired[tx.Hash]:
+				status.Expired = status.Expired.Add(quantity)
+				status.ExpiredLoss = status.ExpiredLoss.Add(soldCost)
+			default:
+				status.Sold = status.Sold.Add(quantity)
+				status.SaleProceeds = status.SaleProceeds.Add(tx.Amount)
+				status.SaleResult = status.SaleResult.Add(tx.Amount.Sub(soldCost))
+			}
+			held = held.Sub(quantity)
+			cost = cost.Sub(soldCost)
+		}
+	}
+
+	status.Remaining = held
+	if held.IsPositive() {
+		status.AverageCost = cost.Div(held).Round(4)
+	}
+	status.RealizedTotal = status.SaleResult.Sub(status.ExpiredLoss)
+
+	return status, nil
+}
+
+func isSubscriptionEvent(eventType string) bool {
+	switch eventType {
+	case EventRightsReceived, EventRightsExercised, EventRightsExpired:
+		return true
+	}
+	return false
+}
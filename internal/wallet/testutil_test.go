@@ -0,0 +1,36 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/shopspring/decimal"
+)
+
+// newTestWallet creates an encrypted wallet in a temporary directory
+func newTestWallet(t *testing.T) (*Wallet, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	w, err := Create(dir, "test-password")
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	return w, dir
+}
+
+// testBuy builds an imported purchase at R$ 10 per share
+func testBuy(ticker string, date time.Time, quantity int64) parser.Transaction {
+	tx := parser.Transaction{
+		Date:        date,
+		Type:        "Compra",
+		Institution: "XP",
+		Ticker:      ticker,
+		Quantity:    decimal.NewFromInt(quantity),
+		Price:       decimal.NewFromInt(10),
+		Amount:      decimal.NewFromInt(quantity * 10),
+	}
+	tx.Hash = parser.CalculateHash(&tx)
+	return tx
+}
@@ -2,6 +2,8 @@ package wallet
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/john/b3-project/internal/parser"
 )
@@ -26,6 +28,8 @@ func (w *Wallet) AddTransaction(tx parser.Transaction) error {
 		return fmt.Errorf("duplicate transaction detected")
 	}
 
+	before := w.mutationHash()
+
 	// Add to wallet transactions
 	w.Transactions = append(w.Transactions, tx)
 	w.TransactionsByHash[tx.Hash] = tx
@@ -49,6 +53,8 @@ func (w *Wallet) AddTransaction(tx parser.Transaction) error {
 	// Recalculate all asset values
 	w.RecalculateAssets()
 
+	w.RecordMutation("AddTransaction", transactionInputs(tx), before)
+
 	return nil
 }
 
@@ -57,6 +63,9 @@ func (w *Wallet) AddTransaction(tx parser.Transaction) error {
 // and any error that occurred during validation.
 // If a transaction is invalid, the entire operation is aborted and an error is returned.
 func (w *Wallet) AddTransactions(transactions []parser.Transaction) (added int, duplicates int, err error) {
+	before := w.mutationHash()
+	tickers := make(map[string]bool)
+
 	for _, tx := range transactions {
 		// Calculate hash if not already set
 		if tx.Hash == "" {
@@ -93,13 +102,43 @@ func (w *Wallet) AddTransactions(transactions []parser.Transaction) (added int,
 
 		// Add transaction to asset
 		asset.Negotiations = append(asset.Negotiations, tx)
+		tickers[tx.Ticker] = true
 		added++
 	}
 
 	// Recalculate all asset values after adding all transactions
 	if added > 0 {
 		w.RecalculateAssets()
+		w.RecordMutation("AddTransactions", map[string]string{
+			"added":      fmt.Sprint(added),
+			"duplicates": fmt.Sprint(duplicates),
+			"tickers":    joinTickers(tickers),
+		}, before)
 	}
 
 	return added, duplicates, nil
 }
+
+// transactionInputs describes a transaction for the journal
+func transactionInputs(tx parser.Transaction) map[string]string {
+	return map[string]string{
+		"date":        tx.Date.Format("2006-01-02"),
+		"type":        tx.Type,
+		"institution": tx.Institution,
+		"ticker":      tx.Ticker,
+		"quantity":    tx.Quantity.String(),
+		"price":       tx.Price.String(),
+		"amount":      tx.Amount.String(),
+		"hash":        tx.Hash,
+	}
+}
+
+// joinTickers returns the tickers of a set sorted and comma separated
+func joinTickers(tickers map[string]bool) string {
+	list := make([]string, 0, len(tickers))
+	for ticker := range tickers {
+		list = append(list, ticker)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
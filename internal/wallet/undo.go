@@ -0,0 +1,425 @@
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	wcrypto "github.com/john/b3-project/internal/wallet/crypto"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultUndoDepth is how many snapshots are kept when no depth is configured
+const DefaultUndoDepth = 10
+
+// Snapshot directories (inside the wallet directory)
+const (
+	undoDirName = "undo"
+	redoDirName = "redo"
+)
+
+// Snapshot is a copy of the encrypted vault taken before a save that changed the wallet
+// Restoring it reverts the operations listed in Operations
+type Snapshot struct {
+	ID         string         `json:"id"`
+	CreatedAt  time.Time      `json:"created_at"`
+	Operations []JournalEntry `json:"operations"`
+}
+
+// Summary returns a one-line description of the operations in the snapshot
+func (s Snapshot) Summary() string {
+	names := make([]string, 0, len(s.Operations))
+	for _, op := range s.Operations {
+		names = append(names, op.Operation)
+	}
+	return strings.Join(names, ", ")
+}
+
+// AssetChange describes how an asset differs between two wallet states
+type AssetChange struct {
+	Ticker          string
+	QuantityBefore  int
+	QuantityAfter   int
+	AveragePriceOld decimal.Decimal
+	AveragePriceNew decimal.Decimal
+	Added           bool
+	Removed         bool
+}
+
+// SetUndoDepth sets how many undo snapshots are kept (0 disables snapshots)
+func (w *Wallet) SetUndoDepth(depth int) {
+	if depth < 0 {
+		depth = 0
+	}
+	w.undoDepth = &depth
+}
+
+// getUndoDepth returns the configured undo depth
+func (w *Wallet) getUndoDepth() int {
+	if w.undoDepth == nil {
+		return DefaultUndoDepth
+	}
+	return *w.undoDepth
+}
+
+// takeSnapshot stores the vault content from before this save on the undo stack
+// A new change invalidates everything that could be redone
+func (w *Wallet) takeSnapshot(dirPath string, previousVault []byte) error {
+	if len(w.pendingJournal) == 0 {
+		return nil
+	}
+
+	if err := os.RemoveAll(filepath.Join(dirPath, redoDirName)); err != nil {
+		return fmt.Errorf("failed to clear redo history: %w", err)
+	}
+
+	depth := w.getUndoDepth()
+	if depth == 0 || previousVault == nil {
+		return nil
+	}
+
+	snapshot := Snapshot{
+		ID:         newSnapshotID(),
+		CreatedAt:  time.Now().UTC(),
+		Operations: w.pendingJournal,
+	}
+
+	if err := writeSnapshot(dirPath, undoDirName, snapshot, previousVault, w.encryptionKey); err != nil {
+		return err
+	}
+
+	return trimSnapshots(dirPath, undoDirName, depth)
+}
+
+// UndoHistory returns the snapshots that can be undone (newest first)
+func (w *Wallet) UndoHistory() ([]Snapshot, error) {
+	return w.listSnapshots(undoDirName)
+}
+
+// RedoHistory returns the snapshots that can be redone (newest first)
+func (w *Wallet) RedoHistory() ([]Snapshot, error) {
+	return w.listSnapshots(redoDirName)
+}
+
+// PreviewUndo returns the next snapshot to undo and how assets would change
+func (w *Wallet) PreviewUndo() (*Snapshot, []AssetChange, error) {
+	return w.preview(undoDirName)
+}
+
+// PreviewRedo returns the next snapshot to redo and how assets would change
+func (w *Wallet) PreviewRedo() (*Snapshot, []AssetChange, error) {
+	return w.preview(redoDirName)
+}
+
+// Undo restores the vault from before the most recent change
+// The current state is kept on the redo stack. Returns the restored wallet
+func (w *Wallet) Undo() (*Wallet, *Snapshot, error) {
+	return w.restore(undoDirName, redoDirName, "Undo")
+}
+
+// Redo re-applies the most recently undone change
+// Returns the restored wallet
+func (w *Wallet) Redo() (*Wallet, *Snapshot, error) {
+	return w.restore(redoDirName, undoDirName, "Redo")
+}
+
+// preview loads the newest snapshot of a stack and diffs it against the current state
+func (w *Wallet) preview(stack string) (*Snapshot, []AssetChange, error) {
+	if w.IsLocked() {
+		return nil, nil, fmt.Errorf("wallet is locked")
+	}
+
+	snapshot, vault, err := w.peekSnapshot(stack)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	restored, err := walletFromEncryptedVault(vault, w.encryptionKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return snapshot, DiffAssets(w, restored), nil
+}
+
+// restore swaps the current vault with the newest snapshot of one stack,
+// pushing the current vault onto the other stack
+func (w *Wallet) restore(from, to, operation string) (*Wallet, *Snapshot, error) {
+	if w.IsLocked() {
+		return nil, nil, fmt.Errorf("wallet is locked")
+	}
+	dirPath := w.dirPath
+
+	snapshot, vault, err := w.peekSnapshot(from)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Make sure the snapshot is readable before touching the vault
+	if _, err := walletFromEncryptedVault(vault, w.encryptionKey); err != nil {
+		return nil, nil, fmt.Errorf("snapshot %s is unreadable: %w", snapshot.ID, err)
+	}
+
+	currentVault, err := os.ReadFile(filepath.Join(dirPath, wcrypto.VaultFileName))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read vault: %w", err)
+	}
+
+	// Keep the current state so the operation can be reversed
+	counterpart := Snapshot{ID: newSnapshotID(), CreatedAt: time.Now().UTC(), Operations: snapshot.Operations}
+	if err := writeSnapshot(dirPath, to, counterpart, currentVault, w.encryptionKey); err != nil {
+		return nil, nil, err
+	}
+
+	if err := writeFileAtomic(filepath.Join(dirPath, wcrypto.VaultFileName), vault); err != nil {
+		return nil, nil, fmt.Errorf("failed to restore vault: %w", err)
+	}
+
+	if err := removeSnapshot(dirPath, from, snapshot.ID); err != nil {
+		return nil, nil, err
+	}
+
+	// Reload the restored vault (running migrations if the snapshot is older)
+	restored, err := LoadWithKey(dirPath, append([]byte(nil), w.encryptionKey...))
+	if err != nil {
+		return nil, nil, err
+	}
+	restored.session = w.session
+	restored.undoDepth = w.undoDepth
+
+	// Record the operation in the journal
+	inputs := map[string]string{
+		"snapshot":   snapshot.ID,
+		"operations": snapshot.Summary(),
+	}
+	// The snapshot holds an older journal head: continue from the current one
+	restored.journalHead = w.journalHead
+	restored.RecordMutation(operation, inputs, w.StateHash())
+
+	// Save the new head with the restored data (also refreshes the unlocked cache)
+	if err := restored.save(dirPath, false); err != nil {
+		return nil, nil, err
+	}
+
+	return restored, snapshot, nil
+}
+
+// DiffAssets compares assets of two wallet states
+// Only assets whose quantity or average price differ are returned, sorted by ticker
+func DiffAssets(from, to *Wallet) []AssetChange {
+	tickers := make(map[string]bool)
+	for ticker := range from.Assets {
+		tickers[ticker] = true
+	}
+	for ticker := range to.Assets {
+		tickers[ticker] = true
+	}
+
+	changes := make([]AssetChange, 0)
+	for ticker := range tickers {
+		before, hadBefore := from.Assets[ticker]
+		after, hasAfter := to.Assets[ticker]
+
+		change := AssetChange{Ticker: ticker, Added: !hadBefore, Removed: !hasAfter}
+		if hadBefore {
+			change.QuantityBefore = before.Quantity
+			change.AveragePriceOld = before.AveragePrice
+		}
+		if hasAfter {
+			change.QuantityAfter = after.Quantity
+			change.AveragePriceNew = after.AveragePrice
+		}
+
+		if !change.Added && !change.Removed &&
+			change.QuantityBefore == change.QuantityAfter &&
+			change.AveragePriceOld.Equal(change.AveragePriceNew) {
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Ticker < changes[j].Ticker
+	})
+
+	return changes
+}
+
+// walletFromEncryptedVault decrypts a vault copy into a wallet without touching disk
+func walletFromEncryptedVault(encryptedData, encryptionKey []byte) (*Wallet, error) {
+	data, err := wcrypto.DecryptVault(encryptedData, encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	vaultData, err := migrateInMemory(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromVaultData(vaultData)
+}
+
+// listSnapshots reads the metadata of every snapshot in a stack (newest first)
+func (w *Wallet) listSnapshots(stack string) ([]Snapshot, error) {
+	if w.IsLocked() {
+		return nil, fmt.Errorf("wallet is locked")
+	}
+
+	ids, err := snapshotIDs(w.dirPath, stack)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]Snapshot, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		snapshot, err := readSnapshotMeta(w.dirPath, stack, ids[i], w.encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, *snapshot)
+	}
+
+	return snapshots, nil
+}
+
+// peekSnapshot returns the newest snapshot of a stack with its vault content
+func (w *Wallet) peekSnapshot(stack string) (*Snapshot, []byte, error) {
+	ids, err := snapshotIDs(w.dirPath, stack)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil, fmt.Errorf("nothing to %s", stack)
+	}
+	id := ids[len(ids)-1]
+
+	snapshot, err := readSnapshotMeta(w.dirPath, stack, id, w.encryptionKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vault, err := os.ReadFile(snapshotPath(w.dirPath, stack, id, ".vault"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read snapshot %s: %w", id, err)
+	}
+
+	return snapshot, vault, nil
+}
+
+// writeSnapshot stores the vault copy and its encrypted metadata
+func writeSnapshot(dirPath, stack string, snapshot Snapshot, vault, encryptionKey []byte) error {
+	if err := os.MkdirAll(filepath.Join(dirPath, stack), 0700); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", stack, err)
+	}
+
+	meta, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to serialize snapshot: %w", err)
+	}
+	encryptedMeta, err := wcrypto.Encrypt(meta, encryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt snapshot: %w", err)
+	}
+
+	// Vault first: a snapshot is only listed once its metadata exists
+	if err := os.WriteFile(snapshotPath(dirPath, stack, snapshot.ID, ".vault"), vault, 0600); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.WriteFile(snapshotPath(dirPath, stack, snapshot.ID, ".meta"), encryptedMeta, 0600); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// readSnapshotMeta decrypts the metadata of a snapshot
+func readSnapshotMeta(dirPath, stack, id string, encryptionKey []byte) (*Snapshot, error) {
+	encryptedMeta, err := os.ReadFile(snapshotPath(dirPath, stack, id, ".meta"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", id, err)
+	}
+
+	meta, err := wcrypto.Decrypt(encryptedMeta, encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt snapshot %s: %w", id, err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(meta, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", id, err)
+	}
+
+	return &snapshot, nil
+}
+
+// snapshotIDs lists the snapshot IDs of a stack, oldest first
+func snapshotIDs(dirPath, stack string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(dirPath, stack))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s snapshots: %w", stack, err)
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if id, ok := strings.CutSuffix(entry.Name(), ".meta"); ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// trimSnapshots removes the oldest snapshots beyond depth
+func trimSnapshots(dirPath, stack string, depth int) error {
+	ids, err := snapshotIDs(dirPath, stack)
+	if err != nil {
+		return err
+	}
+
+	for len(ids) > depth {
+		if err := removeSnapshot(dirPath, stack, ids[0]); err != nil {
+			return err
+		}
+		ids = ids[1:]
+	}
+
+	return nil
+}
+
+// removeSnapshot deletes the files of a snapshot
+func removeSnapshot(dirPath, stack, id string) error {
+	for _, ext := range []string{".meta", ".vault"} {
+		if err := os.Remove(snapshotPath(dirPath, stack, id, ext)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove snapshot %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// snapshotPath returns the path of a snapshot file
+func snapshotPath(dirPath, stack, id, ext string) string {
+	return filepath.Join(dirPath, stack, id+ext)
+}
+
+// newSnapshotID returns a sortable unique snapshot identifier
+func newSnapshotID() string {
+	return fmt.Sprintf("%020d", time.Now().UnixNano())
+}
+
+// writeFileAtomic replaces a file by writing a temporary copy and renaming it
+func writeFileAtomic(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,140 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUndoRedo(t *testing.T) {
+	w, dir := newTestWallet(t)
+
+	if err := w.AddTransaction(testBuy("PETR4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+	if err := w.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if err := w.AddTransaction(testBuy("PETR4", time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+	if err := w.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	afterSecond := w.StateHash()
+
+	snapshot, changes, err := w.PreviewUndo()
+	if err != nil {
+		t.Fatalf("PreviewUndo returned error: %v", err)
+	}
+	if snapshot.Summary() != "AddTransaction" {
+		t.Errorf("snapshot summary = %q, expected AddTransaction", snapshot.Summary())
+	}
+	if len(changes) != 1 || changes[0].QuantityBefore != 200 || changes[0].QuantityAfter != 100 {
+		t.Errorf("unexpected preview changes: %+v", changes)
+	}
+
+	undone, _, err := w.Undo()
+	if err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if undone.Assets["PETR4"].Quantity != 100 {
+		t.Errorf("quantity after undo = %d, expected 100", undone.Assets["PETR4"].Quantity)
+	}
+
+	// The restored state is what is on disk
+	reloaded, err := LoadWithKey(dir, append([]byte(nil), w.GetEncryptionKey()...))
+	if err != nil {
+		t.Fatalf("LoadWithKey returned error: %v", err)
+	}
+	if reloaded.Assets["PETR4"].Quantity != 100 {
+		t.Errorf("quantity on disk after undo = %d, expected 100", reloaded.Assets["PETR4"].Quantity)
+	}
+
+	redone, _, err := undone.Redo()
+	if err != nil {
+		t.Fatalf("Redo returned error: %v", err)
+	}
+	if redone.StateHash() != afterSecond {
+		t.Error("redo did not restore the state from before undo")
+	}
+
+	// Undo and redo are recorded in the journal
+	entries, issues, err := redone.History()
+	if err != nil {
+		t.Fatalf("History returned error: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("unexpected journal issues: %+v", issues)
+	}
+	last := entries[len(entries)-1]
+	if last.Operation != "Redo" || last.AfterHash != afterSecond {
+		t.Errorf("last journal entry = %s (after %s)", last.Operation, last.AfterHash)
+	}
+}
+
+func TestUndo_NewChangeClearsRedo(t *testing.T) {
+	w, dir := newTestWallet(t)
+
+	if err := w.AddTransaction(testBuy("PETR4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+	if err := w.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	undone, _, err := w.Undo()
+	if err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if redo, _ := undone.RedoHistory(); len(redo) != 1 {
+		t.Fatalf("redo history has %d entries, expected 1", len(redo))
+	}
+
+	if err := undone.AddTransaction(testBuy("VALE3", time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+	if err := undone.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	if redo, _ := undone.RedoHistory(); len(redo) != 0 {
+		t.Errorf("redo history has %d entries after a new change, expected 0", len(redo))
+	}
+	if _, _, err := undone.Redo(); err == nil {
+		t.Error("expected error when there is nothing to redo")
+	}
+}
+
+func TestUndo_DepthLimit(t *testing.T) {
+	w, dir := newTestWallet(t)
+	w.SetUndoDepth(2)
+
+	for day := 1; day <= 4; day++ {
+		if err := w.AddTransaction(testBuy("PETR4", time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+			t.Fatalf("AddTransaction returned error: %v", err)
+		}
+		if err := w.Save(dir); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+	}
+
+	history, err := w.UndoHistory()
+	if err != nil {
+		t.Fatalf("UndoHistory returned error: %v", err)
+	}
+	if len(history) != 2 {
+		t.Errorf("undo history has %d entries, expected 2", len(history))
+	}
+
+	// Depth 0 disables snapshots
+	w.SetUndoDepth(0)
+	if err := w.AddTransaction(testBuy("PETR4", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+	if err := w.Save(dir); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if history, _ := w.UndoHistory(); len(history) != 2 {
+		t.Errorf("undo history has %d entries with depth 0, expected unchanged 2", len(history))
+	}
+}
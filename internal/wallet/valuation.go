@@ -0,0 +1,194 @@
+package wallet
+
+import (
+	"sort"
+	"time"
+
+	"github.com/john/b3-project/internal/parser"
+	"github.com/shopspring/decimal"
+)
+
+// AssetValuation é a posição atual de um ativo avaliada a um preço de mercado
+type AssetValuation struct {
+	Quantity        int
+	AveragePrice    decimal.Decimal
+	Price           decimal.Decimal
+	CostBasis       decimal.Decimal // Quantidade × preço médio
+	MarketValue     decimal.Decimal // Quantidade × preço
+	UnrealizedGain  decimal.Decimal // Valor de mercado - custo
+	UnrealizedRatio decimal.Decimal // Ganho não realizado / custo (0 se custo zero)
+}
+
+// CostBasis retorna o custo da posição atual (quantidade × preço médio)
+func (a *Asset) CostBasis() decimal.Decimal {
+	return a.AveragePrice.Mul(decimal.NewFromInt(int64(a.Quantity))).Round(2)
+}
+
+// Valuate avalia a posição atual do ativo ao preço informado
+func (a *Asset) Valuate(price decimal.Decimal) AssetValuation {
+	v := AssetValuation{
+		Quantity:     a.Quantity,
+		AveragePrice: a.AveragePrice,
+		Price:        price,
+		CostBasis:    a.CostBasis(),
+		MarketValue:  price.Mul(decimal.NewFromInt(int64(a.Quantity))).Round(2),
+	}
+	v.UnrealizedGain = v.MarketValue.Sub(v.CostBasis)
+	if v.CostBasis.IsPositive() {
+		v.UnrealizedRatio = v.UnrealizedGain.Div(v.CostBasis)
+	}
+	return v
+}
+
+// ValuateActiveAssets avalia os ativos em carteira com os preços informados
+// Ativos sem preço ficam de fora do resultado e são listados em missing
+func (w *Wallet) ValuateActiveAssets(prices map[string]decimal.Decimal) (valuations map[string]AssetValuation, missing []string) {
+	valuations = make(map[string]AssetValuation)
+	for ticker, asset := range w.GetActiveAssets() {
+		price, ok := prices[ticker]
+		if !ok {
+			missing = append(missing, ticker)
+			continue
+		}
+		valuations[ticker] = asset.Valuate(price)
+	}
+	sort.Strings(missing)
+	return valuations, missing
+}
+
+// PriceSource fornece o preço de fechamento de um ticker em uma data
+// (ex: a série histórica importada do COTAHIST)
+type PriceSource interface {
+	CloseOn(ticker string, date time.Time) (decimal.Decimal, bool)
+}
+
+// PortfolioValuation é a carteira avaliada em uma data
+type PortfolioValuation struct {
+	Date        time.Time
+	Assets      map[string]AssetValuation // Ativos em carteira na data, com preço
+	Missing     []string                  // Ativos em carteira na data, sem preço
+	CostBasis   decimal.Decimal
+	MarketValue decimal.Decimal
+}
+
+// UnrealizedGain retorna o ganho não realizado dos ativos com preço
+func (p PortfolioValuation) UnrealizedGain() decimal.Decimal {
+	return p.MarketValue.Sub(p.CostBasis)
+}
+
+// HoldingsAt retorna a posição de cada ativo ao final do dia informado
+//
+// Só entram os eventos corporativos até a data: as quantidades e o preço médio são
+// os vigentes naquele dia (antes de desdobramentos posteriores), comparáveis aos
+// preços de fechamento históricos, que não são ajustados
+func (w *Wallet) HoldingsAt(date time.Time) map[string]*Asset {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	positions := w.replayPositions(w.SortedCorporateEvents(""), day)
+	nextDay := day.AddDate(0, 0, 1)
+
+	holdings := make(map[string]*Asset)
+	for ticker, negotiations := range positions {
+		before := make([]parser.Transaction, 0, len(negotiations))
+		for _, tx := range negotiations {
+			if tx.Date.Before(nextDay) {
+				before = append(before, tx)
+			}
+		}
+
+		holding := &Asset{ID: ticker, AdjustedNegotiations: before}
+		if asset, ok := w.Assets[ticker]; ok {
+			holding.Type = asset.Type
+			holding.SubType = asset.SubType
+			holding.Segment = asset.Segment
+			holding.IsSubscription = asset.IsSubscription
+			holding.SubscriptionOf = asset.SubscriptionOf
+		}
+		holding.Quantity = calculateQuantity(holding)
+		if holding.Quantity == 0 {
+			continue
+		}
+		holding.AveragePrice = calculateAveragePrice(holding)
+		holding.TotalInvestedValue = calculateTotalInvestedValue(holding)
+		holdings[ticker] = holding
+	}
+
+	return holdings
+}
+
+// ValuateAt avalia a carteira ao final do dia informado com os preços da fonte
+func (w *Wallet) ValuateAt(date time.Time, prices PriceSource) PortfolioValuation {
+	result := PortfolioValuation{
+		Date:        date,
+		Assets:      make(map[string]AssetValuation),
+		CostBasis:   decimal.Zero,
+		MarketValue: decimal.Zero,
+	}
+
+	for ticker, holding := range w.HoldingsAt(date) {
+		price, ok := prices.CloseOn(ticker, date)
+		if !ok {
+			result.Missing = append(result.Missing, ticker)
+			continue
+		}
+		v := holding.Valuate(price)
+		result.Assets[ticker] = v
+		result.CostBasis = result.CostBasis.Add(v.CostBasis)
+		result.MarketValue = result.MarketValue.Add(v.MarketValue)
+	}
+	sort.Strings(result.Missing)
+
+	return result
+}
+
+// EventCash é o dinheiro movimentado por um evento corporativo
+type EventCash struct {
+	Event  CorporateEvent
+	Ticker string // Ativo em que o dinheiro entrou ou saiu
+	Date   time.Time
+
+	// Amount é positivo quando o investidor paga (exercício de direito) e
+	// negativo quando recebe (leilão de frações, dinheiro da incorporação)
+	Amount decimal.Decimal
+}
+
+// EventCashFlows retorna o dinheiro movimentado pelos eventos corporativos, em
+// ordem cronológica. As demais negociações geradas por eventos (ações
+// bonificadas, ativos da cisão, baixa dos direitos) só transferem custo
+func (w *Wallet) EventCashFlows() []EventCash {
+	sorted := w.SortedCorporateEvents("")
+
+	// As vendas de frações ficam nas posições finais, mesmo que um evento
+	// posterior as tenha convertido para outro ticker
+	sales := make(map[string]parser.Transaction)
+	for _, negotiations := range w.replayPositions(sorted, time.Time{}) {
+		for _, tx := range negotiations {
+			if IsEventTransaction(tx) {
+				sales[tx.Hash] = tx
+			}
+		}
+	}
+
+	var flows []EventCash
+	for _, e := range sorted {
+		cash := EventCash{Event: e, Ticker: e.Ticker, Date: e.Date}
+		switch e.Type {
+		case EventSplit, EventGrouping, EventBonus:
+			if sale, ok := sales[eventTransactionHash(e, e.Ticker, "Venda")]; ok {
+				cash.Amount = sale.Amount.Neg()
+			}
+		case EventMerge:
+			if e.CashPerShare.IsPositive() {
+				// Posição na véspera, com os eventos anteriores aplicados
+				positions := w.replayPositions(sorted, e.Date.AddDate(0, 0, -1))
+				cash.Amount = heldBefore(positions[e.Ticker], e.Date).Mul(e.CashPerShare).Round(2).Neg()
+			}
+		case EventRightsExercised:
+			cash.Ticker = e.TargetTicker
+			cash.Amount = e.Quantity.Mul(e.UnitCost).Round(2)
+		}
+		if !cash.Amount.IsZero() {
+			flows = append(flows, cash)
+		}
+	}
+	return flows
+}
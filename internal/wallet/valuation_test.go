@@ -0,0 +1,163 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TestValuateActiveAssets testa a avaliação da carteira a preço de mercado
+func TestValuateActiveAssets(t *testing.T) {
+	w := &Wallet{Assets: map[string]*Asset{
+		"PETR4": {ID: "PETR4", Quantity: 100, AveragePrice: decimal.RequireFromString("30.5000")},
+		"VALE3": {ID: "VALE3", Quantity: 10, AveragePrice: decimal.RequireFromString("70.0000")},
+		"ITSA4": {ID: "ITSA4", Quantity: 0, AveragePrice: decimal.RequireFromString("9.0000")},
+	}}
+
+	prices := map[string]decimal.Decimal{
+		"PETR4": decimal.RequireFromString("38.51"),
+		"ITSA4": decimal.RequireFromString("10.00"),
+	}
+
+	valuations, missing := w.ValuateActiveAssets(prices)
+
+	if len(missing) != 1 || missing[0] != "VALE3" {
+		t.Errorf("missing = %v, expected [VALE3]", missing)
+	}
+	if _, ok := valuations["ITSA4"]; ok {
+		t.Error("ativo vendido não deveria ser avaliado")
+	}
+
+	v, ok := valuations["PETR4"]
+	if !ok {
+		t.Fatal("PETR4 deveria ser avaliado")
+	}
+	checks := map[string][2]decimal.Decimal{
+		"custo":          {v.CostBasis, decimal.RequireFromString("3050")},
+		"valor":          {v.MarketValue, decimal.RequireFromString("3851")},
+		"ganho":          {v.UnrealizedGain, decimal.RequireFromString("801")},
+		"ganho relativo": {v.UnrealizedRatio.Round(4), decimal.RequireFromString("0.2626")},
+	}
+	for name, c := range checks {
+		if !c[0].Equal(c[1]) {
+			t.Errorf("%s = %s, expected %s", name, c[0], c[1])
+		}
+	}
+}
+
+// closeTable é uma fonte de preços fixa por ticker e dia
+type closeTable map[string]map[int]string
+
+func (c closeTable) CloseOn(ticker string, date time.Time) (decimal.Decimal, bool) {
+	price, ok := c[ticker][date.Day()]
+	if !ok {
+		return decimal.Zero, false
+	}
+	return decimal.RequireFromString(price), true
+}
+
+// TestValuateAt_UsesPositionOfTheDay testa que a avaliação em data passada usa a
+// quantidade vigente no dia (sem ajuste de desdobramentos posteriores)
+func TestValuateAt_UsesPositionOfTheDay(t *testing.T) {
+	w, _ := newTestWallet(t)
+
+	// 100 @ 10 no dia 10, desdobramento 1:2 no dia 20, 100 @ 10 no dia 25
+	for _, day := range []int{10, 25} {
+		if err := w.AddTransaction(testBuy("PETR4", time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+			t.Fatalf("AddTransaction returned error: %v", err)
+		}
+	}
+	if _, err := w.AddCorporateEvent(splitEvent("PETR4", 20, 2)); err != nil {
+		t.Fatalf("AddCorporateEvent returned error: %v", err)
+	}
+
+	prices := closeTable{"PETR4": {5: "9", 15: "12", 20: "6", 30: "7"}}
+	on := func(day int) PortfolioValuation {
+		return w.ValuateAt(time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC), prices)
+	}
+
+	tests := []struct {
+		day      int
+		quantity int
+		market   string
+		cost     string
+	}{
+		{15, 100, "1200", "1000"},    // antes do desdobramento: preço não ajustado
+		{20, 200, "1200", "1000"},    // data "ex": posição já desdobrada
+		{30, 300, "2100", "2000.01"}, // custo = quantidade × PM arredondado (6.6667)
+	}
+	for _, tt := range tests {
+		v := on(tt.day)
+		got := v.Assets["PETR4"]
+		if got.Quantity != tt.quantity || !v.MarketValue.Equal(decimal.RequireFromString(tt.market)) || !v.CostBasis.Equal(decimal.RequireFromString(tt.cost)) {
+			t.Errorf("dia %d: quantidade %d, mercado %s, custo %s; expected %d, %s, %s",
+				tt.day, got.Quantity, v.MarketValue, v.CostBasis, tt.quantity, tt.market, tt.cost)
+		}
+	}
+
+	// Antes da primeira compra não há posição
+	if v := on(5); len(v.Assets) != 0 || len(v.Missing) != 0 {
+		t.Errorf("dia 5: expected empty valuation, got %+v", v)
+	}
+
+	// Posição sem preço na fonte
+	if v := on(25); len(v.Missing) != 1 || v.Missing[0] != "PETR4" {
+		t.Errorf("dia 25: missing = %v", v.Missing)
+	}
+}
+
+func TestEventCashFlows(t *testing.T) {
+	w, _ := newTestWallet(t)
+	if err := w.AddTransaction(testBuy("PETR4", time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), 100)); err != nil {
+		t.Fatalf("AddTransaction returned error: %v", err)
+	}
+
+	jan := func(day int) time.Time { return time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC) }
+	events := []CorporateEvent{
+		// Desdobramento e bonificação sem frações: não movimentam dinheiro
+		splitEvent("PETR4", 12, 2),
+		{
+			Type:     EventBonus,
+			Ticker:   "PETR4",
+			Date:     jan(14),
+			Percent:  decimal.NewFromInt(10),
+			UnitCost: decimal.NewFromInt(5),
+		},
+		// 220 ações × R$ 0,50 recebidos na incorporação
+		{
+			Type:         EventMerge,
+			Ticker:       "PETR4",
+			TargetTicker: "VALE3",
+			Date:         jan(20),
+			RatioFrom:    decimal.NewFromInt(1),
+			RatioTo:      decimal.NewFromInt(1),
+			CashPerShare: decimal.RequireFromString("0.5"),
+		},
+		// 12 direitos exercidos a R$ 9 pagos no ativo pai
+		{
+			Type:         EventRightsExercised,
+			Ticker:       "MXRF12",
+			TargetTicker: "MXRF11",
+			Date:         jan(25),
+			Quantity:     decimal.NewFromInt(12),
+			UnitCost:     decimal.NewFromInt(9),
+		},
+	}
+	for _, e := range events {
+		if _, err := w.AddCorporateEvent(e); err != nil {
+			t.Fatalf("AddCorporateEvent returned error: %v", err)
+		}
+	}
+
+	flows := w.EventCashFlows()
+	if len(flows) != 2 {
+		t.Fatalf("got %d flows, expected 2: %+v", len(flows), flows)
+	}
+	if flows[0].Ticker != "PETR4" || !flows[0].Amount.Equal(decimal.NewFromInt(-110)) {
+		t.Errorf("merge cash = %s %s, expected PETR4 -110", flows[0].Ticker, flows[0].Amount)
+	}
+	if flows[1].Ticker != "MXRF11" || !flows[1].Amount.Equal(decimal.NewFromInt(108)) {
+		t.Errorf("exercise = %s %s, expected MXRF11 108", flows[1].Ticker, flows[1].Amount)
+	}
+}
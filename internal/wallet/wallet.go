@@ -19,12 +19,39 @@ type Wallet struct {
 	// Assets mapeia ticker -> Asset para acesso rápido aos ativos
 	Assets map[string]*Asset
 
+	// CorporateEvents são os eventos corporativos registrados (desdobramentos, grupamentos...)
+	// Aplicados no cálculo das posições sem alterar as transações (ver corporate_events.go)
+	CorporateEvents []CorporateEvent
+
+	// AnnouncedEarnings são proventos anunciados, ainda pendentes até serem creditados
+	// Não alteram posições nem totais (ver announced_earnings.go)
+	AnnouncedEarnings []AnnouncedEarning
+
 	// encryptionKey é a chave usada para criptografar/descriptografar a wallet
 	// Mantida em memória apenas durante a sessão (nunca salva em disco)
 	encryptionKey []byte
 
 	// dirPath é o caminho do diretório onde a wallet está armazenada
 	dirPath string
+
+	// session descreve a sessão desbloqueada em disco (vault.unlocked)
+	// Zero se a wallet não foi aberta em uma sessão
+	session Session
+
+	// migration registra a atualização de schema aplicada no Load (nil se nenhuma)
+	migration *MigrationResult
+
+	// pendingJournal são as entradas de auditoria ainda não gravadas (ver journal.go)
+	pendingJournal []JournalEntry
+
+	// journalHead é a entrada mais recente do journal gravada no cofre (nil em cofres antigos)
+	journalHead *JournalHead
+
+	// batching indica que as mutações fazem parte de um JournalBatch em andamento
+	batching bool
+
+	// undoDepth é a quantidade de snapshots de undo mantidos (nil = padrão)
+	undoDepth *int
 }
 
 // NewWallet cria uma nova Wallet a partir de uma lista de transações
@@ -75,11 +102,14 @@ func NewWallet(transactions []parser.Transaction) *Wallet {
 
 // RecalculateAssets recalcula todos os campos derivados de todos os Assets
 func (w *Wallet) RecalculateAssets() {
+	w.applyCorporateEvents()
+
 	for _, asset := range w.Assets {
 		asset.AveragePrice = calculateAveragePrice(asset)
 		asset.TotalInvestedValue = calculateTotalInvestedValue(asset)
 		asset.Quantity = calculateQuantity(asset)
 		asset.TotalEarnings = calculateTotalEarnings(asset)
+		asset.TotalCapitalReturned = calculateTotalCapitalReturned(asset)
 	}
 }
 
@@ -102,6 +132,8 @@ func (w *Wallet) ConvertSubscriptionToParent(subscriptionTicker, parentTicker st
 		return nil, fmt.Errorf("ativo de subscrição %s não encontrado", subscriptionTicker)
 	}
 
+	before := w.mutationHash()
+
 	// Verificar se o ativo pai existe, se não criar
 	parentAsset, exists := w.Assets[parentTicker]
 	if !exists {
@@ -203,6 +235,14 @@ func (w *Wallet) ConvertSubscriptionToParent(subscriptionTicker, parentTicker st
 		result.ParentAveragePrice = parentAsset.AveragePrice
 	}
 
+	w.RecordMutation("ConvertSubscriptionToParent", map[string]string{
+		"subscription":       subscriptionTicker,
+		"parent":             parentTicker,
+		"purchases_found":    fmt.Sprint(result.PurchasesFound),
+		"sales_found":        fmt.Sprint(result.SalesFound),
+		"transactions_added": fmt.Sprint(result.TransactionsAdded),
+	}, before)
+
 	return result, nil
 }
 